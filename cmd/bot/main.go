@@ -1,43 +1,69 @@
 package main
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	"work-schedule-bot/internal/api"
 	"work-schedule-bot/internal/config"
 	"work-schedule-bot/internal/handler"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/migrations"
+	"work-schedule-bot/internal/models"
 	"work-schedule-bot/internal/repository"
 	"work-schedule-bot/internal/service"
+	"work-schedule-bot/pkg/clock"
+	"work-schedule-bot/pkg/messenger"
 	"work-schedule-bot/pkg/telegram"
 
 	"github.com/sirupsen/logrus"
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
 )
 
+// updatesDrainTimeout - сколько ждать обработки уже принятых обновлений при остановке
+const updatesDrainTimeout = 30 * time.Second
+
 func main() {
+	// Подкоманды обслуживания базы: bot migrate [up | down [N] | status | version], bot restore <file>
+	if len(os.Args) > 1 && (os.Args[1] == "migrate" || os.Args[1] == "restore") {
+		runMaintenance(os.Args[1], os.Args[2:])
+		return
+	}
+
 	logrus.Info("Initializing config...")
 	cfg := config.GetBotConfig()
 	logrus.Info("Config initialized...")
 
-	// Инициализируем SQLite базу данных
-	db, err := gorm.Open(sqlite.Open(cfg.DatabaseURL), &gorm.Config{
-		DisableForeignKeyConstraintWhenMigrating: true,
-	})
+	// Нормы, ограничения и режимы учета отсутствий из конфига передаются сервисам
+	workPolicy := cfg.WorkPolicy()
+	for absenceType, mode := range workPolicy.AbsenceCreditModes {
+		logrus.Infof("Absence type %s credit mode: %s", absenceType, mode)
+	}
+
+	// Календарные дни и месяцы считаются в часовом поясе компании, а не сервера
+	systemClock := clock.System(cfg.Location)
+	logrus.Infof("Business timezone: %s", cfg.Location)
+
+	// Инициализируем базу данных (SQLite или PostgreSQL)
+	db, err := repository.OpenDatabase(cfg.Database.Driver, cfg.Database.URL)
 	if err != nil {
 		logrus.Fatal("Failed to connect to database:", err)
 	}
 
-	// Включаем поддержку внешних ключей для SQLite
 	sqlDB, err := db.DB()
 	if err != nil {
 		logrus.Fatal("Failed to get database instance:", err)
 	}
 
-	_, err = sqlDB.Exec("PRAGMA foreign_keys = ON")
+	logrus.Infof("Connected to %s database", db.Dialector.Name())
+
+	// Применяем непримененные миграции
+	applied, err := migrations.NewMigrator(db).Up()
 	if err != nil {
-		logrus.Infof("Warning: Failed to enable foreign keys: %v", err)
+		logrus.WithError(err).Fatal("Failed to apply migrations")
 	}
+	logrus.Infof("Applied %d migrations", applied)
 
 	// Создаем репозитории
 	userRepo, err := repository.NewUserRepository(db)
@@ -45,17 +71,17 @@ func main() {
 		logrus.WithError(err).Fatal("Failed to create user repository")
 	}
 
-	workScheduleRepo, err := repository.NewGormWorkScheduleRepository(db)
+	workScheduleRepo, err := repository.NewGormWorkScheduleRepository(db, systemClock)
 	if err != nil {
 		logrus.WithError(err).Fatal("Failed to create work schedule repository")
 	}
 
-	userMonthlyStatRepo, err := repository.NewGormUserMonthlyStatRepository(db)
+	userMonthlyStatRepo, err := repository.NewGormUserMonthlyStatRepository(db, systemClock)
 	if err != nil {
 		logrus.WithError(err).Fatal("Failed to create user monthly stat repository")
 	}
 
-	workSessionRepo, err := repository.NewGormWorkSessionRepository(db)
+	workSessionRepo, err := repository.NewGormWorkSessionRepository(db, systemClock)
 	if err != nil {
 		logrus.WithError(err).Fatal("Failed to create work session repository")
 	}
@@ -65,29 +91,62 @@ func main() {
 		logrus.WithError(err).Fatal("Failed to create non-working day repository")
 	}
 
-	absencePeriodRepo, err := repository.NewGormAbsencePeriodRepository(db)
+	absencePeriodRepo, err := repository.NewGormAbsencePeriodRepository(db, systemClock)
 	if err != nil {
 		logrus.WithError(err).Fatal("Failed to create absence period repository")
 	}
 
-	// Создаем сервисы
-	nonWorkingDayService := service.NewNonWorkingDayService(nonWorkingDayRepo)
+	timeBankRepo, err := repository.NewGormTimeBankRepository(db)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create time bank repository")
+	}
 
-	// Загружаем выходные дни из JSON
-	logrus.Info("Loading non-working days from JSON...")
-	count, err := nonWorkingDayService.LoadFromJSON("jsons/weekends_2026.json")
+	teamRepo, err := repository.NewGormTeamRepository(db)
 	if err != nil {
-		logrus.WithError(err).Fatal("Failed to load non-working days")
+		logrus.WithError(err).Fatal("Failed to create team repository")
+	}
+
+	dialogStateRepo, err := repository.NewGormDialogStateRepository(db)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create dialog state repository")
+	}
+
+	apiTokenRepo, err := repository.NewGormAPITokenRepository(db)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create API token repository")
+	}
+
+	auditRepo, err := repository.NewGormAuditRepository(db, systemClock)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create audit repository")
+	}
+
+	// Единица работы для операций, затрагивающих несколько репозиториев
+	unitOfWork := repository.NewGormUnitOfWork(db, systemClock)
+
+	// Создаем сервисы
+	nonWorkingDayService := service.NewNonWorkingDayService(nonWorkingDayRepo, systemClock)
+
+	// Загружаем выходные дни из файлов производственного календаря
+	for _, file := range cfg.Calendar.Files {
+		logrus.Infof("Loading non-working days from %s...", file)
+		count, err := nonWorkingDayService.LoadFromJSON(file)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to load non-working days")
+		}
+		logrus.Infof("Loaded %d non-working days from %s", count, file)
 	}
-	logrus.Infof("Loaded %d non-working days for 2026", count)
 
-	userMonthlyStatService := service.NewUserMonthlyStatService(userMonthlyStatRepo, userRepo)
+	userMonthlyStatService := service.NewUserMonthlyStatService(userMonthlyStatRepo, userRepo, unitOfWork, workPolicy, systemClock)
 
 	// Создаем WorkScheduleService с зависимостью от NonWorkingDayService
 	workScheduleService := service.NewWorkScheduleService(
 		workScheduleRepo,
 		userMonthlyStatService,
 		nonWorkingDayService, // ДОБАВЛЕНО
+		unitOfWork,
+		workPolicy,
+		systemClock,
 	)
 
 	absenceService := service.NewAbsenceService( // ДОБАВЛЕНО
@@ -96,16 +155,45 @@ func main() {
 		userMonthlyStatRepo,
 		userRepo,
 		workScheduleRepo,
+		timeBankRepo,
+		unitOfWork,
 		nonWorkingDayService,
+		workPolicy,
+		systemClock,
 	)
 
-	// Автоматически создаем/обновляем графики на основе выходных дней
-	logrus.Info("Generating work schedules from non-working days...")
-	generatedSchedules, err := workScheduleService.GenerateSchedulesFromNonWorkingDays(2026, 8*60+40) // 8 часов = 480 минут
-	if err != nil {
-		logrus.WithError(err).Error("Failed to generate work schedules")
+	teamCalendarService := service.NewTeamCalendarService(
+		absencePeriodRepo,
+		userRepo,
+		nonWorkingDayService,
+		cfg.Policy.TeamAbsenceThresholdPercent,
+		systemClock,
+	)
+
+	teamService := service.NewTeamService(teamRepo, userRepo, unitOfWork)
+	dialogService := service.NewDialogService(dialogStateRepo, systemClock)
+	apiTokenService := service.NewAPITokenService(apiTokenRepo, unitOfWork, systemClock)
+	sessionImportService := service.NewSessionImportService(unitOfWork, workPolicy, systemClock)
+	backupService := service.NewBackupService(db, systemClock, cfg.Backup.Dir, cfg.Backup.Keep)
+	auditService := service.NewAuditService(auditRepo, userRepo, apiTokenRepo, systemClock)
+
+	// Переносим итоги прошлого месяца в банк времени
+	timeBankService := service.NewTimeBankService(timeBankRepo, userMonthlyStatRepo, userRepo, unitOfWork, systemClock)
+	if closed, err := timeBankService.ClosePreviousMonth(); err != nil {
+		logrus.WithError(err).Error("Failed to close previous month into time bank")
 	} else {
-		logrus.Infof("Generated %d work schedules for 2026", len(generatedSchedules))
+		logrus.Infof("Previous month closed into time bank for %d users", closed)
+	}
+
+	// Автоматически создаем/обновляем графики на основе выходных дней
+	for _, year := range cfg.Calendar.ScheduleYears {
+		logrus.Infof("Generating work schedules for %d from non-working days...", year)
+		generatedSchedules, err := workScheduleService.GenerateSchedulesFromNonWorkingDays(year, cfg.Norms.WorkDayMinutes, models.SystemActor)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to generate work schedules")
+			continue
+		}
+		logrus.Infof("Generated %d work schedules for %d", len(generatedSchedules), year)
 
 		// Проверяем созданные графики
 		totalDays := 0
@@ -114,27 +202,31 @@ func main() {
 			logrus.Infof("Month %02d: %d working days, %d minutes per day",
 				schedule.Month, schedule.WorkDays, schedule.WorkMinutesPerDay)
 		}
-		logrus.Infof("Total working days in 2026: %d", totalDays)
+		logrus.Infof("Total working days in %d: %d", year, totalDays)
 	}
 
 	// Создаем остальные сервисы
-	userService := service.NewUserService(userRepo, workScheduleRepo, userMonthlyStatService)
+	userService := service.NewUserService(userRepo, workScheduleRepo, userMonthlyStatService, unitOfWork, systemClock)
 	workSessionService := service.NewWorkSessionService(
 		workSessionRepo,
 		userMonthlyStatRepo,
 		workScheduleRepo,
 		absencePeriodRepo,
+		nonWorkingDayService,
+		unitOfWork,
+		workPolicy,
+		systemClock,
 	)
 
 	// Инициализируем администратора
-	if err := userService.InitializeAdmin(cfg.BaseAdminChatID); err != nil {
+	if err := userService.InitializeAdmin(cfg.Telegram.AdminChatID); err != nil {
 		logrus.Infof("Warning: Failed to initialize admin: %v", err)
-	} else if cfg.BaseAdminChatID != 0 {
-		logrus.Infof("Admin initialized with chat ID: %d", cfg.BaseAdminChatID)
+	} else if cfg.Telegram.AdminChatID != 0 {
+		logrus.Infof("Admin initialized with chat ID: %d", cfg.Telegram.AdminChatID)
 	}
 
 	// Создаем клиент Telegram
-	client, err := telegram.NewClient(cfg.TelegramToken)
+	client, err := telegram.NewClient(cfg.Telegram.Token)
 	if err != nil {
 		logrus.Fatal("Failed to create Telegram client:", err)
 	}
@@ -150,21 +242,94 @@ func main() {
 		workSessionService,
 		nonWorkingDayService,
 		absenceService,
+		timeBankService,
+		teamCalendarService,
+		teamService,
+		dialogService,
+		apiTokenService,
+		sessionImportService,
+		backupService,
+		auditService,
+		systemClock,
 		cfg,
 	)
 
 	// Настраиваем канал обновлений
-	updates := client.Bot.GetUpdatesChan(client.UpdateConfig)
+	updates, stopUpdates, err := startUpdates(client, cfg)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to start receiving updates")
+	}
+
+	// HTTP API для внешних систем
+	var apiServer *api.Server
+	if cfg.API.ListenAddr != "" {
+		apiServer = api.NewServer(
+			userService,
+			workSessionService,
+			absenceService,
+			workScheduleService,
+			userMonthlyStatService,
+			apiTokenService,
+			workPolicy,
+			systemClock,
+		)
+		go func() {
+			if err := apiServer.ListenAndServe(cfg.API.ListenAddr); err != nil {
+				logrus.WithError(err).Fatal("API server failed")
+			}
+		}()
+	}
 
 	// Обработка сигналов
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
-	// Запускаем обработку сообщений
-	go botHandler.HandleUpdates(updates)
+	// Запускаем обработку сообщений; канал закрывается, когда обработаны все принятые обновления
+	handlerDone := make(chan struct{})
+	go func() {
+		defer close(handlerDone)
+		botHandler.HandleUpdates(telegram.ConvertUpdates(updates))
+	}()
+
+	// Раз в сутки в заданное время сверяем статистику с исходными данными, переносим итоги
+	// прошлого месяца в банк времени, удаляем брошенные диалоги и сохраняем резервную копию
+	go func() {
+		for {
+			next := cfg.Jobs.DailyAt.Next(systemClock.Now(), systemClock.Location())
+			logrus.Infof("Next daily maintenance at %s", next.Format("02.01.2006 15:04 MST"))
+			time.Sleep(time.Until(next))
+
+			if err := dialogService.CleanupExpired(); err != nil {
+				logrus.WithError(err).Error("Failed to remove expired dialogs")
+			}
+			checkMonthlyStats(client, userMonthlyStatService, userService, cfg.Telegram.AdminChatID)
+			if _, err := timeBankService.ClosePreviousMonth(); err != nil {
+				logrus.WithError(err).Error("Failed to close previous month into time bank")
+			}
+			if _, err := backupService.WriteScheduled(); err != nil {
+				logrus.WithError(err).Error("Failed to write scheduled backup")
+			}
+		}
+	}()
 
 	logrus.Info("Bot started. Press Ctrl+C to stop.")
 	<-stop
+	stopUpdates()
+
+	// Даем обработчикам закончить начатые обновления, пока база данных еще открыта
+	select {
+	case <-handlerDone:
+	case <-time.After(updatesDrainTimeout):
+		logrus.Warn("Timed out waiting for update handlers to finish")
+	}
+
+	if apiServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := apiServer.Shutdown(ctx); err != nil {
+			logrus.WithError(err).Error("Failed to stop API server")
+		}
+		cancel()
+	}
 
 	// Закрываем соединение с БД
 	if err := sqlDB.Close(); err != nil {
@@ -173,3 +338,61 @@ func main() {
 
 	logrus.Info("Bot stopped gracefully")
 }
+
+// runMaintenance выполняет подкоманду обслуживания базы. Ей нужен только раздел database
+// конфигурации: токен бота и файлы календаря не проверяются и не загружаются.
+func runMaintenance(command string, args []string) {
+	dbConfig := config.GetDatabaseConfig()
+
+	db, err := repository.OpenDatabase(dbConfig.Driver, dbConfig.URL)
+	if err != nil {
+		logrus.Fatal("Failed to connect to database:", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		logrus.Fatal("Failed to get database instance:", err)
+	}
+
+	logrus.Infof("Connected to %s database", db.Dialector.Name())
+
+	failure := "Migration failed: "
+	if command == "migrate" {
+		err = runMigrate(db, args)
+	} else {
+		failure = "Restore failed: "
+		err = runRestore(db, args)
+	}
+	sqlDB.Close()
+
+	if err != nil {
+		logrus.Fatal(failure, err)
+	}
+}
+
+// checkMonthlyStats исправляет расхождения статистики и сообщает о них администратору
+func checkMonthlyStats(client messenger.Client, statService *service.UserMonthlyStatService, userService *service.UserService, adminChatID int64) {
+	drifts, err := statService.CheckConsistency()
+	if err != nil {
+		logrus.WithError(err).Error("Monthly stats consistency check failed")
+	}
+	if len(drifts) == 0 || adminChatID == 0 {
+		return
+	}
+
+	users, err := userService.GetAllUsers()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get users for consistency report")
+	}
+
+	// Отчет - на языке профиля администратора
+	tr := i18n.For("")
+	if admin, err := userService.FindUser(adminChatID); err == nil && admin != nil {
+		tr = i18n.For(admin.Language)
+	}
+
+	msg := messenger.NewMessage(adminChatID, statService.FormatDrifts(tr, drifts, users))
+	if err := client.Send(msg); err != nil {
+		logrus.WithError(err).Error("Failed to send consistency report")
+	}
+}
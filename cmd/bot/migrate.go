@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"work-schedule-bot/internal/migrations"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const migrateUsage = "usage: bot migrate [up | down [N] | status | version]"
+
+// runMigrate выполняет подкоманду migrate
+func runMigrate(db *gorm.DB, args []string) error {
+	migrator := migrations.NewMigrator(db)
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		count, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		logrus.Infof("Applied %d migrations", count)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return errors.New(migrateUsage)
+			}
+			steps = n
+		}
+
+		count, err := migrator.Down(steps)
+		if err != nil {
+			return err
+		}
+		logrus.Infof("Rolled back %d migrations", count)
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, state)
+		}
+
+	case "version":
+		version, err := migrator.Version()
+		if err != nil {
+			return err
+		}
+		fmt.Println(version)
+
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	return nil
+}
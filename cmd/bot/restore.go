@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"work-schedule-bot/internal/backup"
+	"work-schedule-bot/internal/migrations"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const restoreUsage = "usage: bot restore <file>"
+
+// runRestore выполняет подкоманду restore: создает схему и загружает резервную копию
+// (JSON-дамп или файл SQLite от /backup) в пустую базу
+func runRestore(db *gorm.DB, args []string) error {
+	if len(args) != 1 {
+		return errors.New(restoreUsage)
+	}
+
+	dump, err := backup.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	// Модели соответствуют последней версии схемы, поэтому копия должна быть сделана той же
+	// версией бота; копию старой версии восстанавливают ее бинарником, затем запускают новый
+	if _, err := migrations.NewMigrator(db).Up(); err != nil {
+		return err
+	}
+
+	if err := backup.Load(db, dump); err != nil {
+		return err
+	}
+
+	logrus.Infof("Restored %d rows from %s (schema version %d, created %s)",
+		dump.Rows(), args[0], dump.Schema, dump.CreatedAt.Format("2006-01-02 15:04:05"))
+	return nil
+}
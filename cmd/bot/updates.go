@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"time"
+	"work-schedule-bot/internal/config"
+	"work-schedule-bot/pkg/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// startUpdates запускает получение обновлений выбранным способом.
+// Возвращает канал обновлений и функцию, которая останавливает получение.
+func startUpdates(client *telegram.Client, cfg *config.BotConfig) (tgbotapi.UpdatesChannel, func(), error) {
+	if cfg.Telegram.UpdateMode != config.UpdateModeWebhook {
+		// Пока зарегистрирован вебхук, getUpdates возвращает ошибку
+		if err := client.DeleteWebhook(); err != nil {
+			return nil, nil, err
+		}
+
+		logrus.Info("Receiving updates via long polling")
+		return client.Bot.GetUpdatesChan(client.UpdateConfig), client.Bot.StopReceivingUpdates, nil
+	}
+
+	webhookConfig := telegram.WebhookConfig{
+		URL:         cfg.Telegram.Webhook.URL,
+		ListenAddr:  cfg.Telegram.Webhook.ListenAddr,
+		Path:        cfg.Telegram.Webhook.Path,
+		SecretToken: cfg.Telegram.Webhook.Secret,
+		CertFile:    cfg.Telegram.Webhook.CertFile,
+		KeyFile:     cfg.Telegram.Webhook.KeyFile,
+	}
+	if err := webhookConfig.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	server := telegram.NewWebhookServer(webhookConfig)
+	go func() {
+		if err := server.ListenAndServe(); err != nil {
+			logrus.WithError(err).Fatal("Webhook server failed")
+		}
+	}()
+
+	if err := client.SetWebhook(webhookConfig); err != nil {
+		return nil, nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"url":    webhookConfig.URL,
+		"listen": webhookConfig.ListenAddr,
+		"path":   webhookConfig.Path,
+	}).Info("Receiving updates via webhook")
+
+	stop := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			logrus.WithError(err).Error("Failed to stop webhook server")
+		}
+	}
+
+	return server.Updates(), stop, nil
+}
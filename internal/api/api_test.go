@@ -0,0 +1,297 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+	"work-schedule-bot/internal/migrations"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/internal/service"
+	"work-schedule-bot/pkg/clock"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type testAPI struct {
+	db     *gorm.DB
+	server *httptest.Server
+	token  string
+	tokens *service.APITokenService
+}
+
+// newTestAPI поднимает API поверх настоящих сервисов и временной базы SQLite
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+
+	db, err := repository.OpenDatabase(repository.DriverSQLite, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database instance: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if _, err := migrations.NewMigrator(db).Up(); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	clk := clock.System(nil)
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+	}
+	userRepo, err := repository.NewUserRepository(db)
+	must(err)
+	scheduleRepo, err := repository.NewGormWorkScheduleRepository(db, clk)
+	must(err)
+	statRepo, err := repository.NewGormUserMonthlyStatRepository(db, clk)
+	must(err)
+	sessionRepo, err := repository.NewGormWorkSessionRepository(db, clk)
+	must(err)
+	nonWorkingDayRepo, err := repository.NewGormNonWorkingDayRepository(db)
+	must(err)
+	absenceRepo, err := repository.NewGormAbsencePeriodRepository(db, clk)
+	must(err)
+	timeBankRepo, err := repository.NewGormTimeBankRepository(db)
+	must(err)
+	tokenRepo, err := repository.NewGormAPITokenRepository(db)
+	must(err)
+
+	uow := repository.NewGormUnitOfWork(db, clk)
+	nonWorkingDayService := service.NewNonWorkingDayService(nonWorkingDayRepo, clk)
+	policy := models.DefaultWorkPolicy()
+	statService := service.NewUserMonthlyStatService(statRepo, userRepo, uow, policy, clk)
+	tokenService := service.NewAPITokenService(tokenRepo, uow, clk)
+
+	server := NewServer(
+		service.NewUserService(userRepo, scheduleRepo, statService, uow, clk),
+		service.NewWorkSessionService(sessionRepo, statRepo, scheduleRepo, absenceRepo, nonWorkingDayService, uow, policy, clk),
+		service.NewAbsenceService(absenceRepo, sessionRepo, statRepo, userRepo, scheduleRepo, timeBankRepo, uow, nonWorkingDayService, policy, clk),
+		service.NewWorkScheduleService(scheduleRepo, statService, nonWorkingDayService, uow, policy, clk),
+		statService,
+		tokenService,
+		policy,
+		clk,
+	)
+
+	token, _, err := tokenService.CreateToken("HR-портал", 1)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+
+	httpServer := httptest.NewServer(server.Handler())
+	t.Cleanup(httpServer.Close)
+
+	user := models.User{ChatID: 100, FirstName: "Иван", LastName: "Петров", Role: models.RoleEmployee}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	return &testAPI{db: db, server: httpServer, token: token, tokens: tokenService}
+}
+
+// do выполняет запрос с токеном и раскладывает JSON-ответ в result
+func (a *testAPI) do(t *testing.T, method, path, token string, body interface{}, result interface{}) int {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to encode body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, a.server.URL+path, reader)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			t.Fatalf("%s %s: failed to decode response: %v", method, path, err)
+		}
+	}
+
+	return resp.StatusCode
+}
+
+func TestAPIRequiresValidToken(t *testing.T) {
+	a := newTestAPI(t)
+
+	if status := a.do(t, http.MethodGet, "/api/v1/users", "", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("without token: status = %d, want %d", status, http.StatusUnauthorized)
+	}
+	if status := a.do(t, http.MethodGet, "/api/v1/users", "wsb_unknown", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("unknown token: status = %d, want %d", status, http.StatusUnauthorized)
+	}
+
+	var users []models.User
+	if status := a.do(t, http.MethodGet, "/api/v1/users", a.token, nil, &users); status != http.StatusOK {
+		t.Fatalf("valid token: status = %d, want %d", status, http.StatusOK)
+	}
+	if len(users) != 1 || users[0].ChatID != 100 {
+		t.Errorf("users = %+v, want one user with chat ID 100", users)
+	}
+
+	// Отозванный токен больше не принимается
+	tokens, err := a.tokens.GetTokens()
+	if err != nil || len(tokens) != 1 {
+		t.Fatalf("GetTokens = %v, %v", tokens, err)
+	}
+	if _, err := a.tokens.RevokeToken(tokens[0].ID, models.SystemActor); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if status := a.do(t, http.MethodGet, "/api/v1/users", a.token, nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("revoked token: status = %d, want %d", status, http.StatusUnauthorized)
+	}
+}
+
+func TestAPIClockInAndOut(t *testing.T) {
+	a := newTestAPI(t)
+
+	if status := a.do(t, http.MethodPost, "/api/v1/users/999/clock-in", a.token, nil, nil); status != http.StatusNotFound {
+		t.Errorf("unknown user: status = %d, want %d", status, http.StatusNotFound)
+	}
+
+	clockIn := time.Now().Add(-time.Minute).Truncate(time.Second)
+	var session sessionResponse
+	status := a.do(t, http.MethodPost, "/api/v1/users/100/clock-in", a.token, map[string]interface{}{"time": clockIn}, &session)
+	if status != http.StatusCreated {
+		t.Fatalf("clock-in: status = %d, want %d", status, http.StatusCreated)
+	}
+	if session.Status != models.StatusActive || !session.ClockInTime.Equal(clockIn) {
+		t.Errorf("clock-in session = %+v, want active session started at %s", session, clockIn)
+	}
+
+	// Повторное начало дня нарушает правила, как и в боте
+	var apiErr map[string]string
+	if status := a.do(t, http.MethodPost, "/api/v1/users/100/clock-in", a.token, nil, &apiErr); status != http.StatusConflict {
+		t.Errorf("second clock-in: status = %d, want %d", status, http.StatusConflict)
+	}
+	if apiErr["error"] == "" {
+		t.Error("second clock-in: error message is empty")
+	}
+
+	if status := a.do(t, http.MethodPost, "/api/v1/users/100/clock-out", a.token, nil, &session); status != http.StatusOK {
+		t.Fatalf("clock-out: status = %d, want %d", status, http.StatusOK)
+	}
+	if session.Status != models.StatusCompleted || session.ClockOutTime == nil {
+		t.Errorf("clock-out session = %+v, want completed session", session)
+	}
+
+	var sessions []sessionResponse
+	path := "/api/v1/users/100/sessions?from=" + clockIn.Format(dateLayout) + "&to=" + time.Now().Format(dateLayout)
+	if status := a.do(t, http.MethodGet, path, a.token, nil, &sessions); status != http.StatusOK {
+		t.Fatalf("sessions: status = %d, want %d", status, http.StatusOK)
+	}
+	if len(sessions) != 1 || sessions[0].ID != session.ID {
+		t.Errorf("sessions = %+v, want the completed session", sessions)
+	}
+}
+
+func TestAPICreateAbsence(t *testing.T) {
+	a := newTestAPI(t)
+
+	body := map[string]string{"type": models.AbsenceTypeVacation, "start_date": "2030-07-01", "end_date": "2030-07-05"}
+	var absence absenceResponse
+	if status := a.do(t, http.MethodPost, "/api/v1/users/100/absences", a.token, body, &absence); status != http.StatusCreated {
+		t.Fatalf("create absence: status = %d, want %d", status, http.StatusCreated)
+	}
+	if absence.Type != models.AbsenceTypeVacation || absence.StartDate != "2030-07-01" || absence.EndDate != "2030-07-05" {
+		t.Errorf("absence = %+v, want vacation 2030-07-01..2030-07-05", absence)
+	}
+
+	// Пересечение с существующим отпуском отклоняется правилами AbsenceService
+	overlap := map[string]string{"type": models.AbsenceTypeSickLeave, "start_date": "2030-07-03", "end_date": "2030-07-04"}
+	if status := a.do(t, http.MethodPost, "/api/v1/users/100/absences", a.token, overlap, nil); status != http.StatusUnprocessableEntity {
+		t.Errorf("overlapping absence: status = %d, want %d", status, http.StatusUnprocessableEntity)
+	}
+
+	unknown := map[string]string{"type": "holiday", "start_date": "2030-08-01"}
+	if status := a.do(t, http.MethodPost, "/api/v1/users/100/absences", a.token, unknown, nil); status != http.StatusBadRequest {
+		t.Errorf("unknown type: status = %d, want %d", status, http.StatusBadRequest)
+	}
+
+	var absences []absenceResponse
+	if status := a.do(t, http.MethodGet, "/api/v1/users/100/absences?from=2030-07-04&to=2030-07-31", a.token, nil, &absences); status != http.StatusOK {
+		t.Fatalf("list absences: status = %d, want %d", status, http.StatusOK)
+	}
+	if len(absences) != 1 || absences[0].ID != absence.ID {
+		t.Errorf("absences = %+v, want the created vacation", absences)
+	}
+}
+
+func TestAPIErrorLanguage(t *testing.T) {
+	a := newTestAPI(t)
+
+	errorText := func(token, acceptLanguage string) string {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, a.server.URL+"/api/v1/users/999", nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var apiErr map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return apiErr["error"]
+	}
+
+	// Владельца токена нет среди пользователей - язык по умолчанию
+	if got, want := errorText(a.token, ""), "пользователь 999 не найден"; got != want {
+		t.Errorf("default language error = %q, want %q", got, want)
+	}
+	if got, want := errorText("", "en-US,en;q=0.9"), "Authorization: Bearer <token> header is required"; got != want {
+		t.Errorf("unauthorized error = %q, want %q", got, want)
+	}
+
+	owner := models.User{ChatID: 1, FirstName: "Admin", Role: models.RoleAdmin, Language: "en"}
+	if err := a.db.Create(&owner).Error; err != nil {
+		t.Fatalf("failed to create token owner: %v", err)
+	}
+	if got, want := errorText(a.token, ""), "user 999 not found"; got != want {
+		t.Errorf("token owner language error = %q, want %q", got, want)
+	}
+	// Accept-Language важнее языка владельца токена
+	if got, want := errorText(a.token, "ru"), "пользователь 999 не найден"; got != want {
+		t.Errorf("Accept-Language error = %q, want %q", got, want)
+	}
+}
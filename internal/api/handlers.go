@@ -0,0 +1,486 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/service"
+	"work-schedule-bot/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// dateLayout - формат дат в параметрах и телах запросов
+const dateLayout = "2006-01-02"
+
+// sessionResponse - рабочая сессия в ответе API
+type sessionResponse struct {
+	ID              uint       `json:"id"`
+	Date            string     `json:"date"`
+	ClockInTime     time.Time  `json:"clock_in_time"`
+	ClockOutTime    *time.Time `json:"clock_out_time"`
+	RequiredMinutes int        `json:"required_minutes"`
+	WorkedMinutes   int        `json:"worked_minutes"`
+	DiffMinutes     int        `json:"diff_minutes"`
+	Status          string     `json:"status"`
+	SessionType     string     `json:"session_type"`
+	Notes           string     `json:"notes,omitempty"`
+}
+
+func newSessionResponse(session *models.WorkSession) sessionResponse {
+	return sessionResponse{
+		ID:              session.ID,
+		Date:            session.Date.Format(dateLayout),
+		ClockInTime:     session.ClockInTime,
+		ClockOutTime:    session.ClockOutTime,
+		RequiredMinutes: session.RequiredMinutes,
+		WorkedMinutes:   session.WorkedMinutes,
+		DiffMinutes:     session.DiffMinutes,
+		Status:          session.Status,
+		SessionType:     session.SessionType,
+		Notes:           session.Notes,
+	}
+}
+
+// absenceResponse - период отсутствия в ответе API
+type absenceResponse struct {
+	ID        uint   `json:"id"`
+	Type      string `json:"type"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+func newAbsenceResponse(period *models.AbsencePeriod) absenceResponse {
+	return absenceResponse{
+		ID:        period.ID,
+		Type:      period.Type,
+		StartDate: period.StartDate.Format(dateLayout),
+		EndDate:   period.EndDate.Format(dateLayout),
+	}
+}
+
+// clockRequest - тело запросов clock-in и clock-out
+type clockRequest struct {
+	Time               *time.Time `json:"time"`                  // по умолчанию - текущее время
+	AllowNonWorkingDay bool       `json:"allow_non_working_day"` // подтверждение завершения в выходной день
+}
+
+// absenceRequest - тело запроса создания отсутствия
+type absenceRequest struct {
+	Type      string `json:"type"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"` // для day_off и truancy не нужен
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := s.userService.GetAllUsers()
+	if err != nil {
+		s.internalError(w, r, err, "Failed to get users")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) listSessions(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+
+	// По умолчанию - текущий месяц по календарю сотрудника
+	now := s.clock.Now().In(user.Location(s.clock.Location()))
+	from := clock.MonthStart(now.Year(), now.Month(), s.clock.Location())
+	to := from.AddDate(0, 1, -1)
+	if !s.parseDateQuery(w, r, "from", &from) || !s.parseDateQuery(w, r, "to", &to) {
+		return
+	}
+
+	sessions, err := s.workSessionService.GetSessionsForPeriod(user.ID, from, to)
+	if err != nil {
+		writeServiceError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	response := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, newSessionResponse(session))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// clockIn начинает рабочий день по тем же правилам, что и команда /in
+func (s *Server) clockIn(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.activePathUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req clockRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	// Время переводится в пояс сотрудника: по его календарю определяется рабочий день
+	targetTime := s.clock.Now().In(user.Location(s.clock.Location()))
+	if req.Time != nil {
+		targetTime = req.Time.In(user.Location(s.clock.Location()))
+	}
+
+	session, err := s.workSessionService.ClockIn(user.ID, targetTime, requestActor(r))
+	if err != nil {
+		s.clockError(w, r, err, "Failed to clock in")
+		return
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"client":  requestToken(r).Name,
+		"chat_id": user.ChatID,
+	}).Info("Clock in via API")
+
+	writeJSON(w, http.StatusCreated, newSessionResponse(session))
+}
+
+// clockOut завершает рабочий день по тем же правилам, что и команда /out
+func (s *Server) clockOut(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.activePathUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req clockRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	// Время переводится в пояс сотрудника: по его календарю определяется рабочий день
+	targetTime := s.clock.Now().In(user.Location(s.clock.Location()))
+	if req.Time != nil {
+		targetTime = req.Time.In(user.Location(s.clock.Location()))
+	}
+
+	session, err := s.workSessionService.ClockOut(user.ID, targetTime, req.AllowNonWorkingDay, requestActor(r))
+	var clockErr *service.ClockError
+	if errors.As(err, &clockErr) && clockErr.Reason == service.ClockNonWorkingDay {
+		writeError(w, r, http.StatusUnprocessableEntity, "api.error.clock_out_non_working", targetTime)
+		return
+	}
+	if err != nil {
+		s.clockError(w, r, err, "Failed to clock out")
+		return
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"client":  requestToken(r).Name,
+		"chat_id": user.ChatID,
+	}).Info("Clock out via API")
+
+	writeJSON(w, http.StatusOK, newSessionResponse(session))
+}
+
+func (s *Server) listAbsences(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+
+	var periods []models.AbsencePeriod
+	var err error
+	if r.URL.Query().Get("from") == "" && r.URL.Query().Get("to") == "" {
+		periods, err = s.absenceService.GetUserAbsences(user.ID)
+	} else {
+		from := clock.Date(s.policy.MinYear, time.January, 1, s.clock.Location())
+		to := clock.Date(s.policy.MaxYear, time.December, 31, s.clock.Location())
+		if !s.parseDateQuery(w, r, "from", &from) || !s.parseDateQuery(w, r, "to", &to) {
+			return
+		}
+		periods, err = s.absenceService.GetUserAbsencesForPeriod(user.ID, from, to)
+	}
+	if err != nil {
+		writeServiceError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	response := make([]absenceResponse, 0, len(periods))
+	for i := range periods {
+		response = append(response, newAbsenceResponse(&periods[i]))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// createAbsence добавляет отсутствие через AbsenceService с его проверками
+func (s *Server) createAbsence(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.activePathUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req absenceRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	startDate, err := time.ParseInLocation(dateLayout, req.StartDate, s.clock.Location())
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "api.error.invalid_start_date")
+		return
+	}
+	endDate := startDate
+	if req.EndDate != "" {
+		endDate, err = time.ParseInLocation(dateLayout, req.EndDate, s.clock.Location())
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "api.error.invalid_end_date")
+			return
+		}
+	}
+	if endDate.Before(startDate) {
+		writeError(w, r, http.StatusBadRequest, "api.error.end_before_start")
+		return
+	}
+
+	actor := requestActor(r)
+	var period *models.AbsencePeriod
+	switch req.Type {
+	case models.AbsenceTypeVacation:
+		period, err = s.absenceService.AddVacation(user.ID, startDate, endDate, actor)
+	case models.AbsenceTypeSickLeave:
+		period, err = s.absenceService.AddSickLeave(user.ID, startDate, endDate, actor)
+	case models.AbsenceTypeUnpaidLeave:
+		period, err = s.absenceService.AddUnpaidLeave(user.ID, startDate, endDate, actor)
+	case models.AbsenceTypeDayOff:
+		period, err = s.absenceService.AddDayOff(user.ID, startDate, actor)
+	case models.AbsenceTypeTruancy:
+		period, err = s.absenceService.AddTruancy(user.ID, startDate, actor)
+	default:
+		writeError(w, r, http.StatusBadRequest, "api.error.unknown_absence_type", req.Type)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"client":  requestToken(r).Name,
+		"chat_id": user.ChatID,
+		"type":    period.Type,
+	}).Info("Absence created via API")
+
+	writeJSON(w, http.StatusCreated, newAbsenceResponse(period))
+}
+
+func (s *Server) listStats(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+
+	year := 0
+	if value := r.URL.Query().Get("year"); value != "" {
+		var err error
+		year, err = strconv.Atoi(value)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "api.error.invalid_year")
+			return
+		}
+	}
+
+	stats, err := s.userMonthlyStatService.GetUserStats(user.ID)
+	if err != nil {
+		s.internalError(w, r, err, "Failed to get user stats")
+		return
+	}
+
+	response := make([]*models.UserMonthlyStat, 0, len(stats))
+	for _, stat := range stats {
+		if year == 0 || stat.Year == year {
+			response = append(response, stat)
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getStat(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+	year, month, ok := s.pathYearMonth(w, r)
+	if !ok {
+		return
+	}
+
+	stat, err := s.userMonthlyStatService.GetUserStatByMonth(user.ID, year, month)
+	if err != nil {
+		s.internalError(w, r, err, "Failed to get user stat")
+		return
+	}
+	if stat == nil {
+		writeError(w, r, http.StatusNotFound, "api.error.stat_not_found", month, year)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, stat)
+}
+
+func (s *Server) listSchedules(w http.ResponseWriter, r *http.Request) {
+	var schedules []*models.WorkSchedule
+	var err error
+
+	if value := r.URL.Query().Get("year"); value != "" {
+		year, convErr := strconv.Atoi(value)
+		if convErr != nil {
+			writeError(w, r, http.StatusBadRequest, "api.error.invalid_year")
+			return
+		}
+		schedules, err = s.workScheduleService.GetSchedulesByYear(year)
+	} else {
+		schedules, err = s.workScheduleService.GetAllSchedules()
+	}
+	if err != nil {
+		s.internalError(w, r, err, "Failed to get schedules")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schedules)
+}
+
+func (s *Server) getSchedule(w http.ResponseWriter, r *http.Request) {
+	year, month, ok := s.pathYearMonth(w, r)
+	if !ok {
+		return
+	}
+
+	schedule, err := s.workScheduleService.GetScheduleByYearMonth(year, month)
+	if err != nil {
+		s.internalError(w, r, err, "Failed to get schedule")
+		return
+	}
+	if schedule == nil {
+		writeError(w, r, http.StatusNotFound, "api.error.schedule_not_found", month, year)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schedule)
+}
+
+// pathUser находит пользователя по chat ID из пути запроса
+func (s *Server) pathUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	chatID, err := strconv.ParseInt(r.PathValue("chatID"), 10, 64)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "api.error.invalid_chat_id")
+		return nil, false
+	}
+
+	user, err := s.userService.FindUser(chatID)
+	if err != nil {
+		s.internalError(w, r, err, "Failed to get user")
+		return nil, false
+	}
+	if user == nil {
+		writeError(w, r, http.StatusNotFound, "api.error.user_not_found", chatID)
+		return nil, false
+	}
+
+	return user, true
+}
+
+// activePathUser - pathUser для изменений: данные деактивированного и неодобренного сотрудника не меняются
+func (s *Server) activePathUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return nil, false
+	}
+	if !user.IsActive() {
+		writeError(w, r, http.StatusConflict, "api.error.user_inactive", user.ChatID)
+		return nil, false
+	}
+	if user.PendingApproval {
+		writeError(w, r, http.StatusConflict, "api.error.user_pending", user.ChatID)
+		return nil, false
+	}
+
+	return user, true
+}
+
+// clockError отвечает на неудавшуюся отметку: 409 - состояние сессий не позволяет ее сделать,
+// 422 - неподходящее время или данные
+func (s *Server) clockError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	var clockErr *service.ClockError
+	var localized *i18n.Error
+	switch {
+	case errors.As(err, &clockErr) && clockErr.Reason == service.ClockNotAllowed:
+		writeServiceError(w, r, http.StatusConflict, err)
+	case errors.As(err, &localized):
+		writeServiceError(w, r, http.StatusUnprocessableEntity, err)
+	default:
+		s.internalError(w, r, err, message)
+	}
+}
+
+func (s *Server) internalError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	s.logger.WithError(err).Error(message)
+	writeError(w, r, http.StatusInternalServerError, "api.error.internal")
+}
+
+// pathYearMonth читает год и месяц из пути запроса
+func (s *Server) pathYearMonth(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	year, err := strconv.Atoi(r.PathValue("year"))
+	if err != nil || !s.policy.ValidYear(year) {
+		writeError(w, r, http.StatusBadRequest, "api.error.invalid_year_range", s.policy.MinYear, s.policy.MaxYear)
+		return 0, 0, false
+	}
+
+	month, err := strconv.Atoi(r.PathValue("month"))
+	if err != nil || month < 1 || month > 12 {
+		writeError(w, r, http.StatusBadRequest, "api.error.invalid_month")
+		return 0, 0, false
+	}
+
+	return year, month, true
+}
+
+// parseDateQuery читает дату из параметра запроса, если он задан
+func (s *Server) parseDateQuery(w http.ResponseWriter, r *http.Request, name string, date *time.Time) bool {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return true
+	}
+
+	parsed, err := time.ParseInLocation(dateLayout, value, s.clock.Location())
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "api.error.invalid_date_param", name)
+		return false
+	}
+
+	*date = parsed
+	return true
+}
+
+// decodeBody читает JSON из тела запроса; пустое тело допустимо
+func decodeBody(w http.ResponseWriter, r *http.Request, value interface{}) bool {
+	if r.ContentLength == 0 {
+		return true
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(value); err != nil {
+		writeError(w, r, http.StatusBadRequest, "api.error.invalid_body", err.Error())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,195 @@
+// Package api - HTTP JSON API для внешних систем (HR-портал и т.п.).
+// Доступ по токенам клиентов, которые администратор выпускает командой /addapitoken.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/service"
+	"work-schedule-bot/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+type tokenContextKey struct{}
+
+type localizerContextKey struct{}
+
+type Server struct {
+	userService            *service.UserService
+	workSessionService     *service.WorkSessionService
+	absenceService         *service.AbsenceService
+	workScheduleService    *service.WorkScheduleService
+	userMonthlyStatService *service.UserMonthlyStatService
+	tokenService           *service.APITokenService
+	policy                 models.WorkPolicy
+	clock                  clock.Clock
+
+	server *http.Server
+	logger *logrus.Logger
+}
+
+func NewServer(
+	userService *service.UserService,
+	workSessionService *service.WorkSessionService,
+	absenceService *service.AbsenceService,
+	workScheduleService *service.WorkScheduleService,
+	userMonthlyStatService *service.UserMonthlyStatService,
+	tokenService *service.APITokenService,
+	policy models.WorkPolicy,
+	clk clock.Clock,
+) *Server {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+
+	return &Server{
+		userService:            userService,
+		workSessionService:     workSessionService,
+		absenceService:         absenceService,
+		workScheduleService:    workScheduleService,
+		userMonthlyStatService: userMonthlyStatService,
+		tokenService:           tokenService,
+		policy:                 policy,
+		clock:                  clk,
+		logger:                 logger,
+	}
+}
+
+// Handler возвращает обработчик всех маршрутов API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/v1/users", s.listUsers)
+	mux.HandleFunc("GET /api/v1/users/{chatID}", s.getUser)
+	mux.HandleFunc("GET /api/v1/users/{chatID}/sessions", s.listSessions)
+	mux.HandleFunc("POST /api/v1/users/{chatID}/clock-in", s.clockIn)
+	mux.HandleFunc("POST /api/v1/users/{chatID}/clock-out", s.clockOut)
+	mux.HandleFunc("GET /api/v1/users/{chatID}/absences", s.listAbsences)
+	mux.HandleFunc("POST /api/v1/users/{chatID}/absences", s.createAbsence)
+	mux.HandleFunc("GET /api/v1/users/{chatID}/stats", s.listStats)
+	mux.HandleFunc("GET /api/v1/users/{chatID}/stats/{year}/{month}", s.getStat)
+	mux.HandleFunc("GET /api/v1/schedules", s.listSchedules)
+	mux.HandleFunc("GET /api/v1/schedules/{year}/{month}", s.getSchedule)
+
+	return s.authenticate(mux)
+}
+
+// ListenAndServe запускает HTTP-сервер API и блокируется до его остановки
+func (s *Server) ListenAndServe(addr string) error {
+	s.server = &http.Server{
+		Addr:    addr,
+		Handler: s.Handler(),
+	}
+
+	s.logger.WithField("addr", addr).Info("API server started")
+
+	err := s.server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown останавливает HTTP-сервер, дожидаясь текущих запросов
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Shutdown(ctx)
+}
+
+// authenticate пропускает только запросы с действующим токеном в заголовке Authorization: Bearer <токен>
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			writeError(w, r, http.StatusUnauthorized, "api.error.auth_required")
+			return
+		}
+
+		token, err := s.tokenService.Authenticate(strings.TrimSpace(value))
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to authenticate API token")
+			writeError(w, r, http.StatusInternalServerError, "api.error.auth_failed")
+			return
+		}
+		if token == nil {
+			writeError(w, r, http.StatusUnauthorized, "api.error.token_invalid")
+			return
+		}
+
+		s.logger.WithFields(logrus.Fields{
+			"client": token.Name,
+			"method": r.Method,
+			"path":   r.URL.Path,
+		}).Info("API request")
+
+		ctx := context.WithValue(r.Context(), tokenContextKey{}, token)
+		ctx = context.WithValue(ctx, localizerContextKey{}, s.tokenLocalizer(r, token))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// tokenLocalizer выбирает язык ответов: из заголовка Accept-Language, иначе язык администратора,
+// выпустившего токен, иначе язык по умолчанию
+func (s *Server) tokenLocalizer(r *http.Request, token *models.APIToken) *i18n.Localizer {
+	if lang, ok := i18n.ParseAcceptLanguage(r.Header.Get("Accept-Language")); ok {
+		return i18n.For(string(lang))
+	}
+
+	owner, err := s.userService.FindUser(token.CreatedBy)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to get API token owner")
+	}
+	if owner != nil {
+		return i18n.For(owner.Language)
+	}
+	return i18n.For("")
+}
+
+// requestLocalizer возвращает Localizer запроса. До проверки токена язык берется только из Accept-Language.
+func requestLocalizer(r *http.Request) *i18n.Localizer {
+	if tr, ok := r.Context().Value(localizerContextKey{}).(*i18n.Localizer); ok {
+		return tr
+	}
+	lang, _ := i18n.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	return i18n.For(string(lang))
+}
+
+// requestToken возвращает токен клиента, выполнившего запрос
+func requestToken(r *http.Request) *models.APIToken {
+	token, _ := r.Context().Value(tokenContextKey{}).(*models.APIToken)
+	return token
+}
+
+// requestActor возвращает автора изменений для журнала - токен запроса
+func requestActor(r *http.Request) models.Actor {
+	if token := requestToken(r); token != nil {
+		return models.TokenActor(token.ID)
+	}
+	return models.SystemActor
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeError отвечает ошибкой с сообщением из каталога на языке запроса
+func writeError(w http.ResponseWriter, r *http.Request, status int, key string, args ...interface{}) {
+	writeJSON(w, status, map[string]string{"error": requestLocalizer(r).T(key, args...)})
+}
+
+// writeServiceError отвечает ошибкой сервиса на языке запроса
+func writeServiceError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": requestLocalizer(r).Error(err)})
+}
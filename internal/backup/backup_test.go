@@ -0,0 +1,188 @@
+package backup
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"work-schedule-bot/internal/migrations"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestDatabase(t *testing.T, path string) *gorm.DB {
+	t.Helper()
+
+	db, err := repository.OpenDatabase(repository.DriverSQLite, path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database instance: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if _, err := migrations.NewMigrator(db).Up(); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+// seed создает данные, которые теряются при наивном дампе: скрытый в JSON хеш токена,
+// нулевые лимиты банка со значением по умолчанию 2400 и необязательные поля
+func seed(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	loc := time.FixedZone("MSK", 3*60*60)
+	date := time.Date(2026, time.March, 2, 0, 0, 0, 0, loc)
+	clockOut := date.Add(18 * time.Hour)
+
+	team := models.Team{Name: "Разработка", Department: models.Department{Name: "ИТ"}}
+	if err := db.Create(&team).Error; err != nil {
+		t.Fatalf("failed to create team: %v", err)
+	}
+	user := models.User{ChatID: 101, Username: "ivan", FirstName: "Иван", Role: models.RoleEmployee, TeamID: &team.ID}
+	token := models.APIToken{Name: "payroll", TokenHash: "hash", CreatedBy: 101}
+	for _, record := range []interface{}{&user, &token} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("failed to create %T: %v", record, err)
+		}
+	}
+	settings := models.TimeBankSettings{MaxMonthlyDeficitMinutes: 600}
+	if err := db.Create(&settings).Error; err != nil {
+		t.Fatalf("failed to create time bank settings: %v", err)
+	}
+	if err := db.Model(&settings).Update("max_monthly_surplus_minutes", 0).Error; err != nil {
+		t.Fatalf("failed to update time bank settings: %v", err)
+	}
+
+	session := models.WorkSession{
+		UserID:          user.ID,
+		Date:            date,
+		ClockInTime:     date.Add(9 * time.Hour),
+		ClockOutTime:    &clockOut,
+		RequiredMinutes: 480,
+		WorkedMinutes:   540,
+		Status:          models.StatusCompleted,
+		SessionType:     models.SessionTypeWork,
+	}
+	if err := db.Create(&session).Error; err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+}
+
+func checkRestored(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	var settings models.TimeBankSettings
+	if err := db.First(&settings).Error; err != nil {
+		t.Fatalf("settings were not restored: %v", err)
+	}
+	if settings.MaxMonthlySurplusMinutes != 0 || settings.MaxMonthlyDeficitMinutes != 600 {
+		t.Errorf("settings = %+v, want surplus 0 and deficit 600", settings)
+	}
+
+	var token models.APIToken
+	if err := db.First(&token).Error; err != nil || token.TokenHash != "hash" {
+		t.Errorf("token = %+v (%v), want the hash restored", token, err)
+	}
+
+	var session models.WorkSession
+	if err := db.Preload("User.Team.Department").First(&session).Error; err != nil {
+		t.Fatalf("session was not restored: %v", err)
+	}
+	if session.User.ChatID != 101 || session.User.Team == nil || session.User.Team.Department.Name != "ИТ" ||
+		session.WorkedMinutes != 540 || session.ClockOutTime == nil {
+		t.Errorf("session = %+v, want Ivan's completed session", session)
+	}
+
+	// Новые записи получают ID после восстановленных
+	user := models.User{ChatID: 102, Username: "petr", Role: models.RoleEmployee}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user after restore: %v", err)
+	}
+	if user.ID != 2 {
+		t.Errorf("new user ID = %d, want 2", user.ID)
+	}
+}
+
+func TestDumpRoundTrip(t *testing.T) {
+	source := openTestDatabase(t, filepath.Join(t.TempDir(), "source.db"))
+	seed(t, source)
+
+	dump, err := Export(source, time.Now())
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := dump.Write(&buf); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	read, err := ReadDump(&buf)
+	if err != nil {
+		t.Fatalf("ReadDump: %v", err)
+	}
+	if read.Rows() != 6 {
+		t.Errorf("%d rows, want 6", read.Rows())
+	}
+
+	target := openTestDatabase(t, filepath.Join(t.TempDir(), "target.db"))
+	if err := Load(target, read); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	checkRestored(t, target)
+
+	// Повторная загрузка в ту же базу запрещена
+	if err := Load(target, read); err == nil || !strings.Contains(err.Error(), "не пустая") {
+		t.Errorf("Load into a non-empty database: %v, want refusal", err)
+	}
+
+	read.Schema++
+	empty := openTestDatabase(t, filepath.Join(t.TempDir(), "empty.db"))
+	if err := Load(empty, read); err == nil || !strings.Contains(err.Error(), "версии") {
+		t.Errorf("Load with another schema version: %v, want refusal", err)
+	}
+}
+
+func TestRestoreFromSQLiteSnapshot(t *testing.T) {
+	source := openTestDatabase(t, filepath.Join(t.TempDir(), "source.db"))
+	seed(t, source)
+
+	dir := t.TempDir()
+	now := time.Date(2026, time.March, 20, 3, 0, 0, 0, time.UTC)
+	var paths []string
+	for i := 0; i < 4; i++ {
+		path, err := WriteRotated(source, dir, 2, now.Add(time.Duration(i)*24*time.Hour))
+		if err != nil {
+			t.Fatalf("WriteRotated: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name() != filepath.Base(paths[2]) || entries[1].Name() != "backup-20260323-030000.db" {
+		t.Errorf("backups = %v, want the two newest", entries)
+	}
+
+	dump, err := ReadFile(paths[3])
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	target := openTestDatabase(t, filepath.Join(t.TempDir(), "target.db"))
+	if err := Load(target, dump); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	checkRestored(t, target)
+}
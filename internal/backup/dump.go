@@ -0,0 +1,274 @@
+// Package backup - резервные копии базы бота: снимки для отправки и хранения
+// и восстановление из них в пустую базу.
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+	"work-schedule-bot/internal/migrations"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// dumpFormat - идентификатор формата логического дампа
+const dumpFormat = "work-schedule-bot/dump/v1"
+
+// loadBatchSize - сколько строк вставляется одним запросом при восстановлении
+const loadBatchSize = 200
+
+// tables - модели всех таблиц данных в порядке загрузки: таблицы, на которые ссылаются
+// внешние ключи, идут раньше ссылающихся
+var tables = []interface{}{
+	&models.Department{},
+	&models.Team{},
+	&models.User{},
+	&models.WorkSchedule{},
+	&models.UserMonthlyStat{},
+	&models.NonWorkingDay{},
+	&models.AbsencePeriod{},
+	&models.WorkSession{},
+	&models.TimeBankEntry{},
+	&models.TimeBankSettings{},
+	&models.DialogState{},
+	&models.APIToken{},
+	&models.AuditEntry{},
+}
+
+// Row - строка таблицы: значения колонок в JSON
+type Row map[string]json.RawMessage
+
+// Dump - логический дамп базы, не зависящий от драйвера
+type Dump struct {
+	Format    string           `json:"format"`
+	Schema    int              `json:"schema"` // версия схемы - последняя примененная миграция
+	CreatedAt time.Time        `json:"created_at"`
+	Tables    map[string][]Row `json:"tables"`
+}
+
+// Rows возвращает общее количество строк дампа
+func (d *Dump) Rows() int {
+	count := 0
+	for _, rows := range d.Tables {
+		count += len(rows)
+	}
+	return count
+}
+
+// Export читает все таблицы в одной транзакции, поэтому дамп согласован
+func Export(db *gorm.DB, now time.Time) (*Dump, error) {
+	version, err := migrations.NewMigrator(db).Version()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения версии схемы: %w", err)
+	}
+
+	dump := &Dump{
+		Format:    dumpFormat,
+		Schema:    version,
+		CreatedAt: now,
+		Tables:    make(map[string][]Row, len(tables)),
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range tables {
+			table, rows, err := exportTable(tx, model)
+			if err != nil {
+				return err
+			}
+			dump.Tables[table] = rows
+		}
+		return nil
+	}, exportTxOptions(db))
+	if err != nil {
+		return nil, err
+	}
+
+	return dump, nil
+}
+
+// exportTxOptions - транзакция только для чтения со снимком данных на ее начало.
+// SQLite и так читает из снимка, а уровни изоляции драйвер SQLite не поддерживает.
+func exportTxOptions(db *gorm.DB) *sql.TxOptions {
+	if db.Dialector.Name() == repository.DriverPostgres {
+		return &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	}
+	return nil
+}
+
+func exportTable(tx *gorm.DB, model interface{}) (string, []Row, error) {
+	modelSchema, err := parseSchema(tx, model)
+	if err != nil {
+		return "", nil, err
+	}
+
+	records := reflect.New(reflect.SliceOf(modelSchema.ModelType))
+	if err := tx.Order(clause.OrderByColumn{Column: clause.Column{Name: modelSchema.PrioritizedPrimaryField.DBName}}).
+		Find(records.Interface()).Error; err != nil {
+		return "", nil, fmt.Errorf("ошибка чтения таблицы %s: %w", modelSchema.Table, err)
+	}
+
+	ctx := context.Background()
+	records = records.Elem()
+	rows := make([]Row, 0, records.Len())
+	for i := 0; i < records.Len(); i++ {
+		record := records.Index(i)
+		row := make(Row, len(modelSchema.DBNames))
+		for _, name := range modelSchema.DBNames {
+			field := modelSchema.FieldsByDBName[name]
+			value, err := json.Marshal(field.ReflectValueOf(ctx, record).Interface())
+			if err != nil {
+				return "", nil, fmt.Errorf("%s.%s: %w", modelSchema.Table, name, err)
+			}
+			row[name] = value
+		}
+		rows = append(rows, row)
+	}
+
+	return modelSchema.Table, rows, nil
+}
+
+// Write записывает дамп в JSON
+func (d *Dump) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(d)
+}
+
+// ReadDump читает дамп и проверяет его формат
+func ReadDump(r io.Reader) (*Dump, error) {
+	var dump Dump
+	if err := json.NewDecoder(r).Decode(&dump); err != nil {
+		return nil, fmt.Errorf("некорректный дамп: %w", err)
+	}
+	if dump.Format != dumpFormat {
+		return nil, fmt.Errorf("неизвестный формат дампа %q, ожидается %q", dump.Format, dumpFormat)
+	}
+	return &dump, nil
+}
+
+// Load загружает дамп в пустую базу. Схема базы должна быть той же версии, что и в дампе.
+// Загрузка выполняется в одной транзакции: при любой ошибке база остается пустой.
+func Load(db *gorm.DB, dump *Dump) error {
+	version, err := migrations.NewMigrator(db).Version()
+	if err != nil {
+		return fmt.Errorf("ошибка чтения версии схемы: %w", err)
+	}
+	if dump.Schema != version {
+		return fmt.Errorf("дамп создан для схемы версии %d, а схема базы - версии %d", dump.Schema, version)
+	}
+
+	known := make(map[string]bool, len(tables))
+	for _, model := range tables {
+		modelSchema, err := parseSchema(db, model)
+		if err != nil {
+			return err
+		}
+		known[modelSchema.Table] = true
+	}
+	for table := range dump.Tables {
+		if !known[table] {
+			return fmt.Errorf("неизвестная таблица в дампе: %s", table)
+		}
+	}
+
+	if err := requireEmpty(db); err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range tables {
+			if err := loadTable(tx, model, dump); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// requireEmpty проверяет, что в таблицах данных нет строк
+func requireEmpty(db *gorm.DB) error {
+	for _, model := range tables {
+		var count int64
+		if err := db.Model(model).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			modelSchema, err := parseSchema(db, model)
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("база не пустая: в таблице %s %d строк", modelSchema.Table, count)
+		}
+	}
+	return nil
+}
+
+func loadTable(tx *gorm.DB, model interface{}, dump *Dump) error {
+	modelSchema, err := parseSchema(tx, model)
+	if err != nil {
+		return err
+	}
+
+	rows := dump.Tables[modelSchema.Table]
+	if len(rows) == 0 {
+		return nil
+	}
+
+	// Строки вставляются картами колонок: при вставке структур GORM заменил бы нулевые
+	// значения значениями колонок по умолчанию (например, лимит банка 0 стал бы 2400)
+	ctx := context.Background()
+	values := make([]map[string]interface{}, 0, len(rows))
+	for i, row := range rows {
+		record := reflect.New(modelSchema.ModelType).Elem()
+		value := make(map[string]interface{}, len(row))
+		for name, raw := range row {
+			field, ok := modelSchema.FieldsByDBName[name]
+			if !ok {
+				return fmt.Errorf("%s, строка %d: неизвестная колонка %s", modelSchema.Table, i+1, name)
+			}
+			target := field.ReflectValueOf(ctx, record)
+			if err := json.Unmarshal(raw, target.Addr().Interface()); err != nil {
+				return fmt.Errorf("%s, строка %d, колонка %s: %w", modelSchema.Table, i+1, name, err)
+			}
+			value[name] = target.Interface()
+		}
+		values = append(values, value)
+	}
+
+	if err := tx.Table(modelSchema.Table).CreateInBatches(values, loadBatchSize).Error; err != nil {
+		return fmt.Errorf("ошибка загрузки таблицы %s: %w", modelSchema.Table, err)
+	}
+
+	return resetSequence(tx, modelSchema)
+}
+
+// resetSequence продолжает автоинкремент PostgreSQL после загруженных ID.
+// SQLite берет следующий ID из самой таблицы.
+func resetSequence(tx *gorm.DB, modelSchema *schema.Schema) error {
+	primary := modelSchema.PrioritizedPrimaryField
+	if tx.Dialector.Name() != repository.DriverPostgres || primary == nil || !primary.AutoIncrement {
+		return nil
+	}
+
+	query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', '%s'), MAX(%s)) FROM %s",
+		modelSchema.Table, primary.DBName, primary.DBName, modelSchema.Table)
+	if err := tx.Exec(query).Error; err != nil {
+		return fmt.Errorf("ошибка обновления последовательности %s: %w", modelSchema.Table, err)
+	}
+	return nil
+}
+
+func parseSchema(db *gorm.DB, model interface{}) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
@@ -0,0 +1,153 @@
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"work-schedule-bot/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// sqliteHeader - начало любого файла базы SQLite
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+// filePrefix - начало имен файлов резервных копий
+const filePrefix = "backup-"
+
+// Extension возвращает расширение файла снимка: копия файла для SQLite, JSON-дамп для PostgreSQL
+func Extension(db *gorm.DB) string {
+	if db.Dialector.Name() == repository.DriverSQLite {
+		return ".db"
+	}
+	return ".json"
+}
+
+// FileName возвращает имя файла снимка, созданного в момент now
+func FileName(db *gorm.DB, now time.Time) string {
+	return filePrefix + now.Format("20060102-150405") + Extension(db)
+}
+
+// Snapshot записывает в path согласованный снимок базы. SQLite копируется целиком
+// командой VACUUM INTO, не останавливая запись в базу; для PostgreSQL пишется логический дамп.
+func Snapshot(db *gorm.DB, path string, now time.Time) error {
+	if db.Dialector.Name() == repository.DriverSQLite {
+		// VACUUM INTO не перезаписывает существующий файл
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := db.Exec("VACUUM INTO ?", path).Error; err != nil {
+			return fmt.Errorf("ошибка копирования базы SQLite: %w", err)
+		}
+		return nil
+	}
+
+	dump, err := Export(db, now)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := dump.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteRotated создает снимок в каталоге dir и удаляет старые, оставляя keep последних.
+// Возвращает путь созданного файла.
+func WriteRotated(db *gorm.DB, dir string, keep int, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	// Снимок пишется во временный файл, чтобы в каталоге не оказалось недописанной копии
+	path := filepath.Join(dir, FileName(db, now))
+	partial := path + ".partial"
+	if err := Snapshot(db, partial, now); err != nil {
+		os.Remove(partial)
+		return "", err
+	}
+	if err := os.Rename(partial, path); err != nil {
+		return "", err
+	}
+
+	return path, rotate(dir, keep)
+}
+
+// rotate удаляет самые старые резервные копии в каталоге, оставляя keep последних
+func rotate(dir string, keep int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, filePrefix) &&
+			(strings.HasSuffix(name, ".db") || strings.HasSuffix(name, ".json")) {
+			names = append(names, name)
+		}
+	}
+
+	// Время в имени файла сортируется как строка
+	sort.Strings(names)
+	for len(names) > keep {
+		if err := os.Remove(filepath.Join(dir, names[0])); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+// ReadFile читает резервную копию: JSON-дамп или файл базы SQLite, созданный Snapshot
+func ReadFile(path string) (*Dump, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(data, sqliteHeader) {
+		return ReadDump(bytes.NewReader(data))
+	}
+
+	snapshot, err := repository.OpenDatabase(repository.DriverSQLite, "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия копии SQLite: %w", err)
+	}
+	sqlDB, err := snapshot.DB()
+	if err != nil {
+		return nil, err
+	}
+	defer sqlDB.Close()
+
+	if err := requireSchema(snapshot); err != nil {
+		return nil, err
+	}
+	return Export(snapshot, time.Now())
+}
+
+// requireSchema проверяет, что в копии есть все таблицы данных
+func requireSchema(db *gorm.DB) error {
+	for _, model := range tables {
+		if db.Migrator().HasTable(model) {
+			continue
+		}
+		modelSchema, err := parseSchema(db, model)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("в копии нет таблицы %s", modelSchema.Table)
+	}
+	return nil
+}
@@ -1,72 +1,433 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
-	"strconv"
+	"strings"
 	"sync"
+	"time"
+	"work-schedule-bot/internal/models"
 
+	"github.com/BurntSushi/toml"
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 )
 
+// BotConfig - конфигурация бота. Значения берутся по умолчанию, затем из файла конфигурации
+// (TOML, см. config.example.toml), затем из переменных окружения, которые важнее файла.
 type BotConfig struct {
-	TelegramToken   string
-	BaseAdminChatID int64
-	DatabaseURL     string
+	// Часовой пояс компании (например Europe/Moscow). По нему считаются
+	// календарные дни и месяцы независимо от пояса сервера. По умолчанию - пояс сервера.
+	Timezone string         `toml:"timezone" env:"TIMEZONE"`
+	Location *time.Location `toml:"-"`
+
+	Telegram     TelegramConfig     `toml:"telegram"`
+	Database     DatabaseConfig     `toml:"database"`
+	API          APIConfig          `toml:"api"`
+	Calendar     CalendarConfig     `toml:"calendar"`
+	Norms        NormsConfig        `toml:"norms"`
+	Policy       PolicyConfig       `toml:"policy"`
+	Jobs         JobsConfig         `toml:"jobs"`
+	Backup       BackupConfig       `toml:"backup"`
+	Registration RegistrationConfig `toml:"registration"`
 }
 
-var instance *BotConfig
-var once sync.Once
+// TelegramConfig - подключение к Telegram
+type TelegramConfig struct {
+	Token string `toml:"token" env:"TELEGRAM_BOT_TOKEN"`
 
-func GetBotConfig() *BotConfig {
-	once.Do(func() {
-		instance = &BotConfig{}
+	// Chat ID базового администратора, которого нельзя разжаловать. 0 - не задан.
+	AdminChatID int64 `toml:"admin_chat_id" env:"BASE_ADMIN_CHAT_ID"`
+
+	// Способ получения обновлений: polling (по умолчанию) или webhook
+	UpdateMode string `toml:"update_mode" env:"UPDATE_MODE"`
+
+	Webhook WebhookConfig `toml:"webhook"`
+}
+
+// WebhookConfig - настройки вебхука (update_mode = "webhook").
+// Path по умолчанию берется из пути URL.
+// Без CertFile/KeyFile сервер слушает HTTP, TLS завершается на обратном прокси.
+type WebhookConfig struct {
+	URL        string `toml:"url" env:"WEBHOOK_URL"`
+	ListenAddr string `toml:"listen_addr" env:"WEBHOOK_LISTEN_ADDR"`
+	Path       string `toml:"path" env:"WEBHOOK_PATH"`
+	Secret     string `toml:"secret" env:"WEBHOOK_SECRET"`
+	CertFile   string `toml:"cert_file" env:"WEBHOOK_CERT_FILE"`
+	KeyFile    string `toml:"key_file" env:"WEBHOOK_KEY_FILE"`
+}
+
+// DatabaseConfig - подключение к базе данных
+type DatabaseConfig struct {
+	// Драйвер: sqlite или postgres. Если не задан, определяется по URL
+	// (postgres:// - PostgreSQL, иначе SQLite)
+	Driver string `toml:"driver" env:"DATABASE_DRIVER"`
+	URL    string `toml:"url" env:"DATABASE_URL"`
+}
+
+// APIConfig - HTTP API для внешних систем
+type APIConfig struct {
+	// Адрес, например :8080. Пустой - API выключен.
+	ListenAddr string `toml:"listen_addr" env:"API_LISTEN_ADDR"`
+}
+
+// CalendarConfig - производственный календарь
+type CalendarConfig struct {
+	// JSON-файлы с выходными днями, загружаются при запуске
+	Files []string `toml:"files" env:"CALENDAR_FILES"`
+
+	// Годы, для которых при запуске создаются графики по выходным дням
+	ScheduleYears []int `toml:"schedule_years" env:"SCHEDULE_YEARS"`
+}
+
+// NormsConfig - нормы рабочего времени в минутах
+type NormsConfig struct {
+	// Норма рабочего дня для графиков по календарю, дней отсутствия и прогноза в статистике
+	WorkDayMinutes int `toml:"work_day_minutes" env:"WORK_DAY_MINUTES"`
+
+	// Норма дня, если график месяца не задан
+	DefaultDayMinutes int `toml:"default_day_minutes" env:"DEFAULT_DAY_MINUTES"`
+
+	// Норма смены, если ее не удалось получить из статистики
+	FallbackRequiredMinutes int `toml:"fallback_required_minutes" env:"FALLBACK_REQUIRED_MINUTES"`
+
+	// Норма короче этой показывается при начале работы как норма сокращенного дня
+	ShortDayMinutes int `toml:"short_day_minutes" env:"SHORT_DAY_MINUTES"`
+}
+
+// PolicyConfig - ограничения и правила учета
+type PolicyConfig struct {
+	// Допустимый диапазон годов в командах и API
+	MinYear int `toml:"min_year" env:"MIN_YEAR"`
+	MaxYear int `toml:"max_year" env:"MAX_YEAR"`
+
+	// Раньше этого года нельзя отметить начало работы
+	MinClockInYear int `toml:"min_clock_in_year" env:"MIN_CLOCK_IN_YEAR"`
+
+	// Доля отсутствующих сотрудников (в процентах), при превышении которой день помечается в календаре
+	TeamAbsenceThresholdPercent int `toml:"team_absence_threshold_percent" env:"TEAM_ABSENCE_THRESHOLD_PERCENT"`
+
+	// Режимы учета отсутствий по типам (vacation, sick_leave, day_off, unpaid_leave, truancy).
+	// В переменной окружения: "unpaid_leave:reduce_plan,truancy:none"
+	AbsenceCreditModes map[string]string `toml:"absence_credit_modes" env:"ABSENCE_CREDIT_MODES"`
+}
+
+// JobsConfig - расписание фоновых задач
+type JobsConfig struct {
+	// Время (в часовом поясе компании) ежедневной сверки статистики, закрытия месяца
+	// в банке времени и удаления брошенных диалогов
+	DailyAt TimeOfDay `toml:"daily_at" env:"DAILY_JOBS_AT"`
+}
 
-		if err := godotenv.Load(); err != nil {
-			logrus.Fatalf("error loading env variables: %s", err.Error())
+// BackupConfig - ежедневные резервные копии базы
+type BackupConfig struct {
+	// Каталог для копий. Пустой - копии по расписанию не создаются.
+	Dir string `toml:"dir" env:"BACKUP_DIR"`
+
+	// Сколько последних копий хранить в каталоге
+	Keep int `toml:"keep" env:"BACKUP_KEEP"`
+}
+
+// RegistrationConfig - регистрация новых сотрудников
+type RegistrationConfig struct {
+	// Новый профиль ждет одобрения HR или администратора и до одобрения не может вести учет
+	RequireApproval bool `toml:"require_approval" env:"REGISTRATION_REQUIRE_APPROVAL"`
+}
+
+// Способы получения обновлений
+const (
+	UpdateModePolling = "polling"
+	UpdateModeWebhook = "webhook"
+)
+
+// defaultConfigFile - файл конфигурации, который читается, если CONFIG_FILE не задан и файл существует
+const defaultConfigFile = "config.toml"
+
+// Default возвращает конфигурацию по умолчанию
+func Default() *BotConfig {
+	policy := models.DefaultWorkPolicy()
+
+	return &BotConfig{
+		Timezone: "Local",
+		Location: time.Local,
+		Telegram: TelegramConfig{
+			UpdateMode: UpdateModePolling,
+			Webhook:    WebhookConfig{ListenAddr: ":8443"},
+		},
+		Calendar: CalendarConfig{
+			Files:         []string{"jsons/weekends_2026.json"},
+			ScheduleYears: []int{2026},
+		},
+		Norms: NormsConfig{
+			WorkDayMinutes:          policy.WorkDayMinutes,
+			DefaultDayMinutes:       policy.DefaultDayMinutes,
+			FallbackRequiredMinutes: policy.FallbackRequiredMinutes,
+			ShortDayMinutes:         policy.ShortDayMinutes,
+		},
+		Policy: PolicyConfig{
+			MinYear:                     policy.MinYear,
+			MaxYear:                     policy.MaxYear,
+			MinClockInYear:              policy.MinClockInYear,
+			TeamAbsenceThresholdPercent: 30,
+			AbsenceCreditModes:          map[string]string{},
+		},
+		Jobs:         JobsConfig{DailyAt: TimeOfDay{Hour: 3}},
+		Backup:       BackupConfig{Keep: 7},
+		Registration: RegistrationConfig{RequireApproval: true},
+	}
+}
+
+// WorkPolicy возвращает нормы и ограничения учета для моделей и сервисов
+func (c *BotConfig) WorkPolicy() models.WorkPolicy {
+	return models.WorkPolicy{
+		WorkDayMinutes:          c.Norms.WorkDayMinutes,
+		DefaultDayMinutes:       c.Norms.DefaultDayMinutes,
+		FallbackRequiredMinutes: c.Norms.FallbackRequiredMinutes,
+		ShortDayMinutes:         c.Norms.ShortDayMinutes,
+		MinYear:                 c.Policy.MinYear,
+		MaxYear:                 c.Policy.MaxYear,
+		MinClockInYear:          c.Policy.MinClockInYear,
+		AbsenceCreditModes:      c.Policy.AbsenceCreditModes,
+	}
+}
+
+// ValidationError перечисляет все ошибки конфигурации сразу
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "некорректная конфигурация:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Load читает конфигурацию из файла path (пустой - без файла) и переменных окружения lookupEnv.
+// Все ошибки разбора и проверки возвращаются одной *ValidationError.
+func Load(path string, lookupEnv func(string) (string, bool)) (*BotConfig, error) {
+	cfg := Default()
+	var problems []string
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось прочитать файл конфигурации: %w", err)
+		}
+		metadata, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
 		}
+		for _, key := range metadata.Undecoded() {
+			problems = append(problems, fmt.Sprintf("%s: неизвестный параметр", key))
+		}
+	}
+
+	applyEnv(cfg, lookupEnv, &problems)
+
+	cfg.normalize()
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	return cfg, nil
+}
 
-		instance.TelegramToken = getEnv("TELEGRAM_BOT_TOKEN", "")
-		if instance.TelegramToken == "" {
-			logrus.Fatal("could not get bot token")
+// normalize приводит значения к каноническому виду и заполняет производные поля
+func (c *BotConfig) normalize() {
+	c.Telegram.UpdateMode = strings.ToLower(strings.TrimSpace(c.Telegram.UpdateMode))
+	if c.Telegram.Webhook.Path == "" && c.Telegram.Webhook.URL != "" {
+		c.Telegram.Webhook.Path = webhookPathFromURL(c.Telegram.Webhook.URL)
+	}
+	if c.Policy.AbsenceCreditModes == nil {
+		c.Policy.AbsenceCreditModes = map[string]string{}
+	}
+}
+
+// validate возвращает все найденные ошибки конфигурации
+func (c *BotConfig) validate() []string {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.Telegram.Token == "" {
+		add("telegram.token (TELEGRAM_BOT_TOKEN): не задан токен бота")
+	}
+	switch c.Telegram.UpdateMode {
+	case UpdateModePolling:
+	case UpdateModeWebhook:
+		if c.Telegram.Webhook.URL == "" {
+			add("telegram.webhook.url (WEBHOOK_URL): обязателен при update_mode = webhook")
+		} else if parsed, err := url.Parse(c.Telegram.Webhook.URL); err != nil || parsed.Scheme != "https" {
+			add("telegram.webhook.url (WEBHOOK_URL): ожидается адрес https://, получено %q", c.Telegram.Webhook.URL)
+		} else if !strings.HasPrefix(c.Telegram.Webhook.Path, "/") {
+			add("telegram.webhook.path (WEBHOOK_PATH): путь должен начинаться с /, получено %q", c.Telegram.Webhook.Path)
+		}
+		if c.Telegram.Webhook.Secret == "" {
+			add("telegram.webhook.secret (WEBHOOK_SECRET): обязателен при update_mode = webhook")
+		}
+		if (c.Telegram.Webhook.CertFile == "") != (c.Telegram.Webhook.KeyFile == "") {
+			add("telegram.webhook: для TLS нужны и cert_file, и key_file")
+		}
+	default:
+		add("telegram.update_mode (UPDATE_MODE): ожидается polling или webhook, получено %q", c.Telegram.UpdateMode)
+	}
+
+	problems = append(problems, c.Database.validate()...)
+
+	if c.Timezone == "" {
+		c.Timezone = "Local"
+	}
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		add("timezone (TIMEZONE): неизвестный часовой пояс %q", c.Timezone)
+	} else {
+		c.Location = location
+	}
+
+	for _, file := range c.Calendar.Files {
+		if _, err := os.Stat(file); err != nil {
+			add("calendar.files (CALENDAR_FILES): файл %s недоступен: %v", file, errors.Unwrap(err))
+		}
+	}
+	for _, year := range c.Calendar.ScheduleYears {
+		if year < c.Policy.MinYear || year > c.Policy.MaxYear {
+			add("calendar.schedule_years (SCHEDULE_YEARS): год %d вне диапазона %d-%d", year, c.Policy.MinYear, c.Policy.MaxYear)
+		}
+	}
+
+	minutes := []struct {
+		name  string
+		value int
+	}{
+		{"norms.work_day_minutes (WORK_DAY_MINUTES)", c.Norms.WorkDayMinutes},
+		{"norms.default_day_minutes (DEFAULT_DAY_MINUTES)", c.Norms.DefaultDayMinutes},
+		{"norms.fallback_required_minutes (FALLBACK_REQUIRED_MINUTES)", c.Norms.FallbackRequiredMinutes},
+		{"norms.short_day_minutes (SHORT_DAY_MINUTES)", c.Norms.ShortDayMinutes},
+	}
+	for _, m := range minutes {
+		if m.value <= 0 || m.value > 24*60 {
+			add("%s: ожидается от 1 до 1440 минут, получено %d", m.name, m.value)
+		}
+	}
+
+	if c.Policy.MinYear > c.Policy.MaxYear {
+		add("policy.min_year (MIN_YEAR): %d больше max_year %d", c.Policy.MinYear, c.Policy.MaxYear)
+	}
+	if c.Policy.MinClockInYear < c.Policy.MinYear || c.Policy.MinClockInYear > c.Policy.MaxYear {
+		add("policy.min_clock_in_year (MIN_CLOCK_IN_YEAR): год %d вне диапазона %d-%d", c.Policy.MinClockInYear, c.Policy.MinYear, c.Policy.MaxYear)
+	}
+	if c.Policy.TeamAbsenceThresholdPercent <= 0 || c.Policy.TeamAbsenceThresholdPercent > 100 {
+		add("policy.team_absence_threshold_percent (TEAM_ABSENCE_THRESHOLD_PERCENT): ожидается от 1 до 100, получено %d", c.Policy.TeamAbsenceThresholdPercent)
+	}
+	for absenceType, mode := range c.Policy.AbsenceCreditModes {
+		if !models.IsValidAbsenceType(absenceType) {
+			add("policy.absence_credit_modes (ABSENCE_CREDIT_MODES): неизвестный тип отсутствия %q", absenceType)
 		}
+		if !models.IsValidAbsenceCreditMode(mode) {
+			add("policy.absence_credit_modes (ABSENCE_CREDIT_MODES): неизвестный режим учета %q", mode)
+		}
+	}
+
+	if c.Backup.Keep < 1 {
+		add("backup.keep (BACKUP_KEEP): ожидается не меньше 1, получено %d", c.Backup.Keep)
+	}
+
+	return problems
+}
+
+// validate возвращает ошибки настроек подключения к базе данных
+func (c DatabaseConfig) validate() []string {
+	var problems []string
+	if c.URL == "" {
+		problems = append(problems, "database.url (DATABASE_URL): не задан адрес базы данных")
+	}
+	switch c.Driver {
+	case "", "sqlite", "postgres":
+	default:
+		problems = append(problems, fmt.Sprintf("database.driver (DATABASE_DRIVER): ожидается sqlite или postgres, получено %q", c.Driver))
+	}
+	return problems
+}
 
-		instance.BaseAdminChatID = getEnvAsInt("BASE_ADMIN_CHAT_ID", -2)
-		if instance.BaseAdminChatID == -2 {
-			logrus.Fatal("could not get admin chat id")
+// LoadDatabase читает из файла path и переменных окружения только раздел database.
+// Его хватает подкомандам обслуживания базы, которым не нужны токен бота и календари.
+func LoadDatabase(path string, lookupEnv func(string) (string, bool)) (*DatabaseConfig, error) {
+	var file struct {
+		Database DatabaseConfig `toml:"database"`
+	}
+	var problems []string
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось прочитать файл конфигурации: %w", err)
+		}
+		if _, err := toml.Decode(string(data), &file); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
 		}
+	}
 
-		instance.DatabaseURL = getEnv("DATABASE_URL", "")
-		if instance.TelegramToken == "" {
-			logrus.Fatal("could not get db url")
+	applyEnv(&file.Database, lookupEnv, &problems)
+	problems = append(problems, file.Database.validate()...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	return &file.Database, nil
+}
+
+var instance *BotConfig
+var once sync.Once
+
+// GetBotConfig загружает конфигурацию при первом вызове. Файл .env необязателен: в контейнерах
+// переменные окружения обычно заданы напрямую. Файл конфигурации задается CONFIG_FILE,
+// по умолчанию читается config.toml, если он есть.
+func GetBotConfig() *BotConfig {
+	once.Do(func() {
+		cfg, err := Load(configFile(), os.LookupEnv)
+		if err != nil {
+			logrus.Fatal(err)
 		}
+		instance = cfg
 	})
 
 	return instance
 }
 
-func getEnv(key string, defaultVal string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+// GetDatabaseConfig загружает только раздел database из тех же источников, что и GetBotConfig
+func GetDatabaseConfig() *DatabaseConfig {
+	cfg, err := LoadDatabase(configFile(), os.LookupEnv)
+	if err != nil {
+		logrus.Fatal(err)
 	}
-
-	return defaultVal
+	return cfg
 }
 
-func getEnvAsBool(name string, defaultVal bool) bool {
-	valStr := getEnv(name, "")
-	if val, err := strconv.ParseBool(valStr); err == nil {
-		return val
+// configFile загружает .env и возвращает путь к файлу конфигурации (пустой - файла нет)
+func configFile() string {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logrus.Warnf("failed to load .env: %s", err.Error())
 	}
 
-	return defaultVal
+	path, ok := os.LookupEnv("CONFIG_FILE")
+	if !ok {
+		if _, err := os.Stat(defaultConfigFile); err == nil {
+			path = defaultConfigFile
+		}
+	}
+	if path != "" {
+		logrus.Infof("Reading config file %s", path)
+	}
+	return path
 }
 
-func getEnvAsInt(name string, defaultVal int64) int64 {
-	valStr := getEnv(name, "")
-	if val, err := strconv.Atoi(valStr); err == nil {
-		return int64(val)
+// webhookPathFromURL возвращает путь из публичного адреса вебхука
+func webhookPathFromURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Path == "" {
+		return "/"
 	}
-
-	return defaultVal
+	return parsed.Path
 }
@@ -0,0 +1,263 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"work-schedule-bot/internal/models"
+)
+
+// env возвращает функцию поиска переменных окружения по карте
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := vars[key]
+		return value, ok
+	}
+}
+
+// writeConfig записывает файл конфигурации во временный каталог
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+// withCalendar переходит в корень репозитория, чтобы пути к календарю по умолчанию существовали
+func withCalendar(t *testing.T) {
+	t.Helper()
+	t.Chdir("../..")
+}
+
+func TestLoadFileWithEnvOverrides(t *testing.T) {
+	withCalendar(t)
+	path := writeConfig(t, `
+timezone = "Europe/Moscow"
+
+[telegram]
+token = "file-token" # комментарий
+admin_chat_id = 42
+
+[database]
+url = "file.db"
+
+[calendar]
+files = [
+  "jsons/weekends_2026.json", # многострочный массив
+]
+schedule_years = [2026]
+
+[norms]
+work_day_minutes = 480
+
+[policy]
+absence_credit_modes = { truancy = "none" }
+
+[jobs]
+daily_at = "04:30"
+`)
+
+	cfg, err := Load(path, env(map[string]string{
+		"TELEGRAM_BOT_TOKEN":   "env-token",
+		"DATABASE_URL":         "postgres://localhost/bot",
+		"ABSENCE_CREDIT_MODES": "unpaid_leave:reduce_plan, truancy:none",
+	}))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Telegram.Token != "env-token" || cfg.Telegram.AdminChatID != 42 {
+		t.Errorf("telegram = %+v, want env token and admin 42 from the file", cfg.Telegram)
+	}
+	if cfg.Database.URL != "postgres://localhost/bot" {
+		t.Errorf("database url = %q, want the env value", cfg.Database.URL)
+	}
+	if cfg.Location.String() != "Europe/Moscow" {
+		t.Errorf("location = %s, want Europe/Moscow", cfg.Location)
+	}
+	if cfg.Norms.WorkDayMinutes != 480 || cfg.Norms.DefaultDayMinutes != 480 {
+		t.Errorf("norms = %+v, want work day 480 from the file and defaults for the rest", cfg.Norms)
+	}
+	if want := map[string]string{"unpaid_leave": "reduce_plan", "truancy": "none"}; !reflect.DeepEqual(cfg.Policy.AbsenceCreditModes, want) {
+		t.Errorf("absence credit modes = %v, want %v", cfg.Policy.AbsenceCreditModes, want)
+	}
+	if cfg.Jobs.DailyAt != (TimeOfDay{Hour: 4, Minute: 30}) {
+		t.Errorf("daily at = %s, want 04:30", cfg.Jobs.DailyAt)
+	}
+	if cfg.Telegram.UpdateMode != UpdateModePolling || cfg.Policy.TeamAbsenceThresholdPercent != 30 {
+		t.Errorf("defaults = %q, %d; want polling and 30%%", cfg.Telegram.UpdateMode, cfg.Policy.TeamAbsenceThresholdPercent)
+	}
+}
+
+func TestLoadWithoutFile(t *testing.T) {
+	withCalendar(t)
+
+	cfg, err := Load("", env(map[string]string{
+		"TELEGRAM_BOT_TOKEN":            "token",
+		"DATABASE_URL":                  "bot.db",
+		"UPDATE_MODE":                   "WEBHOOK",
+		"WEBHOOK_URL":                   "https://bot.example.com/hook",
+		"WEBHOOK_SECRET":                "secret",
+		"REGISTRATION_REQUIRE_APPROVAL": "false",
+	}))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Telegram.UpdateMode != UpdateModeWebhook || cfg.Telegram.Webhook.Path != "/hook" || cfg.Telegram.Webhook.ListenAddr != ":8443" {
+		t.Errorf("telegram = %+v, want webhook on /hook at :8443", cfg.Telegram)
+	}
+	if cfg.Registration.RequireApproval {
+		t.Error("registration requires approval, want it disabled by the env value")
+	}
+}
+
+func TestValidationReportsAllProblems(t *testing.T) {
+	path := writeConfig(t, `
+timezone = "Mars/Olympus"
+
+[telegram]
+update_mode = "webhook"
+
+[calendar]
+files = ["missing.json"]
+
+[norms]
+work_day_minutes = 0
+
+[policy]
+team_absence_threshold_percent = 150
+absence_credit_modes = { holiday = "credit" }
+unknown_option = true
+
+[backup]
+keep = 0
+`)
+
+	_, err := Load(path, env(map[string]string{"SCHEDULE_YEARS": "2026,abc"}))
+
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("Load() error = %v, want *ValidationError", err)
+	}
+
+	for _, want := range []string{
+		"policy.unknown_option",
+		"SCHEDULE_YEARS",
+		"telegram.token",
+		"telegram.webhook.url",
+		"telegram.webhook.secret",
+		"database.url",
+		"timezone",
+		"missing.json",
+		"norms.work_day_minutes",
+		"team_absence_threshold_percent",
+		`"holiday"`,
+		"backup.keep",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %s:\n%v", want, err)
+		}
+	}
+	if len(validation.Problems) != 12 {
+		t.Errorf("%d problems, want 12:\n%v", len(validation.Problems), err)
+	}
+}
+
+func TestExampleConfigIsValid(t *testing.T) {
+	withCalendar(t)
+
+	cfg, err := Load("config.example.toml", env(nil))
+	if err != nil {
+		t.Fatalf("config.example.toml: %v", err)
+	}
+	// Пример перечисляет режимы учета явно, поэтому сравниваются итоговые режимы, а не сама таблица
+	policy, defaults := cfg.WorkPolicy(), Default().WorkPolicy()
+	for _, absenceType := range []string{
+		models.AbsenceTypeVacation, models.AbsenceTypeSickLeave, models.AbsenceTypeDayOff,
+		models.AbsenceTypeUnpaidLeave, models.AbsenceTypeTruancy,
+	} {
+		if got, want := policy.AbsenceCreditMode(absenceType), defaults.AbsenceCreditMode(absenceType); got != want {
+			t.Errorf("example credit mode for %s = %q, want the default %q", absenceType, got, want)
+		}
+	}
+	policy.AbsenceCreditModes, defaults.AbsenceCreditModes = nil, nil
+	if !reflect.DeepEqual(policy, defaults) {
+		t.Errorf("example policy = %+v, want the defaults %+v", policy, defaults)
+	}
+}
+
+func TestLoadDatabaseIgnoresOtherSections(t *testing.T) {
+	// Ни токена, ни существующих файлов календаря: для подкоманд обслуживания базы это не ошибка
+	path := writeConfig(t, `
+[calendar]
+files = ["missing.json"]
+
+[database]
+url = "from-file.db"
+`)
+
+	cfg, err := LoadDatabase(path, env(map[string]string{"DATABASE_DRIVER": "sqlite", "SCHEDULE_YEARS": "abc"}))
+	if err != nil {
+		t.Fatalf("LoadDatabase() error = %v", err)
+	}
+	if cfg.URL != "from-file.db" || cfg.Driver != "sqlite" {
+		t.Errorf("database = %+v, want url from the file and driver from env", cfg)
+	}
+
+	_, err = LoadDatabase("", env(nil))
+	if err == nil || !strings.Contains(err.Error(), "database.url") {
+		t.Errorf("LoadDatabase() without url error = %v, want database.url problem", err)
+	}
+}
+
+func TestLoadInvalidFile(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"missing value", "key =", "line 1"},
+		{"unclosed string", `key = "value`, "line 1"},
+		{"duplicate key", "[norms]\nwork_day_minutes = 1\nwork_day_minutes = 2", "work_day_minutes"},
+		{"trailing garbage", "key = 1 2", "line 1"},
+		{"wrong type", "[norms]\nwork_day_minutes = \"8h\"", "work_day_minutes"},
+		{"invalid time of day", "[jobs]\ndaily_at = \"25:00\"", "25:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.data)
+			_, err := Load(path, env(nil))
+			if err == nil || !strings.Contains(err.Error(), path) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load(%q) error = %v, want the file path and %q", tt.data, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeOfDayNext(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("failed to load Europe/Moscow: %v", err)
+	}
+	at := TimeOfDay{Hour: 3}
+
+	// 23:00 UTC - уже 02:00 следующего дня в Москве
+	now := time.Date(2026, time.March, 1, 23, 0, 0, 0, time.UTC)
+	if got, want := at.Next(now, moscow), time.Date(2026, time.March, 2, 3, 0, 0, 0, moscow); !got.Equal(want) {
+		t.Errorf("Next(%s) = %s, want %s", now, got, want)
+	}
+
+	now = time.Date(2026, time.March, 2, 3, 0, 0, 0, moscow)
+	if got, want := at.Next(now, moscow), time.Date(2026, time.March, 3, 3, 0, 0, 0, moscow); !got.Equal(want) {
+		t.Errorf("Next(%s) = %s, want the next day %s", now, got, want)
+	}
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Поля конфигурации описываются тегами:
+//   toml:"name" - ключ в файле (вложенные структуры - таблицы), файл разбирает github.com/BurntSushi/toml,
+//   env:"NAME"  - переменная окружения, которая переопределяет значение из файла.
+// В переменных окружения поддерживаются string, int, int64, bool, []string, []int, map[string]string
+// и типы, реализующие encoding.TextUnmarshaler.
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// isLeaf сообщает, что поле - значение, а не таблица
+func isLeaf(t reflect.Type) bool {
+	return t.Kind() != reflect.Struct || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// applyEnv переопределяет значения переменными окружения
+func applyEnv(cfg interface{}, lookupEnv func(string) (string, bool), problems *[]string) {
+	walk(reflect.ValueOf(cfg).Elem(), "", func(field reflect.Value, _, env string) {
+		if env == "" {
+			return
+		}
+		raw, ok := lookupEnv(env)
+		if !ok {
+			return
+		}
+		if err := setFromString(field, raw); err != nil {
+			*problems = append(*problems, fmt.Sprintf("%s: %v", env, err))
+		}
+	})
+}
+
+// walk обходит поля с тегом toml
+func walk(v reflect.Value, prefix string, visit func(field reflect.Value, key, env string)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("toml")
+		if name == "" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		field := v.Field(i)
+		if isLeaf(field.Type()) {
+			visit(field, key, t.Field(i).Tag.Get("env"))
+		} else {
+			walk(field, key, visit)
+		}
+	}
+}
+
+// setFromString записывает в поле значение переменной окружения.
+// Списки задаются через запятую, словари - парами "key1:value1,key2:value2".
+func setFromString(field reflect.Value, raw string) error {
+	if field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			return fmt.Errorf("ожидается целое число, получено %q", raw)
+		}
+		field.SetInt(n)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("ожидается true или false, получено %q", raw)
+		}
+		field.SetBool(b)
+
+	case reflect.Slice:
+		var parts []string
+		for _, part := range strings.Split(raw, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setFromString(slice.Index(i), part); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+
+	case reflect.Map:
+		m := make(map[string]string)
+		for _, pair := range strings.Split(raw, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(pair, ":")
+			if !ok {
+				return fmt.Errorf("ожидается key:value, получено %q", pair)
+			}
+			m[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+		field.Set(reflect.ValueOf(m))
+
+	default:
+		return fmt.Errorf("неподдерживаемый тип %s", field.Type())
+	}
+	return nil
+}
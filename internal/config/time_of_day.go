@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"time"
+	"work-schedule-bot/pkg/clock"
+)
+
+// TimeOfDay - время суток "ЧЧ:ММ"
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+}
+
+// UnmarshalText разбирает время вида "03:00"
+func (t *TimeOfDay) UnmarshalText(text []byte) error {
+	parsed, err := time.Parse("15:04", string(text))
+	if err != nil {
+		return fmt.Errorf("ожидается время ЧЧ:ММ, получено %q", string(text))
+	}
+	t.Hour, t.Minute = parsed.Hour(), parsed.Minute()
+	return nil
+}
+
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", t.Hour, t.Minute)
+}
+
+// Next возвращает ближайший после now момент с этим временем суток в часовом поясе loc
+func (t TimeOfDay) Next(now time.Time, loc *time.Location) time.Time {
+	today := clock.DayOf(now, loc)
+	next := clock.At(today, t.Hour, t.Minute)
+	if !next.After(now) {
+		next = clock.At(today.AddDate(0, 0, 1), t.Hour, t.Minute)
+	}
+	return next
+}
@@ -0,0 +1,454 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"work-schedule-bot/internal/config"
+	"work-schedule-bot/internal/models"
+)
+
+// monday - понедельник 2 марта 2026, вечер: оба времени /in 09:00 и /out 18:00 уже в прошлом
+var monday = time.Date(2026, time.March, 2, 19, 0, 0, 0, time.Local)
+
+func expectReply(t *testing.T, replies []sentMessage, want string) sentMessage {
+	t.Helper()
+
+	reply := lastReply(t, replies)
+	if !strings.Contains(reply.Text, want) {
+		t.Fatalf("reply = %q, want it to contain %q", reply.Text, want)
+	}
+	return reply
+}
+
+func createMarchSchedule(t *testing.T, b *testBot) {
+	t.Helper()
+
+	schedule := models.WorkSchedule{Year: 2026, Month: 3, WorkDays: 22, WorkMinutesPerDay: 480, TotalMinutes: 22 * 480}
+	if err := b.db.Create(&schedule).Error; err != nil {
+		t.Fatalf("failed to create schedule: %v", err)
+	}
+}
+
+func TestWorkDayConversation(t *testing.T) {
+	b := newTestBot(t, monday)
+	createMarchSchedule(t, b)
+	ivan := b.user(100, "ivan", "Иван")
+
+	expectReply(t, ivan.Say("/createprofile"), "Создание профиля")
+	ivan.Say("Иван")
+	expectReply(t, ivan.Say("Петров"), "отчество")
+	expectReply(t, ivan.Say("Сергеевич"), "часовом поясе")
+	expectReply(t, ivan.Say("-"), "Профиль успешно создан")
+
+	var user models.User
+	if err := b.db.Where("chat_id = ?", 100).First(&user).Error; err != nil {
+		t.Fatalf("user was not created: %v", err)
+	}
+	if user.FirstName != "Иван" || user.LastName != "Петров" || user.Patronymic != "Сергеевич" || user.Username != "ivan" {
+		t.Errorf("user = %+v, want Иван Петров Сергеевич (ivan)", user)
+	}
+
+	clockIn := expectReply(t, ivan.Say("/in 09:00"), "Рабочий день начат")
+	if !strings.Contains(clockIn.Text, "02.03.2026") || clockIn.ParseMode != "Markdown" {
+		t.Errorf("clock in reply = %+v, want markdown reply for 02.03.2026", clockIn)
+	}
+	if len(clockIn.Buttons) != 1 || clockIn.Buttons[0][0].Text != "⏰ Завершить рабочий день" {
+		t.Errorf("clock in buttons = %+v, want a clock out button", clockIn.Buttons)
+	}
+
+	expectReply(t, ivan.Say("/out 18:00"), "Отработано: 9ч")
+
+	var session models.WorkSession
+	if err := b.db.Where("user_id = ?", user.ID).First(&session).Error; err != nil {
+		t.Fatalf("session was not created: %v", err)
+	}
+	if session.Status != models.StatusCompleted || session.WorkedMinutes != 540 {
+		t.Errorf("session = %s, %d minutes; want completed, 540 minutes", session.Status, session.WorkedMinutes)
+	}
+
+	stat := expectReply(t, ivan.Say("/currentstat"), "март 2026")
+	if !strings.Contains(stat.Text, "9ч") {
+		t.Errorf("current stat = %q, want 9h worked", stat.Text)
+	}
+
+	var monthly models.UserMonthlyStat
+	if err := b.db.Where("user_id = ? AND year = ? AND month = ?", user.ID, 2026, 3).First(&monthly).Error; err != nil {
+		t.Fatalf("monthly stat was not created: %v", err)
+	}
+	if monthly.WorkedMinutes != 540 || monthly.PlannedMinutes != 22*480 {
+		t.Errorf("monthly stat worked %d of %d minutes, want 540 of %d", monthly.WorkedMinutes, monthly.PlannedMinutes, 22*480)
+	}
+}
+
+func TestClockOutWithButton(t *testing.T) {
+	b := newTestBot(t, monday)
+	createMarchSchedule(t, b)
+	ivan := b.user(100, "ivan", "Иван")
+
+	ivan.Say("/createprofile")
+	ivan.Say("Иван")
+	ivan.Say("-")
+	ivan.Say("-")
+	ivan.Say("-")
+
+	clockIn := lastReply(t, ivan.Say("/in 10:00"))
+	b.clock.Set(monday.Add(-30 * time.Minute)) // 18:30
+	clockOut := expectReply(t, ivan.Press(clockIn, "⏰ Завершить рабочий день"), "Рабочий день завершен")
+
+	if !strings.Contains(clockOut.Text, "10:00 - 18:30") {
+		t.Errorf("clock out reply = %q, want 10:00 - 18:30", clockOut.Text)
+	}
+	if edited := b.api.editedMessages(); len(edited) != 1 || edited[0] != clockIn.MessageID {
+		t.Errorf("edited messages = %v, want buttons removed from message %d", edited, clockIn.MessageID)
+	}
+	if len(clockOut.Buttons) != 1 || clockOut.Buttons[0][0].Text != "🔄 Начать новый рабочий день" {
+		t.Errorf("clock out buttons = %+v, want a clock in button", clockOut.Buttons)
+	}
+}
+
+func TestDialogExpiresWithClock(t *testing.T) {
+	b := newTestBot(t, monday)
+	ivan := b.user(100, "ivan", "Иван")
+
+	ivan.Say("/createprofile")
+	b.clock.Advance(time.Hour)
+	expectReply(t, ivan.Say("Иван"), "Время ожидания ответа истекло")
+
+	var count int64
+	b.db.Model(&models.User{}).Count(&count)
+	if count != 0 {
+		t.Errorf("%d users created after an expired dialog, want 0", count)
+	}
+}
+
+func TestClockInUsesUserTimezone(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("failed to load Europe/Moscow: %v", err)
+	}
+
+	// 21:00 понедельника в Москве - уже 01:00 вторника в Новосибирске
+	b := newTestBot(t, time.Date(2026, time.March, 2, 21, 0, 0, 0, moscow))
+	createMarchSchedule(t, b)
+	ivan := b.user(100, "ivan", "Иван")
+
+	ivan.Say("/createprofile")
+	ivan.Say("Иван")
+	ivan.Say("-")
+	ivan.Say("-")
+	expectReply(t, ivan.Say("Новосибирск"), "Asia/Novosibirsk")
+
+	clockIn := expectReply(t, ivan.Say("/in"), "Рабочий день начат")
+	if !strings.Contains(clockIn.Text, "01:00") || !strings.Contains(clockIn.Text, "03.03.2026") {
+		t.Errorf("clock in reply = %q, want 01:00 on 03.03.2026", clockIn.Text)
+	}
+
+	b.clock.Set(time.Date(2026, time.March, 3, 6, 0, 0, 0, moscow)) // 10:00 в Новосибирске
+	clockOut := expectReply(t, ivan.Say("/out"), "Рабочий день завершен")
+	if !strings.Contains(clockOut.Text, "01:00 - 10:00") {
+		t.Errorf("clock out reply = %q, want 01:00 - 10:00", clockOut.Text)
+	}
+
+	var session models.WorkSession
+	if err := b.db.First(&session).Error; err != nil {
+		t.Fatalf("session was not created: %v", err)
+	}
+	if session.Date.Day() != 3 {
+		t.Errorf("session date = %s, want the user's local day 03.03", session.Date.Format("02.01.2006"))
+	}
+	if want := time.Date(2026, time.March, 2, 18, 0, 0, 0, time.UTC); !session.ClockInTime.Equal(want) || session.ClockInTime.Location() != time.UTC {
+		t.Errorf("clock in = %s, want %s stored in UTC", session.ClockInTime, want)
+	}
+
+	expectReply(t, ivan.Say("/today"), "⏰ Пришел: 01:00 | Ушел: 10:00")
+
+	expectReply(t, ivan.Say("/settimezone мск"), "Europe/Moscow")
+	expectReply(t, ivan.Say("/history"), "03.03 - 9ч (⏰ Пришел: 21:00 | Ушел: 06:00)")
+}
+
+func TestInterfaceLanguage(t *testing.T) {
+	b := newTestBot(t, monday)
+	createMarchSchedule(t, b)
+	john := b.user(200, "john", "John")
+	john.user.LanguageCode = "en-GB"
+
+	// Без профиля бот отвечает на языке клиента Telegram
+	expectReply(t, john.Say("/help"), "Available commands")
+
+	john.Say("/createprofile")
+	john.Say("John")
+	john.Say("-")
+	john.Say("-")
+	john.Say("-")
+
+	var user models.User
+	if err := b.db.Where("chat_id = ?", 200).First(&user).Error; err != nil {
+		t.Fatalf("user was not created: %v", err)
+	}
+	if user.Language != "en" {
+		t.Errorf("user language = %q, want en from the client", user.Language)
+	}
+
+	clockIn := expectReply(t, john.Say("/in 09:00"), "Work day started")
+	if len(clockIn.Buttons) != 1 || clockIn.Buttons[0][0].Text != "⏰ Finish the work day" {
+		t.Errorf("clock in buttons = %+v, want an English clock out button", clockIn.Buttons)
+	}
+
+	// Язык профиля важнее языка клиента
+	expectReply(t, john.Say("/language ru"), "русский")
+	expectReply(t, john.Say("/out 18:00"), "Отработано: 9ч")
+	expectReply(t, john.Say("/language de"), "неизвестный язык")
+}
+
+func TestImportSessions(t *testing.T) {
+	b := newTestBot(t, monday)
+	admin := b.user(300, "boss", "Анна")
+	ivan := b.user(100, "ivan", "Иван")
+	for _, user := range []*models.User{
+		{ChatID: 300, Username: "boss", FirstName: "Анна", Role: models.RoleAdmin},
+		{ChatID: 100, Username: "ivan", FirstName: "Иван", Role: models.RoleEmployee},
+	} {
+		if err := b.db.Create(user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	expectReply(t, admin.Say("/importsessions"), "Импорт истории рабочих дней")
+	expectReply(t, ivan.SendDocument("history.csv", []byte("ivan,26.02.2026,vacation"), "/importsessions"), "Недостаточно прав")
+
+	// Dry run показывает ошибки строк и ничего не предлагает импортировать
+	report := expectReply(t, admin.SendDocument("history.csv", []byte("ivan,26.02.2026,09:00,18:00\nanna,27.02.2026,09:00,18:00"), "/importsessions"), "Ошибок в файле: 1")
+	if !strings.Contains(report.Text, "строка 2: сотрудник anna не найден") || len(report.Buttons) != 0 {
+		t.Errorf("dry run report = %+v, want an unknown user on line 2 without buttons", report)
+	}
+
+	data := []byte("user,date,clock_in,clock_out\nivan,26.02.2026,09:00,18:00\nivan,27.02.2026,sick_leave\n")
+	checked := expectReply(t, admin.SendDocument("history.csv", data, "/importsessions"), "Файл проверен, ошибок нет")
+	if !strings.Contains(checked.Text, "Рабочих дней: 1, дней отсутствия: 1, сотрудников: 1") {
+		t.Errorf("dry run report = %q, want 1 work day and 1 absence day", checked.Text)
+	}
+
+	var count int64
+	b.db.Model(&models.WorkSession{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("dry run created %d sessions", count)
+	}
+
+	expectReply(t, admin.Press(checked, "✅ Импортировать"), "Импорт выполнен")
+
+	var sessions []models.WorkSession
+	b.db.Order("date").Find(&sessions)
+	if len(sessions) != 2 || sessions[0].WorkedMinutes != 540 || sessions[1].SessionType != models.SessionTypeSickLeave {
+		t.Errorf("sessions = %+v, want a 9h work day and a sick leave", sessions)
+	}
+
+	// Повторная загрузка того же файла пересекается с импортированными днями
+	expectReply(t, admin.SendDocument("history.csv", data, "/importsessions"), "Ошибок в файле: 2")
+}
+
+func TestBackupCommand(t *testing.T) {
+	b := newTestBot(t, monday)
+	admin := b.user(300, "boss", "Анна")
+	ivan := b.user(100, "ivan", "Иван")
+	for _, user := range []*models.User{
+		{ChatID: 300, Username: "boss", FirstName: "Анна", Role: models.RoleAdmin},
+		{ChatID: 100, Username: "ivan", FirstName: "Иван", Role: models.RoleEmployee},
+	} {
+		if err := b.db.Create(user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	expectReply(t, ivan.Say("/backup"), "Недостаточно прав")
+
+	backup := expectReply(t, admin.Say("/backup"), "Резервная копия базы")
+	if backup.Method != "sendDocument" || !strings.HasPrefix(backup.FileName, "backup-") || !strings.HasSuffix(backup.FileName, ".db") {
+		t.Errorf("backup = %+v, want an SQLite file sent as a document", backup)
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	b := newTestBot(t, monday)
+	createMarchSchedule(t, b)
+	admin := b.user(300, "boss", "Анна")
+	ivan := b.user(100, "ivan", "Иван")
+	for _, user := range []*models.User{
+		{ChatID: 300, Username: "boss", FirstName: "Анна", Role: models.RoleAdmin},
+		{ChatID: 100, Username: "ivan", FirstName: "Иван", Role: models.RoleEmployee},
+	} {
+		if err := b.db.Create(user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	expectReply(t, ivan.Say("/in 09:00"), "Рабочий день начат")
+	expectReply(t, ivan.Say("/out 18:00"), "Отработано: 9ч")
+
+	expectReply(t, ivan.Say("/audit"), "Недостаточно прав")
+
+	audit := expectReply(t, admin.Say("/audit 100 02.03.2026"), "Изменение: рабочая сессия")
+	for _, want := range []string{"Создание: рабочая сессия", "Иван (100)", "clock_out_time: null →", `status: "active" → "completed"`} {
+		if !strings.Contains(audit.Text, want) {
+			t.Errorf("audit = %q, want it to contain %q", audit.Text, want)
+		}
+	}
+
+	expectReply(t, admin.Say("/audit team"), "нет изменений")
+	expectReply(t, admin.Say("/audit 100 03.2025"), "нет изменений")
+	expectReply(t, admin.Say("/audit вчера"), "непонятный аргумент")
+
+	export := expectReply(t, admin.Say("/auditexport session"), "Журнал изменений: 2 записи")
+	if export.Method != "sendDocument" || !strings.HasSuffix(export.FileName, ".csv") {
+		t.Errorf("export = %+v, want a CSV file sent as a document", export)
+	}
+}
+
+func TestDeactivateUser(t *testing.T) {
+	b := newTestBot(t, monday)
+	createMarchSchedule(t, b)
+	admin := b.user(300, "boss", "Анна")
+	ivan := b.user(100, "ivan", "Иван")
+	for _, user := range []*models.User{
+		{ChatID: 300, Username: "boss", FirstName: "Анна", Role: models.RoleAdmin},
+		{ChatID: 100, Username: "ivan", FirstName: "Иван", Role: models.RoleEmployee},
+	} {
+		if err := b.db.Create(user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	expectReply(t, ivan.Say("/in 09:00"), "Рабочий день начат")
+	expectReply(t, admin.Say("/deactivate 100"), "незавершенный рабочий день")
+	expectReply(t, ivan.Say("/out 18:00"), "Отработано: 9ч")
+
+	expectReply(t, admin.Say("/purgeuser 100"), "только деактивированного")
+	expectReply(t, admin.Say("/deactivate 100 01.04.2026"), "не может быть в будущем")
+	expectReply(t, admin.Say("/deactivate 100"), "деактивирован с 02.03.2026")
+	expectReply(t, admin.Say("/deactivate 100"), "уже деактивирован")
+
+	expectReply(t, ivan.Say("/in 09:00"), "профиль деактивирован")
+	expectReply(t, ivan.Say("/myprofile"), "Иван")
+
+	users := expectReply(t, admin.Say("/allusers"), "Деактивированные")
+	if !strings.Contains(users.Text, "ID: 100, с 02.03.2026") {
+		t.Errorf("users = %q, want Иван in the archived section", users.Text)
+	}
+
+	expectReply(t, admin.Say("/reactivate 100"), "снова активен")
+	expectReply(t, ivan.Say("/in 09:00"), "Рабочий день начат")
+	expectReply(t, ivan.Say("/out 18:00"), "Отработано")
+
+	expectReply(t, admin.Say("/deactivate 100"), "деактивирован")
+	confirm := expectReply(t, admin.Say("/purgeuser 100"), "Безвозвратно удалить")
+	expectReply(t, admin.Press(confirm, "🗑 Да, удалить навсегда"), "все его данные удалены")
+
+	var count int64
+	b.db.Model(&models.User{}).Where("chat_id = ?", 100).Count(&count)
+	if count != 0 {
+		t.Errorf("users with chat_id 100 = %d, want 0 after purge", count)
+	}
+}
+
+func TestRegistrationApproval(t *testing.T) {
+	cfg := config.Default()
+	cfg.Registration.RequireApproval = true
+	b := newConfiguredTestBot(t, monday, cfg)
+	createMarchSchedule(t, b)
+	hr := b.user(300, "hr", "Ольга")
+	if err := b.db.Create(&models.User{ChatID: 300, Username: "hr", FirstName: "Ольга", Role: models.RoleHR}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	register := func(u *testUser, firstName string) {
+		t.Helper()
+		u.Say("/createprofile")
+		u.Say(firstName)
+		u.Say("-")
+		u.Say("-")
+		expectReply(t, u.Say("-"), "отправлен на одобрение")
+	}
+
+	ivan := b.user(100, "ivan", "Иван")
+	register(ivan, "Иван")
+
+	request := lastReply(t, b.api.sentTo(300))
+	if !strings.Contains(request.Text, "Иван (@ivan) - ID: 100") || len(request.Buttons) != 1 {
+		t.Fatalf("approval request = %+v, want Иван with approve buttons", request)
+	}
+
+	expectReply(t, ivan.Say("/in 09:00"), "ожидает одобрения")
+	expectReply(t, ivan.Say("/myprofile"), "Ожидает одобрения")
+
+	var stats int64
+	b.db.Model(&models.UserMonthlyStat{}).Count(&stats)
+	if stats != 0 {
+		t.Errorf("monthly stats = %d, want none before approval", stats)
+	}
+
+	users := expectReply(t, hr.Say("/allusers"), "Ожидают одобрения")
+	if !strings.Contains(users.Text, "Иван - ID: 100") || !strings.Contains(users.Text, "Всего пользователей: 1") {
+		t.Errorf("users = %q, want Иван pending and not counted", users.Text)
+	}
+
+	expectReply(t, hr.Press(request, "✅ Одобрить"), "Профиль Иван одобрен")
+	expectReply(t, b.api.sentTo(100), "профиль одобрен")
+	expectReply(t, hr.Say("/approve 100"), "не ожидает одобрения")
+
+	b.db.Model(&models.UserMonthlyStat{}).Count(&stats)
+	if stats != 1 {
+		t.Errorf("monthly stats = %d, want one for the approved profile", stats)
+	}
+	expectReply(t, ivan.Say("/in 09:00"), "Рабочий день начат")
+
+	petr := b.user(200, "petr", "Пётр")
+	register(petr, "Пётр")
+	pending := hr.Say("/pending")
+	if len(pending) != 2 || !strings.Contains(pending[0].Text, "1 профиль") {
+		t.Fatalf("pending = %+v, want a header and one profile", pending)
+	}
+	expectReply(t, hr.Press(pending[1], "❌ Отклонить"), "отклонен и удален")
+	expectReply(t, b.api.sentTo(200), "отклонен и удален")
+	expectReply(t, hr.Say("/pending"), "Нет профилей")
+
+	var count int64
+	b.db.Model(&models.User{}).Where("chat_id = ?", 200).Count(&count)
+	if count != 0 {
+		t.Errorf("users with chat_id 200 = %d, want 0 after rejection", count)
+	}
+}
+
+func TestEmployeeProfileFields(t *testing.T) {
+	b := newTestBot(t, monday)
+	createMarchSchedule(t, b)
+	hr := b.user(300, "hr", "Ольга")
+	ivan := b.user(100, "ivan", "Иван")
+	for _, user := range []*models.User{
+		{ChatID: 300, Username: "hr", FirstName: "Ольга", Role: models.RoleHR, EmployeeNumber: "001"},
+		{ChatID: 100, Username: "ivan", FirstName: "Иван", LastName: "Петров", Role: models.RoleEmployee},
+	} {
+		if err := b.db.Create(user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	expectReply(t, ivan.Say("/setemployee 100 number 042"), "Недостаточно прав")
+
+	expectReply(t, hr.Say("/setemployee 100 position Ведущий инженер"), "Должность: Ведущий инженер")
+	expectReply(t, hr.Say("/setemployee 100 number 001"), "уже у сотрудника Ольга")
+	expectReply(t, hr.Say("/setemployee 100 number 042"), "Табельный номер: 042")
+	expectReply(t, hr.Say("/setemployee 100 hired 02.03.2026"), "Дата приема: 02.03.2026")
+	expectReply(t, hr.Say("/setemployee 100 salary 1"), "Неизвестное поле")
+
+	expectReply(t, ivan.Say("/myprofile"), "Табельный номер: 042")
+	expectReply(t, hr.Say("/teamstats 3"), "Иван Петров (таб. № 042)")
+
+	expectReply(t, hr.Say("/setemployee 100 position -"), "Кадровые данные обновлены")
+	var user models.User
+	if err := b.db.Where("chat_id = ?", 100).First(&user).Error; err != nil {
+		t.Fatalf("failed to get user: %v", err)
+	}
+	if user.Position != "" || user.EmployeeNumber != "042" || user.HireDate == nil {
+		t.Errorf("user = %+v, want the position cleared and the rest kept", user)
+	}
+}
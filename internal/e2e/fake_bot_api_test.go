@@ -0,0 +1,296 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	testBotToken = "test-token"
+
+	// maxPollWait - сколько getUpdates ждет новых обновлений. Меньше, чем просит клиент,
+	// чтобы бот быстро останавливался в конце теста.
+	maxPollWait = 100 * time.Millisecond
+)
+
+// sentMessage - сообщение, которое бот отправил через Bot API
+type sentMessage struct {
+	MessageID int
+	Method    string
+	ChatID    int64
+	Text      string // текст или подпись к файлу
+	ParseMode string
+	Buttons   [][]tgbotapi.InlineKeyboardButton
+	FileName  string
+}
+
+// fakeBotAPI эмулирует методы Bot API, которыми пользуется tgbotapi
+type fakeBotAPI struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu            sync.Mutex
+	updates       []tgbotapi.Update
+	newUpdates    chan struct{} // закрывается и пересоздается при добавлении обновления
+	nextUpdateID  int
+	nextMessageID int
+	sent          []sentMessage
+	edited        []int             // сообщения, у которых бот изменил клавиатуру
+	answered      []string          // подтвержденные callback-запросы
+	files         map[string][]byte // файлы пользователей для getFile по их file_id
+}
+
+func newFakeBotAPI(t *testing.T) *fakeBotAPI {
+	api := &fakeBotAPI{
+		t:             t,
+		newUpdates:    make(chan struct{}),
+		nextUpdateID:  1,
+		nextMessageID: 1,
+	}
+	api.server = httptest.NewServer(api)
+	return api
+}
+
+// Endpoint возвращает шаблон адреса для tgbotapi.NewBotAPIWithAPIEndpoint
+func (api *fakeBotAPI) Endpoint() string {
+	return api.server.URL + "/bot%s/%s"
+}
+
+func (api *fakeBotAPI) Close() {
+	api.server.Close()
+}
+
+func (api *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if fileID, ok := strings.CutPrefix(r.URL.Path, "/file/bot"+testBotToken+"/documents/"); ok {
+		api.serveFile(w, fileID)
+		return
+	}
+
+	method, ok := strings.CutPrefix(r.URL.Path, "/bot"+testBotToken+"/")
+	if !ok {
+		writeAPIError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	} else if err := r.ParseForm(); err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch method {
+	case "getMe":
+		writeAPIResult(w, tgbotapi.User{ID: 1, IsBot: true, FirstName: "Test", UserName: "test_bot"})
+	case "getUpdates":
+		api.getUpdates(w, r)
+	case "sendMessage", "sendDocument", "sendPhoto":
+		writeAPIResult(w, api.recordMessage(method, r))
+	case "editMessageReplyMarkup":
+		messageID, _ := strconv.Atoi(r.FormValue("message_id"))
+		api.mu.Lock()
+		api.edited = append(api.edited, messageID)
+		api.mu.Unlock()
+		writeAPIResult(w, true)
+	case "answerCallbackQuery":
+		api.mu.Lock()
+		api.answered = append(api.answered, r.FormValue("callback_query_id"))
+		api.mu.Unlock()
+		writeAPIResult(w, true)
+	case "deleteWebhook":
+		writeAPIResult(w, true)
+	case "getFile":
+		fileID := r.FormValue("file_id")
+		api.mu.Lock()
+		_, ok := api.files[fileID]
+		api.mu.Unlock()
+		if !ok {
+			writeAPIError(w, http.StatusBadRequest, "Bad Request: invalid file_id")
+			return
+		}
+		writeAPIResult(w, tgbotapi.File{FileID: fileID, FilePath: "documents/" + fileID})
+	default:
+		api.t.Errorf("fake Bot API: unexpected method %s", method)
+		writeAPIError(w, http.StatusNotFound, "Not Found: method not found")
+	}
+}
+
+// FileEndpoint возвращает шаблон адреса скачивания файлов для telegram.Client
+func (api *fakeBotAPI) FileEndpoint() string {
+	return api.server.URL + "/file/bot%s/%s"
+}
+
+// addFile сохраняет файл пользователя и возвращает его file_id
+func (api *fakeBotAPI) addFile(data []byte) string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	if api.files == nil {
+		api.files = make(map[string][]byte)
+	}
+	fileID := fmt.Sprintf("file-%d", len(api.files)+1)
+	api.files[fileID] = data
+	return fileID
+}
+
+func (api *fakeBotAPI) serveFile(w http.ResponseWriter, fileID string) {
+	api.mu.Lock()
+	data, ok := api.files[fileID]
+	api.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, nil)
+		return
+	}
+	w.Write(data)
+}
+
+// getUpdates отдает обновления начиная с offset, ожидая их появления не дольше maxPollWait
+func (api *fakeBotAPI) getUpdates(w http.ResponseWriter, r *http.Request) {
+	offset, _ := strconv.Atoi(r.FormValue("offset"))
+	deadline := time.After(maxPollWait)
+
+	for {
+		api.mu.Lock()
+		var result []tgbotapi.Update
+		for _, update := range api.updates {
+			if update.UpdateID >= offset {
+				result = append(result, update)
+			}
+		}
+		wait := api.newUpdates
+		api.mu.Unlock()
+
+		if len(result) > 0 {
+			writeAPIResult(w, result)
+			return
+		}
+
+		select {
+		case <-wait:
+		case <-deadline:
+			writeAPIResult(w, []tgbotapi.Update{})
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (api *fakeBotAPI) recordMessage(method string, r *http.Request) tgbotapi.Message {
+	chatID, err := strconv.ParseInt(r.FormValue("chat_id"), 10, 64)
+	if err != nil {
+		api.t.Errorf("fake Bot API: %s with invalid chat_id %q", method, r.FormValue("chat_id"))
+	}
+
+	msg := sentMessage{
+		Method:    method,
+		ChatID:    chatID,
+		Text:      r.FormValue("text"),
+		ParseMode: r.FormValue("parse_mode"),
+	}
+	if method != "sendMessage" {
+		msg.Text = r.FormValue("caption")
+		field := "document"
+		if method == "sendPhoto" {
+			field = "photo"
+		}
+		if _, header, err := r.FormFile(field); err == nil {
+			msg.FileName = header.Filename
+		}
+	}
+	if markup := r.FormValue("reply_markup"); markup != "" {
+		var keyboard tgbotapi.InlineKeyboardMarkup
+		if err := json.Unmarshal([]byte(markup), &keyboard); err != nil {
+			api.t.Errorf("fake Bot API: invalid reply_markup %q: %v", markup, err)
+		}
+		msg.Buttons = keyboard.InlineKeyboard
+	}
+
+	api.mu.Lock()
+	msg.MessageID = api.nextMessageID
+	api.nextMessageID++
+	api.sent = append(api.sent, msg)
+	api.mu.Unlock()
+
+	return tgbotapi.Message{
+		MessageID: msg.MessageID,
+		Chat:      &tgbotapi.Chat{ID: chatID, Type: "private"},
+		Date:      int(time.Now().Unix()),
+		Text:      msg.Text,
+	}
+}
+
+// pushUpdate ставит обновление в очередь getUpdates
+func (api *fakeBotAPI) pushUpdate(update tgbotapi.Update) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	update.UpdateID = api.nextUpdateID
+	api.nextUpdateID++
+	api.updates = append(api.updates, update)
+
+	close(api.newUpdates)
+	api.newUpdates = make(chan struct{})
+}
+
+// sentTo возвращает сообщения, отправленные ботом в чат
+func (api *fakeBotAPI) sentTo(chatID int64) []sentMessage {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	var result []sentMessage
+	for _, msg := range api.sent {
+		if msg.ChatID == chatID {
+			result = append(result, msg)
+		}
+	}
+	return result
+}
+
+func (api *fakeBotAPI) editedMessages() []int {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	return append([]int(nil), api.edited...)
+}
+
+func (api *fakeBotAPI) answeredCallbacks() []string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	return append([]string(nil), api.answered...)
+}
+
+func writeAPIResult(w http.ResponseWriter, result interface{}) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"ok":true,"result":%s}`, data)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ok":          false,
+		"error_code":  status,
+		"description": description,
+	})
+}
@@ -0,0 +1,267 @@
+// Package e2e - сквозные тесты: настоящий Handler, база SQLite в памяти и фейковый Bot API,
+// с которым бот общается через tgbotapi так же, как с Telegram.
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"work-schedule-bot/internal/config"
+	"work-schedule-bot/internal/handler"
+	"work-schedule-bot/internal/migrations"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/internal/service"
+	"work-schedule-bot/pkg/clock"
+	"work-schedule-bot/pkg/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	// replyTimeout - сколько ждать первого ответа бота
+	replyTimeout = 5 * time.Second
+	// replySettle - тишина после ответа, по которой считается, что бот закончил отвечать
+	replySettle = 50 * time.Millisecond
+)
+
+// testBot - запущенный бот с фейковым Bot API
+type testBot struct {
+	t     *testing.T
+	api   *fakeBotAPI
+	db    *gorm.DB
+	clock *clock.Fake
+}
+
+// newTestBot запускает бота; часы бота стоят на now, пока тест их не переведет
+func newTestBot(t *testing.T, now time.Time) *testBot {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Registration.RequireApproval = false
+	return newConfiguredTestBot(t, now, cfg)
+}
+
+// newConfiguredTestBot запускает бота с конфигурацией cfg
+func newConfiguredTestBot(t *testing.T, now time.Time, cfg *config.BotConfig) *testBot {
+	t.Helper()
+
+	db, err := repository.OpenDatabase(repository.DriverSQLite, ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database instance: %v", err)
+	}
+	// У каждого соединения своя база в памяти, поэтому соединение одно
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if _, err := migrations.NewMigrator(db).Up(); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	api := newFakeBotAPI(t)
+	t.Cleanup(api.Close)
+
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint(testBotToken, api.Endpoint())
+	if err != nil {
+		t.Fatalf("failed to create bot: %v", err)
+	}
+	updateConfig := tgbotapi.NewUpdate(0)
+	updateConfig.Timeout = 1
+	client := &telegram.Client{Bot: bot, UpdateConfig: updateConfig, FileEndpoint: api.FileEndpoint()}
+
+	// Часовой пояс компании - пояс now
+	fakeClock := clock.NewFake(now)
+	h := newHandler(t, db, client, fakeClock, cfg)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.HandleUpdates(telegram.ConvertUpdates(bot.GetUpdatesChan(updateConfig)))
+	}()
+	t.Cleanup(func() {
+		bot.StopReceivingUpdates()
+		select {
+		case <-done:
+		case <-time.After(replyTimeout):
+			t.Error("bot did not stop")
+		}
+	})
+
+	return &testBot{t: t, api: api, db: db, clock: fakeClock}
+}
+
+func newHandler(t *testing.T, db *gorm.DB, client *telegram.Client, clk clock.Clock, cfg *config.BotConfig) *handler.Handler {
+	t.Helper()
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+	}
+	userRepo, err := repository.NewUserRepository(db)
+	must(err)
+	scheduleRepo, err := repository.NewGormWorkScheduleRepository(db, clk)
+	must(err)
+	statRepo, err := repository.NewGormUserMonthlyStatRepository(db, clk)
+	must(err)
+	sessionRepo, err := repository.NewGormWorkSessionRepository(db, clk)
+	must(err)
+	nonWorkingDayRepo, err := repository.NewGormNonWorkingDayRepository(db)
+	must(err)
+	absenceRepo, err := repository.NewGormAbsencePeriodRepository(db, clk)
+	must(err)
+	timeBankRepo, err := repository.NewGormTimeBankRepository(db)
+	must(err)
+	teamRepo, err := repository.NewGormTeamRepository(db)
+	must(err)
+	dialogRepo, err := repository.NewGormDialogStateRepository(db)
+	must(err)
+	tokenRepo, err := repository.NewGormAPITokenRepository(db)
+	must(err)
+	auditRepo, err := repository.NewGormAuditRepository(db, clk)
+	must(err)
+
+	uow := repository.NewGormUnitOfWork(db, clk)
+	nonWorkingDayService := service.NewNonWorkingDayService(nonWorkingDayRepo, clk)
+	policy := cfg.WorkPolicy()
+	statService := service.NewUserMonthlyStatService(statRepo, userRepo, uow, policy, clk)
+
+	return handler.NewHandler(
+		client,
+		service.NewUserService(userRepo, scheduleRepo, statService, uow, clk),
+		service.NewWorkScheduleService(scheduleRepo, statService, nonWorkingDayService, uow, policy, clk),
+		statService,
+		service.NewWorkSessionService(sessionRepo, statRepo, scheduleRepo, absenceRepo, nonWorkingDayService, uow, policy, clk),
+		nonWorkingDayService,
+		service.NewAbsenceService(absenceRepo, sessionRepo, statRepo, userRepo, scheduleRepo, timeBankRepo, uow, nonWorkingDayService, policy, clk),
+		service.NewTimeBankService(timeBankRepo, statRepo, userRepo, uow, clk),
+		service.NewTeamCalendarService(absenceRepo, userRepo, nonWorkingDayService, 30, clk),
+		service.NewTeamService(teamRepo, userRepo, uow),
+		service.NewDialogService(dialogRepo, clk),
+		service.NewAPITokenService(tokenRepo, uow, clk),
+		service.NewSessionImportService(uow, policy, clk),
+		service.NewBackupService(db, clk, "", 1),
+		service.NewAuditService(auditRepo, userRepo, tokenRepo, clk),
+		clk,
+		cfg,
+	)
+}
+
+// testUser - собеседник бота
+type testUser struct {
+	bot           *testBot
+	chatID        int64
+	user          *tgbotapi.User
+	lastMessageID int
+	callbacks     int
+}
+
+func (b *testBot) user(chatID int64, username, firstName string) *testUser {
+	return &testUser{
+		bot:    b,
+		chatID: chatID,
+		user:   &tgbotapi.User{ID: chatID, UserName: username, FirstName: firstName},
+	}
+}
+
+// Say отправляет боту сообщение и возвращает его ответы
+func (u *testUser) Say(text string) []sentMessage {
+	u.bot.t.Helper()
+
+	u.lastMessageID++
+	message := &tgbotapi.Message{
+		MessageID: u.lastMessageID,
+		From:      u.user,
+		Chat:      &tgbotapi.Chat{ID: u.chatID, Type: "private"},
+		Date:      int(u.bot.clock.Now().Unix()),
+		Text:      text,
+	}
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(text, " ")
+		message.Entities = []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}}
+	}
+
+	return u.exchange(tgbotapi.Update{Message: message}, text)
+}
+
+// SendDocument отправляет боту файл с подписью и возвращает ответы
+func (u *testUser) SendDocument(name string, data []byte, caption string) []sentMessage {
+	u.bot.t.Helper()
+
+	u.lastMessageID++
+	fileID := u.bot.api.addFile(data)
+	message := &tgbotapi.Message{
+		MessageID: u.lastMessageID,
+		From:      u.user,
+		Chat:      &tgbotapi.Chat{ID: u.chatID, Type: "private"},
+		Date:      int(u.bot.clock.Now().Unix()),
+		Caption:   caption,
+		Document:  &tgbotapi.Document{FileID: fileID, FileName: name, FileSize: len(data)},
+	}
+
+	return u.exchange(tgbotapi.Update{Message: message}, name)
+}
+
+// Press нажимает кнопку с текстом label под сообщением бота и возвращает ответы
+func (u *testUser) Press(msg sentMessage, label string) []sentMessage {
+	u.bot.t.Helper()
+
+	for _, row := range msg.Buttons {
+		for _, button := range row {
+			if button.Text != label || button.CallbackData == nil {
+				continue
+			}
+			u.callbacks++
+			return u.exchange(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+				ID:      fmt.Sprintf("callback-%d-%d", u.chatID, u.callbacks),
+				From:    u.user,
+				Message: &tgbotapi.Message{MessageID: msg.MessageID, Chat: &tgbotapi.Chat{ID: u.chatID}},
+				Data:    *button.CallbackData,
+			}}, label)
+		}
+	}
+
+	u.bot.t.Fatalf("message %q has no button %q", msg.Text, label)
+	return nil
+}
+
+// exchange доставляет обновление и ждет, пока бот закончит отвечать
+func (u *testUser) exchange(update tgbotapi.Update, what string) []sentMessage {
+	u.bot.t.Helper()
+
+	before := len(u.bot.api.sentTo(u.chatID))
+	u.bot.api.pushUpdate(update)
+
+	deadline := time.Now().Add(replyTimeout)
+	count := before
+	for {
+		time.Sleep(replySettle)
+		current := len(u.bot.api.sentTo(u.chatID))
+		if current > before && current == count {
+			break
+		}
+		if time.Now().After(deadline) {
+			u.bot.t.Fatalf("no reply to %q", what)
+		}
+		count = current
+	}
+
+	return u.bot.api.sentTo(u.chatID)[before:]
+}
+
+// lastReply возвращает последний из ответов
+func lastReply(t *testing.T, replies []sentMessage) sentMessage {
+	t.Helper()
+
+	if len(replies) == 0 {
+		t.Fatal("no replies")
+	}
+	return replies[len(replies)-1]
+}
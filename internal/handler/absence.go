@@ -2,267 +2,326 @@ package handler
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/clock"
+	"work-schedule-bot/pkg/messenger"
 
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/sirupsen/logrus"
-	"work-schedule-bot/internal/models"
 )
 
 // addVacation добавляет отпуск
-func (h *Handler) addVacation(message *tgbotapi.Message, args string) {
-	chatID := message.Chat.ID
+func (h *Handler) addVacation(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	// Получаем пользователя
 	user, err := h.userService.GetUser(chatID)
 	if err != nil || user == nil {
 		logrus.WithField("chat_id", chatID).Warn("User not found for vacation")
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
 		return
 	}
 
 	if args == "" {
-		msg := tgbotapi.NewMessage(chatID,
-			`🏖️ *Добавление отпуска*
-
-Формат команды:
-/vacation дата_начала дата_окончания
-
-Примеры:
-/vacation 01.07.2026 14.07.2026
-→ Отпуск с 1 по 14 июля 2026
-
-/vacation 15.08.2026 15.08.2026
-→ Отпуск на один день 15 августа 2026
-
-💡 *Важно:*
-• Отпуск можно добавить только на будущие даты
-• В выходные дни отпуск не добавляется
-• Нельзя пересекаться с другими отпусками/больничными`)
-		msg.ParseMode = "Markdown"
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID,
+			tr.T("absence.vacation_usage"))
+		msg.Format = messenger.FormatMarkdown
+		h.client.Send(msg)
 		return
 	}
 
 	// Парсим даты
 	parts := strings.Fields(args)
 	if len(parts) != 2 {
-		msg := tgbotapi.NewMessage(chatID, "❌ Неверный формат. Используйте: /vacation дата_начала дата_окончания")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("absence.vacation_format"))
+		h.client.Send(msg)
 		return
 	}
 
-	startDate, err := parseDate(parts[0])
+	startDate, err := parseDate(parts[0], h.clock.Now())
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка парсинга даты начала: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("absence.start_date_invalid", err))
+		h.client.Send(msg)
 		return
 	}
 
-	endDate, err := parseDate(parts[1])
+	endDate, err := parseDate(parts[1], h.clock.Now())
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка парсинга даты окончания: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("absence.end_date_invalid", err))
+		h.client.Send(msg)
 		return
 	}
 
 	// Добавляем отпуск
-	_, err = h.absenceService.AddVacation(uint(user.ID), startDate, endDate)
+	_, err = h.absenceService.AddVacation(uint(user.ID), startDate, endDate, models.ChatActor(chatID))
 	if err != nil {
 		logrus.WithError(err).Error("Failed to add vacation")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка добавления отпуска: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("absence.vacation_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
 	// Подсчитываем количество дней
 	days := int(endDate.Sub(startDate).Hours()/24) + 1
 	
-	response := fmt.Sprintf(
-		`✅ Отпуск добавлен!
-
-🏖️ Период: %s - %s
-📅 Количество дней: %d
-⏰ Всего часов: %d:%02d
-
-📋 Дни отпуска будут автоматически засчитаны как рабочие (по 8ч 40м).`,
-		startDate.Format("02.01.2006"),
-		endDate.Format("02.01.2006"),
-		days,
-		8, 40, // 8 часов 40 минут
-	)
-
-	msg := tgbotapi.NewMessage(chatID, response)
-	h.client.Bot.Send(msg)
+	response := tr.T("absence.vacation_added", startDate, endDate, days, 8, 40) // 8 часов 40 минут
+
+	// Предупреждаем, если в эти дни отсутствует слишком много сотрудников
+	response += h.formatOverloadedDaysWarning(tr, user, startDate, endDate)
+
+	msg := messenger.NewMessage(chatID, response)
+	h.client.Send(msg)
 }
 
 // addSickLeave добавляет больничный
-func (h *Handler) addSickLeave(message *tgbotapi.Message, args string) {
-	chatID := message.Chat.ID
+func (h *Handler) addSickLeave(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	// Получаем пользователя
 	user, err := h.userService.GetUser(chatID)
 	if err != nil || user == nil {
 		logrus.WithField("chat_id", chatID).Warn("User not found for sick leave")
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
 		return
 	}
 
 	if args == "" {
-		msg := tgbotapi.NewMessage(chatID,
-			`🏥 *Добавление больничного*
-
-Формат команды:
-/sick дата_начала дата_окончания
-
-Примеры:
-/sick 01.07.2026 07.07.2026
-→ Больничный с 1 по 7 июля 2026
-
-/sick 15.08.2026 15.08.2026
-→ Больничный на один день 15 августа 2026
-
-💡 *Важно:*
-• Больничный можно добавить на любые даты (включая прошедшие)
-• Можно добавлять на выходные дни
-• Нельзя пересекаться с другими отпусками/больничными`)
-		msg.ParseMode = "Markdown"
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID,
+			tr.T("absence.sick_usage"))
+		msg.Format = messenger.FormatMarkdown
+		h.client.Send(msg)
 		return
 	}
 
 	// Парсим даты
 	parts := strings.Fields(args)
 	if len(parts) != 2 {
-		msg := tgbotapi.NewMessage(chatID, "❌ Неверный формат. Используйте: /sick дата_начала дата_окончания")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("absence.sick_format"))
+		h.client.Send(msg)
 		return
 	}
 
-	startDate, err := parseDate(parts[0])
+	startDate, err := parseDate(parts[0], h.clock.Now())
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка парсинга даты начала: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("absence.start_date_invalid", err))
+		h.client.Send(msg)
 		return
 	}
 
-	endDate, err := parseDate(parts[1])
+	endDate, err := parseDate(parts[1], h.clock.Now())
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка парсинга даты окончания: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("absence.end_date_invalid", err))
+		h.client.Send(msg)
 		return
 	}
 
 	// Добавляем больничный
-	_, err = h.absenceService.AddSickLeave(uint(user.ID), startDate, endDate)
+	_, err = h.absenceService.AddSickLeave(uint(user.ID), startDate, endDate, models.ChatActor(chatID))
 	if err != nil {
 		logrus.WithError(err).Error("Failed to add sick leave")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка добавления больничного: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("absence.sick_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
 	// Подсчитываем количество дней
 	days := int(endDate.Sub(startDate).Hours()/24) + 1
 	
-	response := fmt.Sprintf(
-		`✅ Больничный добавлен!
-
-🏥 Период: %s - %s
-📅 Количество дней: %d
-⏰ Всего часов: %d:%02d
-
-📋 Дни больничного будут автоматически засчитаны как рабочие (по 8ч 40м).`,
-		startDate.Format("02.01.2006"),
-		endDate.Format("02.01.2006"),
-		days,
-		8, 40,
-	)
-
-	msg := tgbotapi.NewMessage(chatID, response)
-	h.client.Bot.Send(msg)
+	response := tr.T("absence.sick_added", startDate, endDate, days, 8, 40)
+
+	msg := messenger.NewMessage(chatID, response)
+	h.client.Send(msg)
 }
 
 // addDayOff добавляет отгул
-func (h *Handler) addDayOff(message *tgbotapi.Message, args string) {
-	chatID := message.Chat.ID
+func (h *Handler) addDayOff(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	// Получаем пользователя
 	user, err := h.userService.GetUser(chatID)
 	if err != nil || user == nil {
 		logrus.WithField("chat_id", chatID).Warn("User not found for day off")
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
 		return
 	}
 
 	if args == "" {
-		msg := tgbotapi.NewMessage(chatID,
-			`🎯 *Добавление отгула*
-
-Формат команды:
-/dayoff дата
-
-Примеры:
-/dayoff 01.07.2026
-→ Отгул 1 июля 2026
-
-/dayoff 15.08.2026
-→ Отгул 15 августа 2026
-
-💡 *Важно:*
-• Отгул можно добавить на любые даты
-• Нельзя добавить на выходной день
-• Нельзя пересекаться с другими отпусками/больничными`)
-		msg.ParseMode = "Markdown"
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID,
+			tr.T("absence.day_off_usage"))
+		msg.Format = messenger.FormatMarkdown
+		h.client.Send(msg)
 		return
 	}
 
 	// Парсим дату
-	date, err := parseDate(args)
+	date, err := parseDate(args, h.clock.Now())
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка парсинга даты: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("absence.date_invalid", err))
+		h.client.Send(msg)
 		return
 	}
 
 	// Добавляем отгул
-	_, err = h.absenceService.AddDayOff(uint(user.ID), date)
+	_, err = h.absenceService.AddDayOff(uint(user.ID), date, models.ChatActor(chatID))
 	if err != nil {
 		logrus.WithError(err).Error("Failed to add day off")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка добавления отгула: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("absence.day_off_failed", err))
+		h.client.Send(msg)
 		return
 	}
 	
-	response := fmt.Sprintf(
-		`✅ Отгул добавлен!
+	response := tr.T("absence.day_off_added", date)
+
+	if balance, err := h.timeBankService.GetBalance(user.ID); err == nil {
+		response += tr.T("absence.day_off_balance", i18n.SignedMinutes(balance))
+	}
+
+	msg := messenger.NewMessage(chatID, response)
+	h.client.Send(msg)
+}
+
+// addUnpaidLeave добавляет отпуск за свой счёт
+func (h *Handler) addUnpaidLeave(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	// Получаем пользователя
+	user, err := h.userService.GetUser(chatID)
+	if err != nil || user == nil {
+		logrus.WithField("chat_id", chatID).Warn("User not found for unpaid leave")
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
+		return
+	}
+
+	if args == "" {
+		msg := messenger.NewMessage(chatID,
+			tr.T("absence.unpaid_usage", h.absenceCreditDescription(tr, models.AbsenceTypeUnpaidLeave)))
+		msg.Format = messenger.FormatMarkdown
+		h.client.Send(msg)
+		return
+	}
+
+	// Парсим даты
+	parts := strings.Fields(args)
+	if len(parts) != 2 {
+		msg := messenger.NewMessage(chatID, tr.T("absence.unpaid_format"))
+		h.client.Send(msg)
+		return
+	}
+
+	startDate, err := parseDate(parts[0], h.clock.Now())
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("absence.start_date_invalid", err))
+		h.client.Send(msg)
+		return
+	}
+
+	endDate, err := parseDate(parts[1], h.clock.Now())
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("absence.end_date_invalid", err))
+		h.client.Send(msg)
+		return
+	}
 
-🎯 Дата: %s
-⏰ Часы: 8:40
+	// Добавляем отпуск за свой счёт
+	_, err = h.absenceService.AddUnpaidLeave(uint(user.ID), startDate, endDate, models.ChatActor(chatID))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to add unpaid leave")
+		msg := messenger.NewMessage(chatID, tr.T("absence.unpaid_failed", err))
+		h.client.Send(msg)
+		return
+	}
+
+	// Подсчитываем количество дней
+	days := int(endDate.Sub(startDate).Hours()/24) + 1
+
+	response := tr.T("absence.unpaid_added", startDate, endDate, days,
+		h.absenceCreditDescription(tr, models.AbsenceTypeUnpaidLeave))
+
+	msg := messenger.NewMessage(chatID, response)
+	h.client.Send(msg)
+}
+
+// addTruancy отмечает прогул пользователя (только для админов)
+func (h *Handler) addTruancy(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	parts := strings.Fields(args)
+	if len(parts) != 2 {
+		msg := messenger.NewMessage(chatID,
+			tr.T("absence.truancy_usage", h.absenceCreditDescription(tr, models.AbsenceTypeTruancy)))
+		h.client.Send(msg)
+		return
+	}
+
+	targetChatID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("user.invalid_id"))
+		h.client.Send(msg)
+		return
+	}
+
+	date, err := parseDate(parts[1], h.clock.Now())
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("absence.date_invalid", err))
+		h.client.Send(msg)
+		return
+	}
 
-📋 Этот день будет засчитан как рабочий (8ч 40м).`,
-		date.Format("02.01.2006"),
-	)
+	targetUser, err := h.userService.GetManagedUser(chatID, targetChatID, models.PermAbsencesMark)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	_, err = h.absenceService.AddTruancy(targetUser.ID, date, models.ChatActor(chatID))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to add truancy")
+		msg := messenger.NewMessage(chatID, tr.T("absence.truancy_failed", err))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("absence.truancy_marked", targetUser.FirstName, targetUser.LastName, date,
+		h.absenceCreditDescription(tr, models.AbsenceTypeTruancy)))
+	h.client.Send(msg)
+}
 
-	msg := tgbotapi.NewMessage(chatID, response)
-	h.client.Bot.Send(msg)
+// absenceCreditDescription описывает, как тип отсутствия учитывается в статистике
+func (h *Handler) absenceCreditDescription(tr *i18n.Localizer, absenceType string) string {
+	switch h.config.WorkPolicy().AbsenceCreditMode(absenceType) {
+	case models.AbsenceCreditReducePlan:
+		return tr.T("absence.credit.reduce_plan")
+	case models.AbsenceCreditNone:
+		return tr.T("absence.credit.none")
+	default:
+		return tr.T("absence.credit.full")
+	}
 }
 
 // showMyAbsences показывает мои отпуска/больничные/отгулы
-func (h *Handler) showMyAbsences(message *tgbotapi.Message, args string) {
-	chatID := message.Chat.ID
+func (h *Handler) showMyAbsences(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	// Получаем пользователя
 	user, err := h.userService.GetUser(chatID)
 	if err != nil || user == nil {
 		logrus.WithField("chat_id", chatID).Warn("User not found for absences")
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
 		return
 	}
 
@@ -270,14 +329,14 @@ func (h *Handler) showMyAbsences(message *tgbotapi.Message, args string) {
 	periods, err := h.absenceService.GetUserAbsences(uint(user.ID))
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get user absences")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка получения данных: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("absence.list_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
 	if len(periods) == 0 {
-		msg := tgbotapi.NewMessage(chatID, "📭 У вас нет запланированных отпусков, больничных или отгулов.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("absence.list_empty"))
+		h.client.Send(msg)
 		return
 	}
 
@@ -285,7 +344,9 @@ func (h *Handler) showMyAbsences(message *tgbotapi.Message, args string) {
 	vacations := []models.AbsencePeriod{}
 	sickLeaves := []models.AbsencePeriod{}
 	dayOffs := []models.AbsencePeriod{}
-	
+	unpaidLeaves := []models.AbsencePeriod{}
+	truancies := []models.AbsencePeriod{}
+
 	for _, period := range periods {
 		switch period.Type {
 		case models.AbsenceTypeVacation:
@@ -294,42 +355,59 @@ func (h *Handler) showMyAbsences(message *tgbotapi.Message, args string) {
 			sickLeaves = append(sickLeaves, period)
 		case models.AbsenceTypeDayOff:
 			dayOffs = append(dayOffs, period)
+		case models.AbsenceTypeUnpaidLeave:
+			unpaidLeaves = append(unpaidLeaves, period)
+		case models.AbsenceTypeTruancy:
+			truancies = append(truancies, period)
 		}
 	}
 
-	response := "📋 *Мои периоды отсутствия:*\n\n"
+	response := tr.T("absence.list_title") + "\n\n"
 
 	// Отпуска
 	if len(vacations) > 0 {
-		response += "🏖️ *Отпуска:*\n"
+		response += tr.T("absence.list_vacations") + "\n"
 		for _, v := range vacations {
 			days := int(v.EndDate.Sub(v.StartDate).Hours()/24) + 1
-			response += fmt.Sprintf("• %s - %s (%d дней)\n", 
-				v.StartDate.Format("02.01.2006"), 
-				v.EndDate.Format("02.01.2006"),
-				days)
+			response += fmt.Sprintf("• %s - %s (%s)\n", tr.Date(v.StartDate), tr.Date(v.EndDate), tr.N("count.days", days))
 		}
 		response += "\n"
 	}
 
 	// Больничные
 	if len(sickLeaves) > 0 {
-		response += "🏥 *Больничные:*\n"
+		response += tr.T("absence.list_sick_leaves") + "\n"
 		for _, s := range sickLeaves {
 			days := int(s.EndDate.Sub(s.StartDate).Hours()/24) + 1
-			response += fmt.Sprintf("• %s - %s (%d дней)\n", 
-				s.StartDate.Format("02.01.2006"), 
-				s.EndDate.Format("02.01.2006"),
-				days)
+			response += fmt.Sprintf("• %s - %s (%s)\n", tr.Date(s.StartDate), tr.Date(s.EndDate), tr.N("count.days", days))
 		}
 		response += "\n"
 	}
 
 	// Отгулы
 	if len(dayOffs) > 0 {
-		response += "🎯 *Отгулы:*\n"
+		response += tr.T("absence.list_day_offs") + "\n"
 		for _, d := range dayOffs {
-			response += fmt.Sprintf("• %s\n", d.StartDate.Format("02.01.2006"))
+			response += "• " + tr.Date(d.StartDate) + "\n"
+		}
+		response += "\n"
+	}
+
+	// Отпуска за свой счёт
+	if len(unpaidLeaves) > 0 {
+		response += tr.T("absence.list_unpaid_leaves") + "\n"
+		for _, u := range unpaidLeaves {
+			days := int(u.EndDate.Sub(u.StartDate).Hours()/24) + 1
+			response += fmt.Sprintf("• %s - %s (%s)\n", tr.Date(u.StartDate), tr.Date(u.EndDate), tr.N("count.days", days))
+		}
+		response += "\n"
+	}
+
+	// Прогулы
+	if len(truancies) > 0 {
+		response += tr.T("absence.list_truancies") + "\n"
+		for _, t := range truancies {
+			response += "• " + tr.Date(t.StartDate) + "\n"
 		}
 	}
 
@@ -344,18 +422,28 @@ func (h *Handler) showMyAbsences(message *tgbotapi.Message, args string) {
 		totalSickDays += int(s.EndDate.Sub(s.StartDate).Hours()/24) + 1
 	}
 
-	response += "\n📊 *Статистика:*\n"
-	response += fmt.Sprintf("• Всего отпускных дней: %d\n", totalVacationDays)
-	response += fmt.Sprintf("• Всего больничных дней: %d\n", totalSickDays)
-	response += fmt.Sprintf("• Всего отгулов: %d\n", len(dayOffs))
+	response += "\n" + tr.T("absence.list_stats") + "\n"
+	response += tr.T("absence.list_total_vacation", totalVacationDays) + "\n"
+	response += tr.T("absence.list_total_sick", totalSickDays) + "\n"
+	response += tr.T("absence.list_total_day_offs", len(dayOffs)) + "\n"
+	if len(unpaidLeaves) > 0 {
+		totalUnpaidDays := 0
+		for _, u := range unpaidLeaves {
+			totalUnpaidDays += int(u.EndDate.Sub(u.StartDate).Hours()/24) + 1
+		}
+		response += tr.T("absence.list_total_unpaid", totalUnpaidDays) + "\n"
+	}
+	if len(truancies) > 0 {
+		response += tr.T("absence.list_total_truancies", len(truancies)) + "\n"
+	}
 
-	msg := tgbotapi.NewMessage(chatID, response)
-	msg.ParseMode = "Markdown"
-	h.client.Bot.Send(msg)
+	msg := messenger.NewMessage(chatID, response)
+	msg.Format = messenger.FormatMarkdown
+	h.client.Send(msg)
 }
 
-// parseDate парсит дату из строки
-func parseDate(dateStr string) (time.Time, error) {
+// parseDate парсит дату из строки в часовом поясе now
+func parseDate(dateStr string, now time.Time) (time.Time, error) {
 	// Пробуем разные форматы
 	formats := []string{
 		"02.01.2006",
@@ -365,15 +453,14 @@ func parseDate(dateStr string) (time.Time, error) {
 	}
 
 	for _, format := range formats {
-		if t, err := time.Parse(format, dateStr); err == nil {
+		if t, err := time.ParseInLocation(format, dateStr, now.Location()); err == nil {
 			// Если указан только день и месяц, добавляем текущий год
 			if !strings.Contains(format, "2006") {
-				now := time.Now()
-				t = time.Date(now.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+				t = clock.Date(now.Year(), t.Month(), t.Day(), now.Location())
 			}
 			return t, nil
 		}
 	}
 
-	return time.Time{}, fmt.Errorf("неверный формат даты. Используйте ДД.ММ.ГГГГ или ДД.ММ")
+	return time.Time{}, i18n.Errorf("date.invalid_format")
 }
\ No newline at end of file
@@ -5,110 +5,83 @@ import (
 	"strconv"
 	"strings"
 	"work-schedule-bot/internal/models"
-
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"work-schedule-bot/pkg/messenger"
 )
 
-// showAllUsers показывает всех пользователей
-func (h *Handler) showAllUsers(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
+// showAllUsers показывает пользователей (руководителю - только его команду)
+func (h *Handler) showAllUsers(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
-	// Проверяем права доступа
-	isAdmin, err := h.userService.IsAdmin(chatID)
+	users, err := h.userService.GetScopedUsers(chatID, models.PermUsersView)
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка проверки прав доступа: "+err.Error())
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	if !isAdmin {
-		msg := tgbotapi.NewMessage(chatID, "❌ Доступ запрещен. Эта команда только для администраторов.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("users.list_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
-	allUsers, err := h.userService.FormatAllUsers()
-	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка получения списка пользователей: "+err.Error())
-		h.client.Bot.Send(msg)
-		return
+	title := tr.T("users.all_title")
+	if actor, err := h.userService.GetUser(chatID); err == nil && !actor.HasCompanyScope() {
+		title = tr.T("users.team_title")
 	}
 
-	msg := tgbotapi.NewMessage(chatID, allUsers)
-	h.client.Bot.Send(msg)
+	msg := messenger.NewMessage(chatID, h.userService.FormatUsers(tr, title, users))
+	h.client.Send(msg)
 }
 
-// showStats показывает статистику (только для админов)
-func (h *Handler) showStats(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
+// showStats показывает статистику (по всей компании или по команде руководителя)
+func (h *Handler) showStats(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
-	// Проверяем права доступа
-	isAdmin, err := h.userService.IsAdmin(chatID)
+	users, err := h.userService.GetScopedUsers(chatID, models.PermUsersView)
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка проверки прав доступа: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("users.stats_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
-	if !isAdmin {
-		msg := tgbotapi.NewMessage(chatID, "❌ Доступ запрещен. Эта команда только для администраторов.")
-		h.client.Bot.Send(msg)
-		return
+	// Считаем работающих сотрудников по ролям
+	roleCounts := make(map[string]int)
+	active := 0
+	for _, user := range users {
+		if user.CanWork() {
+			roleCounts[models.NormalizeRole(user.Role)]++
+			active++
+		}
 	}
 
-	total, admins, err := h.userService.GetStats()
-	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка получения статистики: "+err.Error())
-		h.client.Bot.Send(msg)
-		return
+	text := tr.T("users.stats_title", active)
+	for _, role := range models.GetRoles() {
+		if roleCounts[role] > 0 {
+			text += fmt.Sprintf("\n• %s: %d", role, roleCounts[role])
+		}
 	}
 
-	clients := total - admins
-
-	text := fmt.Sprintf(`📊 Статистика бота:
-
-👥 Всего пользователей: %d
-👑 Администраторов: %d
-👤 Клиентов: %d`,
-		total, admins, clients)
-
-	msg := tgbotapi.NewMessage(chatID, text)
-	h.client.Bot.Send(msg)
+	msg := messenger.NewMessage(chatID, text)
+	h.client.Send(msg)
 }
 
 // showAdmins показывает всех администраторов (только для админов)
-func (h *Handler) showAdmins(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
-
-	// Проверяем права доступа
-	isAdmin, err := h.userService.IsAdmin(chatID)
-	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка проверки прав доступа: "+err.Error())
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	if !isAdmin {
-		msg := tgbotapi.NewMessage(chatID, "❌ Доступ запрещен. Эта команда только для администраторов.")
-		h.client.Bot.Send(msg)
-		return
-	}
+func (h *Handler) showAdmins(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	admins, err := h.userService.GetAdmins()
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка получения списка администраторов: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("admins.list_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
 	if len(admins) == 0 {
-		msg := tgbotapi.NewMessage(chatID, "👑 Список администраторов пуст.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("admins.empty"))
+		h.client.Send(msg)
 		return
 	}
 
 	var lines []string
-	lines = append(lines, "👑 Администраторы:")
+	lines = append(lines, tr.T("admins.title"))
 	lines = append(lines, "")
 
 	for i, admin := range admins {
@@ -126,160 +99,147 @@ func (h *Handler) showAdmins(message *tgbotapi.Message) {
 		lines = append(lines, adminInfo)
 	}
 
-	msg := tgbotapi.NewMessage(chatID, strings.Join(lines, "\n"))
-	h.client.Bot.Send(msg)
+	msg := messenger.NewMessage(chatID, strings.Join(lines, "\n"))
+	h.client.Send(msg)
 }
 
 // promoteToAdmin назначает пользователя администратором
-func (h *Handler) promoteToAdmin(message *tgbotapi.Message, args string) {
-	chatID := message.Chat.ID
-
-	// Проверяем права доступа
-	isAdmin, err := h.userService.IsAdmin(chatID)
-	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка проверки прав доступа: "+err.Error())
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	if !isAdmin {
-		msg := tgbotapi.NewMessage(chatID, "❌ Доступ запрещен. Эта команда только для администраторов.")
-		h.client.Bot.Send(msg)
-		return
-	}
+func (h *Handler) promoteToAdmin(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	if args == "" {
-		msg := tgbotapi.NewMessage(chatID, "❌ Укажите ID пользователя.\nПример: /promote 123456789")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("admins.promote_usage"))
+		h.client.Send(msg)
 		return
 	}
 
 	targetChatID, err := strconv.ParseInt(args, 10, 64)
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Неверный формат ID.\nID должен быть числом.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("user.invalid_id"))
+		h.client.Send(msg)
 		return
 	}
 
 	err = h.userService.UpdateRole(chatID, targetChatID, "admin")
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка назначения администратора: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("admins.promote_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
-	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("✅ Пользователь с ID %d теперь администратор!", targetChatID))
-	h.client.Bot.Send(msg)
+	msg := messenger.NewMessage(chatID, tr.T("admins.promoted", targetChatID))
+	h.client.Send(msg)
 }
 
 // demoteToClient снимает пользователя с должности администратора
-func (h *Handler) demoteToClient(message *tgbotapi.Message, args string) {
-	chatID := message.Chat.ID
-
-	// Проверяем права доступа
-	isAdmin, err := h.userService.IsAdmin(chatID)
-	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка проверки прав доступа: "+err.Error())
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	if !isAdmin {
-		msg := tgbotapi.NewMessage(chatID, "❌ Доступ запрещен. Эта команда только для администраторов.")
-		h.client.Bot.Send(msg)
-		return
-	}
+func (h *Handler) demoteToClient(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	if args == "" {
-		msg := tgbotapi.NewMessage(chatID, "❌ Укажите ID пользователя.\nПример: /demote 123456789")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("admins.demote_usage"))
+		h.client.Send(msg)
 		return
 	}
 
 	targetChatID, err := strconv.ParseInt(args, 10, 64)
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Неверный формат ID.\nID должен быть числом.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("user.invalid_id"))
+		h.client.Send(msg)
 		return
 	}
 
 	// Не позволяем снять главного администратора из конфига
-	if targetChatID == h.config.BaseAdminChatID && h.config.BaseAdminChatID != 0 {
-		msg := tgbotapi.NewMessage(chatID, "❌ Нельзя снять главного администратора, заданного в конфигурации!")
-		h.client.Bot.Send(msg)
+	if targetChatID == h.config.Telegram.AdminChatID && h.config.Telegram.AdminChatID != 0 {
+		msg := messenger.NewMessage(chatID, tr.T("admins.demote_base_admin"))
+		h.client.Send(msg)
 		return
 	}
 
-	err = h.userService.UpdateRole(chatID, targetChatID, "client")
+	err = h.userService.UpdateRole(chatID, targetChatID, models.Role(models.RoleEmployee))
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка снятия администратора: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("admins.demote_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
-	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("✅ Пользователь с ID %d теперь клиент!", targetChatID))
-	h.client.Bot.Send(msg)
+	msg := messenger.NewMessage(chatID, tr.T("admins.demoted", targetChatID))
+	h.client.Send(msg)
 }
 
 // setUserRole изменяет роль пользователя
-func (h *Handler) setUserRole(message *tgbotapi.Message, args string) {
-	chatID := message.Chat.ID
-
-	// Проверяем права доступа
-	isAdmin, err := h.userService.IsAdmin(chatID)
-	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка проверки прав доступа: "+err.Error())
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	if !isAdmin {
-		msg := tgbotapi.NewMessage(chatID, "❌ Доступ запрещен. Эта команда только для администраторов.")
-		h.client.Bot.Send(msg)
-		return
-	}
+func (h *Handler) setUserRole(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	parts := strings.Fields(args)
 	if len(parts) != 2 {
-		msg := tgbotapi.NewMessage(chatID, "❌ Неверный формат.\nПример: /setrole 123456789 admin\nДоступные роли: admin, client")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("role.set_usage", strings.Join(models.GetRoles(), ", ")))
+		h.client.Send(msg)
 		return
 	}
 
 	targetChatID, err := strconv.ParseInt(parts[0], 10, 64)
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Неверный формат ID.\nID должен быть числом.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("user.invalid_id"))
+		h.client.Send(msg)
 		return
 	}
 
-	roleStr := strings.ToLower(parts[1])
-	var role models.Role
-
-	switch roleStr {
-	case "admin":
-		role = "admin"
-	case "client":
-		// Не позволяем изменить роль главного администратора на client
-		if targetChatID == h.config.BaseAdminChatID && h.config.BaseAdminChatID != 0 {
-			msg := tgbotapi.NewMessage(chatID, "❌ Нельзя изменить роль главного администратора, заданного в конфигурации!")
-			h.client.Bot.Send(msg)
-			return
-		}
-		role = "client"
-	default:
-		msg := tgbotapi.NewMessage(chatID, "❌ Неизвестная роль.\nДоступные роли: admin, client")
-		h.client.Bot.Send(msg)
+	roleStr := models.NormalizeRole(strings.ToLower(parts[1]))
+	if !models.IsValidRole(roleStr) {
+		msg := messenger.NewMessage(chatID, tr.T("role.unknown_reply", strings.Join(models.GetRoles(), ", ")))
+		h.client.Send(msg)
 		return
 	}
 
+	// Не позволяем изменить роль главного администратора
+	if roleStr != models.RoleAdmin && targetChatID == h.config.Telegram.AdminChatID && h.config.Telegram.AdminChatID != 0 {
+		msg := messenger.NewMessage(chatID, tr.T("role.base_admin"))
+		h.client.Send(msg)
+		return
+	}
+	role := models.Role(roleStr)
+
 	err = h.userService.UpdateRole(chatID, targetChatID, role)
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка изменения роли: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("role.set_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
-	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("✅ Роль пользователя с ID %d изменена на '%s'!", targetChatID, role))
-	h.client.Bot.Send(msg)
+	msg := messenger.NewMessage(chatID, tr.T("role.set", targetChatID, role))
+	h.client.Send(msg)
+}
+
+// showRoles показывает роли и их права
+func (h *Handler) showRoles(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	var lines []string
+	lines = append(lines, tr.T("role.list_title"))
+
+	for _, role := range models.GetRoles() {
+		lines = append(lines, "")
+		lines = append(lines, fmt.Sprintf("• %s", role))
+
+		permissions := models.GetRolePermissions(role)
+		if len(permissions) == 0 {
+			lines = append(lines, tr.T("role.own_data_only"))
+			continue
+		}
+
+		for _, permission := range permissions {
+			lines = append(lines, fmt.Sprintf("   %s", permission))
+		}
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, tr.T("role.list_assign_hint"))
+	lines = append(lines, tr.T("role.list_team_lead_hint"))
+
+	msg := messenger.NewMessage(chatID, strings.Join(lines, "\n"))
+	h.client.Send(msg)
 }
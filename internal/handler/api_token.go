@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/messenger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// showAPITokens показывает выпущенные токены API (админы)
+func (h *Handler) showAPITokens(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	tokens, err := h.apiTokenService.GetTokens()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get API tokens")
+		msg := messenger.NewMessage(chatID, tr.T("apitoken.list_failed", err))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, h.apiTokenService.FormatTokens(tr, tokens))
+	h.client.Send(msg)
+}
+
+// addAPIToken выпускает токен для внешней системы (админы). Значение показывается один раз.
+func (h *Handler) addAPIToken(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	if strings.TrimSpace(args) == "" {
+		msg := messenger.NewMessage(chatID, tr.T("apitoken.add_usage"))
+		h.client.Send(msg)
+		return
+	}
+
+	value, token, err := h.apiTokenService.CreateToken(args, chatID)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("apitoken.created", token.Name, token.ID, value, token.ID))
+	h.client.Send(msg)
+}
+
+// revokeAPIToken отзывает токен API (админы)
+func (h *Handler) revokeAPIToken(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	id, err := strconv.ParseUint(strings.TrimSpace(args), 10, 32)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("apitoken.revoke_usage"))
+		h.client.Send(msg)
+		return
+	}
+
+	token, err := h.apiTokenService.RevokeToken(uint(id), models.ChatActor(chatID))
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("apitoken.revoked_reply", token.Name, token.ID))
+	h.client.Send(msg)
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/messenger"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// auditChatLimit - сколько последних записей журнала показывается в чате
+	auditChatLimit = 10
+	// auditExportLimit - наибольшее число записей в выгрузке журнала
+	auditExportLimit = 10000
+)
+
+// auditQuery - разобранные аргументы /audit и /auditexport
+type auditQuery struct {
+	filter repository.AuditFilter
+	chatID int64 // сотрудник, изменения которого нужны (0 - все)
+}
+
+// parseAuditArgs разбирает аргументы вида "[chat_id|сущность[:id]] [период]" в любом порядке.
+// Период - дата ДД.ММ.ГГГГ, месяц ММ.ГГГГ или диапазон дат через дефис.
+func parseAuditArgs(args string, now time.Time) (*auditQuery, error) {
+	query := &auditQuery{}
+	for _, arg := range strings.Fields(args) {
+		if chatID, err := strconv.ParseInt(arg, 10, 64); err == nil {
+			query.chatID = chatID
+			continue
+		}
+
+		entityType, idStr, hasID := strings.Cut(strings.ToLower(arg), ":")
+		if models.IsValidAuditEntity(entityType) {
+			query.filter.EntityType = entityType
+			if hasID {
+				id, err := strconv.ParseUint(idStr, 10, 32)
+				if err != nil {
+					return nil, i18n.Errorf("audit.invalid_entity_id", idStr)
+				}
+				query.filter.EntityID = uint(id)
+			}
+			continue
+		}
+
+		from, to, err := parseAuditPeriod(arg, now)
+		if err != nil {
+			return nil, i18n.Errorf("audit.invalid_arg", arg)
+		}
+		query.filter.From, query.filter.To = from, to
+	}
+	return query, nil
+}
+
+// parseAuditPeriod возвращает границы периода [from, to)
+func parseAuditPeriod(arg string, now time.Time) (time.Time, time.Time, error) {
+	if month, err := time.ParseInLocation("01.2006", arg, now.Location()); err == nil {
+		return month, month.AddDate(0, 1, 0), nil
+	}
+	if date, err := parseDate(arg, now); err == nil {
+		return date, date.AddDate(0, 0, 1), nil
+	}
+
+	// Диапазон: пробуем каждый дефис как разделитель, так как дефис бывает и внутри даты
+	for i := strings.Index(arg, "-"); i >= 0; {
+		from, errFrom := parseDate(arg[:i], now)
+		to, errTo := parseDate(arg[i+1:], now)
+		if errFrom == nil && errTo == nil && !to.Before(from) {
+			return from, to.AddDate(0, 0, 1), nil
+		}
+
+		next := strings.Index(arg[i+1:], "-")
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return time.Time{}, time.Time{}, i18n.Errorf("date.invalid_format")
+}
+
+// auditFilter разбирает аргументы команды журнала и находит сотрудника.
+// При ошибке отправляет ответ сам и возвращает nil.
+func (h *Handler) auditFilter(message *messenger.Message, args string, limit int) *repository.AuditFilter {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	query, err := parseAuditArgs(args, h.clock.Now())
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.Error(err)+"\n\n"+tr.T("usage.audit"))
+		h.client.Send(msg)
+		return nil
+	}
+
+	if query.chatID != 0 {
+		user, err := h.userService.GetUser(query.chatID)
+		if err != nil || user == nil {
+			msg := messenger.NewMessage(chatID, tr.T("error.reply", tr.T("user.not_found_id", query.chatID)))
+			h.client.Send(msg)
+			return nil
+		}
+		query.filter.UserID = &user.ID
+	}
+
+	query.filter.Limit = limit
+	return &query.filter
+}
+
+// showAudit показывает последние изменения данных (админы)
+func (h *Handler) showAudit(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	filter := h.auditFilter(message, args, auditChatLimit)
+	if filter == nil {
+		return
+	}
+
+	entries, err := h.auditService.Find(*filter)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get audit log")
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	formatted, err := h.auditService.FormatEntries(tr, entries)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to format audit log")
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, formatted)
+	h.client.Send(msg)
+}
+
+// exportAudit выгружает журнал изменений в CSV (админы)
+func (h *Handler) exportAudit(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	filter := h.auditFilter(message, args, auditExportLimit)
+	if filter == nil {
+		return
+	}
+
+	entries, err := h.auditService.Find(*filter)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get audit log for export")
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	if len(entries) == 0 {
+		h.client.Send(messenger.NewMessage(chatID, tr.T("audit.empty")))
+		return
+	}
+
+	data, err := h.auditService.ExportCSV(tr, entries)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to export audit log")
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	name := "audit-" + h.clock.Now().Format("20060102-150405") + ".csv"
+	msg := messenger.NewDocument(chatID, name, data, tr.N("audit.export_caption", len(entries)))
+	if err := h.client.Send(msg); err != nil {
+		logrus.WithError(err).Error("Failed to send audit export")
+	}
+}
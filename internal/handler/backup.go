@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"work-schedule-bot/pkg/messenger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxBackupSendSize - наибольший файл, который бот может отправить через Bot API
+const maxBackupSendSize = 50 << 20
+
+// sendBackup создает снимок базы и отправляет его администратору документом
+func (h *Handler) sendBackup(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	snapshot, err := h.backupService.Snapshot()
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("backup.failed", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	if len(snapshot.Data) > maxBackupSendSize {
+		msg := messenger.NewMessage(chatID, tr.T("backup.too_large", len(snapshot.Data)>>20, maxBackupSendSize>>20))
+		h.client.Send(msg)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"chat_id": chatID,
+		"file":    snapshot.Name,
+		"size":    len(snapshot.Data),
+	}).Info("Backup sent")
+
+	msg := messenger.NewDocument(chatID, snapshot.Name, snapshot.Data, tr.T("backup.caption", tr.DateTime(snapshot.CreatedAt)))
+	if err := h.client.Send(msg); err != nil {
+		logrus.WithError(err).Error("Failed to send backup")
+		h.client.Send(messenger.NewMessage(chatID, tr.T("backup.send_failed", err)))
+	}
+}
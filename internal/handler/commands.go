@@ -1,28 +1,34 @@
 package handler
 
 import (
-	"fmt"
 	"strings"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/messenger"
 
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/sirupsen/logrus"
 )
 
-func (h *Handler) sendEchoMessage(message *tgbotapi.Message) {
+func (h *Handler) sendEchoMessage(message *messenger.Message) {
 	responseText := message.Text
 
+	tr := h.localizer(message)
 	if responseText == "" {
-		responseText = "Я получил ваше сообщение, но не могу его повторить 😊"
+		responseText = tr.T("echo.empty")
 	}
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, "🔁 Эхо: "+responseText)
-	h.client.Bot.Send(msg)
+	msg := messenger.NewMessage(message.ChatID, tr.T("echo.reply", responseText))
+	h.client.Send(msg)
 }
 
 // internal/bot/handler/commands.go
-func (h *Handler) handleCommand(message *tgbotapi.Message) {
-	command := message.Command()
-	args := message.CommandArguments()
+func (h *Handler) handleCommand(message *messenger.Message) {
+	command := message.Command
+	args := message.Args
+
+	// Проверяем права по таблице команд (permissions.go)
+	if !h.authorize(message, command) {
+		return
+	}
 
 	switch command {
 	case "start":
@@ -31,18 +37,36 @@ func (h *Handler) handleCommand(message *tgbotapi.Message) {
 		h.sendHelpMessage(message)
 	case "helpadmin":
 		h.sendAdminHelpMessage(message)
+	case "cancel":
+		h.cancelDialog(message)
 	case "createprofile":
 		h.startProfileCreation(message)
 	case "myprofile":
 		h.showProfile(message)
 	case "updateprofile":
 		h.startProfileUpdate(message)
+	case "settimezone":
+		h.setTimezone(message, args)
+	case "language":
+		h.setLanguage(message, args)
 	case "deleteprofile":
 		h.deleteProfile(message)
 	case "allusers":
 		h.showAllUsers(message)
 	case "stats":
 		h.showStats(message)
+	case "pending":
+		h.showPendingUsers(message)
+	case "approve":
+		h.approveUser(message, args)
+	case "reject":
+		h.rejectUser(message, args)
+	case "deactivate":
+		h.deactivateUser(message, args)
+	case "reactivate":
+		h.reactivateUser(message, args)
+	case "purgeuser":
+		h.purgeUser(message, args)
 	case "setrole":
 		h.setUserRole(message, args)
 	case "promote":
@@ -51,6 +75,24 @@ func (h *Handler) handleCommand(message *tgbotapi.Message) {
 		h.demoteToClient(message, args)
 	case "admins":
 		h.showAdmins(message)
+	case "roles":
+		h.showRoles(message)
+
+	// Отделы и команды
+	case "teams":
+		h.showTeams(message)
+	case "adddepartment":
+		h.addDepartment(message, args)
+	case "deletedepartment":
+		h.deleteDepartment(message, args)
+	case "addteam":
+		h.addTeam(message, args)
+	case "deleteteam":
+		h.deleteTeam(message, args)
+	case "setteam":
+		h.setUserTeam(message, args)
+	case "setemployee":
+		h.setEmployeeField(message, args)
 	case "echo":
 		h.sendEchoWithArgs(message, args)
 
@@ -81,6 +123,18 @@ func (h *Handler) handleCommand(message *tgbotapi.Message) {
 		h.getMonthlyStat(message, args)
 	case "currentstat":
 		h.getCurrentMonthStat(message)
+	case "userstat":
+		h.getUserMonthlyStat(message, args)
+	case "teamstats":
+		h.getTeamMonthlyStats(message, args)
+	case "timesheet":
+		h.exportTimesheet(message, args)
+	case "recalcstats":
+		h.recalcMonthlyStats(message, args)
+	case "importsessions":
+		h.importSessions(message)
+	case "fixsession":
+		h.correctSession(message, args)
 
 	// Команды для работы (все пользователи)
 	case "in", "startwork":
@@ -105,231 +159,110 @@ func (h *Handler) handleCommand(message *tgbotapi.Message) {
 		h.addSickLeave(message, args)
 	case "dayoff":
 		h.addDayOff(message, args)
+	case "unpaid", "unpaidleave":
+		h.addUnpaidLeave(message, args)
+	case "truancy":
+		h.addTruancy(message, args)
 	case "myabsences":
 		h.showMyAbsences(message, args)
+	case "teamcalendar":
+		h.showTeamCalendar(message, args)
+
+	// Банк времени
+	case "balance":
+		h.showBalance(message, args)
+	case "closemonth":
+		h.closeMonth(message, args)
+	case "bankcaps":
+		h.bankCaps(message, args)
+	case "bankadjust":
+		h.bankAdjust(message, args)
+
+	// Токены API
+	case "apitokens":
+		h.showAPITokens(message)
+	case "addapitoken":
+		h.addAPIToken(message, args)
+	case "revokeapitoken":
+		h.revokeAPIToken(message, args)
+
+	// Резервные копии
+	case "backup":
+		h.sendBackup(message)
+
+	// Журнал изменений
+	case "audit":
+		h.showAudit(message, args)
+	case "auditexport":
+		h.exportAudit(message, args)
 
 	default:
 		h.sendUnknownCommand(message)
 	}
 }
 
-func (h *Handler) sendUnknownCommand(message *tgbotapi.Message) {
-	msg := tgbotapi.NewMessage(message.Chat.ID, "❌ Неизвестная команда. Используйте /help для списка команд.")
-	h.client.Bot.Send(msg)
+func (h *Handler) sendUnknownCommand(message *messenger.Message) {
+	tr := h.localizer(message)
+	msg := messenger.NewMessage(message.ChatID, tr.T("command.unknown"))
+	h.client.Send(msg)
 }
 
-func (h *Handler) sendEchoWithArgs(message *tgbotapi.Message, args string) {
+func (h *Handler) sendEchoWithArgs(message *messenger.Message, args string) {
 	if strings.TrimSpace(args) == "" {
-		args = "Вы не указали текст для эхо!"
+		args = h.localizer(message).T("echo.empty_args")
 	}
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, "📢: "+args)
-	h.client.Bot.Send(msg)
+	msg := messenger.NewMessage(message.ChatID, "📢: "+args)
+	h.client.Send(msg)
 }
 
-// В sendStartMessage добавляем команды для админов:
-func (h *Handler) sendStartMessage(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
-
-	text := `📋 Доступные команды:
-
-👤 Профиль:
-/createprofile - Создать профиль (ФИО)
-/myprofile - Показать мой профиль
-/updateprofile - Обновить профиль
-/deleteprofile - Удалить профиль
-
-⏰ Учет рабочего времени:
-/in - Начать рабочий день (можно указать дату и время, подробности /helptime)
-/out - Завершить рабочий день (можно указать дату и время, подробности /helptime)
-/helptime - Показать справку по указанию времени для начача\конца рабочего дня
-/today - Информация о сегодняшнем рабочем дне
-/status - Текущий статус работы
-/history [N] - История рабочих дней (последние N, по умолчанию 10)
-/monthwork [месяц] - Рабочие дни за конкретный месяц
-/monthwork [год месяц] - Рабочие дни за месяц и год
-/checkday [дата] - Проверить, является ли день рабочим
-    Пример: /checkday 01.05.2026 или /checkday 01.05
-
-🏖️ Отпуска/Больничные/Отгулы:
-/vacation дата_начала дата_окончания - Добавить отпуск
-    Пример: /vacation 01.07.2026 14.07.2026
-/sick дата_начала дата_окончания - Добавить больничный
-    Пример: /sick 01.07.2026 07.07.2026
-/dayoff дата - Добавить отгул
-    Пример: /dayoff 15.08.2026
-/myabsences - Мои периоды отсутствия
-
-📊 Статистика работы:
-/mystats - Вся моя статистика
-/stat [месяц] - Статистика за конкретный месяц
-/stat [год месяц] - Статистика за месяц и год
-/currentstat - Статистика за текущий месяц
-
-🛠 Утилиты:
-/start - Начать работу с ботом
-/help - Показать это сообщение
-/echo [текст] - Отправить эхо с текстом
-
-💡 Как пользоваться:
-1. Создайте профиль командой /createprofile
-2. Начинайте рабочий день командой /in
-3. Завершайте рабочий день командой /out
-4. Добавляйте отпуска/больничные командой /vacation или /sick
-5. Следите за статистикой командой /mystats
-6. Администраторы настраивают графики работы
-
-📈 Автоматические обновления:
-• При завершении рабочего дня статистика обновляется автоматически
-• При добавлении отпуска/больничного дни засчитываются как отработанные`
-
-
-	msg := tgbotapi.NewMessage(chatID, text)
-	h.client.Bot.Send(msg)
+func (h *Handler) sendStartMessage(message *messenger.Message) {
+	msg := messenger.NewMessage(message.ChatID, h.localizer(message).T("help.commands"))
+	h.client.Send(msg)
 }
 
-func (h *Handler) sendHelpMessage(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
-
-	text := `📋 Доступные команды:
-
-👤 Профиль:
-/createprofile - Создать профиль (ФИО)
-/myprofile - Показать мой профиль
-/updateprofile - Обновить профиль
-/deleteprofile - Удалить профиль
-
-⏰ Учет рабочего времени:
-/in - Начать рабочий день (можно указать дату и время, подробности /helptime)
-/out - Завершить рабочий день (можно указать дату и время, подробности /helptime)
-/helptime - Показать справку по указанию времени для начача\конца рабочего дня
-/today - Информация о сегодняшнем рабочем дне
-/status - Текущий статус работы
-/history [N] - История рабочих дней (последние N, по умолчанию 10)
-/monthwork [месяц] - Рабочие дни за конкретный месяц
-/monthwork [год месяц] - Рабочие дни за месяц и год
-/checkday [дата] - Проверить, является ли день рабочим
-    Пример: /checkday 01.05.2026 или /checkday 01.05
-
-🏖️ Отпуска/Больничные/Отгулы:
-/vacation дата_начала дата_окончания - Добавить отпуск
-    Пример: /vacation 01.07.2026 14.07.2026
-/sick дата_начала дата_окончания - Добавить больничный
-    Пример: /sick 01.07.2026 07.07.2026
-/dayoff дата - Добавить отгул
-    Пример: /dayoff 15.08.2026
-/myabsences - Мои периоды отсутствия
-
-📊 Статистика работы:
-/mystats - Вся моя статистика
-/stat [месяц] - Статистика за конкретный месяц
-/stat [год месяц] - Статистика за месяц и год
-/currentstat - Статистика за текущий месяц
-
-🛠 Утилиты:
-/start - Начать работу с ботом
-/help - Показать это сообщение
-/echo [текст] - Отправить эхо с текстом
-
-💡 Как пользоваться:
-1. Создайте профиль командой /createprofile
-2. Начинайте рабочий день командой /in
-3. Завершайте рабочий день командой /out
-4. Добавляйте отпуска/больничные командой /vacation или /sick
-5. Следите за статистикой командой /mystats
-6. Администраторы настраивают графики работы
-
-📈 Автоматические обновления:
-• При завершении рабочего дня статистика обновляется автоматически
-• При добавлении отпуска/больничного дни засчитываются как отработанные`
-
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = "Markdown"
-	h.client.Bot.Send(msg)
+func (h *Handler) sendHelpMessage(message *messenger.Message) {
+	msg := messenger.NewMessage(message.ChatID, h.localizer(message).T("help.commands"))
+	msg.Format = messenger.FormatMarkdown
+	h.client.Send(msg)
 }
 
-func (h *Handler) sendAdminHelpMessage(message *tgbotapi.Message){
-	chatID := message.Chat.ID
+func (h *Handler) sendAdminHelpMessage(message *messenger.Message){
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
-	// Проверяем, является ли пользователь админом
-	isAdmin, err := h.userService.IsAdmin(chatID)
-	if err != nil {
-		logrus.WithError(err).Error("Error checking admin status")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка проверки прав доступа: "+err.Error())
-		h.client.Bot.Send(msg)
+	user, err := h.userService.GetUser(chatID)
+	if err != nil || user == nil {
+		logrus.WithField("chat_id", chatID).Warn("User not found for admin help")
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
 		return
 	}
 
-	if !isAdmin {
+	commands := formatPermittedCommands(tr, user)
+	if commands == "" {
 		logrus.WithField("chat_id", chatID).Warn("Unauthorized access to admin help commands command")
-		msg := tgbotapi.NewMessage(chatID, "❌ Доступ запрещен. Эта команда только для администраторов.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("help.admin_no_commands"))
+		h.client.Send(msg)
 		return
 	}
 
-	text := `📋 Доступные команды:
-
-👑 Администрирование:
-/allusers - Показать всех пользователей
-/stats - Статистика бота
-/admins - Показать администраторов
-/promote [ID] - Назначить администратора
-/demote [ID] - Снять администратора
-/setrole [ID] [role] - Изменить роль
-
-📅 Управление графиками:
-/addschedule [год месяц дни минуты] - Добавить график
-Пример: /addschedule 2024 12 22 480
-
-/updateschedule [ID дни минуты] - Обновить график
-Пример: /updateschedule 1 23 490
-
-/deleteschedule [ID] - Удалить график
-/getschedules - Все графики работы
-/getschedule [ID] - Показать график по ID
-/getschedule [год месяц] - Показать график по дате
-/currentschedule - График на текущий месяц
-/generateschedules [год] [минуты] - Автоматически создать графики на год
-    Пример: /generateschedules 2026 520 (8ч 40м)
-/updateallschedules - Обновить все графики на основе выходных дней`
+	text := tr.T("help.admin_title", models.NormalizeRole(user.Role), commands)
 
-	if h.config.BaseAdminChatID != 0 {
-		text += fmt.Sprintf("\n\n🔧 ID главного администратора: %d", h.config.BaseAdminChatID)
+	if user.HasPermission(models.PermRolesManage) && h.config.Telegram.AdminChatID != 0 {
+		text += "\n\n" + tr.T("help.admin_base_admin", h.config.Telegram.AdminChatID)
 	}
-	
-	msg := tgbotapi.NewMessage(chatID, text)
-	h.client.Bot.Send(msg)
-}
-
-
-func (h *Handler) showTimeFormatsHelp(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
-	helpText := `📝 *Форматы указания даты и времени:*
-
-*Дата (необязательно):*
-• dd.mm.yyyy (25.12.2023)
-• dd-mm-yyyy (25-12-2023)
 
-*Время (необязательно):*
-• hh:mm (09:30)
-• hh.mm (09.30)
-• hh-mm (09-30)
-
-*Примеры использования:*
-• /in 09:00 — начать работу сегодня в 9:00
-• /in 25.12.2023 09:30 — начать работу 25 декабря 2023 в 9:30
-• /out 18:00 — завершить работу сегодня в 18:00
-• /out 25-12-2023 18-30 — завершить работу 25 декабря 2023 в 18:30
-• /in — начать работу сейчас
-• /out — завершить работу сейчас
+	if !user.HasCompanyScope() {
+		text += "\n\n" + tr.T("help.admin_team_scope")
+	}
 
-⚠️ *Примечания:*
-• Если указать только время, будет использована сегодняшняя дата
-• *Нельзя начать работу в выходной день* согласно производственному календарю
-• Используйте /checkday [дата] чтобы проверить, является ли день рабочим
-• Год должен быть 2026 или позже`
+	msg := messenger.NewMessage(chatID, text)
+	h.client.Send(msg)
+}
 
-	msg := tgbotapi.NewMessage(chatID, helpText)
-	msg.ParseMode = "Markdown"
-	h.client.Bot.Send(msg)
-}
\ No newline at end of file
+func (h *Handler) showTimeFormatsHelp(message *messenger.Message) {
+	msg := messenger.NewMessage(message.ChatID, h.localizer(message).T("help.time_formats", h.config.WorkPolicy().MinClockInYear))
+	msg.Format = messenger.FormatMarkdown
+	h.client.Send(msg)
+}
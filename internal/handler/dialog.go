@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"time"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/messenger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// dialogStepFunc обрабатывает ответ пользователя на шаге диалога.
+// Шаг сам переводит диалог дальше (advanceDialog) или завершает его (finishDialog).
+type dialogStepFunc func(message *messenger.Message, state *models.DialogState)
+
+// dialogFlow - сценарий многошагового диалога
+type dialogFlow struct {
+	Timeout time.Duration             // время ожидания ответа на каждом шаге
+	Command string                    // команда, которой сценарий запускается заново
+	Steps   map[string]dialogStepFunc // обработчики шагов по названию шага
+}
+
+// dialogFlows возвращает сценарии диалогов по их названиям
+func (h *Handler) dialogFlows() map[string]dialogFlow {
+	return map[string]dialogFlow{
+		flowProfileCreate: {
+			Timeout: 15 * time.Minute,
+			Command: "/createprofile",
+			Steps: map[string]dialogStepFunc{
+				stepProfileFirstName:  h.profileFirstNameStep,
+				stepProfileLastName:   h.profileLastNameStep,
+				stepProfilePatronymic: h.profilePatronymicStep,
+				stepProfileTimezone:   h.profileTimezoneStep,
+			},
+		},
+		flowProfileUpdate: {
+			Timeout: 15 * time.Minute,
+			Command: "/updateprofile",
+			Steps: map[string]dialogStepFunc{
+				stepProfileName: h.profileUpdateStep,
+			},
+		},
+		flowSessionsImport: {
+			Timeout: 30 * time.Minute,
+			Command: "/importsessions",
+			Steps: map[string]dialogStepFunc{
+				stepImportConfirm: h.importConfirmStep,
+			},
+		},
+	}
+}
+
+// startDialog начинает сценарий в чате. Незавершенный диалог заменяется.
+func (h *Handler) startDialog(chatID int64, flow, step string, data interface{}) bool {
+	if err := h.dialogService.Start(chatID, flow, step, h.flows[flow].Timeout, data); err != nil {
+		logrus.WithError(err).WithField("flow", flow).Error("Failed to start dialog")
+		msg := messenger.NewMessage(chatID, h.chatLocalizer(chatID, "").T("dialog.start_failed", err))
+		h.client.Send(msg)
+		return false
+	}
+	return true
+}
+
+// advanceDialog переводит диалог на следующий шаг
+func (h *Handler) advanceDialog(state *models.DialogState, step string, data interface{}) bool {
+	if err := h.dialogService.Advance(state, step, data); err != nil {
+		logrus.WithError(err).WithField("flow", state.Flow).Error("Failed to advance dialog")
+		msg := messenger.NewMessage(state.ChatID, h.chatLocalizer(state.ChatID, "").T("dialog.advance_failed", err))
+		h.client.Send(msg)
+		return false
+	}
+	return true
+}
+
+// finishDialog завершает диалог чата
+func (h *Handler) finishDialog(chatID int64) {
+	if err := h.dialogService.Finish(chatID); err != nil {
+		logrus.WithError(err).WithField("chat_id", chatID).Error("Failed to finish dialog")
+	}
+}
+
+// handleDialog передает сообщение активному диалогу чата. Возвращает false, если диалога нет.
+func (h *Handler) handleDialog(message *messenger.Message) bool {
+	chatID := message.ChatID
+
+	state, err := h.dialogService.Current(chatID)
+	if err != nil {
+		logrus.WithError(err).WithField("chat_id", chatID).Error("Failed to get dialog state")
+		return false
+	}
+	if state == nil {
+		return false
+	}
+
+	flow, ok := h.flows[state.Flow]
+	step, stepOk := flow.Steps[state.Step]
+	if !ok || !stepOk {
+		// Сценарий удален или переименован после обновления бота
+		logrus.WithFields(logrus.Fields{
+			"chat_id": chatID,
+			"flow":    state.Flow,
+			"step":    state.Step,
+		}).Warn("Unknown dialog step, dropping dialog")
+		h.finishDialog(chatID)
+		return false
+	}
+
+	if state.IsExpired(h.clock.Now()) {
+		h.finishDialog(chatID)
+		msg := messenger.NewMessage(chatID, h.localizer(message).T("dialog.expired", flow.Command))
+		h.client.Send(msg)
+		return true
+	}
+
+	step(message, state)
+	return true
+}
+
+// cancelDialog отменяет активный диалог по команде /cancel
+func (h *Handler) cancelDialog(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	state, err := h.dialogService.Current(chatID)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.generic", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+	if state == nil {
+		msg := messenger.NewMessage(chatID, tr.T("dialog.nothing_to_cancel"))
+		h.client.Send(msg)
+		return
+	}
+
+	h.finishDialog(chatID)
+
+	msg := messenger.NewMessage(chatID, tr.T("dialog.cancelled"))
+	h.client.Send(msg)
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"sync"
+	"work-schedule-bot/pkg/messenger"
+)
+
+// seenCallbacksLimit - сколько последних ID callback-запросов помнит диспетчер
+const seenCallbacksLimit = 1024
+
+// dispatcher обрабатывает обновления параллельно, но строго последовательно в пределах одного чата:
+// на каждый чат с необработанными обновлениями запускается свой обработчик, который
+// завершается, когда очередь чата опустела.
+type dispatcher struct {
+	handle func(update messenger.Update)
+
+	mu      sync.Mutex
+	pending map[int64][]messenger.Update // очереди чатов, для которых уже запущен обработчик
+	wg      sync.WaitGroup
+
+	callbacks *callbackSet
+}
+
+func newDispatcher(handle func(update messenger.Update)) *dispatcher {
+	return &dispatcher{
+		handle:    handle,
+		pending:   make(map[int64][]messenger.Update),
+		callbacks: newCallbackSet(seenCallbacksLimit),
+	}
+}
+
+// Dispatch ставит обновление в очередь его чата
+func (d *dispatcher) Dispatch(update messenger.Update) {
+	// Повторно доставленный callback-запрос не должен выполнить действие второй раз
+	if update.Callback != nil && !d.callbacks.Add(update.Callback.ID) {
+		return
+	}
+
+	chatID := update.ChatID()
+
+	d.mu.Lock()
+	if queue, running := d.pending[chatID]; running {
+		d.pending[chatID] = append(queue, update)
+		d.mu.Unlock()
+		return
+	}
+	d.pending[chatID] = nil
+	d.mu.Unlock()
+
+	d.wg.Add(1)
+	go d.run(chatID, update)
+}
+
+// run обрабатывает обновления чата, пока его очередь не опустеет
+func (d *dispatcher) run(chatID int64, update messenger.Update) {
+	defer d.wg.Done()
+
+	for {
+		d.handle(update)
+
+		d.mu.Lock()
+		queue := d.pending[chatID]
+		if len(queue) == 0 {
+			delete(d.pending, chatID)
+			d.mu.Unlock()
+			return
+		}
+		update = queue[0]
+		d.pending[chatID] = queue[1:]
+		d.mu.Unlock()
+	}
+}
+
+// Wait ждет завершения обработки всех поставленных в очередь обновлений
+func (d *dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// callbackSet помнит ограниченное количество последних ID callback-запросов
+type callbackSet struct {
+	mu    sync.Mutex
+	seen  map[string]bool
+	order []string
+	limit int
+}
+
+func newCallbackSet(limit int) *callbackSet {
+	return &callbackSet{
+		seen:  make(map[string]bool, limit),
+		order: make([]string, 0, limit),
+		limit: limit,
+	}
+}
+
+// Add запоминает ID и возвращает false, если он уже встречался
+func (s *callbackSet) Add(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.seen[id] {
+		return false
+	}
+
+	if len(s.order) == s.limit {
+		delete(s.seen, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.seen[id] = true
+	s.order = append(s.order, id)
+	return true
+}
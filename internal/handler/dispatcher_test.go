@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"work-schedule-bot/pkg/messenger"
+)
+
+func messageUpdate(updateID int, chatID int64) messenger.Update {
+	return messenger.Update{
+		ID: updateID,
+		Message: &messenger.Message{
+			ChatID: chatID,
+			Text:   fmt.Sprintf("message %d", updateID),
+		},
+	}
+}
+
+func callbackUpdate(updateID int, chatID int64, callbackID string) messenger.Update {
+	return messenger.Update{
+		ID: updateID,
+		Callback: &messenger.Callback{
+			ID:     callbackID,
+			ChatID: chatID,
+			Data:   "command_clock_out",
+		},
+	}
+}
+
+func TestDispatcherSerializesChat(t *testing.T) {
+	const chats, perChat = 8, 50
+
+	var mu sync.Mutex
+	handled := make(map[int64][]int)
+	active := make(map[int64]*int32, chats)
+	for chatID := int64(1); chatID <= chats; chatID++ {
+		active[chatID] = new(int32)
+	}
+
+	d := newDispatcher(func(update messenger.Update) {
+		chatID := update.Message.ChatID
+		if atomic.AddInt32(active[chatID], 1) != 1 {
+			t.Errorf("chat %d: two updates handled at the same time", chatID)
+		}
+		time.Sleep(100 * time.Microsecond)
+		atomic.AddInt32(active[chatID], -1)
+
+		mu.Lock()
+		handled[chatID] = append(handled[chatID], update.ID)
+		mu.Unlock()
+	})
+
+	updateID := 0
+	for i := 0; i < perChat; i++ {
+		for chatID := int64(1); chatID <= chats; chatID++ {
+			updateID++
+			d.Dispatch(messageUpdate(updateID, chatID))
+		}
+	}
+	d.Wait()
+
+	for chatID, ids := range handled {
+		if len(ids) != perChat {
+			t.Errorf("chat %d: handled %d updates, want %d", chatID, len(ids), perChat)
+		}
+		for i := 1; i < len(ids); i++ {
+			if ids[i] < ids[i-1] {
+				t.Errorf("chat %d: update %d handled after %d", chatID, ids[i], ids[i-1])
+				break
+			}
+		}
+	}
+}
+
+func TestDispatcherRunsChatsConcurrently(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan int64, 2)
+
+	d := newDispatcher(func(update messenger.Update) {
+		started <- update.Message.ChatID
+		<-release
+	})
+
+	d.Dispatch(messageUpdate(1, 1))
+	d.Dispatch(messageUpdate(2, 2))
+
+	// Оба чата должны начать обработку, не дожидаясь друг друга
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("updates of different chats were not handled concurrently")
+		}
+	}
+
+	close(release)
+	d.Wait()
+}
+
+func TestDispatcherSkipsDuplicateCallbacks(t *testing.T) {
+	var calls int32
+	d := newDispatcher(func(update messenger.Update) {
+		atomic.AddInt32(&calls, 1)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			d.Dispatch(callbackUpdate(i, 1, "callback-1"))
+		}(i)
+	}
+	wg.Wait()
+	d.Dispatch(callbackUpdate(11, 1, "callback-2"))
+	d.Wait()
+
+	if calls != 2 {
+		t.Errorf("handled %d callbacks, want 2 (one per callback ID)", calls)
+	}
+}
+
+func TestCallbackSetForgetsOldest(t *testing.T) {
+	set := newCallbackSet(2)
+
+	for _, id := range []string{"a", "b", "c"} {
+		if !set.Add(id) {
+			t.Fatalf("Add(%q) = false for a new ID", id)
+		}
+	}
+	if set.Add("c") {
+		t.Error("Add(\"c\") = true for a repeated ID")
+	}
+	if !set.Add("a") {
+		t.Error("Add(\"a\") = false, want the oldest ID to be forgotten")
+	}
+}
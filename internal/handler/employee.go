@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/messenger"
+)
+
+// Кадровые поля профиля, которые задает HR или администратор командой /setemployee
+const (
+	employeeFieldPosition = "position"
+	employeeFieldNumber   = "number"
+	employeeFieldHired    = "hired"
+)
+
+// setEmployeeField задает должность, табельный номер или дату приема сотрудника.
+// Значение "-" очищает поле.
+func (h *Handler) setEmployeeField(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	parts := strings.Fields(args)
+	if len(parts) < 3 {
+		msg := messenger.NewMessage(chatID, tr.T("usage.setemployee"))
+		h.client.Send(msg)
+		return
+	}
+
+	targetChatID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("user.invalid_id"))
+		h.client.Send(msg)
+		return
+	}
+
+	// Должность может состоять из нескольких слов
+	value := strings.Join(parts[2:], " ")
+	if value == "-" {
+		value = ""
+	}
+
+	actor := models.ChatActor(chatID)
+	var user *models.User
+	switch strings.ToLower(parts[1]) {
+	case employeeFieldPosition:
+		user, err = h.userService.SetPosition(targetChatID, value, actor)
+	case employeeFieldNumber:
+		if strings.Contains(value, " ") {
+			msg := messenger.NewMessage(chatID, tr.T("employee.number_invalid"))
+			h.client.Send(msg)
+			return
+		}
+		user, err = h.userService.SetEmployeeNumber(targetChatID, value, actor)
+	case employeeFieldHired:
+		var hireDate *time.Time
+		if value != "" {
+			date, parseErr := parseDate(value, h.clock.Now())
+			if parseErr != nil {
+				msg := messenger.NewMessage(chatID, tr.T("absence.date_invalid", parseErr))
+				h.client.Send(msg)
+				return
+			}
+			hireDate = &date
+		}
+		user, err = h.userService.SetHireDate(targetChatID, hireDate, actor)
+	default:
+		msg := messenger.NewMessage(chatID, tr.T("employee.unknown_field", parts[1])+"\n\n"+tr.T("usage.setemployee"))
+		h.client.Send(msg)
+		return
+	}
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("employee.updated", h.userService.FormatUserInfo(tr, user)))
+	h.client.Send(msg)
+}
@@ -3,36 +3,55 @@ package handler
 import (
 	"strings"
 	"work-schedule-bot/internal/config"
+	"work-schedule-bot/internal/models"
 	"work-schedule-bot/internal/service"
-	"work-schedule-bot/pkg/telegram"
+	"work-schedule-bot/pkg/clock"
+	"work-schedule-bot/pkg/messenger"
 
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/sirupsen/logrus"
 )
 
 type Handler struct {
-	client                 *telegram.Client
+	client                 messenger.Client
 	userService            *service.UserService
 	workScheduleService    *service.WorkScheduleService
 	userMonthlyStatService *service.UserMonthlyStatService
 	workSessionService     *service.WorkSessionService
 	nonWorkingDayService   *service.NonWorkingDayService
 	absenceService         *service.AbsenceService // ДОБАВЛЕНО
-	userStates             map[int64]string
+	timeBankService        *service.TimeBankService
+	teamCalendarService    *service.TeamCalendarService
+	teamService            *service.TeamService
+	dialogService          *service.DialogService
+	apiTokenService        *service.APITokenService
+	sessionImportService   *service.SessionImportService
+	backupService          *service.BackupService
+	auditService           *service.AuditService
+	flows                  map[string]dialogFlow
+	clock                  clock.Clock
 	config                 *config.BotConfig
 }
 
 func NewHandler(
-	client *telegram.Client,
+	client messenger.Client,
 	userService *service.UserService,
 	workScheduleService *service.WorkScheduleService,
 	userMonthlyStatService *service.UserMonthlyStatService,
 	workSessionService *service.WorkSessionService,
 	nonWorkingDayService *service.NonWorkingDayService,
 	absenceService *service.AbsenceService, // ДОБАВЛЕНО
+	timeBankService *service.TimeBankService,
+	teamCalendarService *service.TeamCalendarService,
+	teamService *service.TeamService,
+	dialogService *service.DialogService,
+	apiTokenService *service.APITokenService,
+	sessionImportService *service.SessionImportService,
+	backupService *service.BackupService,
+	auditService *service.AuditService,
+	clk clock.Clock,
 	cfg *config.BotConfig,
 ) *Handler {
-	return &Handler{
+	h := &Handler{
 		client:                 client,
 		userService:            userService,
 		workScheduleService:    workScheduleService,
@@ -40,134 +59,166 @@ func NewHandler(
 		workSessionService:     workSessionService,
 		nonWorkingDayService:   nonWorkingDayService,
 		absenceService:         absenceService, // ДОБАВЛЕНО
-		userStates:             make(map[int64]string),
+		timeBankService:        timeBankService,
+		teamCalendarService:    teamCalendarService,
+		teamService:            teamService,
+		dialogService:          dialogService,
+		apiTokenService:        apiTokenService,
+		sessionImportService:   sessionImportService,
+		backupService:          backupService,
+		auditService:           auditService,
+		clock:                  clk,
 		config:                 cfg,
 	}
+	h.flows = h.dialogFlows()
+
+	return h
 }
 
-func (h *Handler) HandleUpdates(updates tgbotapi.UpdatesChannel) {
+// HandleUpdates обрабатывает обновления: разные чаты - параллельно, один чат - по порядку
+func (h *Handler) HandleUpdates(updates <-chan messenger.Update) {
+	d := newDispatcher(h.handleUpdate)
 	for update := range updates {
-		// Обработка callback query (для inline кнопок)
-		if update.CallbackQuery != nil {
-			h.handleCallbackQuery(update.CallbackQuery)
-			continue
-		}
+		d.Dispatch(update)
+	}
+	d.Wait()
+}
 
-		if update.Message == nil {
-			continue
-		}
+func (h *Handler) handleUpdate(update messenger.Update) {
+	if !h.checkActive(update) {
+		return
+	}
+
+	// Обработка callback query (для inline кнопок)
+	if update.Callback != nil {
+		h.handleCallbackQuery(update.Callback)
+		return
+	}
 
-		h.handleMessage(update.Message)
+	if update.Message == nil {
+		return
+	}
+
+	h.handleMessage(update.Message)
+}
+
+// callbackMessage - сообщение с командой от имени пользователя, нажавшего кнопку
+func callbackMessage(callback *messenger.Callback, text string) *messenger.Message {
+	command, args := messenger.ParseCommand(text)
+	return &messenger.Message{
+		ID:      callback.MessageID,
+		ChatID:  callback.ChatID,
+		From:    callback.From,
+		Text:    text,
+		Command: command,
+		Args:    args,
 	}
 }
 
 // handleCallbackQuery обрабатывает inline кнопки
-func (h *Handler) handleCallbackQuery(callback *tgbotapi.CallbackQuery) {
-	chatID := callback.Message.Chat.ID
+func (h *Handler) handleCallbackQuery(callback *messenger.Callback) {
+	chatID := callback.ChatID
 	data := callback.Data
+	tr := h.chatLocalizer(chatID, callback.From.LanguageCode)
 
 	// Удаляем клавиатуру
-	editMsg := tgbotapi.NewEditMessageReplyMarkup(chatID, callback.Message.MessageID, tgbotapi.NewInlineKeyboardMarkup())
-	h.client.Bot.Send(editMsg)
+	h.client.RemoveButtons(chatID, callback.MessageID)
 
 	// Обработка callback для графиков
 	if strings.HasPrefix(data, "confirm_delete_schedule_") || data == "cancel_delete_schedule" {
+		if !h.authorize(callbackMessage(callback, "/deleteschedule"), "deleteschedule") {
+			return
+		}
 		h.handleScheduleCallback(callback)
 		return
 	}
 
-	// Обработка завершения работы в выходной день
-	if data == "confirm_clockout_holiday" {
-		// Создаем фейковое сообщение с командой /out (продолжаем завершение)
-		fakeMessage := &tgbotapi.Message{
-			MessageID: callback.Message.MessageID,
-			Chat: &tgbotapi.Chat{
-				ID: chatID,
-			},
-			From: callback.From,
-			Text: "/out confirm_holiday",
+	// Решение по новому профилю, ожидающему одобрения
+	if strings.HasPrefix(data, callbackApproveUser) || strings.HasPrefix(data, callbackRejectUser) {
+		if !h.authorize(callbackMessage(callback, "/approve"), "approve") {
+			return
 		}
+		h.handleApprovalCallback(callback)
+		return
+	}
 
-		// Запускаем обработчик команды /out с флагом подтверждения
-		h.clockOut(fakeMessage)
+	// Подтверждение безвозвратного удаления сотрудника
+	if strings.HasPrefix(data, "confirm_purge_") || data == "cancel_purge" {
+		if !h.authorize(callbackMessage(callback, "/purgeuser"), "purgeuser") {
+			return
+		}
+		h.handlePurgeCallback(callback)
+		return
+	}
+
+	// Подтверждение импорта рабочих дней
+	if data == "confirm_import_sessions" || data == "cancel_import_sessions" {
+		if !h.authorize(callbackMessage(callback, "/importsessions"), "importsessions") {
+			return
+		}
+		h.handleImportCallback(callback)
+		return
+	}
+
+	// Обработка завершения работы в выходной день
+	if data == "confirm_clockout_holiday" {
+		// Запускаем обработчик команды /out с флагом подтверждения (продолжаем завершение)
+		h.clockOut(callbackMessage(callback, "/out confirm_holiday"))
 		return
 	}
 
 	if data == "cancel_clockout_holiday" {
-		msg := tgbotapi.NewMessage(chatID, "❌ Завершение работы отменено.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("session.clock_out_cancelled"))
+		h.client.Send(msg)
 		return
 	}
 
 	if data == "command_clock_out" {
-		// Создаем фейковое сообщение с командой /out
-		fakeMessage := &tgbotapi.Message{
-			MessageID: callback.Message.MessageID,
-			Chat: &tgbotapi.Chat{
-				ID: chatID,
-			},
-			From: callback.From,
-			Text: "/out",
-		}
-
 		// Запускаем обработчик команды /out
-		h.clockOut(fakeMessage)
+		h.clockOut(callbackMessage(callback, "/out"))
 		return
 	}
 
 	// Обработка callback для начала новой рабочей сессии
 	if data == "command_clock_in" {
-		// Создаем фейковое сообщение с командой /in
-		fakeMessage := &tgbotapi.Message{
-			MessageID: callback.Message.MessageID,
-			Chat: &tgbotapi.Chat{
-				ID: chatID,
-			},
-			From: callback.From,
-			Text: "/in",
-		}
-
 		// Запускаем обработчик команды /in
-		h.clockIn(fakeMessage)
+		h.clockIn(callbackMessage(callback, "/in"))
 		return
 	}
 
 	// Существующая обработка для профилей
 	switch data {
 	case "confirm_delete":
-		err := h.userService.DeleteUser(chatID)
+		// Сотрудник увольняется сам: профиль деактивируется, история сохраняется
+		_, err := h.userService.DeactivateUser(chatID, clock.Today(h.clock), models.ChatActor(chatID))
 		if err != nil {
-			msg := tgbotapi.NewMessage(chatID, "❌ Ошибка удаления профиля: "+err.Error())
-			h.client.Bot.Send(msg)
+			msg := messenger.NewMessage(chatID, tr.T("profile.delete_failed", err))
+			h.client.Send(msg)
 		} else {
-			msg := tgbotapi.NewMessage(chatID, "✅ Ваш профиль успешно удален!")
-			h.client.Bot.Send(msg)
+			msg := messenger.NewMessage(chatID, tr.T("profile.deleted"))
+			h.client.Send(msg)
 		}
 
 	case "cancel_delete":
-		msg := tgbotapi.NewMessage(chatID, "❌ Удаление профиля отменено.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.delete_cancelled"))
+		h.client.Send(msg)
 	}
 
 	// Отвечаем на callback (убираем "часики" у кнопки)
-	callbackConfig := tgbotapi.NewCallback(callback.ID, "")
-	h.client.Bot.Send(callbackConfig)
+	h.client.AnswerCallback(callback.ID, "")
 }
 
-func (h *Handler) handleMessage(message *tgbotapi.Message) {
-	logrus.Infof("[%s] %s", message.From.UserName, message.Text)
-
-	chatID := message.Chat.ID
+func (h *Handler) handleMessage(message *messenger.Message) {
+	logrus.Infof("[%s] %s", message.From.Username, message.Text)
 
-	// Проверяем, находится ли пользователь в процессе создания/обновления профиля
-	if state, exists := h.userStates[chatID]; exists {
-		h.handleProfileState(message, state)
+	// Команды обрабатываются всегда, даже во время диалога
+	if message.IsCommand() {
+		h.handleCommand(message)
 		return
 	}
 
-	// Обработка команд
-	if message.IsCommand() {
-		h.handleCommand(message)
+	// Ответ на шаг активного диалога (создание/обновление профиля и т.п.)
+	if h.handleDialog(message) {
 		return
 	}
 
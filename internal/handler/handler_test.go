@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"work-schedule-bot/internal/config"
+	"work-schedule-bot/internal/migrations"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/internal/service"
+	"work-schedule-bot/pkg/clock"
+	"work-schedule-bot/pkg/messenger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testChatID int64 = 100
+
+// newTestHandler собирает Handler поверх временной базы SQLite и MemoryClient
+func newTestHandler(t *testing.T) (*Handler, *messenger.MemoryClient, *gorm.DB) {
+	t.Helper()
+
+	db, err := repository.OpenDatabase(repository.DriverSQLite, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database instance: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if _, err := migrations.NewMigrator(db).Up(); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	clk := clock.System(nil)
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+	}
+	userRepo, err := repository.NewUserRepository(db)
+	must(err)
+	scheduleRepo, err := repository.NewGormWorkScheduleRepository(db, clk)
+	must(err)
+	statRepo, err := repository.NewGormUserMonthlyStatRepository(db, clk)
+	must(err)
+	sessionRepo, err := repository.NewGormWorkSessionRepository(db, clk)
+	must(err)
+	nonWorkingDayRepo, err := repository.NewGormNonWorkingDayRepository(db)
+	must(err)
+	absenceRepo, err := repository.NewGormAbsencePeriodRepository(db, clk)
+	must(err)
+	timeBankRepo, err := repository.NewGormTimeBankRepository(db)
+	must(err)
+	teamRepo, err := repository.NewGormTeamRepository(db)
+	must(err)
+	dialogRepo, err := repository.NewGormDialogStateRepository(db)
+	must(err)
+	tokenRepo, err := repository.NewGormAPITokenRepository(db)
+	must(err)
+	auditRepo, err := repository.NewGormAuditRepository(db, clk)
+	must(err)
+
+	uow := repository.NewGormUnitOfWork(db, clk)
+	nonWorkingDayService := service.NewNonWorkingDayService(nonWorkingDayRepo, clk)
+	cfg := config.Default()
+	cfg.Registration.RequireApproval = false
+	policy := cfg.WorkPolicy()
+	statService := service.NewUserMonthlyStatService(statRepo, userRepo, uow, policy, clk)
+
+	client := messenger.NewMemoryClient()
+	h := NewHandler(
+		client,
+		service.NewUserService(userRepo, scheduleRepo, statService, uow, clk),
+		service.NewWorkScheduleService(scheduleRepo, statService, nonWorkingDayService, uow, policy, clk),
+		statService,
+		service.NewWorkSessionService(sessionRepo, statRepo, scheduleRepo, absenceRepo, nonWorkingDayService, uow, policy, clk),
+		nonWorkingDayService,
+		service.NewAbsenceService(absenceRepo, sessionRepo, statRepo, userRepo, scheduleRepo, timeBankRepo, uow, nonWorkingDayService, policy, clk),
+		service.NewTimeBankService(timeBankRepo, statRepo, userRepo, uow, clk),
+		service.NewTeamCalendarService(absenceRepo, userRepo, nonWorkingDayService, 30, clk),
+		service.NewTeamService(teamRepo, userRepo, uow),
+		service.NewDialogService(dialogRepo, clk),
+		service.NewAPITokenService(tokenRepo, uow, clk),
+		service.NewSessionImportService(uow, policy, clk),
+		service.NewBackupService(db, clk, "", 1),
+		service.NewAuditService(auditRepo, userRepo, tokenRepo, clk),
+		clk,
+		cfg,
+	)
+
+	return h, client, db
+}
+
+// send обрабатывает текст так, как будто его написал пользователь testChatID
+func send(h *Handler, text string) {
+	command, args := messenger.ParseCommand(text)
+	h.handleUpdate(messenger.Update{Message: &messenger.Message{
+		ChatID:  testChatID,
+		From:    messenger.User{ID: testChatID, Username: "ivan"},
+		Text:    text,
+		Command: command,
+		Args:    args,
+	}})
+}
+
+// lastText возвращает текст последнего ответа бота
+func lastText(t *testing.T, client *messenger.MemoryClient) string {
+	t.Helper()
+
+	msg, ok := client.Last(testChatID)
+	if !ok {
+		t.Fatal("bot did not reply")
+	}
+	return msg.Text
+}
+
+func TestCreateProfileDialog(t *testing.T) {
+	h, client, _ := newTestHandler(t)
+
+	send(h, "/createprofile")
+	send(h, "Иван")
+	send(h, "Петров")
+	send(h, "-")
+	send(h, "Марс")
+
+	if text := lastText(t, client); !strings.Contains(text, "неизвестный часовой пояс") {
+		t.Fatalf("reply = %q, want unknown time zone", text)
+	}
+
+	send(h, "нск")
+
+	if text := lastText(t, client); !strings.Contains(text, "Профиль успешно создан") {
+		t.Fatalf("reply = %q, want profile created", text)
+	}
+
+	user, err := h.userService.GetUser(testChatID)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.FirstName != "Иван" || user.LastName != "Петров" || user.Username != "ivan" {
+		t.Errorf("user = %+v, want Иван Петров (ivan)", user)
+	}
+	if user.Timezone != "Asia/Novosibirsk" {
+		t.Errorf("timezone = %q, want Asia/Novosibirsk", user.Timezone)
+	}
+}
+
+func TestProtectedCommandDenied(t *testing.T) {
+	h, client, db := newTestHandler(t)
+
+	if err := db.Create(&models.User{ChatID: testChatID, FirstName: "Иван", Role: models.RoleEmployee}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	send(h, "/allusers")
+	if text := lastText(t, client); !strings.Contains(text, "Доступ запрещен") {
+		t.Errorf("reply = %q, want access denied", text)
+	}
+}
+
+func TestClockOutButton(t *testing.T) {
+	h, client, db := newTestHandler(t)
+
+	if err := db.Create(&models.User{ChatID: testChatID, FirstName: "Иван", Role: models.RoleEmployee}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	send(h, "/in")
+	reply, ok := client.Last(testChatID)
+	if !ok || len(reply.Buttons) != 1 || reply.Buttons[0][0].Data != "command_clock_out" {
+		t.Fatalf("reply to /in = %+v, want a clock out button", reply)
+	}
+	if reply.Format != messenger.FormatMarkdown {
+		t.Errorf("reply format = %q, want markdown", reply.Format)
+	}
+
+	// Нажатие кнопки завершает рабочий день и убирает кнопки
+	h.handleUpdate(messenger.Update{Callback: &messenger.Callback{
+		ID:        "callback-1",
+		ChatID:    testChatID,
+		MessageID: 42,
+		From:      messenger.User{ID: testChatID},
+		Data:      reply.Buttons[0][0].Data,
+	}})
+
+	reply, _ = client.Last(testChatID)
+	if len(reply.Buttons) != 1 || reply.Buttons[0][0].Data != "command_clock_in" {
+		t.Errorf("reply to clock out = %+v, want a clock in button", reply)
+	}
+	if removed := client.RemovedButtons(); len(removed) != 1 || removed[0] != 42 {
+		t.Errorf("removed buttons = %v, want message 42", removed)
+	}
+
+	var session models.WorkSession
+	if err := db.First(&session).Error; err != nil {
+		t.Fatalf("failed to load session: %v", err)
+	}
+	if session.Status != models.StatusCompleted {
+		t.Errorf("session status = %q, want %q", session.Status, models.StatusCompleted)
+	}
+}
+
+func TestVacationOverloadWarning(t *testing.T) {
+	h, client, db := newTestHandler(t)
+
+	// 10 сотрудников, порог 30%. С новым отпуском в первый день отсутствуют двое (ниже порога),
+	// во второй - трое (ровно порог), в третий - четверо (выше порога)
+	var users []models.User
+	for i := 0; i < 10; i++ {
+		user := models.User{ChatID: testChatID + int64(i), FirstName: "User", Role: models.RoleEmployee}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		users = append(users, user)
+	}
+
+	start := time.Now().AddDate(0, 1, 0)
+	for start.Weekday() != time.Monday {
+		start = start.AddDate(0, 0, 1)
+	}
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local)
+	for day := 0; day < 3; day++ {
+		date := start.AddDate(0, 0, day)
+		for i := 1; i <= day+1; i++ {
+			period := models.AbsencePeriod{UserID: users[i].ID, StartDate: date, EndDate: date, Type: models.AbsenceTypeVacation}
+			if err := db.Create(&period).Error; err != nil {
+				t.Fatalf("failed to create absence period: %v", err)
+			}
+		}
+	}
+
+	end := start.AddDate(0, 0, 2)
+	send(h, "/vacation "+start.Format("02.01.2006")+" "+end.Format("02.01.2006"))
+	text := lastText(t, client)
+	want := "более 30% команды: " + end.Format("02.01") + "\n"
+	if !strings.Contains(text, want) {
+		t.Errorf("reply = %q, want a warning for %s only", text, end.Format("02.01"))
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"strings"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/pkg/messenger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// localizer возвращает Localizer на языке автора сообщения: язык из профиля,
+// а без профиля - язык клиента Telegram
+func (h *Handler) localizer(message *messenger.Message) *i18n.Localizer {
+	return h.chatLocalizer(message.ChatID, message.From.LanguageCode)
+}
+
+// chatLocalizer возвращает Localizer на языке профиля чата или на языке clientLanguage,
+// если профиля нет
+func (h *Handler) chatLocalizer(chatID int64, clientLanguage string) *i18n.Localizer {
+	user, err := h.userService.FindUser(chatID)
+	if err != nil {
+		logrus.WithError(err).WithField("chat_id", chatID).Warn("Failed to get user language")
+	}
+	if user != nil && user.Language != "" {
+		return i18n.For(user.Language)
+	}
+	return i18n.For(clientLanguage)
+}
+
+// setLanguage меняет язык интерфейса пользователя
+func (h *Handler) setLanguage(message *messenger.Message, args string) {
+	chatID := message.ChatID
+
+	args = strings.TrimSpace(args)
+	if args == "" {
+		tr := h.localizer(message)
+		msg := messenger.NewMessage(chatID, tr.T("language.usage", tr.T("language.name")))
+		h.client.Send(msg)
+		return
+	}
+
+	user, err := h.userService.SetLanguage(chatID, args)
+	if err != nil {
+		tr := h.localizer(message)
+		msg := messenger.NewMessage(chatID, tr.T("language.set_failed", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	// Ответ уже на новом языке
+	tr := i18n.For(user.Language)
+	msg := messenger.NewMessage(chatID, tr.T("language.set", tr.T("language.name")))
+	h.client.Send(msg)
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"strings"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/messenger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// protectedCommand - команда, требующая права доступа
+type protectedCommand struct {
+	Command    string
+	Permission models.Permission
+}
+
+// usageKey - ключ каталога с описанием команды для /helpadmin
+func (pc protectedCommand) usageKey() string {
+	return "usage." + pc.Command
+}
+
+// protectedCommands - таблица команда → право. Команды, которых здесь нет, доступны всем.
+// Порядок определяет вывод в /helpadmin.
+var protectedCommands = []protectedCommand{
+	{"allusers", models.PermUsersView},
+	{"stats", models.PermUsersView},
+	{"admins", models.PermUsersView},
+	{"teams", models.PermUsersView},
+
+	{"pending", models.PermUsersApprove},
+	{"approve", models.PermUsersApprove},
+	{"reject", models.PermUsersApprove},
+	{"deactivate", models.PermUsersManage},
+	{"reactivate", models.PermUsersManage},
+	{"purgeuser", models.PermUsersPurge},
+	{"setemployee", models.PermUsersManage},
+
+	{"setrole", models.PermRolesManage},
+	{"promote", models.PermRolesManage},
+	{"demote", models.PermRolesManage},
+	{"roles", models.PermRolesManage},
+
+	{"adddepartment", models.PermTeamsManage},
+	{"deletedepartment", models.PermTeamsManage},
+	{"addteam", models.PermTeamsManage},
+	{"deleteteam", models.PermTeamsManage},
+	{"setteam", models.PermTeamsManage},
+
+	{"getschedules", models.PermScheduleView},
+	{"getschedule", models.PermScheduleView},
+	{"currentschedule", models.PermScheduleView},
+	{"addschedule", models.PermScheduleEdit},
+	{"updateschedule", models.PermScheduleEdit},
+	{"deleteschedule", models.PermScheduleEdit},
+	{"generateschedules", models.PermScheduleEdit},
+	{"updateallschedules", models.PermScheduleEdit},
+
+	{"userstat", models.PermStatsView},
+	{"teamstats", models.PermStatsView},
+	{"timesheet", models.PermTimesheetExport},
+	{"recalcstats", models.PermStatsManage},
+	{"importsessions", models.PermSessionsImport},
+	{"fixsession", models.PermSessionsCorrect},
+
+	{"truancy", models.PermAbsencesMark},
+
+	{"bankadjust", models.PermTimeBankAdjust},
+	{"closemonth", models.PermTimeBankManage},
+	{"bankcaps", models.PermTimeBankManage},
+
+	{"apitokens", models.PermAPITokensManage},
+	{"addapitoken", models.PermAPITokensManage},
+	{"revokeapitoken", models.PermAPITokensManage},
+
+	{"backup", models.PermBackupManage},
+	{"audit", models.PermAuditView},
+	{"auditexport", models.PermAuditView},
+}
+
+// commandPermissions - индекс таблицы protectedCommands по команде
+var commandPermissions = func() map[string]models.Permission {
+	result := make(map[string]models.Permission, len(protectedCommands))
+	for _, pc := range protectedCommands {
+		result[pc.Command] = pc.Permission
+	}
+	return result
+}()
+
+// authorize проверяет право пользователя на выполнение команды и сообщает об отказе
+func (h *Handler) authorize(message *messenger.Message, command string) bool {
+	permission, protected := commandPermissions[command]
+	if !protected {
+		return true
+	}
+
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	allowed, err := h.userService.HasPermission(chatID, permission)
+	if err != nil {
+		logrus.WithError(err).Error("Error checking permission")
+		msg := messenger.NewMessage(chatID, tr.T("access.check_failed_reply", tr.Error(err)))
+		h.client.Send(msg)
+		return false
+	}
+
+	if !allowed {
+		logrus.WithFields(logrus.Fields{
+			"chat_id":    chatID,
+			"command":    command,
+			"permission": permission,
+		}).Warn("Unauthorized command")
+		msg := messenger.NewMessage(chatID, tr.T("access.denied_command"))
+		h.client.Send(msg)
+		return false
+	}
+
+	return true
+}
+
+// formatPermittedCommands возвращает описание команд, доступных пользователю
+func formatPermittedCommands(tr *i18n.Localizer, user *models.User) string {
+	var lines []string
+	for _, pc := range protectedCommands {
+		if user.HasPermission(pc.Permission) {
+			lines = append(lines, tr.T(pc.usageKey()))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
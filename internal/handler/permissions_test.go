@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/messenger"
+)
+
+func TestCommandPermissionsByRole(t *testing.T) {
+	h, client, db := newTestHandler(t)
+
+	// Команды, доступные роли; остальные защищенные команды должны быть запрещены
+	readUsers := []string{"allusers", "stats", "admins", "teams"}
+	readSchedules := []string{"getschedules", "getschedule", "currentschedule"}
+	readStats := []string{"userstat", "teamstats", "timesheet"}
+	allowed := map[string][]string{
+		models.RoleEmployee: nil,
+		models.RoleTeamLead: join(readUsers, readStats, []string{"pending", "approve", "reject", "fixsession", "truancy", "bankadjust"}),
+		models.RoleHR: join(readUsers, readSchedules, readStats, []string{
+			"pending", "approve", "reject", "deactivate", "reactivate", "setemployee",
+			"adddepartment", "deletedepartment", "addteam", "deleteteam", "setteam",
+			"fixsession", "truancy", "bankadjust",
+		}),
+		models.RoleAccountant: join(readUsers, readSchedules, readStats, []string{"closemonth", "bankcaps"}),
+	}
+	var all []string
+	for _, pc := range protectedCommands {
+		all = append(all, pc.Command)
+	}
+	allowed[models.RoleAdmin] = all
+
+	for i, role := range models.GetRoles() {
+		chatID := int64(1000 + i)
+		commands, ok := allowed[role]
+		if !ok {
+			t.Errorf("role %s is missing from the test", role)
+			continue
+		}
+		user := &models.User{ChatID: chatID, FirstName: role, Role: role}
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("failed to create %s: %v", role, err)
+		}
+
+		permitted := make(map[string]bool, len(commands))
+		for _, command := range commands {
+			permitted[command] = true
+		}
+
+		for _, pc := range protectedCommands {
+			message := &messenger.Message{ChatID: chatID, From: messenger.User{ID: chatID}, Command: pc.Command}
+			if got := h.authorize(message, pc.Command); got != permitted[pc.Command] {
+				t.Errorf("%s /%s: authorized = %v, want %v", role, pc.Command, got, permitted[pc.Command])
+			}
+		}
+
+		if len(commands) < len(protectedCommands) {
+			if reply, ok := client.Last(chatID); !ok || !strings.Contains(reply.Text, "Доступ запрещен") {
+				t.Errorf("%s denial reply = %+v, want access denied", role, reply)
+			}
+		}
+
+		// Команды вне таблицы доступны всем
+		message := &messenger.Message{ChatID: chatID, From: messenger.User{ID: chatID}, Command: "mystats"}
+		if !h.authorize(message, "mystats") {
+			t.Errorf("%s /mystats is denied, want allowed", role)
+		}
+	}
+}
+
+// join объединяет списки команд
+func join(lists ...[]string) []string {
+	var result []string
+	for _, list := range lists {
+		result = append(result, list...)
+	}
+	return result
+}
+
+func TestSetRole(t *testing.T) {
+	h, client, db := newTestHandler(t)
+
+	users := []*models.User{
+		{ChatID: testChatID, FirstName: "Админ", Role: models.RoleAdmin},
+		{ChatID: 200, FirstName: "Ольга", Role: models.RoleEmployee},
+		{ChatID: 300, FirstName: "Петр", Role: models.RoleHR},
+	}
+	for _, user := range users {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	role := func(chatID int64) string {
+		t.Helper()
+		var user models.User
+		if err := db.Where("chat_id = ?", chatID).First(&user).Error; err != nil {
+			t.Fatalf("failed to read user: %v", err)
+		}
+		return user.Role
+	}
+
+	send(h, "/setrole 200 HR")
+	if text := lastText(t, client); !strings.Contains(text, "изменена на 'hr'") {
+		t.Errorf("reply = %q, want the role changed", text)
+	}
+	if got := role(200); got != models.RoleHR {
+		t.Errorf("role = %q, want %q", got, models.RoleHR)
+	}
+
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"200", "/setrole"},
+		{"abc hr", "ID"},
+		{"200 superuser", "Неизвестная роль"},
+		{"200 team_lead", "не состоит в команде"},
+		{"999 accountant", "не найден"},
+	}
+	for _, tt := range tests {
+		send(h, "/setrole "+tt.args)
+		if text := lastText(t, client); !strings.Contains(text, tt.want) {
+			t.Errorf("/setrole %s: reply = %q, want %q", tt.args, text, tt.want)
+		}
+	}
+	if got := role(200); got != models.RoleHR {
+		t.Errorf("role after rejected changes = %q, want %q", got, models.RoleHR)
+	}
+
+	// HR не может назначать роли
+	command, args := messenger.ParseCommand("/setrole 200 admin")
+	h.handleUpdate(messenger.Update{Message: &messenger.Message{
+		ChatID: 300, From: messenger.User{ID: 300}, Text: "/setrole 200 admin", Command: command, Args: args,
+	}})
+	if reply, ok := client.Last(300); !ok || !strings.Contains(reply.Text, "Доступ запрещен") {
+		t.Errorf("reply to HR = %+v, want access denied", reply)
+	}
+	if got := role(200); got != models.RoleHR {
+		t.Errorf("role after HR attempt = %q, want %q", got, models.RoleHR)
+	}
+}
+
+func TestTeamLeadTimesheetAndCorrection(t *testing.T) {
+	h, client, db := newTestHandler(t)
+
+	department := &models.Department{Name: "Разработка"}
+	if err := db.Create(department).Error; err != nil {
+		t.Fatalf("failed to create department: %v", err)
+	}
+	backend := &models.Team{DepartmentID: department.ID, Name: "Backend"}
+	frontend := &models.Team{DepartmentID: department.ID, Name: "Frontend"}
+	for _, team := range []*models.Team{backend, frontend} {
+		if err := db.Create(team).Error; err != nil {
+			t.Fatalf("failed to create team: %v", err)
+		}
+	}
+
+	member := &models.User{ChatID: 200, FirstName: "Иван", Role: models.RoleEmployee, TeamID: &backend.ID}
+	for _, user := range []*models.User{
+		{ChatID: testChatID, FirstName: "Лид", Role: models.RoleTeamLead, TeamID: &backend.ID},
+		member,
+		{ChatID: 300, FirstName: "Петр", Role: models.RoleEmployee, TeamID: &frontend.ID},
+	} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	// Табель руководителя - только его команда
+	send(h, "/timesheet 2026 3")
+	reply, ok := client.Last(testChatID)
+	if !ok || reply.File == nil {
+		t.Fatalf("reply = %+v, want a CSV file", reply)
+	}
+	if reply.File.Name != "timesheet-2026-03.csv" {
+		t.Errorf("file name = %q", reply.File.Name)
+	}
+	data := "\n" + string(reply.File.Data)
+	if !strings.Contains(data, "\n200,") || strings.Contains(data, "\n300,") {
+		t.Errorf("timesheet = %q, want only the lead's team", reply.File.Data)
+	}
+
+	// Исправление - только сотрудникам своей команды
+	send(h, "/fixsession 300 02.03.2026 09:00 18:00")
+	if text := lastText(t, client); !strings.Contains(text, "не из вашей команды") {
+		t.Errorf("reply = %q, want not your team", text)
+	}
+
+	send(h, "/fixsession 200 02.03.2026 09:00 18:00")
+	if text := lastText(t, client); !strings.Contains(text, "Рабочий день исправлен") || !strings.Contains(text, "09:00 - 18:00") {
+		t.Errorf("reply = %q, want the corrected day", text)
+	}
+	var sessions []models.WorkSession
+	if err := db.Where("user_id = ?", member.ID).Find(&sessions).Error; err != nil {
+		t.Fatalf("failed to load sessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].WorkedMinutes != 9*60 {
+		t.Errorf("sessions = %+v, want one day with 9 hours", sessions)
+	}
+
+	send(h, "/fixsession 200")
+	if text := lastText(t, client); !strings.Contains(text, "/fixsession ID") {
+		t.Errorf("reply = %q, want usage", text)
+	}
+}
+
+func TestApprovalRequestsReachTeamLeads(t *testing.T) {
+	h, client, db := newTestHandler(t)
+
+	department := &models.Department{Name: "Разработка"}
+	if err := db.Create(department).Error; err != nil {
+		t.Fatalf("failed to create department: %v", err)
+	}
+	backend := &models.Team{DepartmentID: department.ID, Name: "Backend"}
+	if err := db.Create(backend).Error; err != nil {
+		t.Fatalf("failed to create team: %v", err)
+	}
+
+	const (
+		admin int64 = 500
+		hr    int64 = 600
+		lead  int64 = 700
+	)
+	newcomer := &models.User{ChatID: 200, FirstName: "Иван", Role: models.RoleEmployee, PendingApproval: true}
+	for _, user := range []*models.User{
+		{ChatID: admin, FirstName: "Анна", Role: models.RoleAdmin},
+		{ChatID: hr, FirstName: "Ольга", Role: models.RoleHR},
+		{ChatID: lead, FirstName: "Лид", Role: models.RoleTeamLead, TeamID: &backend.ID},
+		newcomer,
+	} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	sendAs := func(chatID int64, text string) {
+		command, args := messenger.ParseCommand(text)
+		h.handleUpdate(messenger.Update{Message: &messenger.Message{
+			ChatID: chatID, From: messenger.User{ID: chatID}, Text: text, Command: command, Args: args,
+		}})
+	}
+	isRequest := func(msg messenger.OutgoingMessage) bool {
+		return strings.Contains(msg.Text, "ожидает одобрения") && len(msg.Buttons) > 0
+	}
+
+	// Профиль без команды может одобрить только роль уровня компании
+	h.requestApproval(newcomer)
+	for _, chatID := range []int64{admin, hr} {
+		if reply, ok := client.Last(chatID); !ok || !isRequest(reply) {
+			t.Errorf("chat %d: last message = %+v, want an approval request", chatID, reply)
+		}
+	}
+	if sent := client.Sent(lead); len(sent) != 0 {
+		t.Errorf("lead got %d messages for a profile outside the team", len(sent))
+	}
+
+	// После /setteam запрос получает руководитель команды, остальные - не повторно
+	client.Reset()
+	sendAs(hr, fmt.Sprintf("/setteam 200 %d", backend.ID))
+	if reply, ok := client.Last(lead); !ok || !isRequest(reply) {
+		t.Errorf("lead: last message = %+v, want an approval request", reply)
+	}
+	if sent := client.Sent(admin); len(sent) != 0 {
+		t.Errorf("admin got %d more messages after /setteam", len(sent))
+	}
+
+	sendAs(lead, "/pending")
+	if reply, ok := client.Last(lead); !ok || !isRequest(reply) || !strings.Contains(reply.Text, "200") {
+		t.Errorf("lead /pending: last message = %+v, want the newcomer", reply)
+	}
+
+	sendAs(lead, "/approve 200")
+	if reply, ok := client.Last(lead); !ok || !strings.Contains(reply.Text, "Иван") {
+		t.Errorf("lead /approve: reply = %+v, want the profile approved", reply)
+	}
+	var approved models.User
+	if err := db.Where("chat_id = ?", newcomer.ChatID).First(&approved).Error; err != nil {
+		t.Fatalf("failed to read user: %v", err)
+	}
+	if approved.PendingApproval {
+		t.Error("profile is still pending after the lead approved it")
+	}
+}
@@ -1,190 +1,314 @@
 package handler
 
 import (
-	"fmt"
 	"strings"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/clock"
+	"work-schedule-bot/pkg/messenger"
+)
+
+// Сценарии и шаги диалогов профиля
+const (
+	flowProfileCreate = "profile_create"
+	flowProfileUpdate = "profile_update"
 
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	stepProfileFirstName  = "first_name"
+	stepProfileLastName   = "last_name"
+	stepProfilePatronymic = "patronymic"
+	stepProfileTimezone   = "timezone"
+	stepProfileName       = "name"
 )
 
+// profileCreateData - данные, собранные на предыдущих шагах создания профиля
+type profileCreateData struct {
+	FirstName  string `json:"first_name"`
+	LastName   string `json:"last_name"`
+	Patronymic string `json:"patronymic"`
+}
+
 // startProfileCreation начинает процесс создания профиля
-func (h *Handler) startProfileCreation(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
+func (h *Handler) startProfileCreation(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	// Проверяем, есть ли уже профиль
 	user, err := h.userService.GetUser(chatID)
 	if err == nil && user != nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ У вас уже есть профиль!\nИспользуйте /myprofile чтобы посмотреть его или /updateprofile чтобы изменить.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.already_exists"))
+		h.client.Send(msg)
 		return
 	}
 
 	// Начинаем процесс создания
-	h.userStates[chatID] = "awaiting_first_name"
+	if !h.startDialog(chatID, flowProfileCreate, stepProfileFirstName, nil) {
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("profile.create.first_name"))
+	h.client.Send(msg)
+}
 
-	text := `👤 Создание профиля
+// profileFirstNameStep сохраняет имя и запрашивает фамилию
+func (h *Handler) profileFirstNameStep(message *messenger.Message, state *models.DialogState) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+	firstName := strings.TrimSpace(message.Text)
+
+	if firstName == "" {
+		msg := messenger.NewMessage(chatID, tr.T("profile.create.first_name_empty"))
+		h.client.Send(msg)
+		return
+	}
 
-Шаг 1 из 3:
-✏️ Пожалуйста, отправьте ваше имя:`
+	if !h.advanceDialog(state, stepProfileLastName, profileCreateData{FirstName: firstName}) {
+		return
+	}
 
-	msg := tgbotapi.NewMessage(chatID, text)
-	h.client.Bot.Send(msg)
+	msg := messenger.NewMessage(chatID, tr.T("profile.create.last_name", firstName))
+	h.client.Send(msg)
 }
 
-// handleProfileState обрабатывает состояния создания/обновления профиля
-func (h *Handler) handleProfileState(message *tgbotapi.Message, state string) {
-	chatID := message.Chat.ID
-	text := message.Text
-
-	if state == "awaiting_first_name" {
-		// Сохраняем имя и запрашиваем фамилию
-		h.userStates[chatID] = "awaiting_last_name:" + text
-
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(
-			`Шаг 2 из 3:
-	✅ Имя сохранено: %s
-	✏️ Теперь отправьте вашу фамилию (если нет фамилии, отправьте "-"):`,
-			text))
-		h.client.Bot.Send(msg)
-	} else if strings.Contains(state, "awaiting_last_name") {
-		// Извлекаем имя из состояния
-		firstName := strings.TrimPrefix(state, "awaiting_last_name:")
-		lastName := text
-
-		// Обрабатываем случай, когда фамилии нет
-		if lastName == "-" {
-			lastName = ""
-		}
+// profileLastNameStep сохраняет фамилию и запрашивает отчество
+func (h *Handler) profileLastNameStep(message *messenger.Message, state *models.DialogState) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
-		// Получаем username
-		username := ""
-		if message.From.UserName != "" {
-			username = message.From.UserName
-		}
+	var data profileCreateData
+	if err := state.DecodeData(&data); err != nil {
+		h.finishDialog(chatID)
+		msg := messenger.NewMessage(chatID, tr.T("profile.create.data_corrupted"))
+		h.client.Send(msg)
+		return
+	}
 
-		// Создаем профиль
-		user, err := h.userService.CreateUser(chatID, username, firstName, lastName)
-		if err != nil {
-			// Удаляем состояние в случае ошибки
-			delete(h.userStates, chatID)
+	lastName := strings.TrimSpace(message.Text)
 
-			msg := tgbotapi.NewMessage(chatID, "❌ Ошибка создания профиля: "+err.Error())
-			h.client.Bot.Send(msg)
-			return
-		}
+	// Обрабатываем случай, когда фамилии нет
+	if lastName == "-" {
+		lastName = ""
+	}
+	data.LastName = lastName
 
-		// Удаляем состояние после успешного создания
-		delete(h.userStates, chatID)
-
-		// Форматируем и отправляем информацию о профиле
-		profileInfo := h.userService.FormatUserInfo(user)
-
-		responseText := fmt.Sprintf(`🎉 Профиль успешно создан!
-	
-	%s
-	
-	Теперь вы можете использовать команду /myprofile чтобы посмотреть свой профиль в любое время.`,
-			profileInfo)
-
-		msg := tgbotapi.NewMessage(chatID, responseText)
-		h.client.Bot.Send(msg)
-		schedules, _ := h.workScheduleService.GetAllSchedules()
-		h.userMonthlyStatService.CreateStatsForNewUser(user.ID, schedules)
-	} else if state == "awaiting_update" {
-		// Обработка обновления профиля
-		delete(h.userStates, chatID)
-
-		parts := strings.Fields(text)
-		if len(parts) < 1 {
-			msg := tgbotapi.NewMessage(chatID, "❌ Неверный формат. Пожалуйста, отправьте имя и фамилию.")
-			h.client.Bot.Send(msg)
-			return
-		}
+	if !h.advanceDialog(state, stepProfilePatronymic, data) {
+		return
+	}
 
-		firstName := parts[0]
-		lastName := ""
-		if len(parts) > 1 {
-			lastName = parts[1]
-		}
+	msg := messenger.NewMessage(chatID, tr.T("profile.create.patronymic"))
+	h.client.Send(msg)
+}
 
-		username := ""
-		if message.From.UserName != "" {
-			username = message.From.UserName
-		}
+// profilePatronymicStep сохраняет отчество и запрашивает часовой пояс
+func (h *Handler) profilePatronymicStep(message *messenger.Message, state *models.DialogState) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	var data profileCreateData
+	if err := state.DecodeData(&data); err != nil {
+		h.finishDialog(chatID)
+		msg := messenger.NewMessage(chatID, tr.T("profile.create.data_corrupted"))
+		h.client.Send(msg)
+		return
+	}
+
+	// Отчества может не быть
+	patronymic := strings.TrimSpace(message.Text)
+	if patronymic == "-" {
+		patronymic = ""
+	}
+	data.Patronymic = patronymic
+
+	if !h.advanceDialog(state, stepProfileTimezone, data) {
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("profile.create.timezone", tr.T("timezone.hint"), h.clock.Location()))
+	h.client.Send(msg)
+}
 
-		user, err := h.userService.UpdateUser(chatID, username, firstName, lastName)
+// profileTimezoneStep создает профиль из имени, фамилии и часового пояса
+func (h *Handler) profileTimezoneStep(message *messenger.Message, state *models.DialogState) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	var data profileCreateData
+	if err := state.DecodeData(&data); err != nil {
+		h.finishDialog(chatID)
+		msg := messenger.NewMessage(chatID, tr.T("profile.create.data_corrupted"))
+		h.client.Send(msg)
+		return
+	}
+
+	timezone := strings.TrimSpace(message.Text)
+	if timezone == "-" {
+		timezone = ""
+	} else {
+		zone, _, err := clock.ParseZone(timezone)
 		if err != nil {
-			msg := tgbotapi.NewMessage(chatID, "❌ Ошибка обновления профиля: "+err.Error())
-			h.client.Bot.Send(msg)
+			msg := messenger.NewMessage(chatID, tr.T("profile.create.timezone_invalid", tr.Error(err), tr.T("timezone.hint")))
+			h.client.Send(msg)
 			return
 		}
+		timezone = zone
+	}
+
+	// Получаем username
+	username := ""
+	if message.From.Username != "" {
+		username = message.From.Username
+	}
+
+	// Диалог завершается и при ошибке, и после успешного создания
+	h.finishDialog(chatID)
+
+	// Создаем профиль. Если регистрация по одобрению, профиль ждет решения HR или администратора
+	pending := h.config.Registration.RequireApproval
+	user, err := h.userService.CreateUser(chatID, username, data.FirstName, data.LastName, data.Patronymic, timezone, message.From.LanguageCode, pending)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("profile.create_failed", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	// Профиль создан на языке клиента - отвечаем на нем же
+	tr = i18n.For(user.Language)
 
-		profileInfo := h.userService.FormatUserInfo(user)
-		responseText := fmt.Sprintf(`✅ Профиль успешно обновлен!
-	
-	%s`,
-			profileInfo)
+	// Форматируем и отправляем информацию о профиле
+	profileInfo := h.userService.FormatUserInfo(tr, user)
 
-		msg := tgbotapi.NewMessage(chatID, responseText)
-		h.client.Bot.Send(msg)
+	if user.PendingApproval {
+		h.requestApproval(user)
+		msg := messenger.NewMessage(chatID, tr.T("profile.created_pending", profileInfo))
+		h.client.Send(msg)
+		return
 	}
+
+	msg := messenger.NewMessage(chatID, tr.T("profile.created", profileInfo))
+	h.client.Send(msg)
+	schedules, _ := h.workScheduleService.GetAllSchedules()
+	h.userMonthlyStatService.CreateStatsForNewUser(user.ID, schedules)
+}
+
+// profileUpdateStep обновляет имя и фамилию в профиле
+func (h *Handler) profileUpdateStep(message *messenger.Message, state *models.DialogState) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	parts := strings.Fields(message.Text)
+	if len(parts) < 1 {
+		msg := messenger.NewMessage(chatID, tr.T("profile.update.invalid"))
+		h.client.Send(msg)
+		return
+	}
+
+	h.finishDialog(chatID)
+
+	firstName := parts[0]
+	lastName := ""
+	if len(parts) > 1 {
+		lastName = parts[1]
+	}
+	patronymic := ""
+	if len(parts) > 2 {
+		patronymic = parts[2]
+	}
+
+	username := ""
+	if message.From.Username != "" {
+		username = message.From.Username
+	}
+
+	user, err := h.userService.UpdateUser(chatID, username, firstName, lastName, patronymic)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("profile.update_failed", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	profileInfo := h.userService.FormatUserInfo(tr, user)
+	msg := messenger.NewMessage(chatID, tr.T("profile.updated", profileInfo))
+	h.client.Send(msg)
 }
 
 // showProfile показывает профиль пользователя
-func (h *Handler) showProfile(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
+func (h *Handler) showProfile(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	user, err := h.userService.GetUser(chatID)
 	if err != nil || user == nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
 		return
 	}
 
-	profileInfo := h.userService.FormatUserInfo(user)
-	msg := tgbotapi.NewMessage(chatID, profileInfo)
-	h.client.Bot.Send(msg)
+	profileInfo := h.userService.FormatUserInfo(tr, user)
+	msg := messenger.NewMessage(chatID, profileInfo)
+	h.client.Send(msg)
 }
 
 // startProfileUpdate начинает процесс обновления профиля
-func (h *Handler) startProfileUpdate(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
+func (h *Handler) startProfileUpdate(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	user, err := h.userService.GetUser(chatID)
 	if err != nil || user == nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
 		return
 	}
 
-	text := `✏️ Обновление профиля
+	// Устанавливаем состояние для обновления
+	if !h.startDialog(chatID, flowProfileUpdate, stepProfileName, nil) {
+		return
+	}
 
-Отправьте новые данные в формате:
-Имя Фамилия
+	msg := messenger.NewMessage(chatID, tr.T("profile.update.prompt"))
+	h.client.Send(msg)
+}
 
-Например: *Иван Иванов*
-Или просто: *Иван* (если нужно обновить только имя)`
+// setTimezone задает часовой пояс пользователя
+func (h *Handler) setTimezone(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
-	msg := tgbotapi.NewMessage(chatID, text)
-	h.client.Bot.Send(msg)
+	args = strings.TrimSpace(args)
+	if args == "" {
+		msg := messenger.NewMessage(chatID, tr.T("timezone.usage", tr.T("timezone.hint")))
+		h.client.Send(msg)
+		return
+	}
+	if args == "-" {
+		args = ""
+	}
 
-	// Устанавливаем состояние для обновления
-	h.userStates[chatID] = "awaiting_update"
+	user, err := h.userService.SetTimezone(chatID, args)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("timezone.set_failed", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	now := h.clock.Now().In(user.Location(h.clock.Location()))
+	msg := messenger.NewMessage(chatID, tr.T("timezone.set", user.Location(h.clock.Location()), tr.DateTime(now)))
+	h.client.Send(msg)
 }
 
 // deleteProfile удаляет профиль пользователя
-func (h *Handler) deleteProfile(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
+func (h *Handler) deleteProfile(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	// Создаем inline клавиатуру для подтверждения
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("✅ Да, удалить", "confirm_delete"),
-			tgbotapi.NewInlineKeyboardButtonData("❌ Нет, отменить", "cancel_delete"),
+	keyboard := [][]messenger.Button{
+		messenger.NewRow(
+			messenger.NewButton(tr.T("button.yes_delete"), "confirm_delete"),
+			messenger.NewButton(tr.T("button.no_cancel"), "cancel_delete"),
 		),
-	)
+	}
 
-	msg := tgbotapi.NewMessage(chatID, "⚠️ Вы уверены, что хотите удалить свой профиль?\nЭто действие нельзя отменить.")
-	msg.ReplyMarkup = keyboard
-	h.client.Bot.Send(msg)
+	msg := messenger.NewMessage(chatID, tr.T("profile.delete_confirm"))
+	msg.Buttons = keyboard
+	h.client.Send(msg)
 }
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/messenger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Сценарий подтверждения импорта рабочих дней
+const (
+	flowSessionsImport = "sessions_import"
+
+	stepImportConfirm = "confirm"
+)
+
+// maxImportFileSize - наибольший размер файла импорта
+const maxImportFileSize = 1 << 20
+
+// sessionImportData - проверенный файл, ожидающий подтверждения импорта
+type sessionImportData struct {
+	FileID   string `json:"file_id"`
+	FileName string `json:"file_name"`
+}
+
+// importSessions проверяет CSV с историей рабочих дней (dry run) и предлагает импортировать его.
+// Файл отправляется документом с командой /importsessions в подписи.
+func (h *Handler) importSessions(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	document := message.Document
+	if document == nil {
+		msg := messenger.NewMessage(chatID, tr.T("import.usage"))
+		h.client.Send(msg)
+		return
+	}
+	if document.Size > maxImportFileSize {
+		msg := messenger.NewMessage(chatID, tr.T("import.too_large", maxImportFileSize>>10))
+		h.client.Send(msg)
+		return
+	}
+
+	data, err := h.client.Download(document.ID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to download import file")
+		msg := messenger.NewMessage(chatID, tr.T("import.download_failed", err))
+		h.client.Send(msg)
+		return
+	}
+
+	report, err := h.sessionImportService.DryRun(data)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("import.failed", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	text := h.sessionImportService.FormatReport(tr, report)
+	if report.HasErrors() {
+		msg := messenger.NewMessage(chatID, text+"\n\n"+tr.T("import.fix_and_resend"))
+		h.client.Send(msg)
+		return
+	}
+
+	// Файл запоминается до подтверждения: при импорте он скачивается и проверяется заново
+	if !h.startDialog(chatID, flowSessionsImport, stepImportConfirm, sessionImportData{
+		FileID:   document.ID,
+		FileName: document.Name,
+	}) {
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, text+"\n\n"+tr.T("import.confirm_question"))
+	msg.Buttons = [][]messenger.Button{
+		messenger.NewRow(
+			messenger.NewButton(tr.T("button.import"), "confirm_import_sessions"),
+			messenger.NewButton(tr.T("button.cancel"), "cancel_import_sessions"),
+		),
+	}
+	h.client.Send(msg)
+}
+
+// importConfirmStep отвечает на текст, пока импорт ждет подтверждения кнопкой
+func (h *Handler) importConfirmStep(message *messenger.Message, state *models.DialogState) {
+	msg := messenger.NewMessage(message.ChatID, h.localizer(message).T("import.confirm_pending"))
+	h.client.Send(msg)
+}
+
+// handleImportCallback выполняет или отменяет проверенный импорт
+func (h *Handler) handleImportCallback(callback *messenger.Callback) {
+	chatID := callback.ChatID
+	tr := h.chatLocalizer(chatID, callback.From.LanguageCode)
+	defer h.client.AnswerCallback(callback.ID, "")
+
+	state, err := h.dialogService.Current(chatID)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.generic", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+	if state == nil || state.Flow != flowSessionsImport {
+		msg := messenger.NewMessage(chatID, tr.T("import.expired"))
+		h.client.Send(msg)
+		return
+	}
+	h.finishDialog(chatID)
+
+	if callback.Data == "cancel_import_sessions" {
+		msg := messenger.NewMessage(chatID, tr.T("import.cancelled"))
+		h.client.Send(msg)
+		return
+	}
+	if state.IsExpired(h.clock.Now()) {
+		msg := messenger.NewMessage(chatID, tr.T("import.expired"))
+		h.client.Send(msg)
+		return
+	}
+
+	var file sessionImportData
+	if err := state.DecodeData(&file); err != nil {
+		logrus.WithError(err).Error("Failed to decode import dialog data")
+		msg := messenger.NewMessage(chatID, tr.T("import.expired"))
+		h.client.Send(msg)
+		return
+	}
+
+	data, err := h.client.Download(file.FileID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to download import file")
+		msg := messenger.NewMessage(chatID, tr.T("import.download_failed", err))
+		h.client.Send(msg)
+		return
+	}
+
+	report, err := h.sessionImportService.Import(data, models.ChatActor(chatID))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to import sessions")
+		msg := messenger.NewMessage(chatID, tr.T("import.failed", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"chat_id":   chatID,
+		"file":      file.FileName,
+		"committed": report.Committed,
+	}).Info("Sessions import finished")
+
+	text := h.sessionImportService.FormatReport(tr, report)
+	if report.HasErrors() {
+		// Данные в базе изменились после проверки
+		text += "\n\n" + tr.T("import.fix_and_resend")
+	} else {
+		text += "\n\n" + tr.T("import.closed_months_hint")
+	}
+	h.client.Send(messenger.NewMessage(chatID, text))
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/messenger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// showTeams показывает отделы и команды
+func (h *Handler) showTeams(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	text, err := h.teamService.FormatTeams(tr)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to format teams")
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, text)
+	h.client.Send(msg)
+}
+
+// addDepartment создает отдел (админы)
+func (h *Handler) addDepartment(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	if strings.TrimSpace(args) == "" {
+		msg := messenger.NewMessage(chatID, tr.T("team.department_usage"))
+		h.client.Send(msg)
+		return
+	}
+
+	department, err := h.teamService.CreateDepartment(args, models.ChatActor(chatID))
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("team.department_created", department.Name, department.ID, department.ID))
+	h.client.Send(msg)
+}
+
+// deleteDepartment удаляет отдел без команд (админы)
+func (h *Handler) deleteDepartment(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	id, err := strconv.ParseUint(strings.TrimSpace(args), 10, 32)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("team.department_delete_usage"))
+		h.client.Send(msg)
+		return
+	}
+
+	if err := h.teamService.DeleteDepartment(uint(id), models.ChatActor(chatID)); err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("team.department_delete_failed", err))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("team.department_deleted", id))
+	h.client.Send(msg)
+}
+
+// addTeam создает команду в отделе (админы)
+func (h *Handler) addTeam(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	parts := strings.Fields(args)
+	if len(parts) < 2 {
+		msg := messenger.NewMessage(chatID, tr.T("team.add_usage"))
+		h.client.Send(msg)
+		return
+	}
+
+	departmentID, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("team.invalid_department_id"))
+		h.client.Send(msg)
+		return
+	}
+
+	team, err := h.teamService.CreateTeam(uint(departmentID), strings.Join(parts[1:], " "), models.ChatActor(chatID))
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("team.created", team.Name, team.Department.Name, team.ID, team.ID))
+	h.client.Send(msg)
+}
+
+// deleteTeam удаляет команду (админы)
+func (h *Handler) deleteTeam(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	id, err := strconv.ParseUint(strings.TrimSpace(args), 10, 32)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("team.delete_usage"))
+		h.client.Send(msg)
+		return
+	}
+
+	if err := h.teamService.DeleteTeam(uint(id), models.ChatActor(chatID)); err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("team.delete_failed", err))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("team.deleted", id))
+	h.client.Send(msg)
+}
+
+// setUserTeam добавляет пользователя в команду (админы)
+func (h *Handler) setUserTeam(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	parts := strings.Fields(args)
+	if len(parts) != 2 {
+		msg := messenger.NewMessage(chatID, tr.T("team.set_usage"))
+		h.client.Send(msg)
+		return
+	}
+
+	targetChatID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("user.invalid_id"))
+		h.client.Send(msg)
+		return
+	}
+
+	teamID, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("team.invalid_id"))
+		h.client.Send(msg)
+		return
+	}
+
+	user, err := h.teamService.AssignUser(targetChatID, uint(teamID), models.ChatActor(chatID))
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	if teamID == 0 {
+		msg := messenger.NewMessage(chatID, tr.T("team.user_removed", user.FirstName, user.LastName))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("team.user_added", user.FirstName, user.LastName, teamID))
+	h.client.Send(msg)
+
+	// Новый профиль теперь может одобрить и руководитель команды
+	if user.PendingApproval {
+		h.requestTeamApproval(user)
+	}
+}
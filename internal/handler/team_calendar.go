@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/messenger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// showTeamCalendar показывает отсутствия всех сотрудников за месяц
+func (h *Handler) showTeamCalendar(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	// Календарь доступен только пользователям с профилем
+	user, err := h.userService.GetUser(chatID)
+	if err != nil || user == nil {
+		logrus.WithField("chat_id", chatID).Warn("User not found for team calendar")
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
+		return
+	}
+
+	now := h.clock.Now()
+	year, month := now.Year(), int(now.Month())
+	asImage := false
+
+	// По умолчанию показываем команду пользователя, "all" - всю компанию
+	team := user.Team
+
+	var parts []string
+	for _, part := range strings.Fields(args) {
+		switch strings.ToLower(part) {
+		case "image", "img", "картинка":
+			asImage = true
+		case "all", "все":
+			team = nil
+		default:
+			parts = append(parts, part)
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+	case 1:
+		month, err = strconv.Atoi(parts[0])
+		if err != nil || month < 1 || month > 12 {
+			msg := messenger.NewMessage(chatID, tr.T("date.invalid_month_reply"))
+			h.client.Send(msg)
+			return
+		}
+	case 2:
+		year, err = strconv.Atoi(parts[0])
+		if policy := h.config.WorkPolicy(); err != nil || !policy.ValidYear(year) {
+			msg := messenger.NewMessage(chatID, tr.T("date.invalid_year_reply", policy.MinYear, policy.MaxYear))
+			h.client.Send(msg)
+			return
+		}
+		month, err = strconv.Atoi(parts[1])
+		if err != nil || month < 1 || month > 12 {
+			msg := messenger.NewMessage(chatID, tr.T("date.invalid_month_reply"))
+			h.client.Send(msg)
+			return
+		}
+	default:
+		msg := messenger.NewMessage(chatID, tr.T("calendar.usage"))
+		h.client.Send(msg)
+		return
+	}
+
+	cal, err := h.teamCalendarService.GetMonth(team, year, month)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get team calendar")
+		msg := messenger.NewMessage(chatID, tr.T("calendar.get_failed", err))
+		h.client.Send(msg)
+		return
+	}
+
+	if asImage && len(cal.Users) > 0 {
+		data, err := h.teamCalendarService.RenderImage(cal)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to render team calendar")
+			msg := messenger.NewMessage(chatID, tr.T("calendar.render_failed", err))
+			h.client.Send(msg)
+			return
+		}
+
+		photo := messenger.NewImage(chatID, fmt.Sprintf("calendar_%d_%02d.png", year, month), data,
+			h.teamCalendarService.FormatImageLegend(tr, cal))
+		if err := h.client.Send(photo); err != nil {
+			logrus.WithError(err).Error("Failed to send team calendar image")
+		}
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, h.teamCalendarService.FormatCalendar(tr, cal))
+	h.client.Send(msg)
+}
+
+// formatOverloadedDaysWarning формирует предупреждение о днях с превышением порога отсутствующих в команде
+func (h *Handler) formatOverloadedDaysWarning(tr *i18n.Localizer, user *models.User, startDate, endDate time.Time) string {
+	days, err := h.teamCalendarService.GetOverloadedDays(user.Team, startDate, endDate)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to check team absence threshold")
+		return ""
+	}
+
+	if len(days) == 0 {
+		return ""
+	}
+
+	var dates []string
+	for _, day := range days {
+		dates = append(dates, tr.ShortDate(day))
+	}
+
+	return tr.T("calendar.overloaded_warning", h.teamCalendarService.GetThresholdPercent(), strings.Join(dates, ", "),
+		startDate.Year(), int(startDate.Month()))
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/clock"
+	"work-schedule-bot/pkg/messenger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// showBalance показывает банк времени пользователя (админ или руководитель может указать ID)
+func (h *Handler) showBalance(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	user, err := h.userService.GetUser(chatID)
+	if err != nil || user == nil {
+		logrus.WithField("chat_id", chatID).Warn("User not found for balance")
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
+		return
+	}
+
+	// Чужой баланс доступен администраторам и руководителю команды
+	if args = strings.TrimSpace(args); args != "" {
+		targetChatID, err := strconv.ParseInt(args, 10, 64)
+		if err != nil {
+			msg := messenger.NewMessage(chatID, tr.T("user.invalid_id"))
+			h.client.Send(msg)
+			return
+		}
+
+		user, err = h.userService.GetManagedUser(chatID, targetChatID, models.PermTimeBankView)
+		if err != nil {
+			logrus.WithField("chat_id", chatID).Warn("Unauthorized access to balance of another user")
+			msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+			h.client.Send(msg)
+			return
+		}
+	}
+
+	entries, err := h.timeBankService.GetLedger(user.ID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get time bank ledger")
+		msg := messenger.NewMessage(chatID, tr.T("timebank.get_failed_reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, h.timeBankService.FormatLedger(tr, user, entries))
+	h.client.Send(msg)
+}
+
+// closeMonth переносит итоги месяца в банк времени (админы)
+func (h *Handler) closeMonth(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+	var err error
+
+	// По умолчанию закрываем прошлый месяц
+	now := h.clock.Now()
+	prev := clock.MonthStart(now.Year(), now.Month(), h.clock.Location()).AddDate(0, -1, 0)
+	year, month := prev.Year(), int(prev.Month())
+
+	parts := strings.Fields(args)
+	if len(parts) != 0 && len(parts) != 2 {
+		msg := messenger.NewMessage(chatID,
+			tr.T("timebank.close_usage"))
+		h.client.Send(msg)
+		return
+	}
+
+	if len(parts) == 2 {
+		year, err = strconv.Atoi(parts[0])
+		if err != nil {
+			msg := messenger.NewMessage(chatID, tr.T("date.invalid_year_format"))
+			h.client.Send(msg)
+			return
+		}
+		month, err = strconv.Atoi(parts[1])
+		if err != nil {
+			msg := messenger.NewMessage(chatID, tr.T("date.invalid_month_format"))
+			h.client.Send(msg)
+			return
+		}
+	}
+
+	count, err := h.timeBankService.CloseMonth(year, month, chatID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to close month")
+		msg := messenger.NewMessage(chatID, tr.T("timebank.close_failed", err))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("timebank.closed", month, year, count))
+	h.client.Send(msg)
+}
+
+// bankCaps показывает или изменяет лимиты переноса итогов месяца (админы)
+func (h *Handler) bankCaps(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	parts := strings.Fields(args)
+	if len(parts) == 0 {
+		settings, err := h.timeBankService.GetSettings()
+		if err != nil {
+			msg := messenger.NewMessage(chatID, tr.T("timebank.settings_failed_reply", err))
+			h.client.Send(msg)
+			return
+		}
+
+		msg := messenger.NewMessage(chatID, tr.T("timebank.caps", settings.MaxMonthlySurplusMinutes, settings.MaxMonthlyDeficitMinutes))
+		h.client.Send(msg)
+		return
+	}
+
+	if len(parts) != 2 {
+		msg := messenger.NewMessage(chatID, tr.T("timebank.caps_usage"))
+		h.client.Send(msg)
+		return
+	}
+
+	maxSurplus, err1 := strconv.Atoi(parts[0])
+	maxDeficit, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		msg := messenger.NewMessage(chatID, tr.T("timebank.caps_not_numbers"))
+		h.client.Send(msg)
+		return
+	}
+
+	settings, err := h.timeBankService.UpdateSettings(maxSurplus, maxDeficit, chatID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to update time bank settings")
+		msg := messenger.NewMessage(chatID, tr.T("timebank.caps_save_failed", err))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("timebank.caps_updated", settings.MaxMonthlySurplusMinutes, settings.MaxMonthlyDeficitMinutes))
+	h.client.Send(msg)
+}
+
+// bankAdjust вручную корректирует банк времени пользователя (админы и руководители своей команды)
+func (h *Handler) bankAdjust(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	parts := strings.Fields(args)
+	if len(parts) < 3 {
+		msg := messenger.NewMessage(chatID,
+			tr.T("timebank.adjust_usage"))
+		h.client.Send(msg)
+		return
+	}
+
+	targetChatID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("user.invalid_id"))
+		h.client.Send(msg)
+		return
+	}
+
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("timebank.adjust_minutes_invalid"))
+		h.client.Send(msg)
+		return
+	}
+
+	reason := strings.Join(parts[2:], " ")
+
+	targetUser, err := h.userService.GetManagedUser(chatID, targetChatID, models.PermTimeBankAdjust)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	if _, err := h.timeBankService.Adjust(targetUser.ID, minutes, reason, chatID); err != nil {
+		logrus.WithError(err).Error("Failed to adjust time bank")
+		msg := messenger.NewMessage(chatID, tr.T("timebank.adjust_failed_reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	balance, err := h.timeBankService.GetBalance(targetUser.ID)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to get balance after adjustment")
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("timebank.adjusted", targetUser.FirstName, targetUser.LastName,
+		i18n.SignedMinutes(minutes), reason, i18n.SignedMinutes(balance)))
+	h.client.Send(msg)
+}
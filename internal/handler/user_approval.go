@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/messenger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Префиксы callback-данных кнопок одобрения нового профиля
+const (
+	callbackApproveUser = "approve_user_"
+	callbackRejectUser  = "reject_user_"
+)
+
+// approvalButtons - кнопки одобрения и отклонения профиля
+func approvalButtons(tr *i18n.Localizer, chatID int64) [][]messenger.Button {
+	id := strconv.FormatInt(chatID, 10)
+	return [][]messenger.Button{
+		messenger.NewRow(
+			messenger.NewButton(tr.T("button.approve"), callbackApproveUser+id),
+			messenger.NewButton(tr.T("button.reject"), callbackRejectUser+id),
+		),
+	}
+}
+
+// approvalRequestText описывает профиль, ожидающий одобрения
+func approvalRequestText(tr *i18n.Localizer, user *models.User) string {
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if user.Username != "" {
+		name += " (@" + user.Username + ")"
+	}
+	return tr.T("user.approval_request", name, user.ChatID)
+}
+
+// requestApproval сообщает о новом профиле всем, кто может его одобрить:
+// ролям уровня компании и руководителям команды профиля
+func (h *Handler) requestApproval(user *models.User) {
+	h.sendApprovalRequests(user, func(approver *models.User) bool { return true })
+}
+
+// requestTeamApproval сообщает о профиле руководителям команды, в которую его добавили.
+// Роли уровня компании уже получили запрос при регистрации.
+func (h *Handler) requestTeamApproval(user *models.User) {
+	h.sendApprovalRequests(user, func(approver *models.User) bool { return !approver.HasCompanyScope() })
+}
+
+// sendApprovalRequests отправляет запрос на одобрение профиля тем, кто может по нему решать и проходит filter
+func (h *Handler) sendApprovalRequests(user *models.User, filter func(approver *models.User) bool) {
+	approvers, err := h.userService.GetActiveUsers()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get approvers for new profile")
+		return
+	}
+
+	for _, approver := range approvers {
+		if !approver.CanManage(user, models.PermUsersApprove) || !filter(approver) {
+			continue
+		}
+
+		tr := h.chatLocalizer(approver.ChatID, "")
+		msg := messenger.NewMessage(approver.ChatID, approvalRequestText(tr, user))
+		msg.Buttons = approvalButtons(tr, user.ChatID)
+		if err := h.client.Send(msg); err != nil {
+			logrus.WithError(err).WithField("approver", approver.ChatID).Warn("Failed to send approval request")
+		}
+	}
+}
+
+// showPendingUsers показывает профили, ожидающие одобрения, с кнопками решения
+func (h *Handler) showPendingUsers(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	users, err := h.userService.GetPendingUsers(chatID)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	if len(users) == 0 {
+		h.client.Send(messenger.NewMessage(chatID, tr.T("users.pending_empty")))
+		return
+	}
+
+	h.client.Send(messenger.NewMessage(chatID, tr.N("users.pending_count", len(users))))
+	for _, user := range users {
+		msg := messenger.NewMessage(chatID, approvalRequestText(tr, user))
+		msg.Buttons = approvalButtons(tr, user.ChatID)
+		h.client.Send(msg)
+	}
+}
+
+// approveUser одобряет профиль командой /approve
+func (h *Handler) approveUser(message *messenger.Message, args string) {
+	targetChatID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
+	if err != nil {
+		tr := h.localizer(message)
+		h.client.Send(messenger.NewMessage(message.ChatID, tr.T("usage.approve")))
+		return
+	}
+
+	h.decideApproval(message.ChatID, message.From.LanguageCode, targetChatID, true)
+}
+
+// rejectUser отклоняет профиль командой /reject
+func (h *Handler) rejectUser(message *messenger.Message, args string) {
+	targetChatID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
+	if err != nil {
+		tr := h.localizer(message)
+		h.client.Send(messenger.NewMessage(message.ChatID, tr.T("usage.reject")))
+		return
+	}
+
+	h.decideApproval(message.ChatID, message.From.LanguageCode, targetChatID, false)
+}
+
+// handleApprovalCallback обрабатывает кнопки одобрения и отклонения профиля
+func (h *Handler) handleApprovalCallback(callback *messenger.Callback) {
+	approve := strings.HasPrefix(callback.Data, callbackApproveUser)
+	idStr := strings.TrimPrefix(strings.TrimPrefix(callback.Data, callbackApproveUser), callbackRejectUser)
+
+	targetChatID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		tr := h.chatLocalizer(callback.ChatID, callback.From.LanguageCode)
+		h.client.Send(messenger.NewMessage(callback.ChatID, tr.T("user.invalid_id")))
+		return
+	}
+
+	h.decideApproval(callback.ChatID, callback.From.LanguageCode, targetChatID, approve)
+}
+
+// decideApproval одобряет или отклоняет профиль и сообщает о решении его владельцу
+func (h *Handler) decideApproval(chatID int64, languageCode string, targetChatID int64, approve bool) {
+	tr := h.chatLocalizer(chatID, languageCode)
+	actor := models.ChatActor(chatID)
+
+	var user *models.User
+	var err error
+	if approve {
+		user, err = h.userService.ApproveUser(targetChatID, actor)
+	} else {
+		user, err = h.userService.RejectUser(targetChatID, actor)
+	}
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"chat_id":  chatID,
+		"target":   targetChatID,
+		"approved": approve,
+	}).Info("New profile reviewed")
+
+	// Отклоненного профиля больше нет: отвечаем на языке, выбранном при регистрации
+	userTr := i18n.For(user.Language)
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if approve {
+		h.client.Send(messenger.NewMessage(targetChatID, userTr.T("profile.approved")))
+		h.client.Send(messenger.NewMessage(chatID, tr.T("user.approved", name)))
+	} else {
+		h.client.Send(messenger.NewMessage(targetChatID, userTr.T("profile.rejected")))
+		h.client.Send(messenger.NewMessage(chatID, tr.T("user.rejected", name)))
+	}
+}
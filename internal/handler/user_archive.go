@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/clock"
+	"work-schedule-bot/pkg/messenger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// inactiveCommands - команды, доступные деактивированному сотруднику и профилю, ожидающему одобрения
+var inactiveCommands = map[string]bool{
+	"start":     true,
+	"help":      true,
+	"myprofile": true,
+	"language":  true,
+	"cancel":    true,
+}
+
+// checkActive не пропускает действия деактивированного сотрудника и неодобренного профиля,
+// кроме просмотра профиля и справки.
+// Незарегистрированные пользователи проходят проверку: их команды проверяются дальше.
+func (h *Handler) checkActive(update messenger.Update) bool {
+	var chatID int64
+	var languageCode string
+	switch {
+	case update.Callback != nil:
+		chatID, languageCode = update.Callback.ChatID, update.Callback.From.LanguageCode
+	case update.Message != nil:
+		if update.Message.IsCommand() && inactiveCommands[update.Message.Command] {
+			return true
+		}
+		chatID, languageCode = update.Message.ChatID, update.Message.From.LanguageCode
+	default:
+		return true
+	}
+
+	user, err := h.userService.FindUser(chatID)
+	if err != nil || user == nil || user.CanWork() {
+		return true
+	}
+
+	tr := h.chatLocalizer(chatID, languageCode)
+	text := tr.T("profile.pending")
+	if !user.IsActive() {
+		text = tr.T("profile.deactivated", *user.TerminationDate)
+	}
+	h.client.Send(messenger.NewMessage(chatID, text))
+	return false
+}
+
+// deactivateUser деактивирует сотрудника с даты увольнения (по умолчанию - сегодня)
+func (h *Handler) deactivateUser(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	parts := strings.Fields(args)
+	if len(parts) == 0 || len(parts) > 2 {
+		msg := messenger.NewMessage(chatID, tr.T("usage.deactivate"))
+		h.client.Send(msg)
+		return
+	}
+
+	targetChatID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("user.invalid_id"))
+		h.client.Send(msg)
+		return
+	}
+
+	// Главного администратора из конфига деактивировать нельзя
+	if targetChatID == h.config.Telegram.AdminChatID && h.config.Telegram.AdminChatID != 0 {
+		msg := messenger.NewMessage(chatID, tr.T("user.deactivate_base_admin"))
+		h.client.Send(msg)
+		return
+	}
+
+	today := clock.Today(h.clock)
+	terminationDate := today
+	if len(parts) == 2 {
+		terminationDate, err = parseDate(parts[1], h.clock.Now())
+		if err != nil {
+			msg := messenger.NewMessage(chatID, tr.T("absence.date_invalid", err))
+			h.client.Send(msg)
+			return
+		}
+		if terminationDate.After(today) {
+			msg := messenger.NewMessage(chatID, tr.T("user.termination_in_future"))
+			h.client.Send(msg)
+			return
+		}
+	}
+
+	user, err := h.userService.DeactivateUser(targetChatID, terminationDate, models.ChatActor(chatID))
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("user.deactivated", user.FirstName, user.LastName, terminationDate))
+	h.client.Send(msg)
+}
+
+// reactivateUser возвращает деактивированного сотрудника в работающие
+func (h *Handler) reactivateUser(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	targetChatID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("usage.reactivate"))
+		h.client.Send(msg)
+		return
+	}
+
+	user, err := h.userService.ReactivateUser(targetChatID, models.ChatActor(chatID))
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("user.reactivated", user.FirstName, user.LastName))
+	h.client.Send(msg)
+}
+
+// purgeUser просит подтвердить безвозвратное удаление деактивированного сотрудника
+func (h *Handler) purgeUser(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	targetChatID, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("usage.purgeuser"))
+		h.client.Send(msg)
+		return
+	}
+
+	user, err := h.userService.GetUser(targetChatID)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+	if user.IsActive() {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", tr.T("user.purge_active")))
+		h.client.Send(msg)
+		return
+	}
+
+	keyboard := [][]messenger.Button{
+		messenger.NewRow(
+			messenger.NewButton(tr.T("button.yes_purge"), "confirm_purge_"+strconv.FormatInt(targetChatID, 10)),
+			messenger.NewButton(tr.T("button.no_cancel"), "cancel_purge"),
+		),
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("user.purge_confirm", user.FirstName, user.LastName, user.ChatID))
+	msg.Buttons = keyboard
+	h.client.Send(msg)
+}
+
+// handlePurgeCallback удаляет сотрудника после подтверждения
+func (h *Handler) handlePurgeCallback(callback *messenger.Callback) {
+	chatID := callback.ChatID
+	tr := h.chatLocalizer(chatID, callback.From.LanguageCode)
+
+	if callback.Data == "cancel_purge" {
+		h.client.Send(messenger.NewMessage(chatID, tr.T("user.purge_cancelled")))
+		return
+	}
+
+	targetChatID, err := strconv.ParseInt(strings.TrimPrefix(callback.Data, "confirm_purge_"), 10, 64)
+	if err != nil {
+		h.client.Send(messenger.NewMessage(chatID, tr.T("user.invalid_id")))
+		return
+	}
+
+	if err := h.userService.PurgeUser(targetChatID, models.ChatActor(chatID)); err != nil {
+		logrus.WithError(err).Error("Failed to purge user")
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", tr.Error(err)))
+		h.client.Send(msg)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"chat_id": chatID,
+		"target":  targetChatID,
+	}).Warn("User purged by command")
+
+	msg := messenger.NewMessage(chatID, tr.T("user.purged", targetChatID))
+	h.client.Send(msg)
+}
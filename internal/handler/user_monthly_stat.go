@@ -6,21 +6,24 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/messenger"
 
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/sirupsen/logrus"
 )
 
 // getMyMonthlyStats показывает статистику пользователя за все месяцы
-func (h *Handler) getMyMonthlyStats(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
+func (h *Handler) getMyMonthlyStats(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	// Получаем пользователя
 	user, err := h.userService.GetUser(chatID)
 	if err != nil || user == nil {
 		logrus.WithField("chat_id", chatID).Warn("User not found for stats")
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
 		return
 	}
 
@@ -28,32 +31,33 @@ func (h *Handler) getMyMonthlyStats(message *tgbotapi.Message) {
 	stats, err := h.userMonthlyStatService.GetUserStats(user.ID)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get user monthly stats")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка получения статистики: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("users.stats_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
 	// Форматируем результат
-	formatted := h.userMonthlyStatService.FormatStatsList(stats)
-	msg := tgbotapi.NewMessage(chatID, formatted)
-	h.client.Bot.Send(msg)
+	formatted := h.userMonthlyStatService.FormatStatsList(tr, stats)
+	msg := messenger.NewMessage(chatID, formatted)
+	h.client.Send(msg)
 }
 
 // getMonthlyStat показывает статистику за конкретный месяц
-func (h *Handler) getMonthlyStat(message *tgbotapi.Message, args string) {
-	chatID := message.Chat.ID
+func (h *Handler) getMonthlyStat(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	// Получаем пользователя
 	user, err := h.userService.GetUser(chatID)
 	if err != nil || user == nil {
 		logrus.WithField("chat_id", chatID).Warn("User not found for monthly stat")
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
 		return
 	}
 
 	var year, month int
-	now := time.Now()
+	now := h.clock.Now()
 
 	if args == "" {
 		// Если месяц не указан, используем текущий
@@ -66,29 +70,29 @@ func (h *Handler) getMonthlyStat(message *tgbotapi.Message, args string) {
 			// Только месяц
 			month, err = strconv.Atoi(parts[0])
 			if err != nil || month < 1 || month > 12 {
-				msg := tgbotapi.NewMessage(chatID, "❌ Неверный месяц. Используйте число от 1 до 12.")
-				h.client.Bot.Send(msg)
+				msg := messenger.NewMessage(chatID, tr.T("date.invalid_month_reply"))
+				h.client.Send(msg)
 				return
 			}
 			year = now.Year()
 		} else if len(parts) == 2 {
 			// Год и месяц
 			year, err = strconv.Atoi(parts[0])
-			if err != nil || year < 2000 || year > 2100 {
-				msg := tgbotapi.NewMessage(chatID, "❌ Неверный год. Используйте год между 2000 и 2100.")
-				h.client.Bot.Send(msg)
+			if policy := h.config.WorkPolicy(); err != nil || !policy.ValidYear(year) {
+				msg := messenger.NewMessage(chatID, tr.T("date.invalid_year_reply", policy.MinYear, policy.MaxYear))
+				h.client.Send(msg)
 				return
 			}
 
 			month, err = strconv.Atoi(parts[1])
 			if err != nil || month < 1 || month > 12 {
-				msg := tgbotapi.NewMessage(chatID, "❌ Неверный месяц. Используйте число от 1 до 12.")
-				h.client.Bot.Send(msg)
+				msg := messenger.NewMessage(chatID, tr.T("date.invalid_month_reply"))
+				h.client.Send(msg)
 				return
 			}
 		} else {
-			msg := tgbotapi.NewMessage(chatID, "❌ Неверный формат. Используйте: /stat [год месяц] или /stat [месяц]")
-			h.client.Bot.Send(msg)
+			msg := messenger.NewMessage(chatID, tr.T("stats.stat_usage"))
+			h.client.Send(msg)
 			return
 		}
 	}
@@ -97,34 +101,34 @@ func (h *Handler) getMonthlyStat(message *tgbotapi.Message, args string) {
 	stat, err := h.userMonthlyStatService.GetUserStatByMonth(user.ID, year, month)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get user monthly stat")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка получения статистики: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("users.stats_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
 	if stat == nil {
-		monthName := time.Month(month).String()
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("📭 Статистика за %s %d отсутствует.", monthName, year))
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("stats.month_not_found", tr.MonthYear(year, time.Month(month))))
+		h.client.Send(msg)
 		return
 	}
 
 	// Форматируем результат
-	formatted := h.userMonthlyStatService.FormatStat(stat)
-	msg := tgbotapi.NewMessage(chatID, formatted)
-	h.client.Bot.Send(msg)
+	formatted := h.userMonthlyStatService.FormatStat(tr, stat)
+	msg := messenger.NewMessage(chatID, formatted)
+	h.client.Send(msg)
 }
 
 // getCurrentMonthStat показывает статистику за текущий месяц
-func (h *Handler) getCurrentMonthStat(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
+func (h *Handler) getCurrentMonthStat(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	// Получаем пользователя
 	user, err := h.userService.GetUser(chatID)
 	if err != nil || user == nil {
 		logrus.WithField("chat_id", chatID).Warn("User not found for current stat")
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
 		return
 	}
 
@@ -132,21 +136,259 @@ func (h *Handler) getCurrentMonthStat(message *tgbotapi.Message) {
 	stat, err := h.userMonthlyStatService.GetCurrentMonthStat(user.ID)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get current month stat")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка получения статистики: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("users.stats_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
 	if stat == nil {
-		now := time.Now()
-		monthName := now.Month().String()
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("📭 Статистика за текущий месяц (%s %d) отсутствует.", monthName, now.Year()))
-		h.client.Bot.Send(msg)
+		now := h.clock.Now()
+		msg := messenger.NewMessage(chatID, tr.T("stats.current_month_not_found", tr.MonthYear(now.Year(), now.Month())))
+		h.client.Send(msg)
 		return
 	}
 
 	// Форматируем результат
-	formatted := h.userMonthlyStatService.FormatStat(stat)
-	msg := tgbotapi.NewMessage(chatID, formatted)
-	h.client.Bot.Send(msg)
+	formatted := h.userMonthlyStatService.FormatStat(tr, stat)
+	msg := messenger.NewMessage(chatID, formatted)
+	h.client.Send(msg)
+}
+
+// getUserMonthlyStat показывает статистику сотрудника (админы и руководители своей команды)
+func (h *Handler) getUserMonthlyStat(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	parts := strings.Fields(args)
+	if len(parts) == 0 {
+		msg := messenger.NewMessage(chatID, tr.T("stats.userstat_usage"))
+		h.client.Send(msg)
+		return
+	}
+
+	targetChatID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("user.invalid_id"))
+		h.client.Send(msg)
+		return
+	}
+
+	targetUser, err := h.userService.GetManagedUser(chatID, targetChatID, models.PermStatsView)
+	if err != nil {
+		logrus.WithField("chat_id", chatID).Warn("Unauthorized access to user stat")
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	year, month, err := h.parseYearMonthArgs(parts[1:], h.clock.Now())
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	stat, err := h.userMonthlyStatService.GetUserStatByMonth(targetUser.ID, year, month)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get user monthly stat")
+		msg := messenger.NewMessage(chatID, tr.T("users.stats_failed", err))
+		h.client.Send(msg)
+		return
+	}
+
+	if stat == nil {
+		msg := messenger.NewMessage(chatID, tr.T("stats.user_not_found", targetUser.FirstName, targetUser.LastName, month, year))
+		h.client.Send(msg)
+		return
+	}
+
+	formatted := tr.T("stats.user_stat", targetUser.FirstName, targetUser.LastName,
+		h.userMonthlyStatService.FormatStat(tr, stat))
+	msg := messenger.NewMessage(chatID, formatted)
+	h.client.Send(msg)
+}
+
+// recalcMonthlyStats пересчитывает статистику из рабочих сессий, отсутствий и графиков.
+// Без аргументов - все пользователи за все месяцы, [ID] - один пользователь,
+// [год месяц] - все пользователи за месяц, [ID год месяц] - один пользователь за месяц.
+func (h *Handler) recalcMonthlyStats(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	parts := strings.Fields(args)
+	if len(parts) > 3 {
+		msg := messenger.NewMessage(chatID, tr.T("stats.recalc_usage"))
+		h.client.Send(msg)
+		return
+	}
+
+	users, err := h.userService.GetAllUsers()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get users for stats recalculation")
+		msg := messenger.NewMessage(chatID, tr.T("users.get_failed_reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	targets := users
+	if len(parts)%2 == 1 {
+		targetChatID, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			msg := messenger.NewMessage(chatID, tr.T("user.invalid_id"))
+			h.client.Send(msg)
+			return
+		}
+
+		targetUser, err := h.userService.GetManagedUser(chatID, targetChatID, models.PermStatsManage)
+		if err != nil {
+			msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+			h.client.Send(msg)
+			return
+		}
+		targets = []*models.User{targetUser}
+		parts = parts[1:]
+	}
+
+	// Без месяца пересчитываются все месяцы
+	year, month := 0, 0
+	if len(parts) == 2 {
+		year, month, err = h.parseYearMonthArgs(parts, h.clock.Now())
+		if err != nil {
+			msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+			h.client.Send(msg)
+			return
+		}
+	}
+
+	drifts, err := h.userMonthlyStatService.Recalculate(targets, year, month)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to recalculate monthly stats")
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"chat_id": chatID,
+		"users":   len(targets),
+		"fixed":   len(drifts),
+	}).Info("Monthly stats recalculated by command")
+
+	msg := messenger.NewMessage(chatID, h.userMonthlyStatService.FormatDrifts(tr, drifts, users))
+	h.client.Send(msg)
+}
+
+// getTeamMonthlyStats показывает сводку по сотрудникам за месяц (админам - по всем, руководителю - по его команде)
+func (h *Handler) getTeamMonthlyStats(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	users, err := h.userService.GetScopedUsers(chatID, models.PermStatsView)
+	if err != nil {
+		logrus.WithField("chat_id", chatID).Warn("Unauthorized access to team stats")
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	year, month, err := h.parseYearMonthArgs(strings.Fields(args), h.clock.Now())
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	// Уволенные сотрудники остаются в сводках за месяцы, когда они работали
+	employed := make([]*models.User, 0, len(users))
+	for _, user := range users {
+		if user.EmployedIn(year, month) {
+			employed = append(employed, user)
+		}
+	}
+
+	formatted, err := h.userMonthlyStatService.FormatUsersSummary(tr, employed, year, month)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get team monthly stats")
+		msg := messenger.NewMessage(chatID, tr.T("users.stats_failed", err))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, formatted)
+	h.client.Send(msg)
+}
+
+// exportTimesheet выгружает табель за месяц в CSV (админам - по всем, руководителю - по его команде)
+func (h *Handler) exportTimesheet(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	users, err := h.userService.GetScopedUsers(chatID, models.PermTimesheetExport)
+	if err != nil {
+		logrus.WithField("chat_id", chatID).Warn("Unauthorized timesheet export")
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	year, month, err := h.parseYearMonthArgs(strings.Fields(args), h.clock.Now())
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	employed := make([]*models.User, 0, len(users))
+	for _, user := range users {
+		if user.EmployedIn(year, month) {
+			employed = append(employed, user)
+		}
+	}
+
+	if len(employed) == 0 {
+		h.client.Send(messenger.NewMessage(chatID, tr.T("stats.summary_no_users")))
+		return
+	}
+
+	data, err := h.userMonthlyStatService.ExportTimesheetCSV(employed, year, month)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to export timesheet")
+		msg := messenger.NewMessage(chatID, tr.T("timesheet.failed", err))
+		h.client.Send(msg)
+		return
+	}
+
+	name := fmt.Sprintf("timesheet-%d-%02d.csv", year, month)
+	caption := tr.N("timesheet.caption", len(employed), tr.MonthYear(year, time.Month(month)), len(employed))
+	if err := h.client.Send(messenger.NewDocument(chatID, name, data, caption)); err != nil {
+		logrus.WithError(err).Error("Failed to send timesheet export")
+	}
+}
+
+// parseYearMonthArgs разбирает аргументы вида "[месяц]" или "[год месяц]" (по умолчанию - текущий месяц)
+func (h *Handler) parseYearMonthArgs(parts []string, now time.Time) (int, int, error) {
+	year, month := now.Year(), int(now.Month())
+
+	var err error
+	switch len(parts) {
+	case 0:
+	case 1:
+		month, err = strconv.Atoi(parts[0])
+		if err != nil || month < 1 || month > 12 {
+			return 0, 0, i18n.Errorf("date.invalid_month")
+		}
+	case 2:
+		year, err = strconv.Atoi(parts[0])
+		if policy := h.config.WorkPolicy(); err != nil || !policy.ValidYear(year) {
+			return 0, 0, i18n.Errorf("date.invalid_year", policy.MinYear, policy.MaxYear)
+		}
+		month, err = strconv.Atoi(parts[1])
+		if err != nil || month < 1 || month > 12 {
+			return 0, 0, i18n.Errorf("date.invalid_month")
+		}
+	default:
+		return 0, 0, i18n.Errorf("date.invalid_year_month_format")
+	}
+
+	return year, month, nil
 }
@@ -1,14 +1,16 @@
 package handler
 
 import (
-	"fmt"
+	"errors"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"work-schedule-bot/internal/i18n"
 	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/service"
+	"work-schedule-bot/pkg/messenger"
 
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/sirupsen/logrus"
 )
 
@@ -16,13 +18,13 @@ import (
 // Поддерживает форматы:
 // Дата: dd.mm.yyyy, dd-mm-yyyy
 // Время: hh:mm, hh.mm, hh-mm
-func parseDateTime(dateStr, timeStr string, location *time.Location) (time.Time, error) {
+func parseDateTime(dateStr, timeStr string, location *time.Location, now time.Time) (time.Time, error) {
 	var date time.Time
 	var err error
 
 	// Если дата не указана, используем сегодня
 	if dateStr == "" {
-		date = time.Now().In(location)
+		date = now.In(location)
 	} else {
 		// Нормализуем разделители даты
 		dateStr = strings.Replace(dateStr, "-", ".", -1)
@@ -30,7 +32,7 @@ func parseDateTime(dateStr, timeStr string, location *time.Location) (time.Time,
 		// Парсим дату
 		date, err = time.ParseInLocation("02.01.2006", dateStr, location)
 		if err != nil {
-			return time.Time{}, fmt.Errorf("неверный формат даты. Используйте dd.mm.yyyy или dd-mm-yyyy")
+			return time.Time{}, i18n.Errorf("date.invalid_format_full")
 		}
 	}
 
@@ -56,7 +58,7 @@ func parseDateTime(dateStr, timeStr string, location *time.Location) (time.Time,
 	// Парсим время
 	parsedTime, err := time.ParseInLocation("15:04:05", timeStr, location)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("неверный формат времени. Используйте hh:mm, hh.mm или hh-mm")
+		return time.Time{}, i18n.Errorf("time.invalid_format")
 	}
 
 	// Объединяем дату и время
@@ -102,152 +104,103 @@ func parseCommandArgs(text string) (dateStr, timeStr string) {
 	return dateStr, timeStr
 }
 
-func (h *Handler) clockIn(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
+func (h *Handler) clockIn(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	// Получаем пользователя: время вводится и показывается в его часовом поясе
+	user, err := h.userService.GetUser(chatID)
+	if err != nil || user == nil {
+		logrus.WithField("chat_id", chatID).Warn("User not found for clock in")
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
+		return
+	}
+	loc := user.Location(h.clock.Location())
 
 	// Парсим аргументы команды
 	dateStr, timeStr := parseCommandArgs(message.Text)
 
 	var targetTime time.Time
-	var err error
 
 	// Если указаны дата/время, парсим их
 	if dateStr != "" || timeStr != "" {
-		// Определяем часовой пояс (можно получить из профиля пользователя или использовать системный)
-		location := time.Local // или time.UTC
-
-		targetTime, err = parseDateTime(dateStr, timeStr, location)
+		targetTime, err = parseDateTime(dateStr, timeStr, loc, h.clock.Now())
 		if err != nil {
-			msg := tgbotapi.NewMessage(chatID, "❌ "+err.Error()+"\n\nПримеры:\n/in 25.12.2023 09:30\n/in 09.00\n/in 25-12-2023 09-30")
-			h.client.Bot.Send(msg)
-			return
-		}
-
-		// Проверяем, что время не в будущем (для начала работы)
-		if targetTime.After(time.Now()) {
-			msg := tgbotapi.NewMessage(chatID, "❌ Нельзя указать время начала работы в будущем")
-			h.client.Bot.Send(msg)
-			return
-		}
-		if targetTime.Year() < 2026 {
-			msg := tgbotapi.NewMessage(chatID, "❌ Нельзя указать время начала работы раньше 2026 года")
-			h.client.Bot.Send(msg)
+			msg := messenger.NewMessage(chatID, tr.T("session.clock_in_parse_failed", err))
+			h.client.Send(msg)
 			return
 		}
 	} else {
 		// Используем текущее время
-		targetTime = time.Now()
+		targetTime = h.clock.Now().In(loc)
 	}
 
-	// Проверяем, является ли день выходным
-	isNonWorking, err := h.nonWorkingDayService.IsNonWorkingDay(targetTime)
+	// Начинаем работу: правила /in (время, выходной, активная сессия, отсутствие) проверяет сервис
+	session, err := h.workSessionService.ClockIn(user.ID, targetTime, models.ChatActor(chatID))
 	if err != nil {
-		logrus.WithError(err).Warn("Failed to check if day is non-working")
-		// Продолжаем, даже если проверка не удалась
-	} else if isNonWorking {
-		msg := tgbotapi.NewMessage(chatID,
-			fmt.Sprintf("❌ %s - выходной день!\n\n📅 Вы не можете начать работу в выходной день согласно производственному календарю.",
-				targetTime.Format("02.01.2006")))
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	// Получаем пользователя
-	user, err := h.userService.GetUser(chatID)
-	if err != nil || user == nil {
-		logrus.WithField("chat_id", chatID).Warn("User not found for clock in")
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	// Проверяем, может ли пользователь начать работу
-	canClockIn, reason, err := h.workSessionService.CanClockIn(user.ID, targetTime)
-	if err != nil {
-		logrus.WithError(err).Error("Failed to check clock in eligibility")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка проверки: "+err.Error())
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	if !canClockIn {
-		msg := tgbotapi.NewMessage(chatID, "❌ Не могу начать работу: "+reason)
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	// Получаем необходимое время работы на день
-	requiredMinutes, err := h.userMonthlyStatService.GetRequiredMinutesByUserID(user.ID, targetTime.Year(), int(targetTime.Month()))
-	if err != nil {
-		logrus.WithError(err).Warn("Failed to get required minutes, using default")
-		requiredMinutes = 200 // 8 часов 40 минут по умолчанию
-	}
-
-	// Начинаем работу
-	_, err = h.workSessionService.ClockIn(user.ID, targetTime, requiredMinutes)
-	if err != nil {
-		logrus.WithError(err).Error("Failed to clock in")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка начала работы: "+err.Error())
-		h.client.Bot.Send(msg)
+		var clockErr *service.ClockError
+		var text string
+		switch {
+		case errors.As(err, &clockErr) && clockErr.Reason == service.ClockNonWorkingDay:
+			text = tr.T("session.clock_in_non_working", targetTime)
+		case errors.As(err, &clockErr) && clockErr.Reason == service.ClockNotAllowed:
+			text = tr.T("session.clock_in_denied", err)
+		case errors.As(err, &clockErr):
+			text = tr.T("error.reply", err)
+		default:
+			logrus.WithError(err).Error("Failed to clock in")
+			text = tr.T("session.clock_in_failed", err)
+		}
+		h.client.Send(messenger.NewMessage(chatID, text))
 		return
 	}
+	requiredMinutes := session.RequiredMinutes
 
 	// Форматируем время
 	inTime := targetTime.Format("15:04")
-	requiredHours := requiredMinutes / 60
-	requiredMins := requiredMinutes % 60
 	allowedFinishTime := targetTime.Add(time.Duration(requiredMinutes) * time.Minute)
 
 	var requiredTime string
-	if requiredMinutes < 280{
-		requiredTime = fmt.Sprintf("%d часа %d минут", 4, 40)
-	} else{
-		if requiredMins == 0 {
-			requiredTime = fmt.Sprintf("%d часов", requiredHours)
-		} else {
-			requiredTime = fmt.Sprintf("%d часов %d минут", requiredHours, requiredMins)
+	if shortDay := h.config.WorkPolicy().ShortDayMinutes; requiredMinutes < shortDay {
+		requiredTime = tr.N("count.hours", shortDay/60) + " " + tr.N("count.minutes", shortDay%60)
+	} else {
+		requiredTime = tr.N("count.hours", requiredMinutes/60)
+		if requiredMinutes%60 != 0 {
+			requiredTime += " " + tr.N("count.minutes", requiredMinutes%60)
 		}
 	}
 
-	response := fmt.Sprintf(
-		`✅ Рабочий день начат!
-
-⏰ Время начала: %s
-📅 Дата: %s
-⏳ Норма на день: %s
-⏰ Можно уходить в: %s
-
-💡 Не забудьте отметить конец рабочего дня командой /out`,
-		inTime,
-		targetTime.Format("02.01.2006"),
+	response := tr.T("session.clock_in_started", inTime,
+		targetTime,
 		requiredTime,
-		allowedFinishTime.Format("15:04"),
-	)
+		allowedFinishTime.Format("15:04"))
 
 	// Если указано время в прошлом, добавляем предупреждение
-	if targetTime.Before(time.Now().Add(-24 * time.Hour)) {
-		response += "\n\n⚠️ *Внимание:* Работа начата задним числом."
+	if targetTime.Before(h.clock.Now().Add(-24 * time.Hour)) {
+		response += tr.T("session.clock_in_backdated")
 	}
 
-	msg := tgbotapi.NewMessage(chatID, response)
-	msg.ParseMode = "Markdown"
+	msg := messenger.NewMessage(chatID, response)
+	msg.Format = messenger.FormatMarkdown
 
 	// Создаем клавиатуру с кнопкой завершения
-	inlineKeyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				"⏰ Завершить рабочий день",
+	inlineKeyboard := [][]messenger.Button{
+		messenger.NewRow(
+			messenger.NewButton(
+				tr.T("button.clock_out"),
 				"command_clock_out",
 			),
 		),
-	)
+	}
 
-	msg.ReplyMarkup = inlineKeyboard
-	h.client.Bot.Send(msg)
+	msg.Buttons = inlineKeyboard
+	h.client.Send(msg)
 }
 
-func (h *Handler) clockOut(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
+func (h *Handler) clockOut(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	// Проверяем, есть ли специальный флаг для пропуска проверки выходного дня
 	skipHolidayCheck := strings.Contains(message.Text, "confirm_holiday")
@@ -255,251 +208,164 @@ func (h *Handler) clockOut(message *tgbotapi.Message) {
 	// Убираем флаг из текста для парсинга
 	textForParsing := strings.ReplaceAll(message.Text, "confirm_holiday", "")
 
+	// Получаем пользователя: время вводится и показывается в его часовом поясе
+	user, err := h.userService.GetUser(chatID)
+	if err != nil || user == nil {
+		logrus.WithField("chat_id", chatID).Warn("User not found for clock out")
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
+		return
+	}
+	loc := user.Location(h.clock.Location())
+
 	// Парсим аргументы команды
 	dateStr, timeStr := parseCommandArgs(textForParsing)
 
 	var targetTime time.Time
-	var err error
 
 	// Если указаны дата/время, парсим их
 	if dateStr != "" || timeStr != "" {
-		location := time.Local // или time.UTC
-
-		targetTime, err = parseDateTime(dateStr, timeStr, location)
+		targetTime, err = parseDateTime(dateStr, timeStr, loc, h.clock.Now())
 		if err != nil {
-			msg := tgbotapi.NewMessage(chatID, "❌ "+err.Error()+"\n\nПримеры:\n/out 25.12.2023 18:30\n/out 18.00\n/out 25-12-2023 18-30")
-			h.client.Bot.Send(msg)
-			return
-		}
-
-		// Проверяем, что время не в будущем
-		if targetTime.After(time.Now()) {
-			msg := tgbotapi.NewMessage(chatID, "❌ Нельзя указать время завершения в будущем")
-			h.client.Bot.Send(msg)
+			msg := messenger.NewMessage(chatID, tr.T("session.clock_out_parse_failed", err))
+			h.client.Send(msg)
 			return
 		}
 	} else {
 		// Используем текущее время
-		targetTime = time.Now()
+		targetTime = h.clock.Now().In(loc)
 	}
 
-	// Проверяем, является ли день выходным (только если не пропустить проверку)
-	if !skipHolidayCheck {
-		isNonWorking, err := h.nonWorkingDayService.IsNonWorkingDay(targetTime)
-		if err != nil {
-			logrus.WithError(err).Warn("Failed to check if day is non-working")
-		} else if isNonWorking {
+	// Завершаем работу: правила /out проверяет сервис, выходной день - только после подтверждения
+	session, err := h.workSessionService.ClockOut(user.ID, targetTime, skipHolidayCheck, models.ChatActor(chatID))
+	if err != nil {
+		var clockErr *service.ClockError
+		var text string
+		switch {
+		case errors.As(err, &clockErr) && clockErr.Reason == service.ClockNonWorkingDay:
 			// Показываем предупреждение и просим подтверждение
-			warningMsg := tgbotapi.NewMessage(chatID,
-				fmt.Sprintf("⚠️ *Внимание:* %s - выходной день!\n\nВы действительно хотите завершить работу в выходной день?\n\nЭто может быть ошибкой.",
-					targetTime.Format("02.01.2006")))
-			warningMsg.ParseMode = "Markdown"
-
-			// Создаем inline клавиатуру для подтверждения
-			inlineKeyboard := tgbotapi.NewInlineKeyboardMarkup(
-				tgbotapi.NewInlineKeyboardRow(
-					tgbotapi.NewInlineKeyboardButtonData(
-						"✅ Да, завершить",
+			warningMsg := messenger.NewMessage(chatID,
+				tr.T("session.clock_out_non_working", targetTime))
+			warningMsg.Format = messenger.FormatMarkdown
+			warningMsg.Buttons = [][]messenger.Button{
+				messenger.NewRow(
+					messenger.NewButton(
+						tr.T("button.yes_finish"),
 						"confirm_clockout_holiday",
 					),
-					tgbotapi.NewInlineKeyboardButtonData(
-						"❌ Отменить",
+					messenger.NewButton(
+						tr.T("button.cancel"),
 						"cancel_clockout_holiday",
 					),
 				),
-			)
-
-			warningMsg.ReplyMarkup = inlineKeyboard
-			h.client.Bot.Send(warningMsg)
+			}
+			h.client.Send(warningMsg)
 			return
+		case errors.As(err, &clockErr) && clockErr.Reason == service.ClockNotAllowed:
+			text = tr.T("session.clock_out_denied", err)
+		case errors.As(err, &clockErr):
+			text = tr.T("error.reply", err)
+		default:
+			logrus.WithError(err).Error("Failed to clock out")
+			text = tr.T("session.clock_out_failed", err)
 		}
-	}
-
-	// Получаем пользователя
-	user, err := h.userService.GetUser(chatID)
-	if err != nil || user == nil {
-		logrus.WithField("chat_id", chatID).Warn("User not found for clock out")
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	// Проверяем, может ли пользователь закончить работу
-	canClockOut, reason, err := h.workSessionService.CanClockOut(user.ID)
-	if err != nil {
-		logrus.WithError(err).Error("Failed to check clock out eligibility")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка проверки: "+err.Error())
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	if !canClockOut {
-		msg := tgbotapi.NewMessage(chatID, "❌ Не могу закончить работу: "+reason)
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	// Получаем текущую сессию перед завершением
-	activeSession, err := h.workSessionService.GetActiveSession(user.ID)
-	if err != nil {
-		logrus.WithError(err).Error("Failed to get active session")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка получения сессии: "+err.Error())
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	if activeSession == nil {
-		msg := tgbotapi.NewMessage(chatID, "❌ Нет активной рабочей сессии")
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	// Проверяем, что время завершения позже времени начала
-	if targetTime.Before(activeSession.ClockInTime) {
-		msg := tgbotapi.NewMessage(chatID, "❌ Время завершения не может быть раньше времени начала работы")
-		h.client.Bot.Send(msg)
-		return
-	}
-
-	// Завершаем работу
-	session, err := h.workSessionService.ClockOut(user.ID, targetTime)
-	if err != nil {
-		logrus.WithError(err).Error("Failed to clock out")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка завершения работы: "+err.Error())
-		h.client.Bot.Send(msg)
+		h.client.Send(messenger.NewMessage(chatID, text))
 		return
 	}
 
 	// Форматируем результат
-	inTime := activeSession.ClockInTime.Format("15:04")
+	inTime := session.ClockInTime.In(loc).Format("15:04")
 	outTime := targetTime.Format("15:04")
 
-	workedHours := session.WorkedMinutes / 60
-	workedMins := session.WorkedMinutes % 60
-	requiredHours := session.RequiredMinutes / 60
-	requiredMins := session.RequiredMinutes % 60
-
-	var workedTime, requiredTime string
-	if workedMins == 0 {
-		workedTime = fmt.Sprintf("%dч", workedHours)
-	} else {
-		workedTime = fmt.Sprintf("%dч %dм", workedHours, workedMins)
-	}
-
-	if requiredMins == 0 {
-		requiredTime = fmt.Sprintf("%dч", requiredHours)
-	} else {
-		requiredTime = fmt.Sprintf("%dч %dм", requiredHours, requiredMins)
-	}
-
 	diffStatus := ""
 	if session.DiffMinutes > 0 {
-		diffHours := session.DiffMinutes / 60
-		diffMins := session.DiffMinutes % 60
-		if diffMins == 0 {
-			diffStatus = fmt.Sprintf("\n\n➕ Переработка: %dч", diffHours)
-		} else {
-			diffStatus = fmt.Sprintf("\n\n➕ Переработка: %dч %dм", diffHours, diffMins)
-		}
+		diffStatus = "\n\n" + tr.T("session.overtime", i18n.Minutes(session.DiffMinutes))
 	} else if session.DiffMinutes < 0 {
-		diffHours := -session.DiffMinutes / 60
-		diffMins := -session.DiffMinutes % 60
-		if diffMins == 0 {
-			diffStatus = fmt.Sprintf("\n\n➖ Недобор: %dч", diffHours)
-		} else {
-			diffStatus = fmt.Sprintf("\n\n➖ Недобор: %dч %dм", diffHours, diffMins)
-		}
+		diffStatus = "\n\n" + tr.T("session.deficit", i18n.Minutes(-session.DiffMinutes))
 	}
 
-	response := fmt.Sprintf(
-		`✅ Рабочий день завершен!
-
-⏰ Время работы: %s - %s
-⏳ Отработано: %s
-📊 Норма: %s%s
-
-📈 Статистика обновлена автоматически.`,
-		inTime, outTime,
-		workedTime,
-		requiredTime,
-		diffStatus,
-	)
+	response := tr.T("session.clock_out_finished", inTime, outTime,
+		i18n.Minutes(session.WorkedMinutes),
+		i18n.Minutes(session.RequiredMinutes),
+		diffStatus)
 
 	// Если указано время в прошлом, добавляем предупреждение
-	if targetTime.Before(time.Now().Add(-5 * time.Minute)) {
-		response += "\n\n⚠️ *Внимание:* Работа завершена задним числом."
+	if targetTime.Before(h.clock.Now().Add(-5 * time.Minute)) {
+		response += tr.T("session.clock_out_backdated")
 	}
 
 	// Добавляем предупреждение если это был выходной день
 	if skipHolidayCheck {
-		response += "\n\n⚠️ *Внимание:* Работа завершена в выходной день (подтверждено пользователем)."
+		response += tr.T("session.clock_out_on_day_off")
 	}
 
-	msg := tgbotapi.NewMessage(chatID, response)
-	msg.ParseMode = "Markdown"
+	msg := messenger.NewMessage(chatID, response)
+	msg.Format = messenger.FormatMarkdown
 
 	// Добавляем inline-кнопку для начала новой рабочей сессии
-	inlineKeyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				"🔄 Начать новый рабочий день",
+	inlineKeyboard := [][]messenger.Button{
+		messenger.NewRow(
+			messenger.NewButton(
+				tr.T("button.clock_in"),
 				"command_clock_in",
 			),
 		),
-	)
+	}
 
-	msg.ReplyMarkup = inlineKeyboard
-	h.client.Bot.Send(msg)
+	msg.Buttons = inlineKeyboard
+	h.client.Send(msg)
 }
 
 // getTodayWorkSession показывает сегодняшнюю сессию
-func (h *Handler) getTodayWorkSession(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
+func (h *Handler) getTodayWorkSession(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	// Получаем пользователя
 	user, err := h.userService.GetUser(chatID)
 	if err != nil || user == nil {
 		logrus.WithField("chat_id", chatID).Warn("User not found for today session")
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
 		return
 	}
 
 	// Получаем сегодняшнюю сессию
-	sessions, err := h.workSessionService.GetAllTodaySessions(user.ID)
+	sessions, err := h.workSessionService.GetAllTodaySessions(user.ID, user.Location(h.clock.Location()))
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get today's work session")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка получения сессии: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("session.get_failed_reply", err))
+		h.client.Send(msg)
 		return
 	}
 
-	if sessions == nil {
-		msg := tgbotapi.NewMessage(chatID, "📭 Сегодня вы еще не начинали работу.\nИспользуйте /in чтобы начать рабочий день.")
-		h.client.Bot.Send(msg)
+	if sessions == nil || len(*sessions) == 0 {
+		msg := messenger.NewMessage(chatID, tr.T("session.today_empty"))
+		h.client.Send(msg)
 		return
 	}
 
 	// Форматируем сессию
 	var formated_all strings.Builder
 	for _, session := range *sessions {
-		formatted := h.workSessionService.FormatSession(&session)
+		formatted := h.workSessionService.FormatSession(tr, &session, user.Location(h.clock.Location()))
 		formated_all.WriteString("\n" + formatted)
 	}
-	msg := tgbotapi.NewMessage(chatID, formated_all.String())
-	h.client.Bot.Send(msg)
+	msg := messenger.NewMessage(chatID, formated_all.String())
+	h.client.Send(msg)
 }
 
 // getWorkHistory показывает историю рабочих дней
-func (h *Handler) getWorkHistory(message *tgbotapi.Message, args string) {
-	chatID := message.Chat.ID
+func (h *Handler) getWorkHistory(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	// Получаем пользователя
 	user, err := h.userService.GetUser(chatID)
 	if err != nil || user == nil {
 		logrus.WithField("chat_id", chatID).Warn("User not found for work history")
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
 		return
 	}
 
@@ -515,31 +381,32 @@ func (h *Handler) getWorkHistory(message *tgbotapi.Message, args string) {
 	sessions, err := h.workSessionService.GetSessionHistory(user.ID, limit)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get work history")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка получения истории: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("session.history_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
 	// Форматируем результат
-	formatted := h.workSessionService.FormatSessionList(sessions)
-	msg := tgbotapi.NewMessage(chatID, formatted)
-	h.client.Bot.Send(msg)
+	formatted := h.workSessionService.FormatSessionList(tr, sessions, user.Location(h.clock.Location()))
+	msg := messenger.NewMessage(chatID, formatted)
+	h.client.Send(msg)
 }
 
 // getMonthWorkSessions показывает рабочие дни за месяц
-func (h *Handler) getMonthWorkSessions(message *tgbotapi.Message, args string) {
-	chatID := message.Chat.ID
+func (h *Handler) getMonthWorkSessions(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	// Получаем пользователя
 	user, err := h.userService.GetUser(chatID)
 	if err != nil || user == nil {
 		logrus.WithField("chat_id", chatID).Warn("User not found for month sessions")
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
 		return
 	}
 
-	now := time.Now()
+	now := h.clock.Now().In(user.Location(h.clock.Location()))
 	year := now.Year()
 	month := int(now.Month())
 
@@ -549,25 +416,25 @@ func (h *Handler) getMonthWorkSessions(message *tgbotapi.Message, args string) {
 			// Только месяц
 			parsedMonth, err := strconv.Atoi(parts[0])
 			if err != nil || parsedMonth < 1 || parsedMonth > 12 {
-				msg := tgbotapi.NewMessage(chatID, "❌ Неверный месяц. Используйте число от 1 до 12.")
-				h.client.Bot.Send(msg)
+				msg := messenger.NewMessage(chatID, tr.T("date.invalid_month_reply"))
+				h.client.Send(msg)
 				return
 			}
 			month = parsedMonth
 		} else if len(parts) == 2 {
 			// Год и месяц
 			parsedYear, err := strconv.Atoi(parts[0])
-			if err != nil || parsedYear < 2000 || parsedYear > 2100 {
-				msg := tgbotapi.NewMessage(chatID, "❌ Неверный год. Используйте год между 2000 и 2100.")
-				h.client.Bot.Send(msg)
+			if policy := h.config.WorkPolicy(); err != nil || !policy.ValidYear(parsedYear) {
+				msg := messenger.NewMessage(chatID, tr.T("date.invalid_year_reply", policy.MinYear, policy.MaxYear))
+				h.client.Send(msg)
 				return
 			}
 			year = parsedYear
 
 			parsedMonth, err := strconv.Atoi(parts[1])
 			if err != nil || parsedMonth < 1 || parsedMonth > 12 {
-				msg := tgbotapi.NewMessage(chatID, "❌ Неверный месяц. Используйте число от 1 до 12.")
-				h.client.Bot.Send(msg)
+				msg := messenger.NewMessage(chatID, tr.T("date.invalid_month_reply"))
+				h.client.Send(msg)
 				return
 			}
 			month = parsedMonth
@@ -578,15 +445,15 @@ func (h *Handler) getMonthWorkSessions(message *tgbotapi.Message, args string) {
 	sessions, err := h.workSessionService.GetMonthSessions(user.ID, year, month)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get month sessions")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка получения сессий: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("session.month_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
-	monthName := time.Month(month).String()
+	monthName := tr.MonthYear(year, time.Month(month))
 	if len(sessions) == 0 {
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("📭 В %s %d у вас не было рабочих дней.", monthName, year))
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("session.month_empty", monthName))
+		h.client.Send(msg)
 		return
 	}
 
@@ -601,47 +468,29 @@ func (h *Handler) getMonthWorkSessions(message *tgbotapi.Message, args string) {
 				dataMap[dataStr] = "here"
 				completedDays++
 			}
-			totalMinutes += session.WorkedMinutes
+			totalMinutes += session.CountedMinutes(h.config.WorkPolicy())
 		}
 	}
 
-	totalHours := totalMinutes / 60
-	totalMins := totalMinutes % 60
-
-	var totalTime string
-	if totalMins == 0 {
-		totalTime = fmt.Sprintf("%dч", totalHours)
-	} else {
-		totalTime = fmt.Sprintf("%dч %dм", totalHours, totalMins)
-	}
-
-	response := fmt.Sprintf(
-		`📊 Рабочие дни за %s %d
-
-%s
+	response := tr.T("session.month_report", monthName,
+		h.workSessionService.FormatSessionList(tr, sessions, user.Location(h.clock.Location())),
+		completedDays, i18n.Minutes(totalMinutes))
 
-📈 Итоги за месяц:
-   📋 Отработано дней: %d
-   ⏰ Всего времени: %s`,
-		monthName, year,
-		h.workSessionService.FormatSessionList(sessions),
-		completedDays, totalTime,
-	)
-
-	msg := tgbotapi.NewMessage(chatID, response)
-	h.client.Bot.Send(msg)
+	msg := messenger.NewMessage(chatID, response)
+	h.client.Send(msg)
 }
 
 // getWorkStatus показывает текущий статус работы
-func (h *Handler) getWorkStatus(message *tgbotapi.Message) {
-	chatID := message.Chat.ID
+func (h *Handler) getWorkStatus(message *messenger.Message) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
 
 	// Получаем пользователя
 	user, err := h.userService.GetUser(chatID)
 	if err != nil || user == nil {
 		logrus.WithField("chat_id", chatID).Warn("User not found for work status")
-		msg := tgbotapi.NewMessage(chatID, "❌ Профиль не найден.\nИспользуйте /createprofile чтобы создать профиль.")
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("profile.not_found_reply"))
+		h.client.Send(msg)
 		return
 	}
 
@@ -649,68 +498,108 @@ func (h *Handler) getWorkStatus(message *tgbotapi.Message) {
 	activeSession, err := h.workSessionService.GetActiveSession(user.ID)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get active session")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка получения статуса: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("session.status_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
 	if activeSession != nil {
 		// Пользователь на работе
-		inTime := activeSession.ClockInTime.Format("15:04")
-		duration := time.Since(activeSession.ClockInTime)
-		hours := int(duration.Hours())
-		minutes := int(duration.Minutes()) % 60
-
-		var durationStr string
-		if minutes == 0 {
-			durationStr = fmt.Sprintf("%dч", hours)
-		} else {
-			durationStr = fmt.Sprintf("%dч %dм", hours, minutes)
-		}
-
-		response := fmt.Sprintf(
-			`🟢 Вы на работе!
-
-⏰ Начали работу: %s
-⏳ Прошло времени: %s
-📅 Дата: %s
+		inTime := activeSession.ClockInTime.In(user.Location(h.clock.Location())).Format("15:04")
+		duration := h.clock.Now().Sub(activeSession.ClockInTime)
 
-💡 Используйте /out чтобы завершить рабочий день.`,
-			inTime,
-			durationStr,
-			activeSession.Date.Format("02.01.2006"),
-		)
+		response := tr.T("session.status_working", inTime,
+			i18n.Minutes(int(duration.Minutes())),
+			activeSession.Date)
 
-		msg := tgbotapi.NewMessage(chatID, response)
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, response)
+		h.client.Send(msg)
 		return
 	}
 
 	// Проверяем сегодняшнюю сессию
-	todaySession, err := h.workSessionService.GetTodaySession(user.ID)
+	todaySession, err := h.workSessionService.GetTodaySession(user.ID, user.Location(h.clock.Location()))
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get today's session")
-		msg := tgbotapi.NewMessage(chatID, "❌ Ошибка получения статуса: "+err.Error())
-		h.client.Bot.Send(msg)
+		msg := messenger.NewMessage(chatID, tr.T("session.status_failed", err))
+		h.client.Send(msg)
 		return
 	}
 
 	if todaySession != nil && todaySession.Status == models.StatusCompleted {
 		// Рабочий день завершен
-		formatted := h.workSessionService.FormatSession(todaySession)
-		msg := tgbotapi.NewMessage(chatID, formatted)
-		h.client.Bot.Send(msg)
+		formatted := h.workSessionService.FormatSession(tr, todaySession, user.Location(h.clock.Location()))
+		msg := messenger.NewMessage(chatID, formatted)
+		h.client.Send(msg)
 		return
 	}
 
 	// Нет активной сессии и сегодняшней завершенной
-	msg := tgbotapi.NewMessage(chatID,
-		`📭 Сегодня вы еще не работали.
-
-💡 Доступные команды:
-/in - Начать рабочий день
-/today - Информация о сегодняшнем дне
-/history - История рабочих дней
-/status - Текущий статус`)
-	h.client.Bot.Send(msg)
+	msg := messenger.NewMessage(chatID,
+		tr.T("session.status_idle"))
+	h.client.Send(msg)
+}
+
+// correctSession исправляет время прихода и ухода сотрудника за день (руководитель - только своей команды)
+func (h *Handler) correctSession(message *messenger.Message, args string) {
+	chatID := message.ChatID
+	tr := h.localizer(message)
+
+	parts := strings.Fields(args)
+	if len(parts) != 4 {
+		msg := messenger.NewMessage(chatID, tr.T("session.correct.usage"))
+		h.client.Send(msg)
+		return
+	}
+
+	targetChatID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("user.invalid_id"))
+		h.client.Send(msg)
+		return
+	}
+
+	now := h.clock.Now()
+	date, err := parseDate(parts[1], now)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("absence.date_invalid", err))
+		h.client.Send(msg)
+		return
+	}
+
+	targetUser, err := h.userService.GetManagedUser(chatID, targetChatID, models.PermSessionsCorrect)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	// Время прихода и ухода - по часам сотрудника
+	loc := targetUser.Location(h.clock.Location())
+	clockIn, err := parseDateTime(date.Format("02.01.2006"), parts[2], loc, now)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+	clockOut, err := parseDateTime(date.Format("02.01.2006"), parts[3], loc, now)
+	if err != nil {
+		msg := messenger.NewMessage(chatID, tr.T("error.reply", err))
+		h.client.Send(msg)
+		return
+	}
+
+	session, err := h.workSessionService.CorrectSession(targetUser.ID, date, clockIn, clockOut, models.ChatActor(chatID))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to correct work session")
+		msg := messenger.NewMessage(chatID, tr.T("session.correct.failed", err))
+		h.client.Send(msg)
+		return
+	}
+
+	msg := messenger.NewMessage(chatID, tr.T("session.correct.done",
+		targetUser.FirstName, targetUser.LastName, date,
+		session.ClockInTime.In(loc).Format("15:04"), session.ClockOutTime.In(loc).Format("15:04"),
+		i18n.Minutes(session.WorkedMinutes)))
+	h.client.Send(msg)
 }
@@ -0,0 +1,1206 @@
+package i18n
+
+// en - английский каталог сообщений
+var en = Catalog{
+	// Форматы дат и чисел
+	"format.date":          "02 Jan 2006",
+	"format.short_date":    "02 Jan",
+	"format.date_time":     "02 Jan 2006 15:04",
+	"format.month_year":    "%s %d",
+	"format.hours":         "%dh",
+	"format.hours_minutes": "%dh %dm",
+
+	"month.1":  "January",
+	"month.2":  "February",
+	"month.3":  "March",
+	"month.4":  "April",
+	"month.5":  "May",
+	"month.6":  "June",
+	"month.7":  "July",
+	"month.8":  "August",
+	"month.9":  "September",
+	"month.10": "October",
+	"month.11": "November",
+	"month.12": "December",
+
+	"weekday.0": "Su",
+	"weekday.1": "Mo",
+	"weekday.2": "Tu",
+	"weekday.3": "We",
+	"weekday.4": "Th",
+	"weekday.5": "Fr",
+	"weekday.6": "Sa",
+
+	// Типы сессий и отсутствий
+	"session_type.work":         "Work",
+	"session_type.vacation":     "Vacation",
+	"session_type.sick_leave":   "Sick leave",
+	"session_type.day_off":      "Day off",
+	"session_type.unpaid_leave": "Unpaid leave",
+	"session_type.truancy":      "Truancy",
+
+	"absence.type_lower.vacation":     "a vacation",
+	"absence.type_lower.sick_leave":   "a sick leave",
+	"absence.type_lower.day_off":      "a day off",
+	"absence.type_lower.unpaid_leave": "an unpaid leave",
+	"absence.type_lower.truancy":      "a truancy",
+
+	// Рабочие сессии
+	"session.still_working": "still at work",
+	"session.time_in":       "⏰ In: %s",
+	"session.time_in_out":   "⏰ In: %s | Out: %s",
+
+	"session.status.active":    "at work",
+	"session.status.completed": "completed",
+	"session.status.absent":    "absent",
+
+	"session.already_active":              "you already have an active work session",
+	"session.no_active":                   "you have no active work session",
+	"session.invalid":                     "invalid session data",
+	"session.today_absent":                "today you have %s",
+	"session.error.clock_in_future":       "clock-in time cannot be in the future",
+	"session.error.clock_in_too_early":    "clock-in time cannot be earlier than %d",
+	"session.error.clock_in_non_working":  "%s is a day off, you cannot start work",
+	"session.error.clock_out_future":      "clock-out time cannot be in the future",
+	"session.error.clock_out_non_working": "%s is a day off, finishing work needs confirmation",
+	"session.error.clock_out_before_in":   "clock-out time cannot be earlier than clock-in time",
+	"session.not_found":                   "❌ Session not found",
+
+	"session.absence.credited":          "✅ Credited",
+	"session.absence.credited_hint":     "💡 This day counts as a working day according to the schedule",
+	"session.absence.reduce_plan":       "➖ Not credited",
+	"session.absence.reduce_plan_hint":  "💡 This day reduces the planned time of the month",
+	"session.absence.not_credited":      "❌ Not credited",
+	"session.absence.not_credited_hint": "💡 This day counts as 0 worked minutes",
+	"session.absence.details": `%s %s: %s
+
+📅 Date: %s
+⏰ Time: 09:00 - 17:40
+⏳ Recorded: %d:%02d hours
+📊 Status: %s
+
+%s`,
+
+	"session.details": `📅 Work day: %s
+%s %s
+
+%s
+⏳ Duration: %s
+
+📊 Norms:
+   📋 Planned time: %s
+   ⏰ Worked: %s`,
+	"session.overtime":   "➕ Overtime: %s",
+	"session.deficit":    "➖ Deficit: %s",
+	"session.notes":      "📝 Note: %s",
+	"session.updated_at": "🕒 Updated: %s",
+
+	"session.history_empty": "📭 No work sessions yet",
+	"session.history_title": "📋 Work day history:",
+
+	"stats.update_failed":     "failed to update statistics: %v",
+	"period.end_before_start": "the end date is before the start date",
+
+	// Пользователи и профиль
+	"user.admin_default_name":       "Administrator",
+	"user.first_name_empty":         "first name cannot be empty",
+	"user.create_failed":            "failed to create user: %v",
+	"user.get_failed":               "failed to get user: %v",
+	"user.update_failed":            "failed to update user: %v",
+	"user.lookup_failed":            "failed to find user: %v",
+	"user.not_found":                "user not found",
+	"user.not_found_id":             "user with ID %d not found",
+	"user.delete_sessions_failed":   "failed to delete work sessions: %v",
+	"user.delete_absences_failed":   "failed to delete absence periods: %v",
+	"user.delete_stats_failed":      "failed to delete statistics: %v",
+	"user.delete_time_bank_failed":  "failed to delete time bank: %v",
+	"access.check_failed":           "failed to check permissions: %v",
+	"access.denied":                 "access denied",
+	"access.not_your_team":          "access denied: the user is not in your team",
+	"role.change_denied":            "access denied: not enough rights to change roles",
+	"role.unknown":                  "unknown role: %s",
+	"role.team_lead_needs_team":     "the user is not in a team, assign a team first (/setteam)",
+	"timezone.empty":                "time zone is not specified",
+	"timezone.unknown":              "unknown time zone %q",
+	"profile.info.title":            "👤 User profile:",
+	"profile.info.chat_id":          "🆔 Chat ID: %d",
+	"profile.info.username":         "📛 Username: @%s",
+	"profile.info.first_name":       "👨‍💼 First name: %s",
+	"profile.info.last_name":        "👨‍💼 Last name: %s",
+	"profile.info.role":             "%s Role: %s",
+	"profile.info.team":             "👥 Team: %s",
+	"profile.info.timezone":         "🕒 Time zone: %s",
+	"profile.info.timezone_default": "🕒 Time zone: %s (company time zone)",
+	"profile.info.language":         "🌐 Language: %s",
+	"language.name":                 "English",
+	"users.all_title":               "📋 All users:",
+	"users.empty":                   "📭 The user list is empty.",
+	"users.total":                   "📊 Total users: %d",
+	"users.admins":                  "👑 Administrators: %d",
+
+	// Язык
+	"language.unknown": "unknown language %q, available: ru, en",
+
+	// Статистика
+	"stats.not_found":       "❌ Statistics not found",
+	"stats.not_found_month": "no statistics for %02d.%d",
+	"stats.no_days_left":    "no working days left in the month",
+	"count.days.one":        "%d day",
+	"count.days.other":      "%d days",
+	"stats.planned": `📊 Statistics for %s
+
+📅 Plan:
+   📋 Working days: %s
+   ⏰ Planned time: %s`,
+	"stats.plan_reduction": "➖ Plan reduction (unpaid leave): %s",
+	"stats.effective_plan": "📋 Plan including absences: %s",
+	"stats.actual": `✅ Actual:
+   📋 Days worked: %s
+   ⏰ Time worked: %s`,
+	"stats.credited_absences":   "🏖️ Credited absences: %s",
+	"stats.uncredited_absences": "🚫 Uncredited absences: %s (%s)",
+	"stats.counted_total":       "📈 Total credited: %s",
+	"stats.month_overtime":      "➕ Overtime this month: %s",
+	"stats.month_deficit":       "➖ Deficit this month: %s",
+	"stats.remaining":           "⏳ Left to work: %s, %s",
+	"stats.required_per_day":    "📈 Required per day: %s",
+	"stats.expected_overtime":   "➕ Overtime: %s",
+	"stats.expected_deficit":    "➖ Deficit: %s",
+	"stats.on_plan":             "✅ Right on plan",
+	"stats.plan_done":           "✅ You have already met the monthly norm! Overtime: %s",
+	"stats.updated_at":          "📅 Last updated: %s",
+	"stats.summary_title":       "📊 Summary for %s",
+	"stats.summary_no_users":    "📭 No employees.",
+	"stats.summary_no_data":     "%d. %s - no data",
+	"stats.summary_row":         "%d. %s - %s of %s (%.0f%%), %s%s",
+	"stats.list_empty":          "📭 No statistics yet",
+	"stats.list_title":          "📋 Your statistics by month:",
+	"stats.list_row":            "%d. %s %s - %d/%s, %s/%s%s",
+
+	// Банк времени
+	"timebank.settings_get_failed":   "failed to get time bank settings: %v",
+	"timebank.settings_save_failed":  "failed to save time bank settings: %v",
+	"timebank.negative_caps":         "limits cannot be negative",
+	"timebank.month_not_finished":    "only a finished month can be closed",
+	"timebank.stats_get_failed":      "failed to get monthly statistics: %v",
+	"timebank.entry_get_failed":      "failed to get time bank entry: %v",
+	"timebank.transfer_failed":       "failed to transfer the monthly result: %v",
+	"timebank.zero_adjustment":       "the adjustment cannot be zero",
+	"timebank.reason_required":       "a reason for the adjustment is required",
+	"timebank.adjust_failed":         "failed to create the adjustment: %v",
+	"timebank.reason_monthly":        "Result for %02d.%d",
+	"timebank.reason_monthly_capped": "Result for %02d.%d (capped, actually %s)",
+	"timebank.ledger_title":          "🏦 Time bank: %s %s",
+	"timebank.ledger_empty":          "📭 No entries yet.",
+	"timebank.balance":               "💰 Balance: %s",
+	"timebank.current_month":         "📅 Current month (not closed): %s",
+	"date.invalid_month_number":      "invalid month: %d",
+
+	// Отсутствия
+	"absence.vacation_future_only":      "vacation can only be added for future dates",
+	"absence.unpaid_future_only":        "unpaid leave can only be added for future dates",
+	"absence.non_working_day":           "%s is a day off",
+	"absence.conflict_check_failed":     "failed to check for conflicts: %v",
+	"absence.conflict":                  "the period overlaps an existing vacation/sick leave/day off",
+	"absence.availability_check_failed": "failed to check availability of %s: %v",
+	"absence.date_taken":                "there is already a record for %s",
+	"absence.create_failed":             "failed to create the period: %v",
+	"absence.sessions_create_failed":    "failed to create work sessions: %v",
+	"absence.unknown_type":              "unknown absence type: %s",
+	"absence.non_working_check_failed":  "failed to check whether %s is a day off: %v",
+	"absence.session_create_failed":     "failed to create a session for %s: %v",
+	"absence.stats_update_failed":       "failed to update statistics for %02d.%d: %v",
+
+	// Банк времени: отгулы
+	"timebank.balance_get_failed": "failed to get time bank balance: %v",
+	"timebank.insufficient":       "not enough time in the bank: balance %s, required %s",
+	"timebank.reason_day_off":     "Day off %s",
+	"timebank.debit_failed":       "failed to debit the time bank: %v",
+
+	// Календарь отсутствий
+	"users.get_failed":           "failed to get users: %v",
+	"absence.periods_get_failed": "failed to get absence periods: %v",
+	"calendar.title":             "📅 Absence calendar: %s",
+	"calendar.team":              "👥 Team: %s",
+	"calendar.users":             "👥 Employees: %d, threshold: %d%%",
+	"calendar.nobody_absent":     "✅ Nobody is absent this month.",
+	"calendar.flagged_day":       "%d (%d of %d)",
+	"calendar.flagged_days": `⚠️ Days when more than %d%% of employees are absent:
+%s`,
+	"calendar.no_flagged_days": "✅ No days when more than %d%% of employees are absent.",
+	"calendar.image_title":     "📅 Absences for %s",
+	"calendar.legend": `🟦 vacation 🟨 sick leave 🟩 day off 🟪 unpaid leave ⬛ truancy
+🟥 in the header - more than %d%% of employees are absent`,
+
+	// Отделы и команды
+	"team.department_name_empty":       "the department name cannot be empty",
+	"team.department_create_failed":    "failed to create the department: %v",
+	"team.department_teams_get_failed": "failed to get department teams: %v",
+	"team.department_has_teams":        "the department has teams (%d), delete them first",
+	"team.name_empty":                  "the team name cannot be empty",
+	"team.department_lookup_failed":    "failed to find the department: %v",
+	"team.department_not_found":        "department with ID %d not found",
+	"team.create_failed":               "failed to create the team: %v",
+	"team.members_get_failed":          "failed to get team members: %v",
+	"team.lead_reset_failed":           "failed to remove the team lead: %v",
+	"team.lookup_failed":               "failed to find the team: %v",
+	"team.not_found":                   "team with ID %d not found",
+	"team.lead_cannot_leave":           "a team lead cannot be removed from the team, change their role first",
+	"team.assign_failed":               "failed to assign the team: %v",
+	"team.departments_get_failed":      "failed to get departments: %v",
+	"team.departments_empty": `📭 No departments yet.
+Create one with /adddepartment`,
+	"team.departments_title": "🏢 Departments and teams:",
+	"team.no_teams":          "no teams",
+	"team.info":              "👥 %s (ID: %d) - members: %d",
+	"team.info_leads":        ", lead: %s",
+
+	// Графики работы
+	"schedule.invalid":              "invalid data: year %d-%d, month 1-12, days 0-31, minutes per day 1-1440",
+	"schedule.stats_create_failed":  "failed to create statistics for the schedule: %v",
+	"schedule.not_found_id":         "schedule with ID %d not found",
+	"schedule.invalid_after_update": "invalid data after the update",
+	"schedule.stats_update_failed":  "failed to update statistics for the schedule: %v",
+	"schedule.parse_format":         "invalid format. Use: year month days minutes_per_day",
+	"schedule.parse_year":           "invalid year. Must be between %d and %d",
+	"schedule.parse_month":          "invalid month. Must be between 1 and 12",
+	"schedule.parse_days":           "invalid number of days. Must be between 0 and 31",
+	"schedule.parse_minutes":        "invalid minutes per day. Must be between 1 and 1440",
+	"time.parse_format":             "invalid time format. Use HH:MM",
+	"time.parse_hours":              "invalid hours. Must be between 0 and 23",
+	"time.parse_minutes":            "invalid minutes. Must be between 0 and 59",
+	"schedule.not_found":            "❌ Schedule not found",
+	"schedule.details": `📅 Work schedule: %s
+
+🆔 ID: %d
+📊 Working days: %d
+⏰ Time per day: %s
+📈 Total time: %s
+📅 Created: %s
+🔄 Updated: %s`,
+	"schedule.list_empty": "📭 No work schedules yet",
+	"schedule.list_title": "📋 All work schedules:",
+
+	// Пересчет статистики
+	"schedule.get_failed":     "failed to get the schedule: %v",
+	"session.list_get_failed": "failed to get work sessions: %v",
+	"stats.get_failed":        "failed to get statistics: %v",
+	"stats.create_failed":     "failed to create statistics: %v",
+	"stats.save_failed":       "failed to save statistics: %v",
+	"stats.recalc_failed":     "failed to recalculate statistics for user %d: %v",
+	"stats.recalc_no_drifts":  "✅ Statistics match the recalculated values, nothing to fix.",
+	"stats.recalc_fixed":      "🔧 Statistics records fixed: %d",
+	"stats.recalc_created":    "• %s, %02d.%d - record created",
+
+	// Токены API
+	"apitoken.name_required":   "specify the client name",
+	"apitoken.generate_failed": "failed to generate the token: %v",
+	"apitoken.save_failed":     "failed to save the token: %v",
+	"apitoken.not_found":       "token with ID %d not found",
+	"apitoken.already_revoked": "token with ID %d is already revoked",
+	"apitoken.list_empty": `🔑 No API tokens.
+Create one: /addapitoken [name]`,
+	"apitoken.list_title": "🔑 API tokens:",
+	"apitoken.revoked":    "⛔ revoked %s",
+	"apitoken.never_used": "never used",
+	"apitoken.used_at":    "used %s",
+	"apitoken.list_row": `%d. %s %s
+    created %s, %s`,
+
+	// Диалоги
+	"dialog.save_failed": "failed to save dialog data: %v",
+
+	// Управляющие команды (/helpadmin)
+	"usage.allusers":         "/allusers - Show users",
+	"usage.stats":            "/stats - Bot statistics",
+	"usage.admins":           "/admins - Show administrators",
+	"usage.teams":            "/teams - Departments and teams",
+	"usage.setrole":          "/setrole [ID] [role] - Change role (list of roles: /roles)",
+	"usage.promote":          "/promote [ID] - Make administrator",
+	"usage.demote":           "/demote [ID] - Revoke administrator",
+	"usage.roles":            "/roles - Roles and their permissions",
+	"usage.adddepartment":    "/adddepartment [name] - Create a department",
+	"usage.deletedepartment": "/deletedepartment [ID] - Delete a department",
+	"usage.addteam":          "/addteam [department_ID name] - Create a team",
+	"usage.deleteteam":       "/deleteteam [ID] - Delete a team",
+	"usage.setteam":          "/setteam [user_ID team_ID] - Add to a team (0 - remove)",
+	"usage.getschedules":     "/getschedules - All work schedules",
+	"usage.getschedule":      "/getschedule [ID] or [year month] - Show a schedule",
+	"usage.currentschedule":  "/currentschedule - Schedule for the current month",
+	"usage.addschedule": `/addschedule [year month days minutes] - Add a schedule
+    Example: /addschedule 2024 12 22 480`,
+	"usage.updateschedule": `/updateschedule [ID days minutes] - Update a schedule
+    Example: /updateschedule 1 23 490`,
+	"usage.deleteschedule": "/deleteschedule [ID] - Delete a schedule",
+	"usage.generateschedules": `/generateschedules [year] [minutes] - Generate schedules for a year automatically
+    Example: /generateschedules 2026 520 (8h 40m)`,
+	"usage.updateallschedules":  "/updateallschedules - Update all schedules from days off",
+	"usage.userstat":            "/userstat [ID] [year month] - Employee statistics",
+	"usage.teamstats":           "/teamstats [year month] - Employee summary",
+	"usage.recalcstats":         "/recalcstats [ID] [year month] - Recalculate statistics from sessions and absences",
+	"usage.truancy":             "/truancy [ID] [date] - Mark truancy",
+	"usage.bankadjust":          "/bankadjust [ID minutes reason] - Time bank adjustment",
+	"usage.closemonth":          "/closemonth [year month] - Transfer monthly results to the time bank",
+	"usage.bankcaps":            "/bankcaps [overtime deficit] - Monthly transfer limits (minutes)",
+	"usage.apitokens":           "/apitokens - API tokens",
+	"usage.addapitoken":         "/addapitoken [name] - Issue an API token for an external system",
+	"usage.revokeapitoken":      "/revokeapitoken [ID] - Revoke an API token",
+	"access.check_failed_reply": "❌ Failed to check access rights: %s",
+	"access.denied_command":     "❌ Access denied. You do not have permission for this command.",
+
+	// Справка и общие ответы
+	"help.commands": `📋 Available commands:
+
+👤 Profile:
+/createprofile - Create a profile (name and time zone)
+/myprofile - Show my profile
+/updateprofile - Update the profile
+/settimezone [city or zone] - Time zone (- to use the company zone)
+/language [ru|en] - Interface language
+/deleteprofile - Deactivate the profile on leaving (history is kept)
+/cancel - Cancel the current action
+
+⏰ Time tracking:
+/in - Start the working day (date and time can be given, see /helptime)
+/out - Finish the working day (date and time can be given, see /helptime)
+/helptime - Help on giving the time for the start/end of the working day
+/today - Information about today's working day
+/status - Current work status
+/history [N] - Working day history (last N, 10 by default)
+/monthwork [month] - Working days for a month
+/monthwork [year month] - Working days for a month and year
+/checkday [date] - Check whether a day is a working day
+    Example: /checkday 01.05.2026 or /checkday 01.05
+
+🏖️ Vacations/Sick leaves/Days off:
+/vacation start_date end_date - Add a vacation
+    Example: /vacation 01.07.2026 14.07.2026
+/sick start_date end_date - Add a sick leave
+    Example: /sick 01.07.2026 07.07.2026
+/dayoff date - Add a day off
+    Example: /dayoff 15.08.2026
+/unpaid start_date end_date - Add unpaid leave
+    Example: /unpaid 01.07.2026 03.07.2026
+/myabsences - My absence periods
+/teamcalendar [month] [image] [all] - Team absences (all - whole company) for a month
+/balance - Time bank (overtime/deficit by month)
+
+📊 Work statistics:
+/mystats - All my statistics
+/stat [month] - Statistics for a month
+/stat [year month] - Statistics for a month and year
+/currentstat - Statistics for the current month
+
+🛠 Utilities:
+/start - Start using the bot
+/help - Show this message
+/echo [text] - Echo the text back
+
+💡 How to use:
+1. Create a profile with /createprofile
+2. Start the working day with /in
+3. Finish the working day with /out
+4. Add vacations/sick leaves with /vacation or /sick
+5. Follow your statistics with /mystats
+6. Administrators set up work schedules
+
+📈 Automatic updates:
+• Statistics are updated automatically when the working day is finished
+• Vacation/sick leave days are counted as worked
+• Unpaid leave and truancy are not counted as worked time
+• Monthly results go to the time bank, a day off takes 8h 40m from the bank`,
+	"help.time_formats": `📝 *Date and time formats:*
+
+*Date (optional):*
+• dd.mm.yyyy (25.12.2023)
+• dd-mm-yyyy (25-12-2023)
+
+*Time (optional):*
+• hh:mm (09:30)
+• hh.mm (09.30)
+• hh-mm (09-30)
+
+*Examples:*
+• /in 09:00 — start work today at 9:00
+• /in 25.12.2023 09:30 — start work on 25 December 2023 at 9:30
+• /out 18:00 — finish work today at 18:00
+• /out 25-12-2023 18-30 — finish work on 25 December 2023 at 18:30
+• /in — start work now
+• /out — finish work now
+
+⚠️ *Notes:*
+• If only the time is given, today's date is used
+• *Work cannot be started on a day off* according to the production calendar
+• Use /checkday [date] to check whether a day is a working day
+• The year must be %d or later`,
+	"help.admin_no_commands": "❌ Access denied. Your role has no management commands.",
+	"help.admin_title": `📋 Available commands (role: %s):
+
+%s`,
+	"help.admin_base_admin": "🔧 Main administrator ID: %d",
+	"help.admin_team_scope": "💡 Commands only work for members of your team.",
+	"profile.not_found_reply": `❌ Profile not found.
+Use /createprofile to create one.`,
+	"command.unknown": "❌ Unknown command. Use /help for the list of commands.",
+	"echo.empty":      "I got your message, but I can't repeat it 😊",
+	"echo.reply":      "🔁 Echo: %s",
+	"echo.empty_args": "You did not give any text to echo!",
+	"language.usage": `🌐 Current language: %s
+Change: /language ru or /language en`,
+	"language.set_failed": "❌ Failed to change the language: %s",
+	"language.set":        "✅ Interface language: %s",
+
+	// Профиль
+	"profile.already_exists": `❌ You already have a profile!
+Use /myprofile to view it or /updateprofile to change it.`,
+	"profile.create.first_name": `👤 Creating a profile
+
+Step 1 of 4:
+✏️ Please send your first name:
+(/cancel - cancel)`,
+	"profile.create.first_name_empty": "❌ The first name cannot be empty. Send your first name as text:",
+	"profile.create.last_name": `Step 2 of 4:
+✅ First name saved: %s
+✏️ Now send your last name (if you have none, send "-"):`,
+	"profile.create.timezone": `Step 4 of 4:
+🕒 Which time zone do you work in?
+%s
+If you work on company time (%s), send "-":`,
+	"profile.create.timezone_invalid": `❌ %s
+%s
+(/cancel - cancel)`,
+	"profile.create.data_corrupted": "❌ The dialog data is corrupted, start again: /createprofile",
+	"profile.create_failed":         "❌ Failed to create the profile: %s",
+	"profile.created": `🎉 Profile created!
+
+%s
+
+You can now use /myprofile to view your profile at any time.`,
+	"profile.update.prompt": `✏️ Updating the profile
+
+Send the new data in the format:
+FirstName LastName Patronymic
+
+For example: *John Smith* (the patronymic is optional)
+Or just: *John* (to update the first name only)
+(/cancel - cancel)`,
+	"profile.update.invalid": `❌ Invalid format. Please send your first and last name.
+(/cancel - cancel)`,
+	"profile.update_failed": "❌ Failed to update the profile: %s",
+	"profile.updated": `✅ Profile updated!
+
+%s`,
+	"profile.delete_confirm": `⚠️ Are you sure you want to deactivate your profile?
+You will no longer be able to track time; your history is kept. An administrator can restore the profile.`,
+	"button.yes_delete": "✅ Yes, deactivate",
+	"button.no_cancel":  "❌ No, cancel",
+	"timezone.hint":     "Enter a city (Москва, Екатеринбург, Новосибирск, мск, екб, нск) or an IANA zone name (Asia/Omsk).",
+	"timezone.usage": `❌ Specify a time zone: /settimezone Asia/Novosibirsk
+%s
+To return to the company zone: /settimezone -`,
+	"timezone.set_failed": "❌ Failed to change the time zone: %s",
+	"timezone.set": `✅ Time zone: %s
+🕒 Your current time: %s`,
+
+	// Диалоги: ответы
+	"dialog.start_failed":   "❌ Failed to start the dialog: %v",
+	"dialog.advance_failed": "❌ Failed to save the answer: %v",
+	"dialog.expired": `⌛ The answer timed out, the action was cancelled.
+Start again: %s`,
+	"error.generic":            "❌ Error: %s",
+	"dialog.nothing_to_cancel": "ℹ️ There is no active action to cancel.",
+	"dialog.cancelled":         "❌ Action cancelled.",
+
+	// Кнопки
+	"session.clock_out_cancelled": "❌ Clocking out cancelled.",
+	"profile.delete_failed":       "❌ Failed to deactivate the profile: %s",
+	"profile.deleted":             "✅ Your profile has been deactivated. Your time history is kept.",
+	"profile.delete_cancelled":    "❌ Profile deactivation cancelled.",
+
+	// Отделы и команды: ответы
+	"error.reply": "❌ %s",
+	"team.department_usage": `❌ Specify the department name.
+Example: /adddepartment Development`,
+	"team.department_created": `✅ Department "%s" created (ID: %d).
+Add a team: /addteam %d Name`,
+	"team.department_delete_usage": `❌ Specify the department ID.
+Example: /deletedepartment 1`,
+	"team.department_delete_failed": "❌ Failed to delete the department: %s",
+	"team.department_deleted":       "✅ Department with ID %d deleted.",
+	"team.add_usage": `❌ Invalid format.
+Example: /addteam 1 Backend
+(1 - department ID, see /teams)`,
+	"team.invalid_department_id": "❌ Invalid department ID.",
+	"team.created": `✅ Team "%s" created in department "%s" (ID: %d).
+
+Add a member: /setteam user_ID %d
+Assign a lead: /setrole user_ID team_lead`,
+	"team.delete_usage": `❌ Specify the team ID.
+Example: /deleteteam 1`,
+	"team.delete_failed": "❌ Failed to delete the team: %s",
+	"team.deleted":       "✅ Team with ID %d deleted, its members no longer have a team.",
+	"team.set_usage": `❌ Invalid format.
+Example: /setteam 123456789 2
+(2 - team ID, 0 - remove from the team)`,
+	"user.invalid_id": `❌ Invalid ID format.
+The ID must be a number.`,
+	"team.invalid_id":   "❌ Invalid team ID.",
+	"team.user_removed": "✅ %s %s was removed from the team.",
+	"team.user_added":   "✅ %s %s was added to the team with ID %d.",
+
+	// Администрирование
+	"users.list_failed":  "❌ Failed to get the user list: %s",
+	"users.team_title":   "📋 Members of your team:",
+	"users.stats_failed": "❌ Failed to get statistics: %s",
+	"users.stats_title": `📊 Bot statistics:
+
+👥 Total users: %d
+`,
+	"admins.list_failed": "❌ Failed to get the administrator list: %s",
+	"admins.empty":       "👑 The administrator list is empty.",
+	"admins.title":       "👑 Administrators:",
+	"admins.promote_usage": `❌ Specify the user ID.
+Example: /promote 123456789`,
+	"admins.promote_failed": "❌ Failed to make the user an administrator: %s",
+	"admins.promoted":       "✅ The user with ID %d is now an administrator!",
+	"admins.demote_usage": `❌ Specify the user ID.
+Example: /demote 123456789`,
+	"admins.demote_base_admin": "❌ The main administrator set in the configuration cannot be demoted!",
+	"admins.demote_failed":     "❌ Failed to revoke administrator rights: %s",
+	"admins.demoted":           "✅ The user with ID %d is now an employee!",
+	"role.set_usage": `❌ Invalid format.
+Example: /setrole 123456789 hr
+Available roles: %s`,
+	"role.unknown_reply": `❌ Unknown role.
+Available roles: %s`,
+	"role.base_admin":          "❌ The role of the main administrator set in the configuration cannot be changed!",
+	"role.set_failed":          "❌ Failed to change the role: %s",
+	"role.set":                 "✅ The role of the user with ID %d was changed to '%s'!",
+	"role.list_title":          "🔐 Roles and permissions:",
+	"role.own_data_only":       "   own data only",
+	"role.list_assign_hint":    "Assign a role: /setrole ID role",
+	"role.list_team_lead_hint": "team_lead permissions only apply to members of their team.",
+
+	// Токены API: ответы
+	"apitoken.list_failed": "❌ Failed to get tokens: %s",
+	"apitoken.add_usage": `❌ Specify the client name.
+Example: /addapitoken HR-portal`,
+	"apitoken.created": `✅ Token "%s" issued (ID: %d).
+
+%s
+
+⚠️ Save it now - the token will not be shown again.
+Pass it in the header: Authorization: Bearer <token>
+Revoke: /revokeapitoken %d`,
+	"apitoken.revoke_usage": `❌ Specify the token ID.
+Example: /revokeapitoken 1 (list: /apitokens)`,
+	"apitoken.revoked_reply": "✅ Token \"%s\" (ID: %d) revoked.",
+
+	// Календарь отсутствий: ответы
+	"date.invalid_month_reply": "❌ Invalid month. Use a number from 1 to 12.",
+	"date.invalid_year_reply":  "❌ Invalid year. Use a year between %d and %d.",
+	"calendar.usage":           "❌ Invalid format. Use: /teamcalendar [year month] [image] [all] or /teamcalendar [month] [image] [all]",
+	"calendar.get_failed":      "❌ Failed to get the calendar: %s",
+	"calendar.render_failed":   "❌ Failed to render the image: %s",
+	"calendar.overloaded_warning": `
+
+⚠️ More than %d%% of the team is absent on these days: %s
+See the calendar: /teamcalendar %d %d`,
+
+	// Банк времени: ответы
+	"timebank.get_failed_reply": "❌ Failed to get the time bank: %s",
+	"timebank.close_usage": `📅 Closing a month
+
+Command format:
+/closemonth [year month]
+
+Examples:
+/closemonth
+→ Transfer last month's results to the time bank
+
+/closemonth 2026 3
+→ Transfer the results for March 2026
+
+💡 Closing a month again recalculates the transferred results.`,
+	"date.invalid_year_format":  "❌ Invalid year format.",
+	"date.invalid_month_format": "❌ Invalid month format.",
+	"timebank.close_failed":     "❌ Failed to close the month: %s",
+	"timebank.closed": `✅ Results for %02d.%d transferred to the time bank.
+👥 Users: %d`,
+	"timebank.settings_failed_reply": "❌ Failed to get the settings: %s",
+	"timebank.caps": `🏦 Time bank limits (per month):
+
+➕ Overtime: %d min
+➖ Deficit: %d min
+
+Change: /bankcaps overtime deficit
+Example: /bankcaps 2400 1200`,
+	"timebank.caps_usage":       "❌ Command format: /bankcaps overtime deficit (in minutes)",
+	"timebank.caps_not_numbers": "❌ Limits must be numbers (in minutes).",
+	"timebank.caps_save_failed": "❌ Failed to save the limits: %s",
+	"timebank.caps_updated": `✅ Limits updated.
+➕ Overtime: %d min
+➖ Deficit: %d min
+
+💡 The new limits apply at the next month closing (/closemonth).`,
+	"timebank.adjust_usage": `✏️ Time bank adjustment
+
+Command format:
+/bankadjust ID minutes reason
+
+Examples:
+/bankadjust 123456789 120 Work on a day off 07.03
+→ Credit 2 hours
+
+/bankadjust 123456789 -60 Tracking error
+→ Debit 1 hour`,
+	"timebank.adjust_minutes_invalid": "❌ The number of minutes must be a number (a minus sign is allowed).",
+	"timebank.adjust_failed_reply":    "❌ Failed to adjust: %s",
+	"timebank.adjusted": `✅ Time bank adjusted: %s %s
+✏️ %s (%s)
+💰 Balance: %s`,
+
+	// Графики работы: ответы
+	"schedule.add_usage": `📝 Adding a work schedule
+
+Command format:
+/addschedule Year Month Days MinutesPerDay
+
+Example:
+/addschedule 2024 12 22 480
+→ December 2024, 22 working days of 8 hours (480 minutes)
+
+/addschedule 2024 1 20 450
+→ January 2024, 20 working days of 7.5 hours (450 minutes = 7h 30m)
+
+Or just send the data in the format:
+"2024 12 22 480"`,
+	"schedule.parse_failed":  "❌ Failed to parse the data: %s",
+	"schedule.create_failed": "❌ Failed to create the schedule: %s",
+	"schedule.update_usage": `✏️ Updating a work schedule
+
+Command format:
+/updateschedule ID Days MinutesPerDay
+
+Example:
+/updateschedule 1 23 490
+→ Updates the schedule with ID=1 to 23 working days of 490 minutes (8h 10m)
+
+Use /getschedules first to see the schedule IDs`,
+	"schedule.update_format":   "❌ Invalid format. Use: /updateschedule ID days minutes_per_day",
+	"schedule.invalid_id":      "❌ Invalid ID format. The ID must be a number.",
+	"schedule.invalid_days":    "❌ Invalid number of days. Must be between 0 and 31.",
+	"schedule.invalid_minutes": "❌ Invalid minutes per day. Must be between 1 and 1440.",
+	"schedule.update_failed":   "❌ Failed to update the schedule: %s",
+	"schedule.delete_usage": `🗑️ Deleting a work schedule
+
+Command format:
+/deleteschedule ID
+
+Example:
+/deleteschedule 1
+→ Deletes the schedule with ID=1
+
+Use /getschedules first to see the schedule IDs`,
+	"schedule.delete_confirm": `⚠️ Are you sure you want to delete the schedule with ID %d?
+This cannot be undone.`,
+	"schedule.list_failed": "❌ Failed to get schedules: %s",
+	"schedule.get_usage": `📋 Viewing a work schedule
+
+Command format:
+/getschedule ID
+→ Shows the schedule with the given ID
+
+/getschedule 2024 12
+→ Shows the schedule for December 2024
+
+Use /getschedules to see all available schedules`,
+	"schedule.get_failed_reply":      "❌ Failed to get the schedule: %s",
+	"schedule.not_found_id_reply":    "❌ Schedule with ID %d not found.",
+	"schedule.get_format":            "❌ Invalid format. Use: /getschedule ID or /getschedule year month",
+	"schedule.current_failed":        "❌ Failed to get the current schedule: %s",
+	"schedule.callback_invalid_id":   "❌ Error: invalid schedule ID",
+	"schedule.delete_failed":         "❌ Failed to delete the schedule: %s",
+	"schedule.deleted":               "✅ Schedule with ID %d deleted!",
+	"schedule.delete_cancelled":      "❌ Schedule deletion cancelled.",
+	"schedule.generate_year_format":  "❌ Invalid year format. Use: /generateschedules [year] [minutes_per_day]",
+	"schedule.generate_format":       "❌ Invalid format. Use: /generateschedules [year] [minutes_per_day]",
+	"schedule.generate_invalid_year": "❌ Invalid year. The year must be between %d and %d.",
+	"schedule.generate_failed":       "❌ Failed to generate schedules: %s",
+	"schedule.update_all_failed":     "❌ Failed to update schedules: %s",
+	"schedule.update_all_none":       "✅ All schedules are already up to date. Nothing was updated.",
+	"date.invalid_format_short":      "❌ Invalid date format. Use DD.MM.YYYY or DD.MM",
+	"schedule.check_day_failed":      "❌ Failed to check the day: %s",
+	"schedule.day_off":               "❌ Day off",
+
+	// Графики работы: списки
+	"schedule.month_not_found":   "❌ No schedule found for %s.",
+	"schedule.current_not_set":   "❌ No schedule is set for the current month (%s).",
+	"schedule.generated.one":     "✅ Generated %d schedule for %d",
+	"schedule.generated.other":   "✅ Generated %d schedules for %d",
+	"schedule.generated_list":    "📋 Created/updated schedules:",
+	"count.working_days.one":     "%d working day",
+	"count.working_days.other":   "%d working days",
+	"schedule.updated_all.one":   "✅ Updated %d schedule.",
+	"schedule.updated_all.other": "✅ Updated %d schedules.",
+	"schedule.check_day_date":    "📅 Date: %s",
+	"schedule.working_day": `✅ Working day
+⏰ Working time: %s`,
+
+	// Статистика: ответы
+	"stats.stat_usage": "❌ Invalid format. Use: /stat [year month] or /stat [month]",
+	"stats.userstat_usage": `❌ Specify the user ID.
+Example: /userstat 123456789 [year month]`,
+	"stats.user_not_found": "📭 No statistics for %s %s for %02d.%d.",
+	"stats.user_stat": `👤 %s %s
+
+%s`,
+	"stats.recalc_usage": `❌ Invalid format.
+Use: /recalcstats [ID] [year month]`,
+	"users.get_failed_reply": "❌ Failed to get users: %s",
+
+	// Даты: ошибки ввода
+	"stats.month_not_found":          "📭 No statistics for %s.",
+	"stats.current_month_not_found":  "📭 No statistics for the current month (%s).",
+	"date.invalid_month":             "invalid month. Use a number from 1 to 12",
+	"date.invalid_year":              "invalid year. Use a year between %d and %d",
+	"date.invalid_year_month_format": "invalid format. Use: [year month] or [month]",
+
+	// Отсутствия: ответы
+	"absence.vacation_usage": `🏖️ *Adding a vacation*
+
+Command format:
+/vacation start_date end_date
+
+Examples:
+/vacation 01.07.2026 14.07.2026
+→ Vacation from 1 to 14 July 2026
+
+/vacation 15.08.2026 15.08.2026
+→ One-day vacation on 15 August 2026
+
+💡 *Important:*
+• A vacation can only be added for future dates
+• Vacation is not added on days off
+• It cannot overlap other vacations/sick leaves
+• See who else is on vacation with /teamcalendar`,
+	"absence.vacation_format":    "❌ Invalid format. Use: /vacation start_date end_date",
+	"absence.start_date_invalid": "❌ Failed to parse the start date: %s",
+	"absence.end_date_invalid":   "❌ Failed to parse the end date: %s",
+	"absence.vacation_failed":    "❌ Failed to add the vacation: %s",
+	"absence.vacation_added": `✅ Vacation added!
+
+🏖️ Period: %s - %s
+📅 Number of days: %d
+⏰ Total hours: %d:%02d
+
+📋 Vacation days will be counted as worked automatically (8h 40m each).`,
+	"absence.sick_usage": `🏥 *Adding a sick leave*
+
+Command format:
+/sick start_date end_date
+
+Examples:
+/sick 01.07.2026 07.07.2026
+→ Sick leave from 1 to 7 July 2026
+
+/sick 15.08.2026 15.08.2026
+→ One-day sick leave on 15 August 2026
+
+💡 *Important:*
+• A sick leave can be added for any dates (including past ones)
+• It can include days off
+• It cannot overlap other vacations/sick leaves`,
+	"absence.sick_format": "❌ Invalid format. Use: /sick start_date end_date",
+	"absence.sick_failed": "❌ Failed to add the sick leave: %s",
+	"absence.sick_added": `✅ Sick leave added!
+
+🏥 Period: %s - %s
+📅 Number of days: %d
+⏰ Total hours: %d:%02d
+
+📋 Sick leave days will be counted as worked automatically (8h 40m each).`,
+	"absence.day_off_usage": `🎯 *Adding a day off*
+
+Command format:
+/dayoff date
+
+Examples:
+/dayoff 01.07.2026
+→ Day off on 1 July 2026
+
+/dayoff 15.08.2026
+→ Day off on 15 August 2026
+
+💡 *Important:*
+• A day off can be added for any date
+• A day off takes 8h 40m from the time bank (/balance)
+• It cannot be added on a non-working day
+• It cannot overlap other vacations/sick leaves`,
+	"absence.date_invalid":   "❌ Failed to parse the date: %s",
+	"absence.day_off_failed": "❌ Failed to add the day off: %s",
+	"absence.day_off_added": `✅ Day off added!
+
+🎯 Date: %s
+⏰ Hours: 8:40
+
+📋 This day will be counted as worked (8h 40m).
+🏦 8h 40m were taken from the time bank.`,
+	"absence.unpaid_format": "❌ Invalid format. Use: /unpaid start_date end_date",
+	"absence.unpaid_failed": "❌ Failed to add the unpaid leave: %s",
+	"absence.unpaid_added": `✅ Unpaid leave added!
+
+💸 Period: %s - %s
+📅 Number of days: %d
+
+📋 %s`,
+	"absence.truancy_failed": "❌ Failed to mark the truancy: %s",
+	"absence.truancy_marked": `✅ Truancy marked: %s %s, %s
+
+📋 %s`,
+	"absence.credit.reduce_plan": "The days are not counted as worked and reduce the planned time of the month.",
+	"absence.credit.none":        "The days are counted as 0 worked minutes and increase the deficit.",
+	"absence.credit.full":        "The days will be counted as worked automatically.",
+	"absence.list_failed":        "❌ Failed to get the data: %s",
+	"absence.list_empty":         "📭 You have no planned vacations, sick leaves or days off.",
+	"absence.day_off_balance": `
+💰 Remaining: %s`,
+
+	// Отсутствия: справка
+	"absence.unpaid_usage": `💸 *Adding unpaid leave*
+
+Command format:
+/unpaid start_date end_date
+
+Examples:
+/unpaid 01.07.2026 03.07.2026
+→ Unpaid leave from 1 to 3 July 2026
+
+💡 *Important:*
+• Unpaid leave can only be added for future dates
+• Leave is not added on days off
+• It cannot overlap other vacations/sick leaves
+• %s`,
+	"absence.truancy_usage": `🚫 Marking truancy
+
+Command format:
+/truancy ID date
+
+Example:
+/truancy 123456789 15.08.2026
+→ User's truancy on 15 August 2026
+
+💡 %s`,
+
+	// Отсутствия: список
+	"absence.list_title":           "📋 *My absence periods:*",
+	"absence.list_vacations":       "🏖️ *Vacations:*",
+	"absence.list_sick_leaves":     "🏥 *Sick leaves:*",
+	"absence.list_day_offs":        "🎯 *Days off:*",
+	"absence.list_unpaid_leaves":   "💸 *Unpaid leaves:*",
+	"absence.list_truancies":       "🚫 *Truancies:*",
+	"absence.list_stats":           "📊 *Statistics:*",
+	"absence.list_total_vacation":  "• Total vacation days: %d",
+	"absence.list_total_sick":      "• Total sick leave days: %d",
+	"absence.list_total_day_offs":  "• Total days off: %d",
+	"absence.list_total_unpaid":    "• Total unpaid leave days: %d",
+	"absence.list_total_truancies": "• Total truancies: %d",
+	"date.invalid_format":          "invalid date format. Use DD.MM.YYYY or DD.MM",
+
+	// Рабочие сессии: ответы
+	"session.clock_in_parse_failed": `❌ %s
+
+Examples:
+/in 25.12.2023 09:30
+/in 09.00
+/in 25-12-2023 09-30`,
+	"session.clock_in_non_working": `❌ %s is a day off!
+
+📅 You cannot start work on a day off according to the production calendar.`,
+	"session.clock_in_denied": "❌ Cannot start work: %s",
+	"session.clock_in_failed": "❌ Failed to start work: %s",
+	"session.clock_in_started": `✅ Work day started!
+
+⏰ Start time: %s
+📅 Date: %s
+⏳ Daily norm: %s
+⏰ You can leave at: %s
+
+💡 Don't forget to mark the end of the work day with /out`,
+	"session.clock_in_backdated": `
+
+⚠️ *Attention:* Work was started retroactively.`,
+	"button.clock_out": "⏰ Finish the work day",
+	"session.clock_out_parse_failed": `❌ %s
+
+Examples:
+/out 25.12.2023 18:30
+/out 18.00
+/out 25-12-2023 18-30`,
+	"session.clock_out_non_working": `⚠️ *Attention:* %s is a day off!
+
+Do you really want to finish work on a day off?
+
+This may be a mistake.`,
+	"button.yes_finish":        "✅ Yes, finish",
+	"button.cancel":            "❌ Cancel",
+	"session.clock_out_denied": "❌ Cannot finish work: %s",
+	"session.get_failed_reply": "❌ Failed to get the session: %s",
+	"session.clock_out_failed": "❌ Failed to finish work: %s",
+	"session.clock_out_finished": `✅ Work day finished!
+
+⏰ Working time: %s - %s
+⏳ Worked: %s
+📊 Norm: %s%s
+
+📈 Statistics were updated automatically.`,
+	"session.clock_out_backdated": `
+
+⚠️ *Attention:* Work was finished retroactively.`,
+	"session.clock_out_on_day_off": `
+
+⚠️ *Attention:* Work was finished on a day off (confirmed by the user).`,
+	"button.clock_in": "🔄 Start a new work day",
+	"session.today_empty": `📭 You have not started work today yet.
+Use /in to start the work day.`,
+	"session.history_failed": "❌ Failed to get the history: %s",
+	"session.month_failed":   "❌ Failed to get the sessions: %s",
+	"session.month_empty":    "📭 You had no work days in %s.",
+	"session.month_report": `📊 Work days for %s
+
+%s
+
+📈 Month totals:
+   📋 Days worked: %d
+   ⏰ Total time: %s`,
+	"session.status_failed": "❌ Failed to get the status: %s",
+	"session.status_working": `🟢 You are at work!
+
+⏰ Started work: %s
+⏳ Time passed: %s
+📅 Date: %s
+
+💡 Use /out to finish the work day.`,
+	"session.status_idle": `📭 You have not worked today yet.
+
+💡 Available commands:
+/in - Start the work day
+/today - Information about today
+/history - Work day history
+/status - Current status`,
+
+	// Рабочие сессии: разбор времени и длительности
+	"date.invalid_format_full": "invalid date format. Use dd.mm.yyyy or dd-mm-yyyy",
+	"time.invalid_format":      "invalid time format. Use hh:mm, hh.mm or hh-mm",
+	"count.hours.one":          "%d hour",
+	"count.hours.other":        "%d hours",
+	"count.minutes.one":        "%d minute",
+	"count.minutes.other":      "%d minutes",
+
+	// Импорт рабочих дней из CSV
+	"usage.importsessions": "/importsessions - Import attendance history from CSV (file with the command as caption)",
+	"button.import":        "✅ Import",
+	"import.usage": `📥 Attendance history import
+
+Send a CSV file as a document with the caption /importsessions. The bot checks the file and reports errors first; the import runs after you confirm it.
+
+Each line is one day of an employee:
+• work day: employee, date, clock-in, clock-out
+• absence: employee, date, type
+
+Employee is a chat ID or @username, date is DD.MM.YYYY or YYYY-MM-DD, time is HH:MM on the employee's clock.
+Absence types: vacation, sick_leave, day_off, unpaid_leave, truancy.
+Columns are separated by commas or semicolons; the first line may be a header.
+
+Example:
+user,date,clock_in,clock_out
+@ivan,02.03.2026,09:00,18:00
+@ivan,03.03.2026,vacation`,
+	"import.too_large":          "❌ The file is too large. The maximum size is %d KB.",
+	"import.download_failed":    "❌ Failed to get the file: %v",
+	"import.failed":             "❌ Import failed: %s",
+	"import.fix_and_resend":     "Fix the file and send it again.",
+	"import.confirm_question":   "Import this data?",
+	"import.confirm_pending":    "The file has been checked and is waiting for confirmation. Press a button under the report or /cancel.",
+	"import.expired":            "⏰ There is no checked file to import. Send it again with the caption /importsessions.",
+	"import.cancelled":          "❌ Import cancelled.",
+	"import.closed_months_hint": "If these months have already been closed into the time bank, run /closemonth for them again.",
+	"import.checked": `🔎 The file has been checked, no errors found.
+Work days: %d, absence days: %d, employees: %d.`,
+	"import.committed": `✅ Import completed.
+Work days: %d, absence days: %d, employees: %d. Monthly stats recalculated: %d.`,
+	"import.errors_title":         "❌ Errors in the file: %d",
+	"import.error_line":           "• line %d: %s",
+	"import.more_errors":          "… and %d more",
+	"import.invalid_csv":          "invalid CSV: %v",
+	"import.empty":                "the file has no data lines",
+	"import.columns":              "expected 4 columns (employee, date, clock-in, clock-out) or 3 (employee, date, absence type), got %d",
+	"import.unknown_user":         "employee %s not found",
+	"import.invalid_date":         "invalid date %q, use DD.MM.YYYY or YYYY-MM-DD",
+	"import.invalid_time":         "invalid time %q, use HH:MM",
+	"import.not_past":             "%s - only past days can be imported",
+	"import.non_working_day":      "%s is a non-working day",
+	"import.unknown_absence_type": "unknown absence type %q",
+	"import.clock_out_before_in":  "clock-out must be later than clock-in",
+	"import.overlaps_session":     "overlaps a work session on %s",
+	"import.overlaps_absence":     "overlaps an absence period on %s",
+	"import.overlaps_row":         "overlaps line %d",
+	"import.create_failed":        "line %d: failed to save: %v",
+
+	// Резервные копии
+	"usage.backup": "/backup - Database backup as a file",
+	"backup.caption": `💾 Database backup as of %s.
+Restore into an empty database: bot restore <file>`,
+	"backup.failed":      "❌ Failed to create a backup: %s",
+	"backup.too_large":   "⚠️ The backup is %d MB, but the bot can send at most %d MB. Use scheduled backups (backup.dir in the configuration).",
+	"backup.send_failed": "❌ Failed to send the backup: %v",
+
+	// Журнал изменений
+	"usage.audit": `/audit [chat_id|entity[:id]] [period] - Data change log
+Entities: user, session, absence, schedule, timebank, timebank_settings, department, team, apitoken
+Period: DD.MM.YYYY, MM.YYYY or DD.MM.YYYY-DD.MM.YYYY`,
+	"usage.auditexport":              "/auditexport [chat_id|entity[:id]] [period] - Export the change log to CSV",
+	"audit.record_failed":            "failed to record the change in the audit log: %v",
+	"audit.get_failed":               "failed to get the audit log: %v",
+	"audit.invalid_arg":              "unrecognized argument %q",
+	"audit.invalid_entity_id":        "invalid entity ID %q",
+	"audit.empty":                    "📭 No changes match this query.",
+	"audit.title":                    "📜 Change log (latest entries):",
+	"audit.entry_header":             "🕒 %s, %s",
+	"audit.entry_action":             "%s: %s #%d",
+	"audit.change":                   "  • %s: %s → %s",
+	"audit.more_changes":             "  … and %d more fields",
+	"audit.actor_token":              "API token \"%s\" (#%d)",
+	"audit.actor_user":               "%s (%d)",
+	"audit.actor_chat":               "chat %d",
+	"audit.actor_system":             "system",
+	"audit.deleted_user":             "deleted employee #%d",
+	"audit.action.create":            "Created",
+	"audit.action.update":            "Updated",
+	"audit.action.delete":            "Deleted",
+	"audit.entity.user":              "employee",
+	"audit.entity.session":           "work session",
+	"audit.entity.absence":           "absence",
+	"audit.entity.schedule":          "schedule",
+	"audit.entity.timebank":          "time bank entry",
+	"audit.entity.timebank_settings": "time bank limits",
+	"audit.entity.department":        "department",
+	"audit.entity.team":              "team",
+	"audit.entity.apitoken":          "API token",
+	"audit.export_caption.one":       "📜 Change log: %d entry",
+	"audit.export_caption.other":     "📜 Change log: %d entries",
+
+	// Деактивация сотрудников
+	"usage.deactivate":               "/deactivate [chat_id] [DD.MM.YYYY] - Deactivate a leaving employee (termination date, today by default)",
+	"usage.reactivate":               "/reactivate [chat_id] - Restore a deactivated employee",
+	"usage.purgeuser":                "/purgeuser [chat_id] - Permanently delete a deactivated employee and all their data",
+	"session.active_get_failed":      "failed to get the active session: %v",
+	"user.already_deactivated":       "the employee was already deactivated on %s",
+	"user.deactivate_active_session": "the employee has an unfinished work day, finish it first",
+	"user.not_deactivated":           "the employee is not deactivated",
+	"user.purge_active":              "only a deactivated employee can be deleted, run /deactivate first",
+	"profile.deactivated":            "🔒 Your profile was deactivated on %s. Only /myprofile and /help are available. Contact an administrator to restore the profile.",
+	"profile.info.terminated":        "🔒 Deactivated on %s",
+	"user.deactivate_base_admin":     "❌ The base administrator from the configuration cannot be deactivated.",
+	"user.termination_in_future":     "❌ The termination date cannot be in the future.",
+	"user.deactivated":               "✅ Employee %s %s deactivated as of %s. Their history is kept and stays in reports.",
+	"user.reactivated":               "✅ Employee %s %s is active again.",
+	"user.purge_confirm": `⚠️ Permanently delete employee %s %s (ID: %d)?
+The profile, work sessions, absences, stats and time bank will be deleted, and their data removed from the change log. This cannot be undone.`,
+	"button.yes_purge":     "🗑 Yes, delete permanently",
+	"user.purge_cancelled": "❌ Employee deletion cancelled.",
+	"user.purged":          "🗑 Employee %d and all their data have been deleted.",
+	"users.archived_title": "🔒 Deactivated:",
+	"users.archived_line":  "• %s - ID: %d, since %s",
+
+	// Одобрение новых профилей
+	"usage.pending": "/pending - Profiles awaiting approval",
+	"usage.approve": "/approve [chat_id] - Approve a new profile",
+	"usage.reject":  "/reject [chat_id] - Reject and delete a new profile",
+	"profile.created_pending": `📝 Profile created and sent for approval.
+
+%s
+
+Once HR or an administrator approves it, you will get a message and time tracking will become available.`,
+	"profile.pending":           "⏳ Your profile is awaiting approval. Only /myprofile and /help are available.",
+	"profile.info.pending":      "⏳ Awaiting approval",
+	"profile.approved":          "✅ Your profile has been approved! Time tracking is now available: /help",
+	"profile.rejected":          "❌ Your profile was rejected and deleted. If this is a mistake, contact an administrator.",
+	"user.approval_request":     "🆕 A new profile awaits approval: %s - ID: %d",
+	"button.approve":            "✅ Approve",
+	"button.reject":             "❌ Reject",
+	"user.approved":             "✅ Profile %s approved.",
+	"user.rejected":             "🗑 Profile %s rejected and deleted.",
+	"user.not_pending":          "the profile is not awaiting approval",
+	"user.deactivate_pending":   "the profile is not approved yet, reject it with /reject",
+	"users.pending_empty":       "✅ No profiles are awaiting approval.",
+	"users.pending_count.one":   "⏳ %d profile awaits approval:",
+	"users.pending_count.other": "⏳ %d profiles await approval:",
+	"users.pending_title":       "⏳ Awaiting approval:",
+	"users.pending_line":        "• %s - ID: %d",
+
+	// Кадровые данные сотрудника
+	"profile.create.patronymic": `Step 3 of 4:
+✏️ Send your patronymic (if you have none, send "-"):`,
+	"profile.info.patronymic":      "👨‍💼 Patronymic: %s",
+	"profile.info.department":      "🏢 Department: %s",
+	"profile.info.position":        "💼 Position: %s",
+	"profile.info.employee_number": "🔢 Employee number: %s",
+	"profile.info.hire_date":       "📅 Hire date: %s",
+	"usage.setemployee":            "/setemployee [chat_id] [position|number|hired] [value] - Set the position, employee number or hire date (DD.MM.YYYY); \"-\" clears the field",
+	"employee.unknown_field":       "❌ Unknown field: %s",
+	"employee.number_invalid":      "❌ The employee number cannot contain spaces.",
+	"employee.updated": `✅ Employee data updated.
+
+%s`,
+	"user.employee_number_taken":   "employee number %s already belongs to %s",
+	"user.hire_after_termination":  "the hire date cannot be after the termination date %s",
+	"user.termination_before_hire": "the termination date cannot be before the hire date %s",
+	"stats.summary_name_number":    "%s (No. %s)",
+
+	// Ошибки HTTP API
+	"api.error.auth_required":         "Authorization: Bearer <token> header is required",
+	"api.error.auth_failed":           "failed to check the token",
+	"api.error.token_invalid":         "invalid token",
+	"api.error.internal":              "internal error",
+	"api.error.invalid_chat_id":       "invalid chat ID",
+	"api.error.user_not_found":        "user %d not found",
+	"api.error.user_inactive":         "employee %d is deactivated",
+	"api.error.user_pending":          "employee %d profile is pending approval",
+	"api.error.invalid_year":          "invalid year",
+	"api.error.invalid_year_range":    "invalid year, expected a number between %d and %d",
+	"api.error.invalid_month":         "invalid month, expected a number from 1 to 12",
+	"api.error.invalid_date_param":    "invalid parameter %s, expected YYYY-MM-DD",
+	"api.error.invalid_start_date":    "invalid start date, expected YYYY-MM-DD",
+	"api.error.invalid_end_date":      "invalid end date, expected YYYY-MM-DD",
+	"api.error.end_before_start":      "end date is before start date",
+	"api.error.unknown_absence_type":  "unknown absence type: %q",
+	"api.error.stat_not_found":        "stats for %02d.%d not found",
+	"api.error.schedule_not_found":    "schedule for %02d.%d not found",
+	"api.error.invalid_body":          "invalid request body: %s",
+	"api.error.clock_out_non_working": "%s is a non-working day, pass allow_non_working_day to clock out",
+
+	// Табель и исправление рабочих дней
+	"usage.timesheet":         "/timesheet [year month] - Export the timesheet to CSV",
+	"usage.fixsession":        "/fixsession [ID date in out] - Correct a work day's times",
+	"timesheet.failed":        "❌ Timesheet export failed: %s",
+	"timesheet.caption.one":   "📊 Timesheet for %s: %d employee",
+	"timesheet.caption.other": "📊 Timesheet for %s: %d employees",
+	"session.correct.usage": `✏️ Correcting a work day
+
+Command format:
+/fixsession ID date in out
+
+Example:
+/fixsession 123456789 15.08.2026 09:00 18:00
+→ Work day on 15 August 2026 from 9:00 to 18:00
+
+💡 Times are in the employee's time zone. If the employee did not clock in, the work day is created.`,
+	"session.correct.absence_day": "%s is an absence day, its times cannot be corrected",
+	"session.correct.several":     "%s has %d work sessions, only a single one can be corrected",
+	"session.correct.failed":      "❌ Failed to correct the work day: %s",
+	"session.correct.done": `✅ Work day corrected: %s %s, %s
+
+🕐 %s - %s
+⏱ Worked: %s`,
+}
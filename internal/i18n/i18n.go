@@ -0,0 +1,255 @@
+// Package i18n - каталоги сообщений бота на русском и английском языках.
+//
+// Сообщение задается ключом и шаблоном fmt. Для чисел с зависимым словом ("3 дня", "5 дней")
+// в каталоге хранятся формы с суффиксами .one/.few/.many (русский) или .one/.other (английский),
+// нужную форму выбирает Localizer.N по правилам языка.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Lang - язык интерфейса
+type Lang string
+
+const (
+	Russian Lang = "ru"
+	English Lang = "en"
+)
+
+// Default - язык для пользователей, язык которых не поддерживается
+const Default = Russian
+
+// Catalog - шаблоны сообщений по ключам
+type Catalog map[string]string
+
+var catalogs = map[Lang]Catalog{
+	Russian: ru,
+	English: en,
+}
+
+// Supported возвращает поддерживаемые языки
+func Supported() []Lang {
+	return []Lang{Russian, English}
+}
+
+// Parse распознает язык по коду: "ru", "en-US", "EN". Регион не учитывается.
+func Parse(code string) (Lang, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+
+	lang := Lang(code)
+	if _, ok := catalogs[lang]; !ok {
+		return "", false
+	}
+	return lang, true
+}
+
+// Match возвращает язык по коду или язык по умолчанию
+func Match(code string) Lang {
+	if lang, ok := Parse(code); ok {
+		return lang
+	}
+	return Default
+}
+
+// ParseAcceptLanguage выбирает из заголовка Accept-Language ("en-US,en;q=0.9,ru;q=0.8")
+// поддерживаемый язык с наибольшим весом. Языки с весом 0 и "*" не учитываются.
+func ParseAcceptLanguage(header string) (Lang, bool) {
+	var best Lang
+	bestWeight := 0.0
+	for _, part := range strings.Split(header, ",") {
+		code, params, _ := strings.Cut(part, ";")
+		lang, ok := Parse(code)
+		if !ok {
+			continue
+		}
+
+		weight := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		if weight > bestWeight {
+			best, bestWeight = lang, weight
+		}
+	}
+	return best, best != ""
+}
+
+// Localizer форматирует сообщения на одном языке
+type Localizer struct {
+	lang    Lang
+	catalog Catalog
+}
+
+// For возвращает Localizer для языка с кодом code (пустой или неизвестный - язык по умолчанию)
+func For(code string) *Localizer {
+	lang := Match(code)
+	return &Localizer{lang: lang, catalog: catalogs[lang]}
+}
+
+// Lang возвращает язык
+func (l *Localizer) Lang() Lang {
+	return l.lang
+}
+
+// T возвращает сообщение по ключу. Если ключа нет в каталоге языка, берется каталог по умолчанию,
+// а если нет и там - сам ключ. Аргументы-сообщения, ошибки, даты и продолжительности переводятся
+// на язык Localizer.
+func (l *Localizer) T(key string, args ...interface{}) string {
+	template, ok := l.catalog[key]
+	if !ok {
+		if template, ok = catalogs[Default][key]; !ok {
+			return key
+		}
+	}
+
+	if len(args) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, l.localizeArgs(args)...)
+}
+
+// N возвращает форму сообщения для числа n. Если args не переданы, в шаблон подставляется n.
+func (l *Localizer) N(key string, n int, args ...interface{}) string {
+	if len(args) == 0 {
+		args = []interface{}{n}
+	}
+	return l.T(key+"."+pluralForm(l.lang, n), args...)
+}
+
+// Month возвращает название месяца
+func (l *Localizer) Month(month time.Month) string {
+	return l.T(fmt.Sprintf("month.%d", int(month)))
+}
+
+// MonthYear возвращает название месяца с годом: "март 2026"
+func (l *Localizer) MonthYear(year int, month time.Month) string {
+	return l.T("format.month_year", l.Month(month), year)
+}
+
+// Weekday возвращает краткое название дня недели
+func (l *Localizer) Weekday(day time.Weekday) string {
+	return l.T(fmt.Sprintf("weekday.%d", int(day)))
+}
+
+// Date форматирует дату
+func (l *Localizer) Date(t time.Time) string {
+	return t.Format(l.T("format.date"))
+}
+
+// ShortDate форматирует дату без года
+func (l *Localizer) ShortDate(t time.Time) string {
+	return t.Format(l.T("format.short_date"))
+}
+
+// DateTime форматирует дату и время
+func (l *Localizer) DateTime(t time.Time) string {
+	return t.Format(l.T("format.date_time"))
+}
+
+// Duration форматирует количество минут: "8ч 40м", "8ч", "-1ч 30м"
+func (l *Localizer) Duration(minutes int) string {
+	if minutes < 0 {
+		return "-" + l.Duration(-minutes)
+	}
+
+	hours, mins := minutes/60, minutes%60
+	if mins == 0 {
+		return l.T("format.hours", hours)
+	}
+	return l.T("format.hours_minutes", hours, mins)
+}
+
+// Message переводит сообщение из каталога
+func (l *Localizer) Message(m Message) string {
+	return l.T(m.Key, m.Args...)
+}
+
+// SignedDuration форматирует количество минут со знаком: "+8ч 40м", "-1ч"
+func (l *Localizer) SignedDuration(minutes int) string {
+	if minutes < 0 {
+		return l.Duration(minutes)
+	}
+	return "+" + l.Duration(minutes)
+}
+
+// Error возвращает текст ошибки. Ошибки из каталога (*Error) переводятся на язык Localizer.
+func (l *Localizer) Error(err error) string {
+	var localized *Error
+	if errors.As(err, &localized) {
+		return l.Message(localized.Message)
+	}
+	return err.Error()
+}
+
+// localizeArgs переводит аргументы, которые сами являются сообщениями или ошибками из каталога,
+// и форматирует даты по правилам языка
+func (l *Localizer) localizeArgs(args []interface{}) []interface{} {
+	localized := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch value := arg.(type) {
+		case Message:
+			localized[i] = l.Message(value)
+		case error:
+			localized[i] = l.Error(value)
+		case time.Time:
+			localized[i] = l.Date(value)
+		case Minutes:
+			localized[i] = l.Duration(int(value))
+		case SignedMinutes:
+			localized[i] = l.SignedDuration(int(value))
+		default:
+			localized[i] = arg
+		}
+	}
+	return localized
+}
+
+// Minutes - аргумент сообщения, который выводится как продолжительность ("8ч 40м")
+type Minutes int
+
+// SignedMinutes - аргумент сообщения, который выводится как продолжительность со знаком ("+8ч 40м")
+type SignedMinutes int
+
+// Message - ключ сообщения с аргументами. Переводится при выводе пользователю,
+// когда известен его язык.
+type Message struct {
+	Key  string
+	Args []interface{}
+}
+
+// Msg создает сообщение из каталога
+func Msg(key string, args ...interface{}) Message {
+	return Message{Key: key, Args: args}
+}
+
+// IsZero сообщает, что сообщение не задано
+func (m Message) IsZero() bool {
+	return m.Key == ""
+}
+
+// Error - ошибка, текст которой берется из каталога сообщений.
+// Error() возвращает текст на языке по умолчанию (для логов и API).
+type Error struct {
+	Message
+}
+
+// Errorf создает ошибку из каталога сообщений
+func Errorf(key string, args ...interface{}) error {
+	return &Error{Message: Msg(key, args...)}
+}
+
+func (e *Error) Error() string {
+	return For(string(Default)).Message(e.Message)
+}
@@ -0,0 +1,219 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pluralForms возвращает формы множественного числа, которые должны быть в каталоге языка
+func pluralForms(lang Lang) []string {
+	if lang == Russian {
+		return []string{pluralOne, pluralFew, pluralMany}
+	}
+	return []string{pluralOne, pluralOther}
+}
+
+// baseKey возвращает ключ без суффикса формы множественного числа
+func baseKey(key string) (string, bool) {
+	for _, form := range []string{pluralOne, pluralFew, pluralMany, pluralOther} {
+		if strings.HasSuffix(key, "."+form) {
+			return strings.TrimSuffix(key, "."+form), true
+		}
+	}
+	return key, false
+}
+
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	plurals := make(map[string]bool)
+	messages := make(map[string]bool)
+	for _, lang := range Supported() {
+		for key := range catalogs[lang] {
+			if base, ok := baseKey(key); ok {
+				plurals[base] = true
+			} else {
+				messages[key] = true
+			}
+		}
+	}
+
+	for _, lang := range Supported() {
+		catalog := catalogs[lang]
+		for key := range messages {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("%s: missing key %q", lang, key)
+			}
+		}
+		for base := range plurals {
+			for _, form := range pluralForms(lang) {
+				if _, ok := catalog[base+"."+form]; !ok {
+					t.Errorf("%s: missing plural form %q", lang, base+"."+form)
+				}
+			}
+		}
+	}
+}
+
+func TestCatalogsHaveSameVerbs(t *testing.T) {
+	for key, template := range ru {
+		if _, plural := baseKey(key); plural {
+			continue
+		}
+		translated, ok := en[key]
+		if !ok {
+			continue
+		}
+		if got, want := verbs(translated), verbs(template); got != want {
+			t.Errorf("%q: en verbs %q, ru verbs %q", key, got, want)
+		}
+	}
+}
+
+// verbs возвращает глаголы fmt из шаблона по порядку: "%s %d" -> "sd"
+func verbs(template string) string {
+	var b strings.Builder
+	for i := 0; i < len(template)-1; i++ {
+		if template[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(template) && strings.IndexByte("0123456789.+-# ", template[i]) >= 0 {
+			i++
+		}
+		if i < len(template) && template[i] != '%' {
+			b.WriteByte(template[i])
+		}
+	}
+	return b.String()
+}
+
+func TestPluralForm(t *testing.T) {
+	tests := []struct {
+		lang Lang
+		n    int
+		want string
+	}{
+		{Russian, 1, pluralOne},
+		{Russian, 21, pluralOne},
+		{Russian, 2, pluralFew},
+		{Russian, 24, pluralFew},
+		{Russian, 0, pluralMany},
+		{Russian, 11, pluralMany},
+		{Russian, 12, pluralMany},
+		{Russian, 25, pluralMany},
+		{Russian, 111, pluralMany},
+		{Russian, -3, pluralFew},
+		{English, 1, pluralOne},
+		{English, 0, pluralOther},
+		{English, 21, pluralOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %d", tt.lang, tt.n), func(t *testing.T) {
+			if got := pluralForm(tt.lang, tt.n); got != tt.want {
+				t.Errorf("pluralForm(%s, %d) = %s, want %s", tt.lang, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		code   string
+		want   Lang
+		wantOK bool
+	}{
+		{"ru", Russian, true},
+		{"en-US", English, true},
+		{" EN_gb ", English, true},
+		{"de", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Parse(tt.code)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Parse(%q) = %q, %v; want %q, %v", tt.code, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	if got := Match("de"); got != Default {
+		t.Errorf("Match(de) = %q, want default %q", got, Default)
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Lang
+		wantOK bool
+	}{
+		{"en-US,en;q=0.9", English, true},
+		{"de-DE,de;q=0.9,en;q=0.5,ru;q=0.7", Russian, true},
+		{"ru;q=0.2, EN;q=0.8", English, true},
+		{"en;q=0,ru;q=0.1", Russian, true},
+		{"de, *", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseAcceptLanguage(tt.header)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseAcceptLanguage(%q) = %q, %v; want %q, %v", tt.header, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	ru, en := For("ru"), For("en")
+	date := time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"plural ru", ru.N("count.days", 3), "3 дня"},
+		{"plural en", en.N("count.days", 3), "3 days"},
+		{"month ru", ru.MonthYear(2026, time.March), "март 2026"},
+		{"month en", en.MonthYear(2026, time.March), "March 2026"},
+		{"duration", en.Duration(520), "8h 40m"},
+		{"signed duration", ru.SignedDuration(60), "+1ч"},
+		{"date argument ru", ru.T("session.today_absent", date), "сегодня у вас 02.03.2026"},
+		{"date argument en", en.T("session.today_absent", date), "today you have 02 Mar 2026"},
+		{"minutes argument", ru.T("session.overtime", Minutes(90)), "➕ Переработка: 1ч 30м"},
+		{"message argument", en.T("session.today_absent", Msg("session.status.active")), "today you have at work"},
+		{"unknown key", en.T("no.such.key"), "no.such.key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorIsLocalized(t *testing.T) {
+	err := Errorf("session.already_active")
+
+	if got, want := For("en").Error(err), en["session.already_active"]; got != want {
+		t.Errorf("en error = %q, want %q", got, want)
+	}
+	if got, want := For("en").T("error.reply", err), "❌ "+en["session.already_active"]; got != want {
+		t.Errorf("error argument = %q, want %q", got, want)
+	}
+
+	wrapped := fmt.Errorf("wrapped: %w", err)
+	if got, want := wrapped.Error(), "wrapped: "+ru["session.already_active"]; got != want {
+		t.Errorf("Error() = %q, want default language %q", got, want)
+	}
+
+	var localized *Error
+	if !errors.As(wrapped, &localized) || localized.Key != "session.already_active" {
+		t.Errorf("errors.As = %+v, want the catalog error", localized)
+	}
+}
@@ -0,0 +1,34 @@
+package i18n
+
+// Формы множественного числа (обозначения CLDR)
+const (
+	pluralOne   = "one"
+	pluralFew   = "few"
+	pluralMany  = "many"
+	pluralOther = "other"
+)
+
+// pluralForm возвращает форму множественного числа для n по правилам языка
+func pluralForm(lang Lang, n int) string {
+	if n < 0 {
+		n = -n
+	}
+
+	switch lang {
+	case Russian:
+		// 1, 21, 101 день; 2-4, 22-24 дня; 0, 5-20, 25 дней
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return pluralOne
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return pluralFew
+		default:
+			return pluralMany
+		}
+	default:
+		if n == 1 {
+			return pluralOne
+		}
+		return pluralOther
+	}
+}
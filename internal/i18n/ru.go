@@ -0,0 +1,1205 @@
+package i18n
+
+// ru - русский каталог сообщений
+var ru = Catalog{
+	// Форматы дат и чисел
+	"format.date":          "02.01.2006",
+	"format.short_date":    "02.01",
+	"format.date_time":     "02.01.2006 15:04",
+	"format.month_year":    "%s %d",
+	"format.hours":         "%dч",
+	"format.hours_minutes": "%dч %dм",
+
+	"month.1":  "январь",
+	"month.2":  "февраль",
+	"month.3":  "март",
+	"month.4":  "апрель",
+	"month.5":  "май",
+	"month.6":  "июнь",
+	"month.7":  "июль",
+	"month.8":  "август",
+	"month.9":  "сентябрь",
+	"month.10": "октябрь",
+	"month.11": "ноябрь",
+	"month.12": "декабрь",
+
+	"weekday.0": "Вс",
+	"weekday.1": "Пн",
+	"weekday.2": "Вт",
+	"weekday.3": "Ср",
+	"weekday.4": "Чт",
+	"weekday.5": "Пт",
+	"weekday.6": "Сб",
+
+	// Типы сессий и отсутствий
+	"session_type.work":         "Работа",
+	"session_type.vacation":     "Отпуск",
+	"session_type.sick_leave":   "Больничный",
+	"session_type.day_off":      "Отгул",
+	"session_type.unpaid_leave": "Отпуск за свой счёт",
+	"session_type.truancy":      "Прогул",
+
+	"absence.type_lower.vacation":     "отпуск",
+	"absence.type_lower.sick_leave":   "больничный",
+	"absence.type_lower.day_off":      "отгул",
+	"absence.type_lower.unpaid_leave": "отпуск за свой счёт",
+	"absence.type_lower.truancy":      "прогул",
+
+	// Рабочие сессии
+	"session.still_working": "еще на работе",
+	"session.time_in":       "⏰ Пришел: %s",
+	"session.time_in_out":   "⏰ Пришел: %s | Ушел: %s",
+
+	"session.status.active":    "на работе",
+	"session.status.completed": "завершен",
+	"session.status.absent":    "отсутствие",
+
+	"session.already_active":              "у вас уже есть активная рабочая сессия",
+	"session.no_active":                   "у вас нет активной рабочей сессии",
+	"session.invalid":                     "некорректные данные сессии",
+	"session.today_absent":                "сегодня у вас %s",
+	"session.error.clock_in_future":       "нельзя указать время начала работы в будущем",
+	"session.error.clock_in_too_early":    "нельзя указать время начала работы раньше %d года",
+	"session.error.clock_in_non_working":  "%s - выходной день, начать работу нельзя",
+	"session.error.clock_out_future":      "нельзя указать время завершения в будущем",
+	"session.error.clock_out_non_working": "%s - выходной день, завершение нужно подтвердить",
+	"session.error.clock_out_before_in":   "время завершения не может быть раньше времени начала работы",
+	"session.not_found":                   "❌ Сессия не найдена",
+
+	"session.absence.credited":          "✅ Засчитано",
+	"session.absence.credited_hint":     "💡 Этот день засчитан как рабочий согласно графику",
+	"session.absence.reduce_plan":       "➖ Не засчитано",
+	"session.absence.reduce_plan_hint":  "💡 Этот день уменьшает плановое время месяца",
+	"session.absence.not_credited":      "❌ Не засчитано",
+	"session.absence.not_credited_hint": "💡 Этот день засчитан как 0 отработанных минут",
+	"session.absence.details": `%s %s: %s
+
+📅 Дата: %s
+⏰ Время: 09:00 - 17:40
+⏳ Отметка: %d:%02d часов
+📊 Статус: %s
+
+%s`,
+
+	"session.details": `📅 Рабочий день: %s
+%s %s
+
+%s
+⏳ Продолжительность: %s
+
+📊 Нормы:
+   📋 Плановое время: %s
+   ⏰ Отработано: %s`,
+	"session.overtime":   "➕ Переработка: %s",
+	"session.deficit":    "➖ Недобор: %s",
+	"session.notes":      "📝 Примечание: %s",
+	"session.updated_at": "🕒 Обновлено: %s",
+
+	"session.history_empty": "📭 Рабочих сессий пока нет",
+	"session.history_title": "📋 История рабочих дней:",
+
+	"stats.update_failed":     "ошибка обновления статистики: %v",
+	"period.end_before_start": "дата окончания раньше даты начала",
+
+	// Пользователи и профиль
+	"user.admin_default_name":       "Администратор",
+	"user.first_name_empty":         "имя не может быть пустым",
+	"user.create_failed":            "ошибка создания пользователя: %v",
+	"user.get_failed":               "ошибка получения пользователя: %v",
+	"user.update_failed":            "ошибка обновления пользователя: %v",
+	"user.lookup_failed":            "ошибка поиска пользователя: %v",
+	"user.not_found":                "пользователь не найден",
+	"user.not_found_id":             "пользователь с ID %d не найден",
+	"user.delete_sessions_failed":   "ошибка удаления рабочих сессий: %v",
+	"user.delete_absences_failed":   "ошибка удаления периодов отсутствия: %v",
+	"user.delete_stats_failed":      "ошибка удаления статистики: %v",
+	"user.delete_time_bank_failed":  "ошибка удаления банка времени: %v",
+	"access.check_failed":           "ошибка проверки прав доступа: %v",
+	"access.denied":                 "доступ запрещен",
+	"access.not_your_team":          "доступ запрещен: пользователь не из вашей команды",
+	"role.change_denied":            "доступ запрещен: недостаточно прав для смены ролей",
+	"role.unknown":                  "неизвестная роль: %s",
+	"role.team_lead_needs_team":     "пользователь не состоит в команде, сначала назначьте команду (/setteam)",
+	"timezone.empty":                "часовой пояс не указан",
+	"timezone.unknown":              "неизвестный часовой пояс %q",
+	"profile.info.title":            "👤 Профиль пользователя:",
+	"profile.info.chat_id":          "🆔 ID чата: %d",
+	"profile.info.username":         "📛 Никнейм: @%s",
+	"profile.info.first_name":       "👨‍💼 Имя: %s",
+	"profile.info.last_name":        "👨‍💼 Фамилия: %s",
+	"profile.info.role":             "%s Роль: %s",
+	"profile.info.team":             "👥 Команда: %s",
+	"profile.info.timezone":         "🕒 Часовой пояс: %s",
+	"profile.info.timezone_default": "🕒 Часовой пояс: %s (пояс компании)",
+	"profile.info.language":         "🌐 Язык: %s",
+	"language.name":                 "русский",
+	"users.all_title":               "📋 Все пользователи:",
+	"users.empty":                   "📭 Список пользователей пуст.",
+	"users.total":                   "📊 Всего пользователей: %d",
+	"users.admins":                  "👑 Администраторов: %d",
+
+	// Язык
+	"language.unknown": "неизвестный язык %q, доступны: ru, en",
+
+	// Статистика
+	"stats.not_found":       "❌ Статистика не найдена",
+	"stats.not_found_month": "статистика за %02d.%d не найдена",
+	"stats.no_days_left":    "в месяце не осталось рабочих дней",
+	"count.days.one":        "%d день",
+	"count.days.few":        "%d дня",
+	"count.days.many":       "%d дней",
+	"stats.planned": `📊 Статистика за %s
+
+📅 Плановые показатели:
+   📋 Рабочих дней: %s
+   ⏰ Плановое время: %s`,
+	"stats.plan_reduction": "➖ Уменьшение плана (за свой счёт): %s",
+	"stats.effective_plan": "📋 План с учетом отсутствий: %s",
+	"stats.actual": `✅ Фактические показатели:
+   📋 Отработано дней: %s
+   ⏰ Отработано времени: %s`,
+	"stats.credited_absences":   "🏖️ Засчитано отсутствий: %s",
+	"stats.uncredited_absences": "🚫 Не засчитано отсутствий: %s (%s)",
+	"stats.counted_total":       "📈 Итого засчитано: %s",
+	"stats.month_overtime":      "➕ Переработка за месяц: %s",
+	"stats.month_deficit":       "➖ Недобор за месяц: %s",
+	"stats.remaining":           "⏳ Осталось отработать: %s, %s",
+	"stats.required_per_day":    "📈 Необходимо работать в день: %s",
+	"stats.expected_overtime":   "➕ Переработка: %s",
+	"stats.expected_deficit":    "➖ Недобор: %s",
+	"stats.on_plan":             "✅ План выполняется идеально",
+	"stats.plan_done":           "✅ Вы уже выполнили норму месяца! Переработка: %s",
+	"stats.updated_at":          "📅 Последнее обновление: %s",
+	"stats.summary_title":       "📊 Сводка за %s",
+	"stats.summary_no_users":    "📭 Нет сотрудников.",
+	"stats.summary_no_data":     "%d. %s - нет данных",
+	"stats.summary_row":         "%d. %s - %s из %s (%.0f%%), %s%s",
+	"stats.list_empty":          "📭 Статистика пока отсутствует",
+	"stats.list_title":          "📋 Ваша статистика по месяцам:",
+	"stats.list_row":            "%d. %s %s - %d/%s, %s/%s%s",
+
+	// Банк времени
+	"timebank.settings_get_failed":   "ошибка получения настроек банка времени: %v",
+	"timebank.settings_save_failed":  "ошибка сохранения настроек банка времени: %v",
+	"timebank.negative_caps":         "лимиты не могут быть отрицательными",
+	"timebank.month_not_finished":    "можно закрыть только завершившийся месяц",
+	"timebank.stats_get_failed":      "ошибка получения статистики за месяц: %v",
+	"timebank.entry_get_failed":      "ошибка получения записи банка времени: %v",
+	"timebank.transfer_failed":       "ошибка переноса итога месяца: %v",
+	"timebank.zero_adjustment":       "корректировка не может быть нулевой",
+	"timebank.reason_required":       "необходимо указать причину корректировки",
+	"timebank.adjust_failed":         "ошибка создания корректировки: %v",
+	"timebank.reason_monthly":        "Итог за %02d.%d",
+	"timebank.reason_monthly_capped": "Итог за %02d.%d (ограничено, фактически %s)",
+	"timebank.ledger_title":          "🏦 Банк времени: %s %s",
+	"timebank.ledger_empty":          "📭 Записей пока нет.",
+	"timebank.balance":               "💰 Баланс: %s",
+	"timebank.current_month":         "📅 Текущий месяц (не закрыт): %s",
+	"date.invalid_month_number":      "некорректный месяц: %d",
+
+	// Отсутствия
+	"absence.vacation_future_only":      "отпуск можно добавить только на будущие даты",
+	"absence.unpaid_future_only":        "отпуск за свой счёт можно добавить только на будущие даты",
+	"absence.non_working_day":           "дата %s является выходным днем",
+	"absence.conflict_check_failed":     "ошибка проверки конфликтов: %v",
+	"absence.conflict":                  "период пересекается с существующим отпуском/больничным/отгулом",
+	"absence.availability_check_failed": "ошибка проверки доступности даты %s: %v",
+	"absence.date_taken":                "на дату %s уже есть запись",
+	"absence.create_failed":             "ошибка создания периода: %v",
+	"absence.sessions_create_failed":    "ошибка создания рабочих сессий: %v",
+	"absence.unknown_type":              "неизвестный тип отсутствия: %s",
+	"absence.non_working_check_failed":  "ошибка проверки выходного дня %s: %v",
+	"absence.session_create_failed":     "ошибка создания сессии на %s: %v",
+	"absence.stats_update_failed":       "ошибка обновления статистики за %02d.%d: %v",
+
+	// Банк времени: отгулы
+	"timebank.balance_get_failed": "ошибка получения баланса банка времени: %v",
+	"timebank.insufficient":       "недостаточно времени в банке: баланс %s, требуется %s",
+	"timebank.reason_day_off":     "Отгул %s",
+	"timebank.debit_failed":       "ошибка списания из банка времени: %v",
+
+	// Календарь отсутствий
+	"users.get_failed":           "ошибка получения пользователей: %v",
+	"absence.periods_get_failed": "ошибка получения периодов отсутствия: %v",
+	"calendar.title":             "📅 Календарь отсутствий: %s",
+	"calendar.team":              "👥 Команда: %s",
+	"calendar.users":             "👥 Сотрудников: %d, порог: %d%%",
+	"calendar.nobody_absent":     "✅ В этом месяце никто не отсутствует.",
+	"calendar.flagged_day":       "%d (%d из %d)",
+	"calendar.flagged_days": `⚠️ Дни, когда отсутствует более %d%% сотрудников:
+%s`,
+	"calendar.no_flagged_days": "✅ Нет дней, когда отсутствует более %d%% сотрудников.",
+	"calendar.image_title":     "📅 Отсутствия за %s",
+	"calendar.legend": `🟦 отпуск 🟨 больничный 🟩 отгул 🟪 за свой счёт ⬛ прогул
+🟥 в заголовке - отсутствует более %d%% сотрудников`,
+
+	// Отделы и команды
+	"team.department_name_empty":       "название отдела не может быть пустым",
+	"team.department_create_failed":    "ошибка создания отдела: %v",
+	"team.department_teams_get_failed": "ошибка получения команд отдела: %v",
+	"team.department_has_teams":        "в отделе есть команды (%d), сначала удалите их",
+	"team.name_empty":                  "название команды не может быть пустым",
+	"team.department_lookup_failed":    "ошибка поиска отдела: %v",
+	"team.department_not_found":        "отдел с ID %d не найден",
+	"team.create_failed":               "ошибка создания команды: %v",
+	"team.members_get_failed":          "ошибка получения участников команды: %v",
+	"team.lead_reset_failed":           "ошибка снятия руководителя команды: %v",
+	"team.lookup_failed":               "ошибка поиска команды: %v",
+	"team.not_found":                   "команда с ID %d не найдена",
+	"team.lead_cannot_leave":           "руководителя нельзя исключить из команды, сначала смените ему роль",
+	"team.assign_failed":               "ошибка назначения команды: %v",
+	"team.departments_get_failed":      "ошибка получения отделов: %v",
+	"team.departments_empty": `📭 Отделов пока нет.
+Создайте отдел командой /adddepartment`,
+	"team.departments_title": "🏢 Отделы и команды:",
+	"team.no_teams":          "нет команд",
+	"team.info":              "👥 %s (ID: %d) - участников: %d",
+	"team.info_leads":        ", руководитель: %s",
+
+	// Графики работы
+	"schedule.invalid":              "некорректные данные: год %d-%d, месяц 1-12, дни 0-31, минуты в день 1-1440",
+	"schedule.stats_create_failed":  "ошибка создания статистики для графика: %v",
+	"schedule.not_found_id":         "график с ID %d не найден",
+	"schedule.invalid_after_update": "некорректные данные после обновления",
+	"schedule.stats_update_failed":  "ошибка обновления статистики для графика: %v",
+	"schedule.parse_format":         "неверный формат. Используйте: год месяц дни минуты_в_день",
+	"schedule.parse_year":           "неверный год. Должен быть между %d и %d",
+	"schedule.parse_month":          "неверный месяц. Должен быть между 1 и 12",
+	"schedule.parse_days":           "неверное количество дней. Должно быть между 0 и 31",
+	"schedule.parse_minutes":        "неверное количество минут в день. Должно быть между 1 и 1440",
+	"time.parse_format":             "неверный формат времени. Используйте ЧЧ:ММ",
+	"time.parse_hours":              "неверное количество часов. Должно быть между 0 и 23",
+	"time.parse_minutes":            "неверное количество минут. Должно быть между 0 и 59",
+	"schedule.not_found":            "❌ График не найден",
+	"schedule.details": `📅 График работы: %s
+
+🆔 ID: %d
+📊 Рабочих дней: %d
+⏰ Время в день: %s
+📈 Всего времени: %s
+📅 Создан: %s
+🔄 Обновлен: %s`,
+	"schedule.list_empty": "📭 Графиков работы пока нет",
+	"schedule.list_title": "📋 Все графики работы:",
+
+	// Пересчет статистики
+	"schedule.get_failed":     "ошибка получения графика: %v",
+	"session.list_get_failed": "ошибка получения рабочих сессий: %v",
+	"stats.get_failed":        "ошибка получения статистики: %v",
+	"stats.create_failed":     "ошибка создания статистики: %v",
+	"stats.save_failed":       "ошибка сохранения статистики: %v",
+	"stats.recalc_failed":     "ошибка пересчета статистики пользователя %d: %v",
+	"stats.recalc_no_drifts":  "✅ Статистика совпадает с пересчитанной, исправлений нет.",
+	"stats.recalc_fixed":      "🔧 Исправлено записей статистики: %d",
+	"stats.recalc_created":    "• %s, %02d.%d - запись создана",
+
+	// Токены API
+	"apitoken.name_required":   "укажите название клиента",
+	"apitoken.generate_failed": "ошибка генерации токена: %v",
+	"apitoken.save_failed":     "ошибка сохранения токена: %v",
+	"apitoken.not_found":       "токен с ID %d не найден",
+	"apitoken.already_revoked": "токен с ID %d уже отозван",
+	"apitoken.list_empty": `🔑 Токенов API нет.
+Создать: /addapitoken [название]`,
+	"apitoken.list_title": "🔑 Токены API:",
+	"apitoken.revoked":    "⛔ отозван %s",
+	"apitoken.never_used": "не использовался",
+	"apitoken.used_at":    "использован %s",
+	"apitoken.list_row": `%d. %s %s
+    создан %s, %s`,
+
+	// Диалоги
+	"dialog.save_failed": "ошибка сохранения данных диалога: %v",
+
+	// Управляющие команды (/helpadmin)
+	"usage.allusers":         "/allusers - Показать пользователей",
+	"usage.stats":            "/stats - Статистика бота",
+	"usage.admins":           "/admins - Показать администраторов",
+	"usage.teams":            "/teams - Отделы и команды",
+	"usage.setrole":          "/setrole [ID] [роль] - Изменить роль (список ролей: /roles)",
+	"usage.promote":          "/promote [ID] - Назначить администратора",
+	"usage.demote":           "/demote [ID] - Снять администратора",
+	"usage.roles":            "/roles - Роли и их права",
+	"usage.adddepartment":    "/adddepartment [название] - Создать отдел",
+	"usage.deletedepartment": "/deletedepartment [ID] - Удалить отдел",
+	"usage.addteam":          "/addteam [ID_отдела название] - Создать команду",
+	"usage.deleteteam":       "/deleteteam [ID] - Удалить команду",
+	"usage.setteam":          "/setteam [ID_пользователя ID_команды] - Добавить в команду (0 - исключить)",
+	"usage.getschedules":     "/getschedules - Все графики работы",
+	"usage.getschedule":      "/getschedule [ID] или [год месяц] - Показать график",
+	"usage.currentschedule":  "/currentschedule - График на текущий месяц",
+	"usage.addschedule": `/addschedule [год месяц дни минуты] - Добавить график
+    Пример: /addschedule 2024 12 22 480`,
+	"usage.updateschedule": `/updateschedule [ID дни минуты] - Обновить график
+    Пример: /updateschedule 1 23 490`,
+	"usage.deleteschedule": "/deleteschedule [ID] - Удалить график",
+	"usage.generateschedules": `/generateschedules [год] [минуты] - Автоматически создать графики на год
+    Пример: /generateschedules 2026 520 (8ч 40м)`,
+	"usage.updateallschedules":  "/updateallschedules - Обновить все графики на основе выходных дней",
+	"usage.userstat":            "/userstat [ID] [год месяц] - Статистика сотрудника",
+	"usage.teamstats":           "/teamstats [год месяц] - Сводка по сотрудникам",
+	"usage.recalcstats":         "/recalcstats [ID] [год месяц] - Пересчитать статистику из сессий и отсутствий",
+	"usage.truancy":             "/truancy [ID] [дата] - Отметить прогул",
+	"usage.bankadjust":          "/bankadjust [ID минуты причина] - Корректировка банка времени",
+	"usage.closemonth":          "/closemonth [год месяц] - Перенести итоги месяца в банк времени",
+	"usage.bankcaps":            "/bankcaps [переработка недобор] - Лимиты переноса за месяц (минуты)",
+	"usage.apitokens":           "/apitokens - Токены API",
+	"usage.addapitoken":         "/addapitoken [название] - Выпустить токен API для внешней системы",
+	"usage.revokeapitoken":      "/revokeapitoken [ID] - Отозвать токен API",
+	"access.check_failed_reply": "❌ Ошибка проверки прав доступа: %s",
+	"access.denied_command":     "❌ Доступ запрещен. Недостаточно прав для этой команды.",
+
+	// Справка и общие ответы
+	"help.commands": `📋 Доступные команды:
+
+👤 Профиль:
+/createprofile - Создать профиль (ФИО и часовой пояс)
+/myprofile - Показать мой профиль
+/updateprofile - Обновить профиль
+/settimezone [город или пояс] - Часовой пояс (- вернуть пояс компании)
+/language [ru|en] - Язык интерфейса
+/deleteprofile - Деактивировать профиль при увольнении (история сохраняется)
+/cancel - Отменить начатое действие
+
+⏰ Учет рабочего времени:
+/in - Начать рабочий день (можно указать дату и время, подробности /helptime)
+/out - Завершить рабочий день (можно указать дату и время, подробности /helptime)
+/helptime - Показать справку по указанию времени для начача\конца рабочего дня
+/today - Информация о сегодняшнем рабочем дне
+/status - Текущий статус работы
+/history [N] - История рабочих дней (последние N, по умолчанию 10)
+/monthwork [месяц] - Рабочие дни за конкретный месяц
+/monthwork [год месяц] - Рабочие дни за месяц и год
+/checkday [дата] - Проверить, является ли день рабочим
+    Пример: /checkday 01.05.2026 или /checkday 01.05
+
+🏖️ Отпуска/Больничные/Отгулы:
+/vacation дата_начала дата_окончания - Добавить отпуск
+    Пример: /vacation 01.07.2026 14.07.2026
+/sick дата_начала дата_окончания - Добавить больничный
+    Пример: /sick 01.07.2026 07.07.2026
+/dayoff дата - Добавить отгул
+    Пример: /dayoff 15.08.2026
+/unpaid дата_начала дата_окончания - Добавить отпуск за свой счёт
+    Пример: /unpaid 01.07.2026 03.07.2026
+/myabsences - Мои периоды отсутствия
+/teamcalendar [месяц] [image] [all] - Отсутствия команды (all - всей компании) за месяц
+/balance - Банк времени (переработка/недобор по месяцам)
+
+📊 Статистика работы:
+/mystats - Вся моя статистика
+/stat [месяц] - Статистика за конкретный месяц
+/stat [год месяц] - Статистика за месяц и год
+/currentstat - Статистика за текущий месяц
+
+🛠 Утилиты:
+/start - Начать работу с ботом
+/help - Показать это сообщение
+/echo [текст] - Отправить эхо с текстом
+
+💡 Как пользоваться:
+1. Создайте профиль командой /createprofile
+2. Начинайте рабочий день командой /in
+3. Завершайте рабочий день командой /out
+4. Добавляйте отпуска/больничные командой /vacation или /sick
+5. Следите за статистикой командой /mystats
+6. Администраторы настраивают графики работы
+
+📈 Автоматические обновления:
+• При завершении рабочего дня статистика обновляется автоматически
+• При добавлении отпуска/больничного дни засчитываются как отработанные
+• Отпуск за свой счёт и прогулы не засчитываются как отработанное время
+• Итоги месяца переносятся в банк времени, отгул списывает 8ч 40м из банка`,
+	"help.time_formats": `📝 *Форматы указания даты и времени:*
+
+*Дата (необязательно):*
+• dd.mm.yyyy (25.12.2023)
+• dd-mm-yyyy (25-12-2023)
+
+*Время (необязательно):*
+• hh:mm (09:30)
+• hh.mm (09.30)
+• hh-mm (09-30)
+
+*Примеры использования:*
+• /in 09:00 — начать работу сегодня в 9:00
+• /in 25.12.2023 09:30 — начать работу 25 декабря 2023 в 9:30
+• /out 18:00 — завершить работу сегодня в 18:00
+• /out 25-12-2023 18-30 — завершить работу 25 декабря 2023 в 18:30
+• /in — начать работу сейчас
+• /out — завершить работу сейчас
+
+⚠️ *Примечания:*
+• Если указать только время, будет использована сегодняшняя дата
+• *Нельзя начать работу в выходной день* согласно производственному календарю
+• Используйте /checkday [дата] чтобы проверить, является ли день рабочим
+• Год должен быть %d или позже`,
+	"help.admin_no_commands": "❌ Доступ запрещен. У вашей роли нет управляющих команд.",
+	"help.admin_title": `📋 Доступные команды (роль: %s):
+
+%s`,
+	"help.admin_base_admin": "🔧 ID главного администратора: %d",
+	"help.admin_team_scope": "💡 Команды работают только для участников вашей команды.",
+	"profile.not_found_reply": `❌ Профиль не найден.
+Используйте /createprofile чтобы создать профиль.`,
+	"command.unknown": "❌ Неизвестная команда. Используйте /help для списка команд.",
+	"echo.empty":      "Я получил ваше сообщение, но не могу его повторить 😊",
+	"echo.reply":      "🔁 Эхо: %s",
+	"echo.empty_args": "Вы не указали текст для эхо!",
+	"language.usage": `🌐 Текущий язык: %s
+Сменить: /language ru или /language en`,
+	"language.set_failed": "❌ Ошибка смены языка: %s",
+	"language.set":        "✅ Язык интерфейса: %s",
+
+	// Профиль
+	"profile.already_exists": `❌ У вас уже есть профиль!
+Используйте /myprofile чтобы посмотреть его или /updateprofile чтобы изменить.`,
+	"profile.create.first_name": `👤 Создание профиля
+
+Шаг 1 из 4:
+✏️ Пожалуйста, отправьте ваше имя:
+(/cancel - отменить)`,
+	"profile.create.first_name_empty": "❌ Имя не может быть пустым. Отправьте ваше имя текстом:",
+	"profile.create.last_name": `Шаг 2 из 4:
+✅ Имя сохранено: %s
+✏️ Теперь отправьте вашу фамилию (если нет фамилии, отправьте "-"):`,
+	"profile.create.timezone": `Шаг 4 из 4:
+🕒 В каком часовом поясе вы работаете?
+%s
+Если вы работаете по времени компании (%s), отправьте "-":`,
+	"profile.create.timezone_invalid": `❌ %s
+%s
+(/cancel - отменить)`,
+	"profile.create.data_corrupted": "❌ Данные диалога повреждены, начните заново: /createprofile",
+	"profile.create_failed":         "❌ Ошибка создания профиля: %s",
+	"profile.created": `🎉 Профиль успешно создан!
+
+%s
+
+Теперь вы можете использовать команду /myprofile чтобы посмотреть свой профиль в любое время.`,
+	"profile.update.prompt": `✏️ Обновление профиля
+
+Отправьте новые данные в формате:
+Имя Фамилия Отчество
+
+Например: *Иван Иванов Петрович*
+Или просто: *Иван* (если нужно обновить только имя)
+(/cancel - отменить)`,
+	"profile.update.invalid": `❌ Неверный формат. Пожалуйста, отправьте имя и фамилию.
+(/cancel - отменить)`,
+	"profile.update_failed": "❌ Ошибка обновления профиля: %s",
+	"profile.updated": `✅ Профиль успешно обновлен!
+
+%s`,
+	"profile.delete_confirm": `⚠️ Вы уверены, что хотите деактивировать свой профиль?
+Отмечать рабочее время будет нельзя, история сохранится. Вернуть профиль может администратор.`,
+	"button.yes_delete": "✅ Да, деактивировать",
+	"button.no_cancel":  "❌ Нет, отменить",
+	"timezone.hint":     "Укажите город (Москва, Екатеринбург, Новосибирск, мск, екб, нск) или название пояса IANA (Asia/Omsk).",
+	"timezone.usage": `❌ Укажите часовой пояс: /settimezone Новосибирск
+%s
+Чтобы вернуть пояс компании: /settimezone -`,
+	"timezone.set_failed": "❌ Ошибка смены часового пояса: %s",
+	"timezone.set": `✅ Часовой пояс: %s
+🕒 Ваше текущее время: %s`,
+
+	// Диалоги: ответы
+	"dialog.start_failed":   "❌ Не удалось начать диалог: %v",
+	"dialog.advance_failed": "❌ Не удалось сохранить ответ: %v",
+	"dialog.expired": `⌛ Время ожидания ответа истекло, действие отменено.
+Начните заново: %s`,
+	"error.generic":            "❌ Ошибка: %s",
+	"dialog.nothing_to_cancel": "ℹ️ Нет активного действия для отмены.",
+	"dialog.cancelled":         "❌ Действие отменено.",
+
+	// Кнопки
+	"session.clock_out_cancelled": "❌ Завершение работы отменено.",
+	"profile.delete_failed":       "❌ Ошибка деактивации профиля: %s",
+	"profile.deleted":             "✅ Ваш профиль деактивирован. История рабочего времени сохранена.",
+	"profile.delete_cancelled":    "❌ Деактивация профиля отменена.",
+
+	// Отделы и команды: ответы
+	"error.reply": "❌ %s",
+	"team.department_usage": `❌ Укажите название отдела.
+Пример: /adddepartment Разработка`,
+	"team.department_created": `✅ Отдел «%s» создан (ID: %d).
+Добавьте команду: /addteam %d Название`,
+	"team.department_delete_usage": `❌ Укажите ID отдела.
+Пример: /deletedepartment 1`,
+	"team.department_delete_failed": "❌ Ошибка удаления отдела: %s",
+	"team.department_deleted":       "✅ Отдел с ID %d удален.",
+	"team.add_usage": `❌ Неверный формат.
+Пример: /addteam 1 Backend
+(1 - ID отдела, см. /teams)`,
+	"team.invalid_department_id": "❌ Неверный формат ID отдела.",
+	"team.created": `✅ Команда «%s» создана в отделе «%s» (ID: %d).
+
+Добавить участника: /setteam ID_пользователя %d
+Назначить руководителя: /setrole ID_пользователя team_lead`,
+	"team.delete_usage": `❌ Укажите ID команды.
+Пример: /deleteteam 1`,
+	"team.delete_failed": "❌ Ошибка удаления команды: %s",
+	"team.deleted":       "✅ Команда с ID %d удалена, участники остались без команды.",
+	"team.set_usage": `❌ Неверный формат.
+Пример: /setteam 123456789 2
+(2 - ID команды, 0 - исключить из команды)`,
+	"user.invalid_id": `❌ Неверный формат ID.
+ID должен быть числом.`,
+	"team.invalid_id":   "❌ Неверный формат ID команды.",
+	"team.user_removed": "✅ %s %s исключен из команды.",
+	"team.user_added":   "✅ %s %s добавлен в команду с ID %d.",
+
+	// Администрирование
+	"users.list_failed":  "❌ Ошибка получения списка пользователей: %s",
+	"users.team_title":   "📋 Участники вашей команды:",
+	"users.stats_failed": "❌ Ошибка получения статистики: %s",
+	"users.stats_title": `📊 Статистика бота:
+
+👥 Всего пользователей: %d
+`,
+	"admins.list_failed": "❌ Ошибка получения списка администраторов: %s",
+	"admins.empty":       "👑 Список администраторов пуст.",
+	"admins.title":       "👑 Администраторы:",
+	"admins.promote_usage": `❌ Укажите ID пользователя.
+Пример: /promote 123456789`,
+	"admins.promote_failed": "❌ Ошибка назначения администратора: %s",
+	"admins.promoted":       "✅ Пользователь с ID %d теперь администратор!",
+	"admins.demote_usage": `❌ Укажите ID пользователя.
+Пример: /demote 123456789`,
+	"admins.demote_base_admin": "❌ Нельзя снять главного администратора, заданного в конфигурации!",
+	"admins.demote_failed":     "❌ Ошибка снятия администратора: %s",
+	"admins.demoted":           "✅ Пользователь с ID %d теперь сотрудник (employee)!",
+	"role.set_usage": `❌ Неверный формат.
+Пример: /setrole 123456789 hr
+Доступные роли: %s`,
+	"role.unknown_reply": `❌ Неизвестная роль.
+Доступные роли: %s`,
+	"role.base_admin":          "❌ Нельзя изменить роль главного администратора, заданного в конфигурации!",
+	"role.set_failed":          "❌ Ошибка изменения роли: %s",
+	"role.set":                 "✅ Роль пользователя с ID %d изменена на '%s'!",
+	"role.list_title":          "🔐 Роли и права:",
+	"role.own_data_only":       "   только собственные данные",
+	"role.list_assign_hint":    "Назначить роль: /setrole ID роль",
+	"role.list_team_lead_hint": "Права team_lead действуют только на участников его команды.",
+
+	// Токены API: ответы
+	"apitoken.list_failed": "❌ Ошибка получения токенов: %s",
+	"apitoken.add_usage": `❌ Укажите название клиента.
+Пример: /addapitoken HR-портал`,
+	"apitoken.created": `✅ Токен «%s» выпущен (ID: %d).
+
+%s
+
+⚠️ Сохраните его сейчас - повторно токен не показывается.
+Передавайте в заголовке: Authorization: Bearer <токен>
+Отозвать: /revokeapitoken %d`,
+	"apitoken.revoke_usage": `❌ Укажите ID токена.
+Пример: /revokeapitoken 1 (список: /apitokens)`,
+	"apitoken.revoked_reply": "✅ Токен «%s» (ID: %d) отозван.",
+
+	// Календарь отсутствий: ответы
+	"date.invalid_month_reply": "❌ Неверный месяц. Используйте число от 1 до 12.",
+	"date.invalid_year_reply":  "❌ Неверный год. Используйте год между %d и %d.",
+	"calendar.usage":           "❌ Неверный формат. Используйте: /teamcalendar [год месяц] [image] [all] или /teamcalendar [месяц] [image] [all]",
+	"calendar.get_failed":      "❌ Ошибка получения календаря: %s",
+	"calendar.render_failed":   "❌ Ошибка построения изображения: %s",
+	"calendar.overloaded_warning": `
+
+⚠️ В эти дни отсутствует более %d%% команды: %s
+Посмотреть календарь: /teamcalendar %d %d`,
+
+	// Банк времени: ответы
+	"timebank.get_failed_reply": "❌ Ошибка получения банка времени: %s",
+	"timebank.close_usage": `📅 Закрытие месяца
+
+Формат команды:
+/closemonth [год месяц]
+
+Примеры:
+/closemonth
+→ Перенести итоги прошлого месяца в банк времени
+
+/closemonth 2026 3
+→ Перенести итоги марта 2026
+
+💡 Повторное закрытие пересчитывает уже перенесенные итоги.`,
+	"date.invalid_year_format":  "❌ Неверный формат года.",
+	"date.invalid_month_format": "❌ Неверный формат месяца.",
+	"timebank.close_failed":     "❌ Ошибка закрытия месяца: %s",
+	"timebank.closed": `✅ Итоги за %02d.%d перенесены в банк времени.
+👥 Пользователей: %d`,
+	"timebank.settings_failed_reply": "❌ Ошибка получения настроек: %s",
+	"timebank.caps": `🏦 Лимиты банка времени (за месяц):
+
+➕ Переработка: %d мин
+➖ Недобор: %d мин
+
+Изменить: /bankcaps переработка недобор
+Пример: /bankcaps 2400 1200`,
+	"timebank.caps_usage":       "❌ Формат команды: /bankcaps переработка недобор (в минутах)",
+	"timebank.caps_not_numbers": "❌ Лимиты должны быть числами (в минутах).",
+	"timebank.caps_save_failed": "❌ Ошибка сохранения лимитов: %s",
+	"timebank.caps_updated": `✅ Лимиты обновлены.
+➕ Переработка: %d мин
+➖ Недобор: %d мин
+
+💡 Новые лимиты применяются при следующем закрытии месяца (/closemonth).`,
+	"timebank.adjust_usage": `✏️ Корректировка банка времени
+
+Формат команды:
+/bankadjust ID минуты причина
+
+Примеры:
+/bankadjust 123456789 120 Работа в выходной 07.03
+→ Начислить 2 часа
+
+/bankadjust 123456789 -60 Ошибка учета
+→ Списать 1 час`,
+	"timebank.adjust_minutes_invalid": "❌ Количество минут должно быть числом (можно со знаком минус).",
+	"timebank.adjust_failed_reply":    "❌ Ошибка корректировки: %s",
+	"timebank.adjusted": `✅ Банк времени скорректирован: %s %s
+✏️ %s (%s)
+💰 Баланс: %s`,
+
+	// Графики работы: ответы
+	"schedule.add_usage": `📝 Добавление графика работы
+
+Формат команды:
+/addschedule Год Месяц Дни МинутыВДень
+
+Пример:
+/addschedule 2024 12 22 480
+→ Декабрь 2024, 22 рабочих дня по 8 часов (480 минут)
+
+/addschedule 2024 1 20 450
+→ Январь 2024, 20 рабочих дней по 7.5 часов (450 минут = 7ч 30м)
+
+Или просто отправьте данные в формате:
+"2024 12 22 480"`,
+	"schedule.parse_failed":  "❌ Ошибка парсинга данных: %s",
+	"schedule.create_failed": "❌ Ошибка создания графика: %s",
+	"schedule.update_usage": `✏️ Обновление графика работы
+
+Формат команды:
+/updateschedule ID Дни МинутыВДень
+
+Пример:
+/updateschedule 1 23 490
+→ Обновит график с ID=1 на 23 рабочих дня по 490 минут (8ч 10м)
+
+Сначала используйте /getschedules чтобы увидеть ID графиков`,
+	"schedule.update_format":   "❌ Неверный формат. Используйте: /updateschedule ID дни минуты_в_день",
+	"schedule.invalid_id":      "❌ Неверный формат ID. ID должен быть числом.",
+	"schedule.invalid_days":    "❌ Неверное количество дней. Должно быть между 0 и 31.",
+	"schedule.invalid_minutes": "❌ Неверное количество минут в день. Должно быть между 1 и 1440.",
+	"schedule.update_failed":   "❌ Ошибка обновления графика: %s",
+	"schedule.delete_usage": `🗑️ Удаление графика работы
+
+Формат команды:
+/deleteschedule ID
+
+Пример:
+/deleteschedule 1
+→ Удалит график с ID=1
+
+Сначала используйте /getschedules чтобы увидеть ID графиков`,
+	"schedule.delete_confirm": `⚠️ Вы уверены, что хотите удалить график с ID %d?
+Это действие нельзя отменить.`,
+	"schedule.list_failed": "❌ Ошибка получения графиков: %s",
+	"schedule.get_usage": `📋 Просмотр графика работы
+
+Формат команды:
+/getschedule ID
+→ Покажет график с указанным ID
+
+/getschedule 2024 12
+→ Покажет график на декабрь 2024 года
+
+Используйте /getschedules чтобы увидеть все доступные графики`,
+	"schedule.get_failed_reply":      "❌ Ошибка получения графика: %s",
+	"schedule.not_found_id_reply":    "❌ График с ID %d не найден.",
+	"schedule.get_format":            "❌ Неверный формат. Используйте: /getschedule ID или /getschedule год месяц",
+	"schedule.current_failed":        "❌ Ошибка получения текущего графика: %s",
+	"schedule.callback_invalid_id":   "❌ Ошибка: неверный ID графика",
+	"schedule.delete_failed":         "❌ Ошибка удаления графика: %s",
+	"schedule.deleted":               "✅ График с ID %d успешно удален!",
+	"schedule.delete_cancelled":      "❌ Удаление графика отменено.",
+	"schedule.generate_year_format":  "❌ Неверный формат года. Используйте: /generateschedules [год] [минуты_в_день]",
+	"schedule.generate_format":       "❌ Неверный формат. Используйте: /generateschedules [год] [минуты_в_день]",
+	"schedule.generate_invalid_year": "❌ Неверный год. Год должен быть между %d и %d.",
+	"schedule.generate_failed":       "❌ Ошибка генерации графиков: %s",
+	"schedule.update_all_failed":     "❌ Ошибка обновления графиков: %s",
+	"schedule.update_all_none":       "✅ Все графики уже актуальны. Ничего не обновлено.",
+	"date.invalid_format_short":      "❌ Неверный формат даты. Используйте ДД.ММ.ГГГГ или ДД.ММ",
+	"schedule.check_day_failed":      "❌ Ошибка проверки дня: %s",
+	"schedule.day_off":               "❌ Выходной день",
+
+	// Графики работы: списки
+	"schedule.month_not_found":  "❌ График на %s не найден.",
+	"schedule.current_not_set":  "❌ График на текущий месяц (%s) не установлен.",
+	"schedule.generated.one":    "✅ Сгенерирован %d график на %d год",
+	"schedule.generated.few":    "✅ Сгенерировано %d графика на %d год",
+	"schedule.generated.many":   "✅ Сгенерировано %d графиков на %d год",
+	"schedule.generated_list":   "📋 Список созданных/обновленных графиков:",
+	"count.working_days.one":    "%d рабочий день",
+	"count.working_days.few":    "%d рабочих дня",
+	"count.working_days.many":   "%d рабочих дней",
+	"schedule.updated_all.one":  "✅ Обновлен %d график.",
+	"schedule.updated_all.few":  "✅ Обновлено %d графика.",
+	"schedule.updated_all.many": "✅ Обновлено %d графиков.",
+	"schedule.check_day_date":   "📅 Дата: %s",
+	"schedule.working_day": `✅ Рабочий день
+⏰ Время работы: %s`,
+
+	// Статистика: ответы
+	"stats.stat_usage": "❌ Неверный формат. Используйте: /stat [год месяц] или /stat [месяц]",
+	"stats.userstat_usage": `❌ Укажите ID пользователя.
+Пример: /userstat 123456789 [год месяц]`,
+	"stats.user_not_found": "📭 Статистика %s %s за %02d.%d отсутствует.",
+	"stats.user_stat": `👤 %s %s
+
+%s`,
+	"stats.recalc_usage": `❌ Неверный формат.
+Используйте: /recalcstats [ID] [год месяц]`,
+	"users.get_failed_reply": "❌ Ошибка получения пользователей: %s",
+
+	// Даты: ошибки ввода
+	"stats.month_not_found":          "📭 Статистика за %s отсутствует.",
+	"stats.current_month_not_found":  "📭 Статистика за текущий месяц (%s) отсутствует.",
+	"date.invalid_month":             "неверный месяц. Используйте число от 1 до 12",
+	"date.invalid_year":              "неверный год. Используйте год между %d и %d",
+	"date.invalid_year_month_format": "неверный формат. Используйте: [год месяц] или [месяц]",
+
+	// Отсутствия: ответы
+	"absence.vacation_usage": `🏖️ *Добавление отпуска*
+
+Формат команды:
+/vacation дата_начала дата_окончания
+
+Примеры:
+/vacation 01.07.2026 14.07.2026
+→ Отпуск с 1 по 14 июля 2026
+
+/vacation 15.08.2026 15.08.2026
+→ Отпуск на один день 15 августа 2026
+
+💡 *Важно:*
+• Отпуск можно добавить только на будущие даты
+• В выходные дни отпуск не добавляется
+• Нельзя пересекаться с другими отпусками/больничными
+• Кто еще в отпуске, можно посмотреть командой /teamcalendar`,
+	"absence.vacation_format":    "❌ Неверный формат. Используйте: /vacation дата_начала дата_окончания",
+	"absence.start_date_invalid": "❌ Ошибка парсинга даты начала: %s",
+	"absence.end_date_invalid":   "❌ Ошибка парсинга даты окончания: %s",
+	"absence.vacation_failed":    "❌ Ошибка добавления отпуска: %s",
+	"absence.vacation_added": `✅ Отпуск добавлен!
+
+🏖️ Период: %s - %s
+📅 Количество дней: %d
+⏰ Всего часов: %d:%02d
+
+📋 Дни отпуска будут автоматически засчитаны как рабочие (по 8ч 40м).`,
+	"absence.sick_usage": `🏥 *Добавление больничного*
+
+Формат команды:
+/sick дата_начала дата_окончания
+
+Примеры:
+/sick 01.07.2026 07.07.2026
+→ Больничный с 1 по 7 июля 2026
+
+/sick 15.08.2026 15.08.2026
+→ Больничный на один день 15 августа 2026
+
+💡 *Важно:*
+• Больничный можно добавить на любые даты (включая прошедшие)
+• Можно добавлять на выходные дни
+• Нельзя пересекаться с другими отпусками/больничными`,
+	"absence.sick_format": "❌ Неверный формат. Используйте: /sick дата_начала дата_окончания",
+	"absence.sick_failed": "❌ Ошибка добавления больничного: %s",
+	"absence.sick_added": `✅ Больничный добавлен!
+
+🏥 Период: %s - %s
+📅 Количество дней: %d
+⏰ Всего часов: %d:%02d
+
+📋 Дни больничного будут автоматически засчитаны как рабочие (по 8ч 40м).`,
+	"absence.day_off_usage": `🎯 *Добавление отгула*
+
+Формат команды:
+/dayoff дата
+
+Примеры:
+/dayoff 01.07.2026
+→ Отгул 1 июля 2026
+
+/dayoff 15.08.2026
+→ Отгул 15 августа 2026
+
+💡 *Важно:*
+• Отгул можно добавить на любые даты
+• Отгул списывает 8ч 40м из банка времени (/balance)
+• Нельзя добавить на выходной день
+• Нельзя пересекаться с другими отпусками/больничными`,
+	"absence.date_invalid":   "❌ Ошибка парсинга даты: %s",
+	"absence.day_off_failed": "❌ Ошибка добавления отгула: %s",
+	"absence.day_off_added": `✅ Отгул добавлен!
+
+🎯 Дата: %s
+⏰ Часы: 8:40
+
+📋 Этот день будет засчитан как рабочий (8ч 40м).
+🏦 Из банка времени списано 8ч 40м.`,
+	"absence.unpaid_format": "❌ Неверный формат. Используйте: /unpaid дата_начала дата_окончания",
+	"absence.unpaid_failed": "❌ Ошибка добавления отпуска за свой счёт: %s",
+	"absence.unpaid_added": `✅ Отпуск за свой счёт добавлен!
+
+💸 Период: %s - %s
+📅 Количество дней: %d
+
+📋 %s`,
+	"absence.truancy_failed": "❌ Ошибка отметки прогула: %s",
+	"absence.truancy_marked": `✅ Прогул отмечен: %s %s, %s
+
+📋 %s`,
+	"absence.credit.reduce_plan": "Дни не засчитываются как рабочие и уменьшают плановое время месяца.",
+	"absence.credit.none":        "Дни засчитываются как 0 отработанных минут и увеличивают недобор.",
+	"absence.credit.full":        "Дни будут автоматически засчитаны как рабочие.",
+	"absence.list_failed":        "❌ Ошибка получения данных: %s",
+	"absence.list_empty":         "📭 У вас нет запланированных отпусков, больничных или отгулов.",
+	"absence.day_off_balance": `
+💰 Остаток: %s`,
+
+	// Отсутствия: справка
+	"absence.unpaid_usage": `💸 *Добавление отпуска за свой счёт*
+
+Формат команды:
+/unpaid дата_начала дата_окончания
+
+Примеры:
+/unpaid 01.07.2026 03.07.2026
+→ Отпуск за свой счёт с 1 по 3 июля 2026
+
+💡 *Важно:*
+• Отпуск за свой счёт можно добавить только на будущие даты
+• В выходные дни отпуск не добавляется
+• Нельзя пересекаться с другими отпусками/больничными
+• %s`,
+	"absence.truancy_usage": `🚫 Отметка прогула
+
+Формат команды:
+/truancy ID дата
+
+Пример:
+/truancy 123456789 15.08.2026
+→ Прогул пользователя 15 августа 2026
+
+💡 %s`,
+
+	// Отсутствия: список
+	"absence.list_title":           "📋 *Мои периоды отсутствия:*",
+	"absence.list_vacations":       "🏖️ *Отпуска:*",
+	"absence.list_sick_leaves":     "🏥 *Больничные:*",
+	"absence.list_day_offs":        "🎯 *Отгулы:*",
+	"absence.list_unpaid_leaves":   "💸 *Отпуска за свой счёт:*",
+	"absence.list_truancies":       "🚫 *Прогулы:*",
+	"absence.list_stats":           "📊 *Статистика:*",
+	"absence.list_total_vacation":  "• Всего отпускных дней: %d",
+	"absence.list_total_sick":      "• Всего больничных дней: %d",
+	"absence.list_total_day_offs":  "• Всего отгулов: %d",
+	"absence.list_total_unpaid":    "• Всего дней за свой счёт: %d",
+	"absence.list_total_truancies": "• Всего прогулов: %d",
+	"date.invalid_format":          "неверный формат даты. Используйте ДД.ММ.ГГГГ или ДД.ММ",
+
+	// Рабочие сессии: ответы
+	"session.clock_in_parse_failed": "❌ %s\n\nПримеры:\n/in 25.12.2023 09:30\n/in 09.00\n/in 25-12-2023 09-30",
+	"session.clock_in_non_working": `❌ %s - выходной день!
+
+📅 Вы не можете начать работу в выходной день согласно производственному календарю.`,
+	"session.clock_in_denied": "❌ Не могу начать работу: %s",
+	"session.clock_in_failed": "❌ Ошибка начала работы: %s",
+	"session.clock_in_started": `✅ Рабочий день начат!
+
+⏰ Время начала: %s
+📅 Дата: %s
+⏳ Норма на день: %s
+⏰ Можно уходить в: %s
+
+💡 Не забудьте отметить конец рабочего дня командой /out`,
+	"session.clock_in_backdated": `
+
+⚠️ *Внимание:* Работа начата задним числом.`,
+	"button.clock_out":               "⏰ Завершить рабочий день",
+	"session.clock_out_parse_failed": "❌ %s\n\nПримеры:\n/out 25.12.2023 18:30\n/out 18.00\n/out 25-12-2023 18-30",
+	"session.clock_out_non_working": `⚠️ *Внимание:* %s - выходной день!
+
+Вы действительно хотите завершить работу в выходной день?
+
+Это может быть ошибкой.`,
+	"button.yes_finish":        "✅ Да, завершить",
+	"button.cancel":            "❌ Отменить",
+	"session.clock_out_denied": "❌ Не могу закончить работу: %s",
+	"session.get_failed_reply": "❌ Ошибка получения сессии: %s",
+	"session.clock_out_failed": "❌ Ошибка завершения работы: %s",
+	"session.clock_out_finished": `✅ Рабочий день завершен!
+
+⏰ Время работы: %s - %s
+⏳ Отработано: %s
+📊 Норма: %s%s
+
+📈 Статистика обновлена автоматически.`,
+	"session.clock_out_backdated": `
+
+⚠️ *Внимание:* Работа завершена задним числом.`,
+	"session.clock_out_on_day_off": `
+
+⚠️ *Внимание:* Работа завершена в выходной день (подтверждено пользователем).`,
+	"button.clock_in": "🔄 Начать новый рабочий день",
+	"session.today_empty": `📭 Сегодня вы еще не начинали работу.
+Используйте /in чтобы начать рабочий день.`,
+	"session.history_failed": "❌ Ошибка получения истории: %s",
+	"session.month_failed":   "❌ Ошибка получения сессий: %s",
+	"session.month_empty":    "📭 За %s у вас не было рабочих дней.",
+	"session.month_report": `📊 Рабочие дни за %s
+
+%s
+
+📈 Итоги за месяц:
+   📋 Отработано дней: %d
+   ⏰ Всего времени: %s`,
+	"session.status_failed": "❌ Ошибка получения статуса: %s",
+	"session.status_working": `🟢 Вы на работе!
+
+⏰ Начали работу: %s
+⏳ Прошло времени: %s
+📅 Дата: %s
+
+💡 Используйте /out чтобы завершить рабочий день.`,
+	"session.status_idle": `📭 Сегодня вы еще не работали.
+
+💡 Доступные команды:
+/in - Начать рабочий день
+/today - Информация о сегодняшнем дне
+/history - История рабочих дней
+/status - Текущий статус`,
+
+	// Рабочие сессии: разбор времени и длительности
+	"date.invalid_format_full": "неверный формат даты. Используйте dd.mm.yyyy или dd-mm-yyyy",
+	"time.invalid_format":      "неверный формат времени. Используйте hh:mm, hh.mm или hh-mm",
+	"count.hours.one":          "%d час",
+	"count.hours.few":          "%d часа",
+	"count.hours.many":         "%d часов",
+	"count.minutes.one":        "%d минута",
+	"count.minutes.few":        "%d минуты",
+	"count.minutes.many":       "%d минут",
+
+	// Импорт рабочих дней из CSV
+	"usage.importsessions": "/importsessions - Импортировать историю рабочих дней из CSV (файл с командой в подписи)",
+	"button.import":        "✅ Импортировать",
+	"import.usage": `📥 Импорт истории рабочих дней
+
+Отправьте CSV-файл документом с подписью /importsessions. Сначала бот проверит файл и покажет ошибки, импорт выполняется после подтверждения.
+
+Строка файла - один день сотрудника:
+• рабочий день: сотрудник, дата, приход, уход
+• отсутствие: сотрудник, дата, тип
+
+Сотрудник - ID чата или @username, дата - ДД.ММ.ГГГГ или ГГГГ-ММ-ДД, время - ЧЧ:ММ по часам сотрудника.
+Типы отсутствия: vacation, sick_leave, day_off, unpaid_leave, truancy.
+Разделитель - запятая или точка с запятой, первая строка может быть заголовком.
+
+Пример:
+user,date,clock_in,clock_out
+@ivan,02.03.2026,09:00,18:00
+@ivan,03.03.2026,vacation`,
+	"import.too_large":          "❌ Файл слишком большой. Наибольший размер - %d КБ.",
+	"import.download_failed":    "❌ Не удалось получить файл: %v",
+	"import.failed":             "❌ Ошибка импорта: %s",
+	"import.fix_and_resend":     "Исправьте файл и отправьте его снова.",
+	"import.confirm_question":   "Импортировать эти данные?",
+	"import.confirm_pending":    "Файл проверен и ждет подтверждения. Нажмите кнопку под отчетом или /cancel.",
+	"import.expired":            "⏰ Нет проверенного файла для импорта. Отправьте его снова с подписью /importsessions.",
+	"import.cancelled":          "❌ Импорт отменен.",
+	"import.closed_months_hint": "Если эти месяцы уже перенесены в банк времени, выполните /closemonth для них повторно.",
+	"import.checked": `🔎 Файл проверен, ошибок нет.
+Рабочих дней: %d, дней отсутствия: %d, сотрудников: %d.`,
+	"import.committed": `✅ Импорт выполнен.
+Рабочих дней: %d, дней отсутствия: %d, сотрудников: %d. Пересчитано месяцев статистики: %d.`,
+	"import.errors_title":         "❌ Ошибок в файле: %d",
+	"import.error_line":           "• строка %d: %s",
+	"import.more_errors":          "… и еще %d",
+	"import.invalid_csv":          "некорректный CSV: %v",
+	"import.empty":                "в файле нет строк с данными",
+	"import.columns":              "ожидается 4 столбца (сотрудник, дата, приход, уход) или 3 (сотрудник, дата, тип отсутствия), получено %d",
+	"import.unknown_user":         "сотрудник %s не найден",
+	"import.invalid_date":         "неверная дата %q, используйте ДД.ММ.ГГГГ или ГГГГ-ММ-ДД",
+	"import.invalid_time":         "неверное время %q, используйте ЧЧ:ММ",
+	"import.not_past":             "%s - импортировать можно только прошедшие дни",
+	"import.non_working_day":      "%s - нерабочий день",
+	"import.unknown_absence_type": "неизвестный тип отсутствия %q",
+	"import.clock_out_before_in":  "время ухода должно быть позже прихода",
+	"import.overlaps_session":     "пересекается с рабочей сессией за %s",
+	"import.overlaps_absence":     "пересекается с периодом отсутствия на %s",
+	"import.overlaps_row":         "пересекается со строкой %d",
+	"import.create_failed":        "строка %d: ошибка сохранения: %v",
+
+	// Резервные копии
+	"usage.backup": "/backup - Резервная копия базы данных файлом",
+	"backup.caption": `💾 Резервная копия базы на %s.
+Восстановление в пустую базу: bot restore <файл>`,
+	"backup.failed":      "❌ Не удалось создать резервную копию: %s",
+	"backup.too_large":   "⚠️ Копия занимает %d МБ, а бот может отправить не больше %d МБ. Используйте копии по расписанию (backup.dir в конфигурации).",
+	"backup.send_failed": "❌ Не удалось отправить резервную копию: %v",
+
+	// Журнал изменений
+	"usage.audit": `/audit [chat_id|сущность[:id]] [период] - Журнал изменений данных
+Сущности: user, session, absence, schedule, timebank, timebank_settings, department, team, apitoken
+Период: ДД.ММ.ГГГГ, ММ.ГГГГ или ДД.ММ.ГГГГ-ДД.ММ.ГГГГ`,
+	"usage.auditexport":              "/auditexport [chat_id|сущность[:id]] [период] - Выгрузка журнала изменений в CSV",
+	"audit.record_failed":            "не удалось записать изменение в журнал: %v",
+	"audit.get_failed":               "не удалось получить журнал изменений: %v",
+	"audit.invalid_arg":              "непонятный аргумент %q",
+	"audit.invalid_entity_id":        "неверный ID сущности %q",
+	"audit.empty":                    "📭 В журнале нет изменений по этому запросу.",
+	"audit.title":                    "📜 Журнал изменений (последние записи):",
+	"audit.entry_header":             "🕒 %s, %s",
+	"audit.entry_action":             "%s: %s #%d",
+	"audit.change":                   "  • %s: %s → %s",
+	"audit.more_changes":             "  … и еще полей: %d",
+	"audit.actor_token":              "токен API «%s» (#%d)",
+	"audit.actor_user":               "%s (%d)",
+	"audit.actor_chat":               "чат %d",
+	"audit.actor_system":             "система",
+	"audit.deleted_user":             "удаленный сотрудник #%d",
+	"audit.action.create":            "Создание",
+	"audit.action.update":            "Изменение",
+	"audit.action.delete":            "Удаление",
+	"audit.entity.user":              "сотрудник",
+	"audit.entity.session":           "рабочая сессия",
+	"audit.entity.absence":           "отсутствие",
+	"audit.entity.schedule":          "график",
+	"audit.entity.timebank":          "запись банка времени",
+	"audit.entity.timebank_settings": "лимиты банка времени",
+	"audit.entity.department":        "отдел",
+	"audit.entity.team":              "команда",
+	"audit.entity.apitoken":          "токен API",
+	"audit.export_caption.one":       "📜 Журнал изменений: %d запись",
+	"audit.export_caption.few":       "📜 Журнал изменений: %d записи",
+	"audit.export_caption.many":      "📜 Журнал изменений: %d записей",
+
+	// Деактивация сотрудников
+	"usage.deactivate":               "/deactivate [chat_id] [ДД.ММ.ГГГГ] - Деактивировать уволенного сотрудника (дата увольнения, по умолчанию сегодня)",
+	"usage.reactivate":               "/reactivate [chat_id] - Вернуть деактивированного сотрудника",
+	"usage.purgeuser":                "/purgeuser [chat_id] - Безвозвратно удалить деактивированного сотрудника и все его данные",
+	"session.active_get_failed":      "ошибка получения активной сессии: %v",
+	"user.already_deactivated":       "сотрудник уже деактивирован с %s",
+	"user.deactivate_active_session": "у сотрудника незавершенный рабочий день, сначала завершите его",
+	"user.not_deactivated":           "сотрудник не деактивирован",
+	"user.purge_active":              "удалить можно только деактивированного сотрудника, сначала выполните /deactivate",
+	"profile.deactivated":            "🔒 Ваш профиль деактивирован с %s. Доступны только /myprofile и /help. Чтобы вернуть профиль, обратитесь к администратору.",
+	"profile.info.terminated":        "🔒 Деактивирован с %s",
+	"user.deactivate_base_admin":     "❌ Нельзя деактивировать главного администратора из конфигурации.",
+	"user.termination_in_future":     "❌ Дата увольнения не может быть в будущем.",
+	"user.deactivated":               "✅ Сотрудник %s %s деактивирован с %s. История сохранена и остается в отчетах.",
+	"user.reactivated":               "✅ Сотрудник %s %s снова активен.",
+	"user.purge_confirm": `⚠️ Безвозвратно удалить сотрудника %s %s (ID: %d)?
+Будут удалены профиль, рабочие сессии, отсутствия, статистика и банк времени, а из журнала изменений - его данные. Это действие нельзя отменить.`,
+	"button.yes_purge":     "🗑 Да, удалить навсегда",
+	"user.purge_cancelled": "❌ Удаление сотрудника отменено.",
+	"user.purged":          "🗑 Сотрудник %d и все его данные удалены.",
+	"users.archived_title": "🔒 Деактивированные:",
+	"users.archived_line":  "• %s - ID: %d, с %s",
+
+	// Одобрение новых профилей
+	"usage.pending": "/pending - Профили, ожидающие одобрения",
+	"usage.approve": "/approve [chat_id] - Одобрить новый профиль",
+	"usage.reject":  "/reject [chat_id] - Отклонить и удалить новый профиль",
+	"profile.created_pending": `📝 Профиль создан и отправлен на одобрение.
+
+%s
+
+Когда HR или администратор одобрит профиль, вам придет сообщение и станет доступен учет времени.`,
+	"profile.pending":          "⏳ Ваш профиль ожидает одобрения. Доступны только /myprofile и /help.",
+	"profile.info.pending":     "⏳ Ожидает одобрения",
+	"profile.approved":         "✅ Ваш профиль одобрен! Теперь доступен учет времени: /help",
+	"profile.rejected":         "❌ Ваш профиль отклонен и удален. Если это ошибка, обратитесь к администратору.",
+	"user.approval_request":    "🆕 Новый профиль ожидает одобрения: %s - ID: %d",
+	"button.approve":           "✅ Одобрить",
+	"button.reject":            "❌ Отклонить",
+	"user.approved":            "✅ Профиль %s одобрен.",
+	"user.rejected":            "🗑 Профиль %s отклонен и удален.",
+	"user.not_pending":         "профиль не ожидает одобрения",
+	"user.deactivate_pending":  "профиль еще не одобрен, отклоните его командой /reject",
+	"users.pending_empty":      "✅ Нет профилей, ожидающих одобрения.",
+	"users.pending_count.one":  "⏳ Ожидает одобрения %d профиль:",
+	"users.pending_count.few":  "⏳ Ожидают одобрения %d профиля:",
+	"users.pending_count.many": "⏳ Ожидают одобрения %d профилей:",
+	"users.pending_title":      "⏳ Ожидают одобрения:",
+	"users.pending_line":       "• %s - ID: %d",
+
+	// Кадровые данные сотрудника
+	"profile.create.patronymic": `Шаг 3 из 4:
+✏️ Отправьте ваше отчество (если нет отчества, отправьте "-"):`,
+	"profile.info.patronymic":      "👨‍💼 Отчество: %s",
+	"profile.info.department":      "🏢 Отдел: %s",
+	"profile.info.position":        "💼 Должность: %s",
+	"profile.info.employee_number": "🔢 Табельный номер: %s",
+	"profile.info.hire_date":       "📅 Дата приема: %s",
+	"usage.setemployee":            "/setemployee [chat_id] [position|number|hired] [значение] - Задать должность, табельный номер или дату приема (ДД.ММ.ГГГГ); \"-\" очищает поле",
+	"employee.unknown_field":       "❌ Неизвестное поле: %s",
+	"employee.number_invalid":      "❌ Табельный номер не может содержать пробелы.",
+	"employee.updated": `✅ Кадровые данные обновлены.
+
+%s`,
+	"user.employee_number_taken":   "табельный номер %s уже у сотрудника %s",
+	"user.hire_after_termination":  "дата приема не может быть позже даты увольнения %s",
+	"user.termination_before_hire": "дата увольнения не может быть раньше даты приема %s",
+	"stats.summary_name_number":    "%s (таб. № %s)",
+
+	// Ошибки HTTP API
+	"api.error.auth_required":         "требуется заголовок Authorization: Bearer <токен>",
+	"api.error.auth_failed":           "ошибка проверки токена",
+	"api.error.token_invalid":         "недействительный токен",
+	"api.error.internal":              "внутренняя ошибка",
+	"api.error.invalid_chat_id":       "неверный chat ID",
+	"api.error.user_not_found":        "пользователь %d не найден",
+	"api.error.user_inactive":         "сотрудник %d деактивирован",
+	"api.error.user_pending":          "профиль сотрудника %d ожидает одобрения",
+	"api.error.invalid_year":          "неверный год",
+	"api.error.invalid_year_range":    "неверный год, ожидается число между %d и %d",
+	"api.error.invalid_month":         "неверный месяц, ожидается число от 1 до 12",
+	"api.error.invalid_date_param":    "неверный параметр %s, ожидается ГГГГ-ММ-ДД",
+	"api.error.invalid_start_date":    "неверная дата начала, ожидается ГГГГ-ММ-ДД",
+	"api.error.invalid_end_date":      "неверная дата окончания, ожидается ГГГГ-ММ-ДД",
+	"api.error.end_before_start":      "дата окончания раньше даты начала",
+	"api.error.unknown_absence_type":  "неизвестный тип отсутствия: %q",
+	"api.error.stat_not_found":        "статистика за %02d.%d не найдена",
+	"api.error.schedule_not_found":    "график за %02d.%d не найден",
+	"api.error.invalid_body":          "неверное тело запроса: %s",
+	"api.error.clock_out_non_working": "%s - выходной день, для завершения передайте allow_non_working_day",
+
+	// Табель и исправление рабочих дней
+	"usage.timesheet":        "/timesheet [год месяц] - Выгрузка табеля в CSV",
+	"usage.fixsession":       "/fixsession [ID дата приход уход] - Исправить время рабочего дня",
+	"timesheet.failed":       "❌ Ошибка выгрузки табеля: %s",
+	"timesheet.caption.one":  "📊 Табель за %s: %d сотрудник",
+	"timesheet.caption.few":  "📊 Табель за %s: %d сотрудника",
+	"timesheet.caption.many": "📊 Табель за %s: %d сотрудников",
+	"session.correct.usage": `✏️ Исправление рабочего дня
+
+Формат команды:
+/fixsession ID дата приход уход
+
+Пример:
+/fixsession 123456789 15.08.2026 09:00 18:00
+→ Рабочий день 15 августа 2026 с 9:00 до 18:00
+
+💡 Время указывается по часовому поясу сотрудника. Если сотрудник не отмечался, рабочий день будет создан.`,
+	"session.correct.absence_day": "%s - день отсутствия, время не исправляется",
+	"session.correct.several":     "за %s отмечено рабочих сессий: %d, исправляется только единственная",
+	"session.correct.failed":      "❌ Ошибка исправления рабочего дня: %s",
+	"session.correct.done": `✅ Рабочий день исправлен: %s %s, %s
+
+🕐 %s - %s
+⏱ Отработано: %s`,
+}
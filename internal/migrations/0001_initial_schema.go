@@ -0,0 +1,201 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Снимок таблиц на момент появления миграций. Модели из internal/models менять можно,
+// эти структуры - нет: новые колонки добавляются следующими миграциями.
+// Связи не описаны, внешние ключи создает миграция 0002.
+
+type initialDepartment struct {
+	ID        uint      `gorm:"primarykey"`
+	Name      string    `gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (initialDepartment) TableName() string {
+	return "departments"
+}
+
+type initialTeam struct {
+	ID           uint      `gorm:"primarykey"`
+	DepartmentID uint      `gorm:"not null;index"`
+	Name         string    `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+}
+
+func (initialTeam) TableName() string {
+	return "teams"
+}
+
+type initialUser struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt int64
+	UpdatedAt int64
+	ChatID    int64 `gorm:"uniqueIndex;not null"`
+	Username  string
+	FirstName string `gorm:"not null"`
+	LastName  string
+	Role      string `gorm:"default:'employee'"`
+	TeamID    *uint  `gorm:"index"`
+}
+
+func (initialUser) TableName() string {
+	return "users"
+}
+
+type initialWorkSchedule struct {
+	ID                uint      `gorm:"primarykey"`
+	Year              int       `gorm:"not null;index"`
+	Month             int       `gorm:"not null;check:month >= 1 AND month <= 12;index"`
+	WorkDays          int       `gorm:"not null;default:0"`
+	WorkMinutesPerDay int       `gorm:"not null;default:480"`
+	TotalMinutes      int       `gorm:"not null;default:0"`
+	CreatedAt         time.Time `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime"`
+}
+
+func (initialWorkSchedule) TableName() string {
+	return "work_schedules"
+}
+
+type initialUserMonthlyStat struct {
+	ID                       uint      `gorm:"primarykey"`
+	UserID                   uint      `gorm:"not null;index"`
+	Year                     int       `gorm:"not null;index"`
+	Month                    int       `gorm:"not null;check:month >= 1 AND month <= 12;index"`
+	PlannedDays              int       `gorm:"not null;default:0"`
+	PlannedMinutes           int       `gorm:"not null;default:0"`
+	WorkedDays               int       `gorm:"not null;default:0"`
+	WorkedMinutes            int       `gorm:"not null;default:0"`
+	OvertimeMinutes          int       `gorm:"not null;default:0"`
+	DeficitMinutes           int       `gorm:"not null;default:0"`
+	CreditedAbsenceMinutes   int       `gorm:"not null;default:0"`
+	UncreditedAbsenceMinutes int       `gorm:"not null;default:0"`
+	UncreditedAbsenceDays    int       `gorm:"not null;default:0"`
+	PlanReductionMinutes     int       `gorm:"not null;default:0"`
+	CreatedAt                time.Time `gorm:"autoCreateTime"`
+	UpdatedAt                time.Time `gorm:"autoUpdateTime"`
+}
+
+func (initialUserMonthlyStat) TableName() string {
+	return "user_monthly_stats"
+}
+
+type initialNonWorkingDay struct {
+	ID        uint      `gorm:"primaryKey"`
+	Date      time.Time `gorm:"uniqueIndex"`
+	Year      int       `gorm:"index"`
+	Month     int       `gorm:"index"`
+	Day       int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (initialNonWorkingDay) TableName() string {
+	return "non_working_days"
+}
+
+type initialAbsencePeriod struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	StartDate time.Time `gorm:"type:date;not null"`
+	EndDate   time.Time `gorm:"type:date;not null"`
+	Type      string    `gorm:"type:varchar(20);not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (initialAbsencePeriod) TableName() string {
+	return "absence_periods"
+}
+
+type initialWorkSession struct {
+	ID              uint      `gorm:"primarykey"`
+	UserID          uint      `gorm:"not null;index"`
+	Date            time.Time `gorm:"type:date;not null;index"`
+	ClockInTime     time.Time `gorm:"not null"`
+	ClockOutTime    *time.Time
+	RequiredMinutes int    `gorm:"not null;default:480"`
+	WorkedMinutes   int    `gorm:"not null;default:0"`
+	DiffMinutes     int    `gorm:"not null;default:0"`
+	Status          string `gorm:"type:varchar(20);not null;default:'active';index"`
+	SessionType     string `gorm:"type:varchar(20);not null;default:'work';index"`
+	Notes           string
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+	AbsencePeriodID *uint     `gorm:"index"`
+}
+
+func (initialWorkSession) TableName() string {
+	return "work_sessions"
+}
+
+type initialTimeBankEntry struct {
+	ID        uint   `gorm:"primarykey"`
+	UserID    uint   `gorm:"not null;index"`
+	Year      int    `gorm:"not null;index"`
+	Month     int    `gorm:"not null;check:month >= 1 AND month <= 12;index"`
+	Type      string `gorm:"type:varchar(20);not null;index"`
+	Minutes   int    `gorm:"not null;default:0"`
+	Reason    string
+	CreatedBy int64     `gorm:"not null;default:0"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (initialTimeBankEntry) TableName() string {
+	return "time_bank_entries"
+}
+
+type initialTimeBankSettings struct {
+	ID                       uint      `gorm:"primarykey"`
+	MaxMonthlySurplusMinutes int       `gorm:"not null;default:2400"`
+	MaxMonthlyDeficitMinutes int       `gorm:"not null;default:2400"`
+	UpdatedBy                int64     `gorm:"not null;default:0"`
+	CreatedAt                time.Time `gorm:"autoCreateTime"`
+	UpdatedAt                time.Time `gorm:"autoUpdateTime"`
+}
+
+func (initialTimeBankSettings) TableName() string {
+	return "time_bank_settings"
+}
+
+// initialSchemaModels - таблицы, которые раньше создавались AutoMigrate в конструкторах репозиториев.
+// Порядок важен для отката: таблицы удаляются в обратном порядке.
+var initialSchemaModels = []interface{}{
+	&initialDepartment{},
+	&initialTeam{},
+	&initialUser{},
+	&initialWorkSchedule{},
+	&initialUserMonthlyStat{},
+	&initialNonWorkingDay{},
+	&initialAbsencePeriod{},
+	&initialWorkSession{},
+	&initialTimeBankEntry{},
+	&initialTimeBankSettings{},
+}
+
+func init() {
+	register(Migration{
+		Version: 1,
+		Name:    "initial_schema",
+		Up: func(tx *gorm.DB) error {
+			// На существующей базе таблицы уже есть, AutoMigrate только добавит недостающие колонки
+			return tx.AutoMigrate(initialSchemaModels...)
+		},
+		Down: func(tx *gorm.DB) error {
+			for i := len(initialSchemaModels) - 1; i >= 0; i-- {
+				if err := tx.Migrator().DropTable(initialSchemaModels[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,151 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Связи на момент версии 2 описаны поверх снимков из 0001, чтобы изменения моделей
+// не меняли имена и правила внешних ключей, уже созданных в базах.
+
+type fkDepartment struct {
+	Snapshot initialDepartment `gorm:"embedded"`
+	Teams    []initialTeam     `gorm:"foreignKey:DepartmentID"`
+}
+
+func (fkDepartment) TableName() string {
+	return "departments"
+}
+
+type fkUser struct {
+	Snapshot initialUser  `gorm:"embedded"`
+	Team     *initialTeam `gorm:"foreignKey:TeamID;constraint:OnDelete:SET NULL"`
+}
+
+func (fkUser) TableName() string {
+	return "users"
+}
+
+type fkAbsencePeriod struct {
+	Snapshot     initialAbsencePeriod `gorm:"embedded"`
+	User         initialUser          `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	WorkSessions []initialWorkSession `gorm:"foreignKey:AbsencePeriodID;constraint:OnDelete:SET NULL"`
+}
+
+func (fkAbsencePeriod) TableName() string {
+	return "absence_periods"
+}
+
+type fkWorkSession struct {
+	Snapshot initialWorkSession `gorm:"embedded"`
+	User     initialUser        `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (fkWorkSession) TableName() string {
+	return "work_sessions"
+}
+
+type fkUserMonthlyStat struct {
+	Snapshot initialUserMonthlyStat `gorm:"embedded"`
+	User     initialUser            `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (fkUserMonthlyStat) TableName() string {
+	return "user_monthly_stats"
+}
+
+type fkTimeBankEntry struct {
+	Snapshot initialTimeBankEntry `gorm:"embedded"`
+	User     initialUser          `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (fkTimeBankEntry) TableName() string {
+	return "time_bank_entries"
+}
+
+// foreignKey - внешний ключ, описанный тегом constraint у связи модели
+type foreignKey struct {
+	model    interface{} // модель, в которой объявлена связь
+	relation string      // имя поля связи
+	table    interface{} // модель таблицы, в которую добавляется ключ
+}
+
+// foreignKeys перечислены от родительских таблиц к дочерним: SQLite добавляет ключ пересозданием таблицы,
+// и пересоздание родителя после появления ссылок на него запустило бы каскадное удаление.
+var foreignKeys = []foreignKey{
+	{model: &fkDepartment{}, relation: "Teams", table: &initialTeam{}},
+	{model: &fkUser{}, relation: "Team", table: &initialUser{}},
+	{model: &fkAbsencePeriod{}, relation: "User", table: &initialAbsencePeriod{}},
+	{model: &fkAbsencePeriod{}, relation: "WorkSessions", table: &initialWorkSession{}},
+	{model: &fkWorkSession{}, relation: "User", table: &initialWorkSession{}},
+	{model: &fkUserMonthlyStat{}, relation: "User", table: &initialUserMonthlyStat{}},
+	{model: &fkTimeBankEntry{}, relation: "User", table: &initialTimeBankEntry{}},
+}
+
+// orphanCleanup удаляет строки, которые нарушили бы новые внешние ключи
+var orphanCleanup = []string{
+	"DELETE FROM teams WHERE department_id NOT IN (SELECT id FROM departments)",
+	"UPDATE users SET team_id = NULL WHERE team_id IS NOT NULL AND team_id NOT IN (SELECT id FROM teams)",
+	"DELETE FROM absence_periods WHERE user_id NOT IN (SELECT id FROM users)",
+	"UPDATE work_sessions SET absence_period_id = NULL WHERE absence_period_id IS NOT NULL AND absence_period_id NOT IN (SELECT id FROM absence_periods)",
+	"DELETE FROM work_sessions WHERE user_id NOT IN (SELECT id FROM users)",
+	"DELETE FROM user_monthly_stats WHERE user_id NOT IN (SELECT id FROM users)",
+	"DELETE FROM time_bank_entries WHERE user_id NOT IN (SELECT id FROM users)",
+}
+
+func init() {
+	register(Migration{
+		Version: 2,
+		Name:    "foreign_keys",
+		Up: func(tx *gorm.DB) error {
+			for _, query := range orphanCleanup {
+				if err := tx.Exec(query).Error; err != nil {
+					return err
+				}
+			}
+
+			for _, fk := range foreignKeys {
+				if tx.Migrator().HasConstraint(fk.model, fk.relation) {
+					continue
+				}
+				if err := tx.Migrator().CreateConstraint(fk.model, fk.relation); err != nil {
+					return err
+				}
+				if err := restoreIndexes(tx, fk.table); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for i := len(foreignKeys) - 1; i >= 0; i-- {
+				fk := foreignKeys[i]
+				if !tx.Migrator().HasConstraint(fk.model, fk.relation) {
+					continue
+				}
+				if err := tx.Migrator().DropConstraint(fk.model, fk.relation); err != nil {
+					return err
+				}
+				if err := restoreIndexes(tx, fk.table); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// restoreIndexes создает индексы модели, потерянные при пересоздании таблицы в SQLite
+func restoreIndexes(tx *gorm.DB, model interface{}) error {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+
+	for _, index := range stmt.Schema.ParseIndexes() {
+		if tx.Migrator().HasIndex(model, index.Name) {
+			continue
+		}
+		if err := tx.Migrator().CreateIndex(model, index.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
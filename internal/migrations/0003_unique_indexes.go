@@ -0,0 +1,48 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// uniqueIndex - уникальный индекс из prisma/schema.prisma
+type uniqueIndex struct {
+	name    string
+	table   string
+	columns string
+}
+
+// Уникальность (user_id, date) для work_sessions не вводится: за день может быть несколько рабочих сессий
+var uniqueIndexes = []uniqueIndex{
+	{name: "idx_user_monthly_stats_user_year_month", table: "user_monthly_stats", columns: "user_id, year, month"},
+	{name: "idx_work_schedules_year_month", table: "work_schedules", columns: "year, month"},
+}
+
+func init() {
+	register(Migration{
+		Version: 3,
+		Name:    "unique_indexes",
+		Up: func(tx *gorm.DB) error {
+			for _, index := range uniqueIndexes {
+				// Оставляем последнюю запись из дубликатов, иначе индекс не создать
+				dedupe := "DELETE FROM " + index.table + " WHERE id NOT IN " +
+					"(SELECT MAX(id) FROM " + index.table + " GROUP BY " + index.columns + ")"
+				if err := tx.Exec(dedupe).Error; err != nil {
+					return err
+				}
+
+				create := "CREATE UNIQUE INDEX IF NOT EXISTS " + index.name +
+					" ON " + index.table + " (" + index.columns + ")"
+				if err := tx.Exec(create).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for i := len(uniqueIndexes) - 1; i >= 0; i-- {
+				if err := tx.Exec("DROP INDEX IF EXISTS " + uniqueIndexes[i].name).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
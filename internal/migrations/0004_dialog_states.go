@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// dialogStateV4 - снимок таблицы dialog_states на момент версии 4
+type dialogStateV4 struct {
+	ChatID         int64     `gorm:"primarykey;autoIncrement:false"`
+	Flow           string    `gorm:"not null"`
+	Step           string    `gorm:"not null"`
+	Data           string    `gorm:"type:text"`
+	TimeoutSeconds int       `gorm:"not null;default:0"`
+	ExpiresAt      time.Time `gorm:"not null;index"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+}
+
+func (dialogStateV4) TableName() string {
+	return "dialog_states"
+}
+
+func init() {
+	register(Migration{
+		Version: 4,
+		Name:    "dialog_states",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&dialogStateV4{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&dialogStateV4{})
+		},
+	})
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// apiTokenV5 - снимок таблицы api_tokens на момент версии 5
+type apiTokenV5 struct {
+	ID         uint   `gorm:"primarykey"`
+	Name       string `gorm:"not null"`
+	TokenHash  string `gorm:"uniqueIndex;not null"`
+	CreatedBy  int64  `gorm:"not null;default:0"`
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+func (apiTokenV5) TableName() string {
+	return "api_tokens"
+}
+
+func init() {
+	register(Migration{
+		Version: 5,
+		Name:    "api_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&apiTokenV5{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&apiTokenV5{})
+		},
+	})
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"work-schedule-bot/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{
+		Version: 6,
+		Name:    "user_timezone",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&models.User{}, "Timezone") {
+				return nil
+			}
+			return tx.Migrator().AddColumn(&models.User{}, "Timezone")
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumn(tx, &models.User{}, "Timezone")
+		},
+	})
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"work-schedule-bot/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{
+		Version: 7,
+		Name:    "user_language",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&models.User{}, "Language") {
+				return nil
+			}
+			return tx.Migrator().AddColumn(&models.User{}, "Language")
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumn(tx, &models.User{}, "Language")
+		},
+	})
+}
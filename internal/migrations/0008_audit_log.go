@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// auditEntryV8 - снимок таблицы audit_entries на момент версии 8
+type auditEntryV8 struct {
+	ID           uint      `gorm:"primarykey"`
+	ActorChatID  int64     `gorm:"not null;default:0;index"`
+	ActorTokenID uint      `gorm:"not null;default:0"`
+	Action       string    `gorm:"type:varchar(20);not null"`
+	EntityType   string    `gorm:"type:varchar(30);not null;index:idx_audit_entity"`
+	EntityID     uint      `gorm:"not null;default:0;index:idx_audit_entity"`
+	UserID       *uint     `gorm:"index"`
+	Before       string    `gorm:"type:text"`
+	After        string    `gorm:"type:text"`
+	CreatedAt    time.Time `gorm:"not null;index"`
+}
+
+func (auditEntryV8) TableName() string {
+	return "audit_entries"
+}
+
+func init() {
+	register(Migration{
+		Version: 8,
+		Name:    "audit_log",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&auditEntryV8{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&auditEntryV8{})
+		},
+	})
+}
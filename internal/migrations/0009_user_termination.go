@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"work-schedule-bot/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{
+		Version: 9,
+		Name:    "user_termination",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&models.User{}, "TerminationDate") {
+				return nil
+			}
+			return tx.Migrator().AddColumn(&models.User{}, "TerminationDate")
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumn(tx, &models.User{}, "TerminationDate")
+		},
+	})
+}
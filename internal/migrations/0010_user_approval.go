@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"work-schedule-bot/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{
+		Version: 10,
+		Name:    "user_approval",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&models.User{}, "PendingApproval") {
+				return nil
+			}
+			return tx.Migrator().AddColumn(&models.User{}, "PendingApproval")
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumn(tx, &models.User{}, "PendingApproval")
+		},
+	})
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"work-schedule-bot/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// employeeProfileColumns - кадровые поля профиля сотрудника
+var employeeProfileColumns = []string{"Patronymic", "Position", "EmployeeNumber", "HireDate"}
+
+func init() {
+	register(Migration{
+		Version: 11,
+		Name:    "employee_profile",
+		Up: func(tx *gorm.DB) error {
+			for _, column := range employeeProfileColumns {
+				if tx.Migrator().HasColumn(&models.User{}, column) {
+					continue
+				}
+				if err := tx.Migrator().AddColumn(&models.User{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, column := range employeeProfileColumns {
+				if err := dropColumn(tx, &models.User{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// До версии 12 сессии отсутствия хранили отработанные минуты по режиму учета на момент создания:
+// норму дня для засчитываемых типов и 0 для остальных. Теперь режим применяется при подсчете
+// статистики, а сессия хранит только норму дня.
+
+// creditedAbsenceSessionTypes - типы, которые до версии 12 по умолчанию засчитывались полностью
+var creditedAbsenceSessionTypes = []string{"vacation", "sick_leave", "day_off"}
+
+func init() {
+	register(Migration{
+		Version: 12,
+		Name:    "neutral_absence_sessions",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec("UPDATE work_sessions SET worked_minutes = 0, diff_minutes = 0 " +
+				"WHERE session_type NOT IN ('', 'work')").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			// Восстанавливаем значения по режимам учета по умолчанию
+			if err := tx.Exec("UPDATE work_sessions SET worked_minutes = 0, diff_minutes = -required_minutes " +
+				"WHERE session_type NOT IN ('', 'work')").Error; err != nil {
+				return err
+			}
+			return tx.Exec("UPDATE work_sessions SET worked_minutes = required_minutes, diff_minutes = 0 "+
+				"WHERE session_type IN ?", creditedAbsenceSessionTypes).Error
+		},
+	})
+}
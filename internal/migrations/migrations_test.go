@@ -0,0 +1,264 @@
+package migrations
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := repository.OpenDatabase(repository.DriverSQLite, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database instance: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return db
+}
+
+func TestMigrateUpDown(t *testing.T) {
+	db := openTestDatabase(t)
+	migrator := NewMigrator(db)
+
+	count, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if count != len(All()) {
+		t.Errorf("Up applied %d migrations, want %d", count, len(All()))
+	}
+
+	count, err = migrator.Up()
+	if err != nil {
+		t.Fatalf("second Up: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("second Up applied %d migrations, want 0", count)
+	}
+
+	if !db.Migrator().HasIndex(&models.WorkSession{}, "idx_work_sessions_user_id") {
+		t.Error("work_sessions index was lost while adding foreign keys")
+	}
+
+	count, err = migrator.Down(len(All()))
+	if err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if count != len(All()) {
+		t.Errorf("Down rolled back %d migrations, want %d", count, len(All()))
+	}
+	if db.Migrator().HasTable(&models.User{}) {
+		t.Error("users table still exists after full rollback")
+	}
+
+	version, err := migrator.Version()
+	if err != nil {
+		t.Fatalf("Version: %v", err)
+	}
+	if version != 0 {
+		t.Errorf("Version after rollback = %d, want 0", version)
+	}
+
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("Up after rollback: %v", err)
+	}
+}
+
+func TestMigrateConstraints(t *testing.T) {
+	db := openTestDatabase(t)
+	if _, err := NewMigrator(db).Up(); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	user := models.User{ChatID: 1, FirstName: "Test", Role: models.RoleEmployee}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	stat := models.UserMonthlyStat{UserID: user.ID, Year: 2026, Month: 3}
+	if err := db.Create(&stat).Error; err != nil {
+		t.Fatalf("failed to create stat: %v", err)
+	}
+	duplicate := models.UserMonthlyStat{UserID: user.ID, Year: 2026, Month: 3}
+	if err := db.Create(&duplicate).Error; err == nil {
+		t.Error("duplicate user monthly stat was accepted")
+	}
+
+	schedule := models.WorkSchedule{Year: 2026, Month: 3, WorkDays: 21, WorkMinutesPerDay: 480}
+	if err := db.Create(&schedule).Error; err != nil {
+		t.Fatalf("failed to create schedule: %v", err)
+	}
+	duplicateSchedule := models.WorkSchedule{Year: 2026, Month: 3, WorkDays: 20, WorkMinutesPerDay: 480}
+	if err := db.Create(&duplicateSchedule).Error; err == nil {
+		t.Error("duplicate work schedule was accepted")
+	}
+
+	date := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.Local)
+	session := models.WorkSession{
+		UserID:          user.ID,
+		Date:            date,
+		ClockInTime:     date.Add(9 * time.Hour),
+		RequiredMinutes: 480,
+		Status:          models.StatusActive,
+		SessionType:     models.SessionTypeWork,
+	}
+	if err := db.Create(&session).Error; err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	if err := db.Delete(&user).Error; err != nil {
+		t.Fatalf("failed to delete user: %v", err)
+	}
+
+	var sessions, stats int64
+	db.Model(&models.WorkSession{}).Count(&sessions)
+	db.Model(&models.UserMonthlyStat{}).Count(&stats)
+	if sessions != 0 || stats != 0 {
+		t.Errorf("after user deletion %d sessions and %d stats remain, want cascade delete", sessions, stats)
+	}
+}
+
+func TestMigrateLegacyDatabase(t *testing.T) {
+	db := openTestDatabase(t)
+
+	// База, созданная AutoMigrate до появления миграций: дубликаты и строки без пользователя
+	if err := db.AutoMigrate(initialSchemaModels...); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	user := initialUser{ChatID: 1, FirstName: "Test", Role: models.RoleEmployee}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	rows := []initialUserMonthlyStat{
+		{UserID: user.ID, Year: 2026, Month: 3, WorkedMinutes: 100},
+		{UserID: user.ID, Year: 2026, Month: 3, WorkedMinutes: 200},
+		{UserID: user.ID + 100, Year: 2026, Month: 3},
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		t.Fatalf("failed to create stats: %v", err)
+	}
+
+	if _, err := NewMigrator(db).Up(); err != nil {
+		t.Fatalf("Up on legacy database: %v", err)
+	}
+
+	var stats []models.UserMonthlyStat
+	if err := db.Find(&stats).Error; err != nil {
+		t.Fatalf("failed to read stats: %v", err)
+	}
+	if len(stats) != 1 || stats[0].WorkedMinutes != 200 {
+		t.Errorf("stats after migration = %+v, want only the latest row of the user", stats)
+	}
+}
+
+func TestInitialSchemaIsFrozen(t *testing.T) {
+	db := openTestDatabase(t)
+
+	// Первая миграция создает схему своего времени, а не текущие модели
+	initial := &Migrator{db: db, migrations: All()[:1], logger: NewMigrator(db).logger}
+	if _, err := initial.Up(); err != nil {
+		t.Fatalf("Up to version 1: %v", err)
+	}
+	for _, column := range []string{"Timezone", "Language", "TerminationDate", "PendingApproval", "EmployeeNumber"} {
+		if db.Migrator().HasColumn(&models.User{}, column) {
+			t.Errorf("users.%s exists after the initial migration", column)
+		}
+	}
+
+	if _, err := NewMigrator(db).Up(); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	// Каждая колонка текущих моделей должна появиться в какой-либо миграции
+	current := []interface{}{
+		&models.Department{}, &models.Team{}, &models.User{}, &models.WorkSchedule{},
+		&models.UserMonthlyStat{}, &models.NonWorkingDay{}, &models.AbsencePeriod{},
+		&models.WorkSession{}, &models.TimeBankEntry{}, &models.TimeBankSettings{},
+		&models.DialogState{}, &models.APIToken{}, &models.AuditEntry{},
+	}
+	for _, model := range current {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatalf("failed to parse model: %v", err)
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !db.Migrator().HasColumn(model, field.DBName) {
+				t.Errorf("%s.%s is missing after all migrations", stmt.Schema.Table, field.DBName)
+			}
+		}
+	}
+}
+
+// migrateTo применяет миграции до версии version включительно
+func migrateTo(t *testing.T, db *gorm.DB, version int) *Migrator {
+	t.Helper()
+
+	migrator := NewMigrator(db)
+	for i, migration := range migrator.migrations {
+		if migration.Version == version {
+			migrator.migrations = migrator.migrations[:i+1]
+			break
+		}
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("Up to version %d: %v", version, err)
+	}
+	return migrator
+}
+
+func TestMigrateDownKeepsUserData(t *testing.T) {
+	// Миграции, которые при откате удаляют колонку таблицы users
+	for _, version := range []int{6, 7, 9, 10, 11} {
+		t.Run(fmt.Sprintf("%04d", version), func(t *testing.T) {
+			db := openTestDatabase(t)
+			migrator := migrateTo(t, db, version)
+
+			// Строки пишутся напрямую: текущие модели новее схемы этой версии
+			now := time.Now()
+			date := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.Local)
+			rows := []struct {
+				table  string
+				values map[string]interface{}
+			}{
+				{"users", map[string]interface{}{"id": 1, "chat_id": 1, "first_name": "Test", "role": models.RoleEmployee}},
+				{"work_sessions", map[string]interface{}{"user_id": 1, "date": date, "clock_in_time": date.Add(9 * time.Hour), "created_at": now, "updated_at": now}},
+				{"absence_periods", map[string]interface{}{"user_id": 1, "start_date": date, "end_date": date, "type": models.AbsenceTypeVacation}},
+				{"user_monthly_stats", map[string]interface{}{"user_id": 1, "year": 2026, "month": 3, "created_at": now, "updated_at": now}},
+				{"time_bank_entries", map[string]interface{}{"user_id": 1, "year": 2026, "month": 3, "type": models.TimeBankEntryAdjustment, "created_at": now, "updated_at": now}},
+			}
+			for _, row := range rows {
+				if err := db.Table(row.table).Create(row.values).Error; err != nil {
+					t.Fatalf("failed to insert into %s: %v", row.table, err)
+				}
+			}
+
+			if _, err := migrator.Down(1); err != nil {
+				t.Fatalf("Down: %v", err)
+			}
+
+			for _, row := range rows {
+				var count int64
+				if err := db.Table(row.table).Count(&count).Error; err != nil {
+					t.Fatalf("failed to count %s: %v", row.table, err)
+				}
+				if count != 1 {
+					t.Errorf("%s has %d rows after rollback, want 1", row.table, count)
+				}
+			}
+		})
+	}
+}
@@ -1,27 +1,78 @@
 // internal/models/absence_period.go
 package models
 
-import "time"
+import (
+	"time"
+	"work-schedule-bot/internal/i18n"
+
+	"gorm.io/gorm"
+)
 
 type AbsencePeriod struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	UserID    uint      `gorm:"not null;index" json:"user_id"`
 	StartDate time.Time `gorm:"type:date;not null" json:"start_date"`
 	EndDate   time.Time `gorm:"type:date;not null" json:"end_date"`
-	Type      string    `gorm:"type:varchar(20);not null" json:"type"` // vacation, sick_leave, day_off
+	Type      string    `gorm:"type:varchar(20);not null" json:"type"` // vacation, sick_leave, day_off, unpaid_leave, truancy
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-	
-	User         User          `gorm:"foreignKey:UserID" json:"user"`
-	WorkSessions []WorkSession `gorm:"foreignKey:AbsencePeriodID" json:"work_sessions"`
+
+	User         User          `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user"`
+	WorkSessions []WorkSession `gorm:"foreignKey:AbsencePeriodID;constraint:OnDelete:SET NULL" json:"work_sessions"`
 }
 
 func (AbsencePeriod) TableName() string {
 	return "absence_periods"
 }
 
+// AfterFind приводит даты периода к полуночи в поясе компании независимо от драйвера БД
+func (p *AbsencePeriod) AfterFind(tx *gorm.DB) error {
+	loc := queryLocation(tx)
+	p.StartDate = dateIn(p.StartDate, loc)
+	p.EndDate = dateIn(p.EndDate, loc)
+	return nil
+}
+
 const (
-	AbsenceTypeVacation  = "vacation"
-	AbsenceTypeSickLeave = "sick_leave"
-	AbsenceTypeDayOff    = "day_off"
-)
\ No newline at end of file
+	AbsenceTypeVacation    = "vacation"
+	AbsenceTypeSickLeave   = "sick_leave"
+	AbsenceTypeDayOff      = "day_off"
+	AbsenceTypeUnpaidLeave = "unpaid_leave" // отпуск за свой счёт
+	AbsenceTypeTruancy     = "truancy"      // прогул
+)
+
+// Режимы учета отсутствия в месячной статистике
+const (
+	AbsenceCreditFull       = "credit"      // день засчитывается как отработанный
+	AbsenceCreditReducePlan = "reduce_plan" // день уменьшает плановое время месяца
+	AbsenceCreditNone       = "none"        // день засчитывается как 0 отработанных минут
+)
+
+// defaultAbsenceCreditModes режимы учета по умолчанию для каждого типа отсутствия
+var defaultAbsenceCreditModes = map[string]string{
+	AbsenceTypeVacation:    AbsenceCreditFull,
+	AbsenceTypeSickLeave:   AbsenceCreditFull,
+	AbsenceTypeDayOff:      AbsenceCreditFull,
+	AbsenceTypeUnpaidLeave: AbsenceCreditReducePlan,
+	AbsenceTypeTruancy:     AbsenceCreditNone,
+}
+
+// IsValidAbsenceType проверяет, известен ли тип отсутствия
+func IsValidAbsenceType(absenceType string) bool {
+	_, ok := defaultAbsenceCreditModes[absenceType]
+	return ok
+}
+
+// FormatAbsenceType возвращает читаемое название типа отсутствия или сессии
+func FormatAbsenceType(tr *i18n.Localizer, absenceType string) string {
+	key := "session_type." + absenceType
+	if name := tr.T(key); name != key {
+		return name
+	}
+	return absenceType
+}
+
+// IsValidAbsenceCreditMode проверяет корректность режима учета
+func IsValidAbsenceCreditMode(mode string) bool {
+	return mode == AbsenceCreditFull || mode == AbsenceCreditReducePlan || mode == AbsenceCreditNone
+}
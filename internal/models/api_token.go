@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// APIToken - токен клиента HTTP API (например, HR-портала).
+// Сам токен не хранится, только его SHA-256 хэш.
+type APIToken struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	Name       string     `gorm:"not null" json:"name"`
+	TokenHash  string     `gorm:"uniqueIndex;not null" json:"-"`
+	CreatedBy  int64      `gorm:"not null;default:0" json:"created_by"` // chat ID администратора
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (APIToken) TableName() string {
+	return "api_tokens"
+}
+
+// IsActive проверяет, что токен не отозван
+func (t *APIToken) IsActive() bool {
+	return t.RevokedAt == nil
+}
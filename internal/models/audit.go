@@ -0,0 +1,121 @@
+package models
+
+import "time"
+
+// Actor - кто выполняет изменение: пользователь бота, клиент HTTP API или сам бот
+type Actor struct {
+	ChatID  int64 // пользователь бота, 0 - не пользователь
+	TokenID uint  // токен HTTP API, 0 - не API
+}
+
+// SystemActor - изменения фоновых задач и запуска бота
+var SystemActor = Actor{}
+
+// ChatActor возвращает пользователя бота как автора изменения (0 - бот)
+func ChatActor(chatID int64) Actor {
+	return Actor{ChatID: chatID}
+}
+
+// TokenActor возвращает клиента HTTP API как автора изменения
+func TokenActor(tokenID uint) Actor {
+	return Actor{TokenID: tokenID}
+}
+
+// IsSystem проверяет, что изменение сделал сам бот
+func (a Actor) IsSystem() bool {
+	return a == SystemActor
+}
+
+// Действия журнала изменений
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// Типы сущностей журнала изменений
+const (
+	AuditEntityUser             = "user"
+	AuditEntitySession          = "session"
+	AuditEntityAbsence          = "absence"
+	AuditEntitySchedule         = "schedule"
+	AuditEntityTimeBank         = "timebank"
+	AuditEntityTimeBankSettings = "timebank_settings"
+	AuditEntityDepartment       = "department"
+	AuditEntityTeam             = "team"
+	AuditEntityAPIToken         = "apitoken"
+)
+
+// auditEntities - известные типы сущностей в порядке вывода в подсказках
+var auditEntities = []string{
+	AuditEntityUser,
+	AuditEntitySession,
+	AuditEntityAbsence,
+	AuditEntitySchedule,
+	AuditEntityTimeBank,
+	AuditEntityTimeBankSettings,
+	AuditEntityDepartment,
+	AuditEntityTeam,
+	AuditEntityAPIToken,
+}
+
+// AuditEntities возвращает известные типы сущностей журнала
+func AuditEntities() []string {
+	return auditEntities
+}
+
+// IsValidAuditEntity проверяет тип сущности журнала
+func IsValidAuditEntity(entityType string) bool {
+	for _, known := range auditEntities {
+		if known == entityType {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditEntry - запись журнала изменений: кто, когда и как изменил сущность.
+// Записи не ссылаются на пользователей внешними ключами, чтобы пережить их удаление.
+type AuditEntry struct {
+	ID           uint   `gorm:"primarykey" json:"id"`
+	ActorChatID  int64  `gorm:"not null;default:0;index" json:"actor_chat_id"`
+	ActorTokenID uint   `gorm:"not null;default:0" json:"actor_token_id"`
+	Action       string `gorm:"type:varchar(20);not null" json:"action"`
+	EntityType   string `gorm:"type:varchar(30);not null;index:idx_audit_entity" json:"entity_type"`
+	EntityID     uint   `gorm:"not null;default:0;index:idx_audit_entity" json:"entity_id"`
+
+	// Сотрудник, которого касается изменение (nil - общие данные, например график)
+	UserID *uint `gorm:"index" json:"user_id"`
+
+	// Состояние сущности до и после изменения в JSON; пусто при создании и удалении соответственно
+	Before string `gorm:"type:text" json:"before"`
+	After  string `gorm:"type:text" json:"after"`
+
+	CreatedAt time.Time `gorm:"not null;index" json:"created_at"`
+}
+
+func (AuditEntry) TableName() string {
+	return "audit_entries"
+}
+
+// NewAuditEntry создает запись журнала об изменении сущности
+func NewAuditEntry(actor Actor, action, entityType string, entityID uint) *AuditEntry {
+	return &AuditEntry{
+		ActorChatID:  actor.ChatID,
+		ActorTokenID: actor.TokenID,
+		Action:       action,
+		EntityType:   entityType,
+		EntityID:     entityID,
+	}
+}
+
+// ForUser отмечает сотрудника, которого касается изменение
+func (e *AuditEntry) ForUser(userID uint) *AuditEntry {
+	e.UserID = &userID
+	return e
+}
+
+// Actor возвращает автора изменения
+func (e *AuditEntry) Actor() Actor {
+	return Actor{ChatID: e.ActorChatID, TokenID: e.ActorTokenID}
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+	"work-schedule-bot/pkg/clock"
+
+	"gorm.io/gorm"
+)
+
+// LocationSetting - ключ настройки gorm с часовым поясом компании. Репозитории задают его
+// по своим часам, а хуки моделей читают из запроса.
+const LocationSetting = "work_schedule:location"
+
+// queryLocation возвращает часовой пояс компании из настроек запроса, без настройки - пояс сервера
+func queryLocation(tx *gorm.DB) *time.Location {
+	if value, ok := tx.Get(LocationSetting); ok {
+		if loc, ok := value.(*time.Location); ok && loc != nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// dateIn возвращает полночь календарного дня t в поясе loc.
+// PostgreSQL возвращает колонки типа date в UTC, SQLite - с тем смещением, с которым они были записаны,
+// поэтому прочитанные даты приводятся к одному виду.
+func dateIn(t time.Time, loc *time.Location) time.Time {
+	return clock.DateOf(t, loc)
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DialogState - текущий шаг многошагового диалога в чате.
+// Хранится в базе, чтобы диалог переживал перезапуск бота.
+type DialogState struct {
+	ChatID         int64     `gorm:"primarykey;autoIncrement:false" json:"chat_id"`
+	Flow           string    `gorm:"not null" json:"flow"`  // сценарий, например profile_create
+	Step           string    `gorm:"not null" json:"step"`  // шаг внутри сценария
+	Data           string    `gorm:"type:text" json:"data"` // данные предыдущих шагов в JSON
+	TimeoutSeconds int       `gorm:"not null;default:0" json:"timeout_seconds"`
+	ExpiresAt      time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (DialogState) TableName() string {
+	return "dialog_states"
+}
+
+// IsExpired проверяет, истекло ли время ожидания ответа
+func (ds *DialogState) IsExpired(now time.Time) bool {
+	return !now.Before(ds.ExpiresAt)
+}
+
+// Timeout возвращает время ожидания ответа на шаге
+func (ds *DialogState) Timeout() time.Duration {
+	return time.Duration(ds.TimeoutSeconds) * time.Second
+}
+
+// SetData сохраняет данные диалога
+func (ds *DialogState) SetData(data interface{}) error {
+	if data == nil {
+		ds.Data = ""
+		return nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	ds.Data = string(encoded)
+	return nil
+}
+
+// DecodeData читает данные диалога в data
+func (ds *DialogState) DecodeData(data interface{}) error {
+	if ds.Data == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(ds.Data), data)
+}
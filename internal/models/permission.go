@@ -0,0 +1,142 @@
+package models
+
+import (
+	"sort"
+)
+
+// Permission - именованное право доступа
+type Permission string
+
+const (
+	PermUsersView       Permission = "users.view"       // просмотр пользователей и статистики бота
+	PermRolesManage     Permission = "roles.manage"     // назначение ролей
+	PermUsersManage     Permission = "users.manage"     // кадровые данные, деактивация и возврат сотрудников
+	PermUsersApprove    Permission = "users.approve"    // одобрение и отклонение новых профилей
+	PermUsersPurge      Permission = "users.purge"      // безвозвратное удаление сотрудников
+	PermTeamsManage     Permission = "teams.manage"     // управление отделами и командами
+	PermScheduleView    Permission = "schedule.view"    // просмотр графиков работы
+	PermScheduleEdit    Permission = "schedule.edit"    // изменение графиков работы
+	PermStatsView       Permission = "stats.view"       // просмотр статистики сотрудников
+	PermStatsManage     Permission = "stats.manage"     // пересчет статистики из исходных данных
+	PermTimesheetExport Permission = "timesheet.export" // выгрузка табеля учета рабочего времени
+	PermSessionsCorrect Permission = "sessions.correct" // исправление времени прихода и ухода сотрудников
+	PermAbsencesMark    Permission = "absences.mark"    // отметка прогулов
+	PermTimeBankView    Permission = "timebank.view"    // просмотр банка времени сотрудников
+	PermTimeBankAdjust  Permission = "timebank.adjust"  // ручная корректировка банка времени
+	PermTimeBankManage  Permission = "timebank.manage"  // лимиты и закрытие месяца
+	PermSessionsImport  Permission = "sessions.import"  // импорт истории рабочих дней из CSV
+	PermAPITokensManage Permission = "apitokens.manage" // выпуск и отзыв токенов API
+	PermBackupManage    Permission = "backup.manage"    // резервные копии базы
+	PermAuditView       Permission = "audit.view"       // журнал изменений данных
+)
+
+// Роли пользователей
+const (
+	RoleEmployee   string = "employee"
+	RoleHR         string = "hr"
+	RoleAccountant string = "accountant"
+)
+
+// rolePermissions - набор прав для каждой роли
+var rolePermissions = map[string][]Permission{
+	RoleEmployee: {},
+	RoleTeamLead: {
+		PermUsersView,
+		PermUsersApprove,
+		PermStatsView,
+		PermTimesheetExport,
+		PermSessionsCorrect,
+		PermAbsencesMark,
+		PermTimeBankView,
+		PermTimeBankAdjust,
+	},
+	RoleHR: {
+		PermUsersView,
+		PermUsersManage,
+		PermUsersApprove,
+		PermTeamsManage,
+		PermScheduleView,
+		PermStatsView,
+		PermTimesheetExport,
+		PermSessionsCorrect,
+		PermAbsencesMark,
+		PermTimeBankView,
+		PermTimeBankAdjust,
+	},
+	RoleAccountant: {
+		PermUsersView,
+		PermScheduleView,
+		PermStatsView,
+		PermTimesheetExport,
+		PermTimeBankView,
+		PermTimeBankManage,
+	},
+	RoleAdmin: {
+		PermUsersView,
+		PermUsersManage,
+		PermUsersApprove,
+		PermUsersPurge,
+		PermRolesManage,
+		PermTeamsManage,
+		PermScheduleView,
+		PermScheduleEdit,
+		PermStatsView,
+		PermStatsManage,
+		PermTimesheetExport,
+		PermSessionsCorrect,
+		PermAbsencesMark,
+		PermTimeBankView,
+		PermTimeBankAdjust,
+		PermTimeBankManage,
+		PermSessionsImport,
+		PermAPITokensManage,
+		PermBackupManage,
+		PermAuditView,
+	},
+}
+
+// Роли, права которых распространяются на всю компанию (остальные - только на свою команду)
+var companyScopeRoles = map[string]bool{
+	RoleHR:         true,
+	RoleAccountant: true,
+	RoleAdmin:      true,
+}
+
+// NormalizeRole приводит устаревшие названия ролей к актуальным
+func NormalizeRole(role string) string {
+	if role == RoleClient || role == "" {
+		return RoleEmployee
+	}
+	return role
+}
+
+// IsValidRole проверяет, существует ли роль
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[NormalizeRole(role)]
+	return ok
+}
+
+// GetRolePermissions возвращает права роли
+func GetRolePermissions(role string) []Permission {
+	return rolePermissions[NormalizeRole(role)]
+}
+
+// RoleHasPermission проверяет, есть ли у роли право
+func RoleHasPermission(role string, permission Permission) bool {
+	for _, p := range GetRolePermissions(role) {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRoles возвращает список всех ролей
+func GetRoles() []string {
+	roles := make([]string, 0, len(rolePermissions))
+	for role := range rolePermissions {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
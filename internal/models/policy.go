@@ -0,0 +1,43 @@
+package models
+
+// WorkPolicy - нормы рабочего времени и ограничения учета, задаются конфигурацией
+type WorkPolicy struct {
+	WorkDayMinutes          int // норма дня для графиков по календарю, дней отсутствия и прогноза в статистике
+	DefaultDayMinutes       int // норма дня, если график месяца не задан
+	FallbackRequiredMinutes int // норма смены, если ее не удалось получить из статистики
+	ShortDayMinutes         int // норма короче этой показывается как норма сокращенного дня
+	MinYear                 int // допустимый диапазон годов
+	MaxYear                 int
+	MinClockInYear          int // раньше этого года нельзя отметить начало работы
+
+	AbsenceCreditModes map[string]string // режимы учета по типам отсутствия; для остальных типов - режим по умолчанию
+}
+
+// DefaultWorkPolicy возвращает нормы по умолчанию
+func DefaultWorkPolicy() WorkPolicy {
+	return WorkPolicy{
+		WorkDayMinutes:          520, // 8 часов 40 минут
+		DefaultDayMinutes:       480, // 8 часов
+		FallbackRequiredMinutes: 200,
+		ShortDayMinutes:         280,
+		MinYear:                 2000,
+		MaxYear:                 2100,
+		MinClockInYear:          2026,
+	}
+}
+
+// ValidYear проверяет, что год входит в допустимый диапазон
+func (p WorkPolicy) ValidYear(year int) bool {
+	return year >= p.MinYear && year <= p.MaxYear
+}
+
+// AbsenceCreditMode возвращает режим учета для типа отсутствия
+func (p WorkPolicy) AbsenceCreditMode(absenceType string) string {
+	if mode, ok := p.AbsenceCreditModes[absenceType]; ok {
+		return mode
+	}
+	if mode, ok := defaultAbsenceCreditModes[absenceType]; ok {
+		return mode
+	}
+	return AbsenceCreditFull
+}
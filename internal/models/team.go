@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// Department - отдел компании
+type Department struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Name      string    `gorm:"uniqueIndex;not null" json:"name"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Teams []Team `gorm:"foreignKey:DepartmentID" json:"teams"`
+}
+
+func (Department) TableName() string {
+	return "departments"
+}
+
+// Team - команда внутри отдела
+type Team struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	DepartmentID uint      `gorm:"not null;index" json:"department_id"`
+	Name         string    `gorm:"not null" json:"name"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Department Department `gorm:"foreignKey:DepartmentID" json:"department"`
+}
+
+func (Team) TableName() string {
+	return "teams"
+}
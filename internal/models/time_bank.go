@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+)
+
+// TimeBankEntry - запись в накопительном банке времени пользователя
+type TimeBankEntry struct {
+	ID     uint   `gorm:"primarykey" json:"id"`
+	UserID uint   `gorm:"not null;index" json:"user_id"`
+	Year   int    `gorm:"not null;index" json:"year"`
+	Month  int    `gorm:"not null;check:month >= 1 AND month <= 12;index" json:"month"`
+	Type   string `gorm:"type:varchar(20);not null;index" json:"type"`
+
+	// Минуты со знаком: + пополнение банка, - списание
+	Minutes int    `gorm:"not null;default:0" json:"minutes"`
+	Reason  string `json:"reason"`
+
+	// Chat ID того, кто создал запись (0 - система)
+	CreatedBy int64 `gorm:"not null;default:0" json:"created_by"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (TimeBankEntry) TableName() string {
+	return "time_bank_entries"
+}
+
+// Типы записей банка времени
+const (
+	TimeBankEntryMonthly    = "monthly"    // перенос итога месяца (переработка/недобор)
+	TimeBankEntryDayOff     = "day_off"    // списание за отгул
+	TimeBankEntryAdjustment = "adjustment" // ручная корректировка администратором
+)
+
+// TimeBankSettings - ограничения переноса итогов месяца в банк времени
+type TimeBankSettings struct {
+	ID uint `gorm:"primarykey" json:"id"`
+
+	// Максимум переработки, переносимой в банк за один месяц
+	MaxMonthlySurplusMinutes int `gorm:"not null;default:2400" json:"max_monthly_surplus_minutes"`
+	// Максимум недобора, переносимого в банк за один месяц
+	MaxMonthlyDeficitMinutes int `gorm:"not null;default:2400" json:"max_monthly_deficit_minutes"`
+
+	UpdatedBy int64     `gorm:"not null;default:0" json:"updated_by"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (TimeBankSettings) TableName() string {
+	return "time_bank_settings"
+}
+
+// DefaultTimeBankSettings возвращает ограничения по умолчанию (40 часов в обе стороны)
+func DefaultTimeBankSettings() *TimeBankSettings {
+	return &TimeBankSettings{
+		MaxMonthlySurplusMinutes: 2400,
+		MaxMonthlyDeficitMinutes: 2400,
+	}
+}
+
+// ClampMonthlyBalance ограничивает итог месяца установленными лимитами
+func (s *TimeBankSettings) ClampMonthlyBalance(minutes int) int {
+	if minutes > s.MaxMonthlySurplusMinutes {
+		return s.MaxMonthlySurplusMinutes
+	}
+	if minutes < -s.MaxMonthlyDeficitMinutes {
+		return -s.MaxMonthlyDeficitMinutes
+	}
+	return minutes
+}
+
+// IsValid проверяет валидность настроек
+func (s *TimeBankSettings) IsValid() bool {
+	return s.MaxMonthlySurplusMinutes >= 0 && s.MaxMonthlyDeficitMinutes >= 0
+}
+
+// MonthlyBalance возвращает итог месяца для банка времени: + переработка, - недобор
+func (ums *UserMonthlyStat) MonthlyBalance() int {
+	return ums.OvertimeMinutes - ums.DeficitMinutes
+}
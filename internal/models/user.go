@@ -1,10 +1,16 @@
 package models
 
+import (
+	"strings"
+	"time"
+)
+
 type Role string
 
 const (
-	RoleClient string = "client"
-	RoleAdmin  string = "admin"
+	RoleClient   string = "client"    // устаревшее название роли employee
+	RoleTeamLead string = "team_lead" // руководитель команды, права ограничены своей командой
+	RoleAdmin    string = "admin"
 )
 
 type User struct {
@@ -15,7 +21,26 @@ type User struct {
 	Username  string `json:"username"`
 	FirstName string `gorm:"not null" json:"first_name"`
 	LastName  string `json:"last_name"`
-	Role      string `gorm:"default:'client'" json:"role"` // string вместо Role
+	Role      string `gorm:"default:'employee'" json:"role"` // string вместо Role
+	TeamID    *uint  `gorm:"index" json:"team_id"`
+	Timezone  string `gorm:"type:varchar(64)" json:"timezone"` // IANA-имя пояса, пустое - пояс компании
+	Language  string `gorm:"type:varchar(8)" json:"language"`  // язык интерфейса (ru, en), пустой - язык по умолчанию
+
+	// Отчество, пустое - отчества нет
+	Patronymic string `json:"patronymic"`
+
+	// Кадровые данные: задаются HR или администратором, сам сотрудник их не меняет
+	Position       string     `json:"position"`                                // должность
+	EmployeeNumber string     `gorm:"type:varchar(32)" json:"employee_number"` // табельный номер
+	HireDate       *time.Time `gorm:"type:date" json:"hire_date"`              // дата приема, nil - не указана
+
+	// Дата увольнения: сотрудник деактивирован, его история сохраняется. nil - сотрудник работает
+	TerminationDate *time.Time `gorm:"type:date" json:"termination_date"`
+
+	// Профиль создан, но еще не одобрен HR или администратором: учет времени недоступен
+	PendingApproval bool `gorm:"not null;default:false" json:"pending_approval"`
+
+	Team *Team `gorm:"foreignKey:TeamID;constraint:OnDelete:SET NULL" json:"team,omitempty"`
 }
 
 // IsAdmin проверяет, является ли пользователь администратором
@@ -23,6 +48,93 @@ func (u *User) IsAdmin() bool {
 	return u.Role == "admin"
 }
 
+// IsActive проверяет, что сотрудник не деактивирован
+func (u *User) IsActive() bool {
+	return u.TerminationDate == nil
+}
+
+// CanWork проверяет, что профиль одобрен и сотрудник не деактивирован
+func (u *User) CanWork() bool {
+	return u.IsActive() && !u.PendingApproval
+}
+
+// EmployedIn проверяет, работал ли сотрудник в указанном месяце: с месяца приема
+// до месяца увольнения включительно. Неодобренный сотрудник еще не работает:
+// статистика создается при одобрении.
+func (u *User) EmployedIn(year, month int) bool {
+	if u.PendingApproval {
+		return false
+	}
+	if u.HireDate != nil && u.HireDate.Year()*12+int(u.HireDate.Month()) > year*12+month {
+		return false
+	}
+	if u.TerminationDate == nil {
+		return true
+	}
+	terminated := u.TerminationDate.Year()*12 + int(u.TerminationDate.Month())
+	return terminated >= year*12+month
+}
+
+// EmployedOn проверяет, работал ли сотрудник в указанный день: не раньше даты приема
+// и не позже даты увольнения
+func (u *User) EmployedOn(date time.Time) bool {
+	day := date.Format("2006-01-02")
+	if u.HireDate != nil && day < u.HireDate.Format("2006-01-02") {
+		return false
+	}
+	return u.TerminationDate == nil || day <= u.TerminationDate.Format("2006-01-02")
+}
+
+// FullName возвращает фамилию, имя и отчество для кадровых отчетов
+func (u *User) FullName() string {
+	return strings.Join(strings.Fields(u.LastName+" "+u.FirstName+" "+u.Patronymic), " ")
+}
+
+// IsTeamLead проверяет, является ли пользователь руководителем команды
+func (u *User) IsTeamLead() bool {
+	return u.Role == RoleTeamLead
+}
+
+// HasPermission проверяет, есть ли у пользователя право
+func (u *User) HasPermission(permission Permission) bool {
+	return RoleHasPermission(u.Role, permission)
+}
+
+// HasCompanyScope проверяет, распространяются ли права пользователя на всю компанию
+func (u *User) HasCompanyScope() bool {
+	return companyScopeRoles[NormalizeRole(u.Role)]
+}
+
+// InTeam проверяет, состоит ли пользователь в указанной команде
+func (u *User) InTeam(teamID uint) bool {
+	return u.TeamID != nil && *u.TeamID == teamID
+}
+
+// CanManage проверяет, может ли пользователь использовать право в отношении другого пользователя.
+// Роли уровня компании действуют на всех, руководитель - только на участников своей команды.
+func (u *User) CanManage(target *User, permission Permission) bool {
+	if !u.HasPermission(permission) {
+		return false
+	}
+	if u.HasCompanyScope() {
+		return true
+	}
+	return u.TeamID != nil && target.InTeam(*u.TeamID)
+}
+
+// Location возвращает часовой пояс пользователя. Если пояс не задан или не распознан -
+// пояс компании company.
+func (u *User) Location(company *time.Location) *time.Location {
+	if u == nil || u.Timezone == "" {
+		return company
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return company
+	}
+	return loc
+}
+
 // SetRole устанавливает роль
 func (u *User) SetRole(role Role) {
 	u.Role = string(role)
@@ -20,19 +20,96 @@ type UserMonthlyStat struct {
 	OvertimeMinutes int `gorm:"not null;default:0" json:"overtime_minutes"`
 	DeficitMinutes  int `gorm:"not null;default:0" json:"deficit_minutes"`
 
+	// Отсутствия
+	CreditedAbsenceMinutes   int `gorm:"not null;default:0" json:"credited_absence_minutes"`   // засчитанные как отработанные
+	UncreditedAbsenceMinutes int `gorm:"not null;default:0" json:"uncredited_absence_minutes"` // не засчитанные (за свой счёт, прогулы)
+	UncreditedAbsenceDays    int `gorm:"not null;default:0" json:"uncredited_absence_days"`
+	PlanReductionMinutes     int `gorm:"not null;default:0" json:"plan_reduction_minutes"` // на сколько уменьшен план месяца
+
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 
-	User User `gorm:"foreignKey:UserID"`
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
 }
 
 func (UserMonthlyStat) TableName() string {
 	return "user_monthly_stats"
 }
 
+// SessionTypeTotals - агрегированные показатели завершенных сессий одного типа за месяц
+type SessionTypeTotals struct {
+	SessionType     string
+	Days            int
+	WorkedMinutes   int
+	RequiredMinutes int
+}
+
+// MonthTotals - фактические показатели пользователя за месяц с разбивкой по отсутствиям
+type MonthTotals struct {
+	WorkedDays               int
+	WorkedMinutes            int
+	CreditedAbsenceMinutes   int
+	UncreditedAbsenceMinutes int
+	UncreditedAbsenceDays    int
+	PlanReductionMinutes     int
+}
+
+// AggregateMonthTotals собирает показатели месяца с учетом режима учета каждого типа отсутствия из policy.
+// Режим применяется только здесь: сессии отсутствия хранят норму дня без отработанных минут,
+// поэтому смена режима сказывается на уже созданных отсутствиях при следующем пересчете.
+func AggregateMonthTotals(rows []SessionTypeTotals, policy WorkPolicy) MonthTotals {
+	var totals MonthTotals
+
+	for _, row := range rows {
+		if row.SessionType == SessionTypeWork || row.SessionType == "" {
+			totals.WorkedDays += row.Days
+			totals.WorkedMinutes += row.WorkedMinutes
+			continue
+		}
+
+		switch policy.AbsenceCreditMode(row.SessionType) {
+		case AbsenceCreditFull:
+			totals.WorkedDays += row.Days
+			totals.CreditedAbsenceMinutes += row.RequiredMinutes
+		case AbsenceCreditReducePlan:
+			totals.UncreditedAbsenceDays += row.Days
+			totals.UncreditedAbsenceMinutes += row.RequiredMinutes
+			totals.PlanReductionMinutes += row.RequiredMinutes
+		case AbsenceCreditNone:
+			totals.UncreditedAbsenceDays += row.Days
+			totals.UncreditedAbsenceMinutes += row.RequiredMinutes
+		}
+	}
+
+	return totals
+}
+
+// EffectivePlannedMinutes возвращает плановое время с учетом уменьшения плана
+func (ums *UserMonthlyStat) EffectivePlannedMinutes() int {
+	planned := ums.PlannedMinutes - ums.PlanReductionMinutes
+	if planned < 0 {
+		return 0
+	}
+	return planned
+}
+
+// CountedMinutes возвращает время, засчитанное в выполнение плана
+func (ums *UserMonthlyStat) CountedMinutes() int {
+	return ums.WorkedMinutes + ums.CreditedAbsenceMinutes
+}
+
+// RemainingDays возвращает количество оставшихся рабочих дней месяца
+func (ums *UserMonthlyStat) RemainingDays() int {
+	remaining := ums.PlannedDays - ums.WorkedDays - ums.UncreditedAbsenceDays
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
 // CalculateStats вычисляет переработку и недобор
 func (ums *UserMonthlyStat) CalculateStats() {
-	diff := ums.WorkedMinutes - ums.PlannedMinutes
+	diff := ums.CountedMinutes() - ums.EffectivePlannedMinutes()
 	if diff > 0 {
 		ums.OvertimeMinutes = diff
 		ums.DeficitMinutes = 0
@@ -56,6 +133,57 @@ func (ums *UserMonthlyStat) UpdateWorkedTime(workedDays int, workedMinutes int)
 	ums.CalculateStats()
 }
 
+// ApplyMonthTotals обновляет фактические показатели из агрегированных данных сессий
+func (ums *UserMonthlyStat) ApplyMonthTotals(totals MonthTotals) {
+	ums.WorkedDays = totals.WorkedDays
+	ums.WorkedMinutes = totals.WorkedMinutes
+	ums.CreditedAbsenceMinutes = totals.CreditedAbsenceMinutes
+	ums.UncreditedAbsenceMinutes = totals.UncreditedAbsenceMinutes
+	ums.UncreditedAbsenceDays = totals.UncreditedAbsenceDays
+	ums.PlanReductionMinutes = totals.PlanReductionMinutes
+	ums.CalculateStats()
+}
+
+// DiffTotals возвращает названия показателей, которые отличаются от other
+func (ums *UserMonthlyStat) DiffTotals(other *UserMonthlyStat) []string {
+	fields := []struct {
+		name        string
+		left, right int
+	}{
+		{"planned_days", ums.PlannedDays, other.PlannedDays},
+		{"planned_minutes", ums.PlannedMinutes, other.PlannedMinutes},
+		{"worked_days", ums.WorkedDays, other.WorkedDays},
+		{"worked_minutes", ums.WorkedMinutes, other.WorkedMinutes},
+		{"overtime_minutes", ums.OvertimeMinutes, other.OvertimeMinutes},
+		{"deficit_minutes", ums.DeficitMinutes, other.DeficitMinutes},
+		{"credited_absence_minutes", ums.CreditedAbsenceMinutes, other.CreditedAbsenceMinutes},
+		{"uncredited_absence_minutes", ums.UncreditedAbsenceMinutes, other.UncreditedAbsenceMinutes},
+		{"uncredited_absence_days", ums.UncreditedAbsenceDays, other.UncreditedAbsenceDays},
+		{"plan_reduction_minutes", ums.PlanReductionMinutes, other.PlanReductionMinutes},
+	}
+
+	var diff []string
+	for _, field := range fields {
+		if field.left != field.right {
+			diff = append(diff, field.name)
+		}
+	}
+	return diff
+}
+
+// CopyTotals переносит плановые и фактические показатели из other
+func (ums *UserMonthlyStat) CopyTotals(other *UserMonthlyStat) {
+	ums.PlannedDays = other.PlannedDays
+	ums.PlannedMinutes = other.PlannedMinutes
+	ums.WorkedDays = other.WorkedDays
+	ums.WorkedMinutes = other.WorkedMinutes
+	ums.CreditedAbsenceMinutes = other.CreditedAbsenceMinutes
+	ums.UncreditedAbsenceMinutes = other.UncreditedAbsenceMinutes
+	ums.UncreditedAbsenceDays = other.UncreditedAbsenceDays
+	ums.PlanReductionMinutes = other.PlanReductionMinutes
+	ums.CalculateStats()
+}
+
 // IsValid проверяет валидность данных
 func (ums *UserMonthlyStat) IsValid() bool {
 	if ums.Month < 1 || ums.Month > 12 {
@@ -30,11 +30,8 @@ func (ws *WorkSchedule) BeforeSave() error {
 	return nil
 }
 
-// IsValid проверяет валидность данных
+// IsValid проверяет валидность данных. Допустимый диапазон годов задается WorkPolicy и проверяется сервисом.
 func (ws *WorkSchedule) IsValid() bool {
-	if ws.Year < 2000 || ws.Year > 2100 {
-		return false
-	}
 	if ws.Month < 1 || ws.Month > 12 {
 		return false
 	}
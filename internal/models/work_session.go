@@ -1,8 +1,10 @@
 package models
 
 import (
-	"fmt"
 	"time"
+	"work-schedule-bot/internal/i18n"
+
+	"gorm.io/gorm"
 )
 
 type WorkSession struct {
@@ -30,6 +32,8 @@ type WorkSession struct {
 	// "vacation" - отпуск
 	// "sick_leave" - больничный
 	// "day_off" - отгул
+	// "unpaid_leave" - отпуск за свой счёт
+	// "truancy" - прогул
 
 	Notes     string    `json:"notes"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
@@ -38,7 +42,7 @@ type WorkSession struct {
 	// Ссылка на период отсутствия (ДОБАВЛЕНО)
 	AbsencePeriodID *uint `gorm:"index" json:"absence_period_id"`
 
-	User          User          `gorm:"foreignKey:UserID"`
+	User          User           `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
 	AbsencePeriod *AbsencePeriod `gorm:"foreignKey:AbsencePeriodID" json:"absence_period,omitempty"`
 }
 
@@ -46,12 +50,26 @@ func (WorkSession) TableName() string {
 	return "work_sessions"
 }
 
+// AfterFind приводит дату сессии к полуночи в поясе компании, а моменты прихода и ухода - к UTC,
+// независимо от драйвера БД
+func (s *WorkSession) AfterFind(tx *gorm.DB) error {
+	s.Date = dateIn(s.Date, queryLocation(tx))
+	s.ClockInTime = s.ClockInTime.UTC()
+	if s.ClockOutTime != nil {
+		clockOut := s.ClockOutTime.UTC()
+		s.ClockOutTime = &clockOut
+	}
+	return nil
+}
+
 // Типы сессий (ДОБАВЛЕНО)
 const (
-	SessionTypeWork      = "work"
-	SessionTypeVacation  = "vacation"
-	SessionTypeSickLeave = "sick_leave"
-	SessionTypeDayOff    = "day_off"
+	SessionTypeWork        = "work"
+	SessionTypeVacation    = "vacation"
+	SessionTypeSickLeave   = "sick_leave"
+	SessionTypeDayOff      = "day_off"
+	SessionTypeUnpaidLeave = "unpaid_leave"
+	SessionTypeTruancy     = "truancy"
 )
 
 // Статусы рабочих сессий
@@ -79,6 +97,18 @@ func (ws *WorkSession) CalculateDiffMinutes() int {
 	return ws.WorkedMinutes - ws.RequiredMinutes
 }
 
+// CountedMinutes возвращает минуты, засчитанные за день: отработанные, а для отсутствия -
+// норму дня, если его тип засчитывается полностью по policy
+func (ws *WorkSession) CountedMinutes(policy WorkPolicy) int {
+	if !ws.IsAbsence() {
+		return ws.WorkedMinutes
+	}
+	if policy.AbsenceCreditMode(ws.SessionType) == AbsenceCreditFull {
+		return ws.RequiredMinutes
+	}
+	return 0
+}
+
 // UpdateCalculatedFields обновляет вычисляемые поля
 func (ws *WorkSession) UpdateCalculatedFields() {
 	ws.WorkedMinutes = ws.CalculateWorkedMinutes()
@@ -100,39 +130,31 @@ func (ws *WorkSession) IsCompleted() bool {
 	return ws.Status == StatusCompleted
 }
 
-// IsToday проверяет, является ли дата сессии сегодняшней
-func (ws *WorkSession) IsToday() bool {
-	now := time.Now()
-	return ws.Date.Year() == now.Year() &&
-		ws.Date.Month() == now.Month() &&
-		ws.Date.Day() == now.Day()
+// IsToday проверяет, является ли дата сессии днем момента now по календарю его часового пояса
+func (ws *WorkSession) IsToday(now time.Time) bool {
+	year, month, day := ws.Date.Date()
+	nowYear, nowMonth, nowDay := now.Date()
+	return year == nowYear && month == nowMonth && day == nowDay
 }
 
 // Duration возвращает продолжительность работы как строку
-func (ws *WorkSession) Duration() string {
+func (ws *WorkSession) Duration(tr *i18n.Localizer) string {
 	if ws.ClockOutTime == nil || ws.ClockOutTime.IsZero() {
-		return "еще на работе"
+		return tr.T("session.still_working")
 	}
 
-	duration := ws.ClockOutTime.Sub(ws.ClockInTime)
-	hours := int(duration.Hours())
-	minutes := int(duration.Minutes()) % 60
-
-	if minutes == 0 {
-		return fmt.Sprintf("%dч", hours)
-	}
-	return fmt.Sprintf("%dч %dм", hours, minutes)
+	return tr.Duration(int(ws.ClockOutTime.Sub(ws.ClockInTime).Minutes()))
 }
 
-// FormatTime форматирует время для отображения
-func (ws *WorkSession) FormatTime() string {
+// FormatTime форматирует время прихода и ухода в часовом поясе loc
+func (ws *WorkSession) FormatTime(tr *i18n.Localizer, loc *time.Location) string {
+	inTime := ws.ClockInTime.In(loc).Format("15:04")
 	if ws.ClockOutTime == nil || ws.ClockOutTime.IsZero() {
-		return fmt.Sprintf("⏰ Пришел: %s", ws.ClockInTime.Format("15:04"))
+		return tr.T("session.time_in", inTime)
 	}
 
-	inTime := ws.ClockInTime.Format("15:04")
-	outTime := ws.ClockOutTime.Format("15:04")
-	return fmt.Sprintf("⏰ Пришел: %s | Ушел: %s", inTime, outTime)
+	outTime := ws.ClockOutTime.In(loc).Format("15:04")
+	return tr.T("session.time_in_out", inTime, outTime)
 }
 
 // IsValid проверяет валидность данных
@@ -157,9 +179,16 @@ func (ws *WorkSession) IsValid() bool {
 
 // IsAbsence проверяет, является ли сессия отсутствием
 func (ws *WorkSession) IsAbsence() bool {
-	return ws.SessionType == SessionTypeVacation || 
-		ws.SessionType == SessionTypeSickLeave || 
-		ws.SessionType == SessionTypeDayOff
+	return ws.SessionType == SessionTypeVacation ||
+		ws.SessionType == SessionTypeSickLeave ||
+		ws.SessionType == SessionTypeDayOff ||
+		ws.SessionType == SessionTypeUnpaidLeave ||
+		ws.SessionType == SessionTypeTruancy
+}
+
+// IsCreditedAbsence проверяет, засчитывается ли отсутствие как отработанное время по policy
+func (ws *WorkSession) IsCreditedAbsence(policy WorkPolicy) bool {
+	return ws.IsAbsence() && policy.AbsenceCreditMode(ws.SessionType) == AbsenceCreditFull
 }
 
 // GetAbsenceEmoji возвращает эмодзи для типа отсутствия
@@ -171,23 +200,16 @@ func (ws *WorkSession) GetAbsenceEmoji() string {
 		return "🏥"
 	case SessionTypeDayOff:
 		return "🎯"
+	case SessionTypeUnpaidLeave:
+		return "💸"
+	case SessionTypeTruancy:
+		return "🚫"
 	default:
 		return "💼"
 	}
 }
 
 // FormatSessionType возвращает читаемое название типа сессии
-func (ws *WorkSession) FormatSessionType() string {
-	switch ws.SessionType {
-	case SessionTypeWork:
-		return "Работа"
-	case SessionTypeVacation:
-		return "Отпуск"
-	case SessionTypeSickLeave:
-		return "Больничный"
-	case SessionTypeDayOff:
-		return "Отгул"
-	default:
-		return ws.SessionType
-	}
-}
\ No newline at end of file
+func (ws *WorkSession) FormatSessionType(tr *i18n.Localizer) string {
+	return FormatAbsenceType(tr, ws.SessionType)
+}
@@ -4,6 +4,7 @@ package repository
 import (
 	"time"
 	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/clock"
 
 	"gorm.io/gorm"
 )
@@ -13,21 +14,24 @@ type AbsencePeriodRepository interface {
 	GetByID(id uint) (*models.AbsencePeriod, error)
 	GetByUserID(userID uint) ([]models.AbsencePeriod, error)
 	GetByUserIDAndType(userID uint, absenceType string) ([]models.AbsencePeriod, error)
+	GetByUserIDAndPeriod(userID uint, startDate, endDate time.Time) ([]models.AbsencePeriod, error)
 	GetCurrentAbsence(userID uint, date time.Time) (*models.AbsencePeriod, error)
 	CheckPeriodConflict(userID uint, startDate, endDate time.Time) (bool, error)
+	GetOverlapping(startDate, endDate time.Time) ([]models.AbsencePeriod, error)
 	Delete(id uint) error
 	DeleteByUserID(userID uint) error
 }
 
 type GormAbsencePeriodRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	clock clock.Clock
 }
 
-func NewGormAbsencePeriodRepository(db *gorm.DB) (AbsencePeriodRepository, error) {
-	if err := db.AutoMigrate(&models.AbsencePeriod{}); err != nil {
+func NewGormAbsencePeriodRepository(db *gorm.DB, clk clock.Clock) (AbsencePeriodRepository, error) {
+	if err := requireTables(db, &models.AbsencePeriod{}); err != nil {
 		return nil, err
 	}
-	return &GormAbsencePeriodRepository{db: db}, nil
+	return &GormAbsencePeriodRepository{db: withLocation(db, clk.Location()), clock: clk}, nil
 }
 
 func (r *GormAbsencePeriodRepository) Create(period *models.AbsencePeriod) error {
@@ -61,8 +65,9 @@ func (r *GormAbsencePeriodRepository) GetByUserIDAndType(userID uint, absenceTyp
 
 func (r *GormAbsencePeriodRepository) GetCurrentAbsence(userID uint, date time.Time) (*models.AbsencePeriod, error) {
 	var period models.AbsencePeriod
-	err := r.db.Where("user_id = ? AND start_date <= ? AND end_date >= ?", 
-		userID, date, date).
+	dayStart, dayEnd := dayRange(date, r.clock.Location())
+	err := r.db.Where("user_id = ? AND start_date < ? AND end_date >= ?",
+		userID, dayEnd, dayStart).
 		First(&period).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
@@ -75,19 +80,35 @@ func (r *GormAbsencePeriodRepository) GetCurrentAbsence(userID uint, date time.T
 
 func (r *GormAbsencePeriodRepository) CheckPeriodConflict(userID uint, startDate, endDate time.Time) (bool, error) {
 	var count int64
+	periodStart, periodEnd := periodRange(startDate, endDate, r.clock.Location())
 	err := r.db.Model(&models.AbsencePeriod{}).
-		Where("user_id = ? AND "+
-			"(start_date BETWEEN ? AND ? OR "+
-			"end_date BETWEEN ? AND ? OR "+
-			"(start_date <= ? AND end_date >= ?))",
-			userID,
-			startDate, endDate,
-			startDate, endDate,
-			startDate, endDate).
+		Where("user_id = ? AND start_date < ? AND end_date >= ?",
+			userID, periodEnd, periodStart).
 		Count(&count).Error
 	return count > 0, err
 }
 
+// GetOverlapping возвращает периоды всех пользователей, пересекающиеся с указанным
+func (r *GormAbsencePeriodRepository) GetOverlapping(startDate, endDate time.Time) ([]models.AbsencePeriod, error) {
+	var periods []models.AbsencePeriod
+	periodStart, periodEnd := periodRange(startDate, endDate, r.clock.Location())
+	err := r.db.Preload("User").
+		Where("start_date < ? AND end_date >= ?", periodEnd, periodStart).
+		Order("start_date ASC").
+		Find(&periods).Error
+	return periods, err
+}
+
+// GetByUserIDAndPeriod возвращает периоды отсутствия пользователя, пересекающиеся с указанным
+func (r *GormAbsencePeriodRepository) GetByUserIDAndPeriod(userID uint, startDate, endDate time.Time) ([]models.AbsencePeriod, error) {
+	var periods []models.AbsencePeriod
+	periodStart, periodEnd := periodRange(startDate, endDate, r.clock.Location())
+	err := r.db.Where("user_id = ? AND start_date < ? AND end_date >= ?", userID, periodEnd, periodStart).
+		Order("start_date ASC").
+		Find(&periods).Error
+	return periods, err
+}
+
 func (r *GormAbsencePeriodRepository) Delete(id uint) error {
 	return r.db.Delete(&models.AbsencePeriod{}, id).Error
 }
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"testing"
+	"time"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/clock"
+
+	"gorm.io/gorm"
+)
+
+func TestAbsencePeriodOverlaps(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo, err := NewGormAbsencePeriodRepository(db, clock.System(nil))
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+		user := createTestUser(t, db, 2001)
+
+		period := &models.AbsencePeriod{
+			UserID:    user.ID,
+			StartDate: localDate(2026, time.March, 10),
+			EndDate:   localDate(2026, time.March, 12),
+			Type:      models.AbsenceTypeVacation,
+		}
+		if err := repo.Create(period); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		conflicts := []struct {
+			start, end time.Time
+			want       bool
+		}{
+			{localDate(2026, time.March, 12), localDate(2026, time.March, 15), true},
+			{localDate(2026, time.March, 1), localDate(2026, time.March, 10), true},
+			{localDate(2026, time.March, 11), localDate(2026, time.March, 11), true},
+			{localDate(2026, time.March, 13), localDate(2026, time.March, 15), false},
+			{localDate(2026, time.March, 1), localDate(2026, time.March, 9), false},
+		}
+		for _, c := range conflicts {
+			got, err := repo.CheckPeriodConflict(user.ID, c.start, c.end)
+			if err != nil {
+				t.Fatalf("CheckPeriodConflict: %v", err)
+			}
+			if got != c.want {
+				t.Errorf("CheckPeriodConflict(%s, %s) = %v, want %v",
+					c.start.Format("2006-01-02"), c.end.Format("2006-01-02"), got, c.want)
+			}
+		}
+
+		current, err := repo.GetCurrentAbsence(user.ID, localDate(2026, time.March, 12).Add(15*time.Hour))
+		if err != nil {
+			t.Fatalf("GetCurrentAbsence: %v", err)
+		}
+		if current == nil {
+			t.Fatal("GetCurrentAbsence returned no period on its last day")
+		}
+		if !current.StartDate.Equal(period.StartDate) || !current.EndDate.Equal(period.EndDate) {
+			t.Errorf("period dates = %v - %v, want %v - %v",
+				current.StartDate, current.EndDate, period.StartDate, period.EndDate)
+		}
+
+		overlapping, err := repo.GetOverlapping(localDate(2026, time.March, 12), localDate(2026, time.March, 31))
+		if err != nil {
+			t.Fatalf("GetOverlapping: %v", err)
+		}
+		if len(overlapping) != 1 || overlapping[0].User.ChatID != user.ChatID {
+			t.Errorf("GetOverlapping returned %d periods, want 1 with preloaded user", len(overlapping))
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"errors"
+	"time"
+	"work-schedule-bot/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type APITokenRepository interface {
+	Create(token *models.APIToken) error
+	GetByHash(hash string) (*models.APIToken, error)
+	GetByID(id uint) (*models.APIToken, error)
+	GetAll() ([]*models.APIToken, error)
+	Revoke(id uint, revokedAt time.Time) error
+	TouchLastUsed(id uint, usedAt time.Time) error
+}
+
+type GormAPITokenRepository struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+}
+
+func NewGormAPITokenRepository(db *gorm.DB) (*GormAPITokenRepository, error) {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+
+	// Схема создается миграциями (bot migrate)
+	if err := requireTables(db, &models.APIToken{}); err != nil {
+		logger.WithError(err).Error("API tokens table is missing")
+		return nil, err
+	}
+
+	logger.Info("API token repository initialized")
+
+	return &GormAPITokenRepository{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+func (r *GormAPITokenRepository) Create(token *models.APIToken) error {
+	if token.Name == "" || token.TokenHash == "" {
+		return errors.New("у токена должны быть название и хэш")
+	}
+
+	result := r.db.Create(token)
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to create API token")
+		return result.Error
+	}
+
+	return nil
+}
+
+// GetByHash возвращает токен по хэшу или nil, если такого токена нет
+func (r *GormAPITokenRepository) GetByHash(hash string) (*models.APIToken, error) {
+	var token models.APIToken
+	result := r.db.Where("token_hash = ?", hash).First(&token)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get API token by hash")
+		return nil, result.Error
+	}
+
+	return &token, nil
+}
+
+func (r *GormAPITokenRepository) GetByID(id uint) (*models.APIToken, error) {
+	var token models.APIToken
+	result := r.db.First(&token, id)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get API token")
+		return nil, result.Error
+	}
+
+	return &token, nil
+}
+
+func (r *GormAPITokenRepository) GetAll() ([]*models.APIToken, error) {
+	var tokens []*models.APIToken
+	result := r.db.Order("id ASC").Find(&tokens)
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get API tokens")
+		return nil, result.Error
+	}
+
+	return tokens, nil
+}
+
+// Revoke отзывает токен; повторный отзыв не меняет дату
+func (r *GormAPITokenRepository) Revoke(id uint, revokedAt time.Time) error {
+	result := r.db.Model(&models.APIToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to revoke API token")
+		return result.Error
+	}
+
+	return nil
+}
+
+func (r *GormAPITokenRepository) TouchLastUsed(id uint, usedAt time.Time) error {
+	return r.db.Model(&models.APIToken{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"time"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// AuditFilter - условия выборки журнала изменений. Пустые поля не ограничивают выборку.
+type AuditFilter struct {
+	UserID     *uint
+	EntityType string
+	EntityID   uint
+	From       time.Time // включительно
+	To         time.Time // не включительно
+	Limit      int
+}
+
+type AuditRepository interface {
+	Create(entry *models.AuditEntry) error
+	Find(filter AuditFilter) ([]*models.AuditEntry, error)
+	EraseUserData(userID uint) error
+}
+
+type GormAuditRepository struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+	clock  clock.Clock
+}
+
+func NewGormAuditRepository(db *gorm.DB, clk clock.Clock) (*GormAuditRepository, error) {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+
+	// Схема создается миграциями (bot migrate)
+	if err := requireTables(db, &models.AuditEntry{}); err != nil {
+		logger.WithError(err).Error("Audit table is missing")
+		return nil, err
+	}
+
+	logger.Info("Audit repository initialized")
+
+	return &GormAuditRepository{
+		db:     db,
+		logger: logger,
+		clock:  clk,
+	}, nil
+}
+
+// Create записывает изменение; время записи берется из часов бота
+func (r *GormAuditRepository) Create(entry *models.AuditEntry) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = r.clock.Now()
+	}
+
+	result := r.db.Create(entry)
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to create audit entry")
+		return result.Error
+	}
+
+	return nil
+}
+
+// Find возвращает записи журнала от новых к старым
+func (r *GormAuditRepository) Find(filter AuditFilter) ([]*models.AuditEntry, error) {
+	query := r.db.Model(&models.AuditEntry{})
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var entries []*models.AuditEntry
+	if err := query.Order("created_at DESC, id DESC").Find(&entries).Error; err != nil {
+		r.logger.WithError(err).Error("Failed to find audit entries")
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// EraseUserData удаляет из записей журнала о сотруднике сохраненные состояния с его данными.
+// Сами записи (кто, что и когда менял) остаются.
+func (r *GormAuditRepository) EraseUserData(userID uint) error {
+	result := r.db.Model(&models.AuditEntry{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{"before": "", "after": ""})
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to erase user data from audit log")
+		return result.Error
+	}
+
+	return nil
+}
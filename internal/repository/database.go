@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Поддерживаемые драйверы базы данных
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// ResolveDriver определяет драйвер по явному значению или по формату DSN
+func ResolveDriver(driver, dsn string) (string, error) {
+	driver = strings.ToLower(strings.TrimSpace(driver))
+
+	switch driver {
+	case "":
+		if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+			return DriverPostgres, nil
+		}
+		return DriverSQLite, nil
+	case DriverSQLite, "sqlite3":
+		return DriverSQLite, nil
+	case DriverPostgres, "postgresql", "pg":
+		return DriverPostgres, nil
+	}
+
+	return "", fmt.Errorf("неизвестный драйвер базы данных: %s", driver)
+}
+
+// OpenDatabase открывает соединение с базой данных выбранного драйвера
+func OpenDatabase(driver, dsn string) (*gorm.DB, error) {
+	driver, err := ResolveDriver(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	}
+
+	if driver == DriverPostgres {
+		return gorm.Open(postgres.Open(dsn), config)
+	}
+
+	return gorm.Open(sqlite.Open(sqliteDSN(dsn)), config)
+}
+
+// sqliteDSN настраивает каждое соединение пула SQLite: внешние ключи, журнал WAL и транзакции
+// BEGIN IMMEDIATE. Обновления разных чатов пишут в базу параллельно, а отложенная транзакция,
+// которая сначала читает, не может повысить блокировку до записи и сразу падает с "database is locked".
+// Немедленная блокировка ставит пишущие транзакции в очередь с ожиданием _busy_timeout.
+func sqliteDSN(dsn string) string {
+	params := []string{}
+	if !strings.Contains(dsn, "_foreign_keys=") && !strings.Contains(dsn, "_fk=") {
+		params = append(params, "_foreign_keys=on")
+	}
+	if !strings.Contains(dsn, "_busy_timeout=") && !strings.Contains(dsn, "_timeout=") {
+		params = append(params, "_busy_timeout=5000")
+	}
+	if !strings.Contains(dsn, "_txlock=") {
+		params = append(params, "_txlock=immediate")
+	}
+	// Копию, открытую только для чтения, нельзя перевести в WAL
+	if !strings.Contains(dsn, "_journal_mode=") && !strings.Contains(dsn, "_journal=") && !strings.Contains(dsn, "mode=ro") {
+		params = append(params, "_journal_mode=WAL")
+	}
+	if len(params) == 0 {
+		return dsn
+	}
+
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	return dsn + separator + strings.Join(params, "&")
+}
+
+// requireTables проверяет, что таблицы моделей созданы миграциями
+func requireTables(db *gorm.DB, values ...interface{}) error {
+	for _, value := range values {
+		if db.Migrator().HasTable(value) {
+			continue
+		}
+
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(value); err != nil {
+			return err
+		}
+		return fmt.Errorf("таблица %s не найдена, выполните миграции: bot migrate up", stmt.Schema.Table)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"time"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/clock"
+
+	"gorm.io/gorm"
+)
+
+// Даты сравниваются по полуоткрытому интервалу [начало, конец) с параметрами типа time.Time.
+// Так запросы одинаково работают в SQLite (даты хранятся строками) и в PostgreSQL (тип date).
+// Границы строятся в часовом поясе компании (пояс часов репозитория), а не в поясе сервера.
+
+// withLocation передает часовой пояс компании хукам моделей во всех запросах через db
+func withLocation(db *gorm.DB, loc *time.Location) *gorm.DB {
+	return db.Set(models.LocationSetting, loc).Session(&gorm.Session{})
+}
+
+// dayRange возвращает границы календарного дня
+func dayRange(date time.Time, loc *time.Location) (time.Time, time.Time) {
+	start := clock.DateOf(date, loc)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// monthRange возвращает границы календарного месяца
+func monthRange(year, month int, loc *time.Location) (time.Time, time.Time) {
+	start := clock.MonthStart(year, time.Month(month), loc)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// periodRange возвращает границы периода с включенными начальным и конечным днями
+func periodRange(startDate, endDate time.Time, loc *time.Location) (time.Time, time.Time) {
+	start, _ := dayRange(startDate, loc)
+	_, end := dayRange(endDate, loc)
+	return start, end
+}
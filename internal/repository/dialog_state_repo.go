@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"errors"
+	"time"
+	"work-schedule-bot/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DialogStateRepository interface {
+	Get(chatID int64) (*models.DialogState, error)
+	Save(state *models.DialogState) error
+	Delete(chatID int64) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type GormDialogStateRepository struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+}
+
+func NewGormDialogStateRepository(db *gorm.DB) (*GormDialogStateRepository, error) {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+
+	// Схема создается миграциями (bot migrate)
+	if err := requireTables(db, &models.DialogState{}); err != nil {
+		logger.WithError(err).Error("Dialog states table is missing")
+		return nil, err
+	}
+
+	logger.Info("Dialog state repository initialized")
+
+	return &GormDialogStateRepository{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+// Get возвращает состояние диалога чата или nil, если диалога нет
+func (r *GormDialogStateRepository) Get(chatID int64) (*models.DialogState, error) {
+	var state models.DialogState
+	result := r.db.Where("chat_id = ?", chatID).First(&state)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get dialog state")
+		return nil, result.Error
+	}
+
+	return &state, nil
+}
+
+// Save создает или заменяет состояние диалога чата
+func (r *GormDialogStateRepository) Save(state *models.DialogState) error {
+	if state.ChatID == 0 || state.Flow == "" || state.Step == "" {
+		return errors.New("некорректное состояние диалога")
+	}
+
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chat_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"flow", "step", "data", "timeout_seconds", "expires_at", "updated_at"}),
+	}).Create(state)
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to save dialog state")
+		return result.Error
+	}
+
+	return nil
+}
+
+// Delete завершает диалог чата
+func (r *GormDialogStateRepository) Delete(chatID int64) error {
+	result := r.db.Where("chat_id = ?", chatID).Delete(&models.DialogState{})
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to delete dialog state")
+		return result.Error
+	}
+
+	return nil
+}
+
+// DeleteExpired удаляет диалоги, время ожидания которых истекло
+func (r *GormDialogStateRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&models.DialogState{})
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to delete expired dialog states")
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
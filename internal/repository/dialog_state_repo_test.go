@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"testing"
+	"time"
+	"work-schedule-bot/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func TestDialogStateSaveAndExpire(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo, err := NewGormDialogStateRepository(db)
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+
+		now := time.Now()
+		state := &models.DialogState{ChatID: 42, Flow: "profile_create", Step: "first_name", ExpiresAt: now.Add(time.Minute)}
+		if err := repo.Save(state); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		// Повторное сохранение заменяет шаг, а не создает вторую запись
+		next := &models.DialogState{ChatID: 42, Flow: "profile_create", Step: "last_name", Data: `{"first_name":"Иван"}`, ExpiresAt: now.Add(time.Minute)}
+		if err := repo.Save(next); err != nil {
+			t.Fatalf("second Save: %v", err)
+		}
+
+		got, err := repo.Get(42)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if got == nil || got.Step != "last_name" || got.Data != next.Data {
+			t.Errorf("Get = %+v, want the last_name step", got)
+		}
+
+		expired := &models.DialogState{ChatID: 43, Flow: "profile_update", Step: "name", ExpiresAt: now.Add(-time.Minute)}
+		if err := repo.Save(expired); err != nil {
+			t.Fatalf("Save expired: %v", err)
+		}
+
+		deleted, err := repo.DeleteExpired(now)
+		if err != nil {
+			t.Fatalf("DeleteExpired: %v", err)
+		}
+		if deleted != 1 {
+			t.Errorf("DeleteExpired removed %d dialogs, want 1", deleted)
+		}
+
+		if err := repo.Delete(42); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if got, err := repo.Get(42); err != nil || got != nil {
+			t.Errorf("Get after Delete = %+v, %v, want nil", got, err)
+		}
+	})
+}
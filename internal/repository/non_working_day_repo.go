@@ -22,8 +22,8 @@ type GormNonWorkingDayRepository struct {
 }
 
 func NewGormNonWorkingDayRepository(db *gorm.DB) (NonWorkingDayRepository, error) {
-	// Автомиграция для таблицы non_working_days
-	if err := db.AutoMigrate(&models.NonWorkingDay{}); err != nil {
+	// Схема создается миграциями (bot migrate)
+	if err := requireTables(db, &models.NonWorkingDay{}); err != nil {
 		return nil, err
 	}
 
@@ -43,7 +43,8 @@ func (r *GormNonWorkingDayRepository) BulkCreate(days []models.NonWorkingDay) er
 
 func (r *GormNonWorkingDayRepository) GetByDate(date time.Time) (*models.NonWorkingDay, error) {
 	var day models.NonWorkingDay
-	err := r.db.Where("date = ?", date.Format("2006-01-02")).First(&day).Error
+	err := r.db.Where("year = ? AND month = ? AND day = ?", date.Year(), int(date.Month()), date.Day()).
+		First(&day).Error
 	if err != nil {
 		return nil, err
 	}
@@ -63,14 +64,14 @@ func (r *GormNonWorkingDayRepository) GetAll() ([]models.NonWorkingDay, error) {
 }
 
 func (r *GormNonWorkingDayRepository) DeleteAll() error {
-	return r.db.Exec("DELETE FROM non_working_days").Error
+	return r.db.Where("1 = 1").Delete(&models.NonWorkingDay{}).Error
 }
 
 func (r *GormNonWorkingDayRepository) IsNonWorkingDay(date time.Time) (bool, error) {
 	var count int64
-	searchDate := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+	// Сравниваем по году, месяцу и дню, чтобы не зависеть от формата хранения даты
 	err := r.db.Model(&models.NonWorkingDay{}).
-		Where("date = ?", searchDate).
+		Where("year = ? AND month = ? AND day = ?", date.Year(), int(date.Month()), date.Day()).
 		Count(&count).Error
 	return count > 0, err
 }
\ No newline at end of file
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"testing"
+	"time"
+	"work-schedule-bot/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func TestNonWorkingDayLookup(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo, err := NewGormNonWorkingDayRepository(db)
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+
+		days := []models.NonWorkingDay{
+			{Date: localDate(2026, time.January, 1), Year: 2026, Month: 1, Day: 1},
+			{Date: localDate(2026, time.January, 31), Year: 2026, Month: 1, Day: 31},
+		}
+		if err := repo.BulkCreate(days); err != nil {
+			t.Fatalf("BulkCreate: %v", err)
+		}
+
+		isOff, err := repo.IsNonWorkingDay(localDate(2026, time.January, 31).Add(10 * time.Hour))
+		if err != nil {
+			t.Fatalf("IsNonWorkingDay: %v", err)
+		}
+		if !isOff {
+			t.Error("IsNonWorkingDay(2026-01-31) = false, want true")
+		}
+
+		isOff, err = repo.IsNonWorkingDay(localDate(2026, time.January, 30))
+		if err != nil {
+			t.Fatalf("IsNonWorkingDay: %v", err)
+		}
+		if isOff {
+			t.Error("IsNonWorkingDay(2026-01-30) = true, want false")
+		}
+
+		day, err := repo.GetByDate(localDate(2026, time.January, 1))
+		if err != nil {
+			t.Fatalf("GetByDate: %v", err)
+		}
+		if day.Day != 1 || day.Month != 1 {
+			t.Errorf("GetByDate returned %d.%d, want 1.1", day.Day, day.Month)
+		}
+
+		if err := repo.DeleteAll(); err != nil {
+			t.Fatalf("DeleteAll: %v", err)
+		}
+		all, err := repo.GetAll()
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		if len(all) != 0 {
+			t.Errorf("GetAll after DeleteAll returned %d days, want 0", len(all))
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	_ "time/tzdata"
+	"work-schedule-bot/internal/migrations"
+	"work-schedule-bot/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testPostgresDSNEnv - переменная окружения с DSN локального PostgreSQL.
+// Если она не задана, тесты выполняются только на SQLite.
+const testPostgresDSNEnv = "TEST_POSTGRES_DSN"
+
+// testTables - таблицы, которые удаляются перед каждым тестом на PostgreSQL
+var testTables = []interface{}{
+	&migrations.SchemaMigration{},
+	&models.DialogState{},
+	&models.APIToken{},
+	&models.TimeBankSettings{},
+	&models.TimeBankEntry{},
+	&models.WorkSession{},
+	&models.AbsencePeriod{},
+	&models.NonWorkingDay{},
+	&models.UserMonthlyStat{},
+	&models.WorkSchedule{},
+	&models.AuditEntry{},
+	&models.User{},
+	&models.Team{},
+	&models.Department{},
+}
+
+// forEachDatabase запускает тест на каждой доступной базе данных
+func forEachDatabase(t *testing.T, fn func(t *testing.T, db *gorm.DB)) {
+	t.Helper()
+
+	t.Run(DriverSQLite, func(t *testing.T) {
+		fn(t, openTestDatabase(t, DriverSQLite, filepath.Join(t.TempDir(), "test.db")))
+	})
+
+	t.Run(DriverPostgres, func(t *testing.T) {
+		dsn := os.Getenv(testPostgresDSNEnv)
+		if dsn == "" {
+			t.Skipf("%s is not set", testPostgresDSNEnv)
+		}
+
+		resetPostgres(t, dsn)
+		fn(t, openTestDatabase(t, DriverPostgres, dsn))
+	})
+}
+
+// resetPostgres удаляет таблицы, оставшиеся от предыдущего теста
+func resetPostgres(t *testing.T, dsn string) {
+	t.Helper()
+
+	db, err := OpenDatabase(DriverPostgres, dsn)
+	if err != nil {
+		t.Fatalf("failed to open postgres database: %v", err)
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+
+	if err := db.Migrator().DropTable(testTables...); err != nil {
+		t.Fatalf("failed to reset postgres tables: %v", err)
+	}
+
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
+
+func openTestDatabase(t *testing.T, driver, dsn string) *gorm.DB {
+	t.Helper()
+
+	db, err := OpenDatabase(driver, dsn)
+	if err != nil {
+		t.Fatalf("failed to open %s database: %v", driver, err)
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database instance: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if _, err := migrations.NewMigrator(db).Up(); err != nil {
+		t.Fatalf("failed to migrate %s database: %v", driver, err)
+	}
+
+	return db
+}
+
+// createTestUser создает пользователя для тестов
+func createTestUser(t *testing.T, db *gorm.DB, chatID int64) *models.User {
+	t.Helper()
+
+	userRepo, err := NewUserRepository(db)
+	if err != nil {
+		t.Fatalf("failed to create user repository: %v", err)
+	}
+
+	user := &models.User{ChatID: chatID, FirstName: "Test", Role: models.RoleEmployee}
+	if err := userRepo.Create(user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	return user
+}
+
+func localDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+// loadLocation загружает часовой пояс name для часов компании в тесте
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestResolveDriver(t *testing.T) {
+	tests := []struct {
+		driver  string
+		dsn     string
+		want    string
+		wantErr bool
+	}{
+		{driver: "", dsn: "work_schedule.db", want: DriverSQLite},
+		{driver: "", dsn: "postgres://bot@localhost/bot", want: DriverPostgres},
+		{driver: "", dsn: "postgresql://bot@localhost/bot", want: DriverPostgres},
+		{driver: "Postgres", dsn: "host=localhost user=bot", want: DriverPostgres},
+		{driver: "sqlite3", dsn: "work_schedule.db", want: DriverSQLite},
+		{driver: "mysql", dsn: "bot@tcp(localhost)/bot", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ResolveDriver(tt.driver, tt.dsn)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ResolveDriver(%q, %q) error = %v, wantErr %v", tt.driver, tt.dsn, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ResolveDriver(%q, %q) = %q, want %q", tt.driver, tt.dsn, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"errors"
+	"work-schedule-bot/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type TeamRepository interface {
+	CreateDepartment(department *models.Department) error
+	GetDepartments() ([]*models.Department, error)
+	GetDepartmentByID(id uint) (*models.Department, error)
+	DeleteDepartment(id uint) error
+	CreateTeam(team *models.Team) error
+	GetTeams() ([]*models.Team, error)
+	GetTeamByID(id uint) (*models.Team, error)
+	GetTeamsByDepartment(departmentID uint) ([]*models.Team, error)
+	DeleteTeam(id uint) error
+}
+
+type GormTeamRepository struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+}
+
+func NewGormTeamRepository(db *gorm.DB) (*GormTeamRepository, error) {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+
+	// Схема создается миграциями (bot migrate)
+	if err := requireTables(db, &models.Department{}, &models.Team{}); err != nil {
+		logger.WithError(err).Error("Departments and teams tables are missing")
+		return nil, err
+	}
+
+	logger.Info("Team repository initialized")
+
+	return &GormTeamRepository{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+func (r *GormTeamRepository) CreateDepartment(department *models.Department) error {
+	if department.Name == "" {
+		return errors.New("название отдела не может быть пустым")
+	}
+
+	result := r.db.Create(department)
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to create department")
+		return result.Error
+	}
+
+	return nil
+}
+
+func (r *GormTeamRepository) GetDepartments() ([]*models.Department, error) {
+	var departments []*models.Department
+	result := r.db.Preload("Teams").Order("name ASC").Find(&departments)
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get departments")
+		return nil, result.Error
+	}
+
+	return departments, nil
+}
+
+func (r *GormTeamRepository) GetDepartmentByID(id uint) (*models.Department, error) {
+	var department models.Department
+	result := r.db.Preload("Teams").First(&department, id)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get department")
+		return nil, result.Error
+	}
+
+	return &department, nil
+}
+
+func (r *GormTeamRepository) DeleteDepartment(id uint) error {
+	result := r.db.Delete(&models.Department{}, id)
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to delete department")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("отдел не найден")
+	}
+
+	return nil
+}
+
+func (r *GormTeamRepository) CreateTeam(team *models.Team) error {
+	if team.Name == "" || team.DepartmentID == 0 {
+		return errors.New("некорректные данные команды")
+	}
+
+	result := r.db.Create(team)
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to create team")
+		return result.Error
+	}
+
+	return nil
+}
+
+func (r *GormTeamRepository) GetTeams() ([]*models.Team, error) {
+	var teams []*models.Team
+	result := r.db.Preload("Department").Order("department_id ASC, name ASC").Find(&teams)
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get teams")
+		return nil, result.Error
+	}
+
+	return teams, nil
+}
+
+func (r *GormTeamRepository) GetTeamByID(id uint) (*models.Team, error) {
+	var team models.Team
+	result := r.db.Preload("Department").First(&team, id)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get team")
+		return nil, result.Error
+	}
+
+	return &team, nil
+}
+
+func (r *GormTeamRepository) GetTeamsByDepartment(departmentID uint) ([]*models.Team, error) {
+	var teams []*models.Team
+	result := r.db.Where("department_id = ?", departmentID).Order("name ASC").Find(&teams)
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get department teams")
+		return nil, result.Error
+	}
+
+	return teams, nil
+}
+
+// DeleteTeam удаляет команду и исключает из нее всех участников
+func (r *GormTeamRepository) DeleteTeam(id uint) error {
+	result := r.db.Model(&models.User{}).Where("team_id = ?", id).Update("team_id", nil)
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to detach team members")
+		return result.Error
+	}
+
+	result = r.db.Delete(&models.Team{}, id)
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to delete team")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("команда не найдена")
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"errors"
+	"work-schedule-bot/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type TimeBankRepository interface {
+	Create(entry *models.TimeBankEntry) error
+	Update(entry *models.TimeBankEntry) error
+	GetByUserID(userID uint) ([]*models.TimeBankEntry, error)
+	GetMonthlyEntry(userID uint, year, month int) (*models.TimeBankEntry, error)
+	GetBalance(userID uint) (int, error)
+	DeleteByUserID(userID uint) error
+	GetSettings() (*models.TimeBankSettings, error)
+	SaveSettings(settings *models.TimeBankSettings) error
+}
+
+type GormTimeBankRepository struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+}
+
+func NewGormTimeBankRepository(db *gorm.DB) (*GormTimeBankRepository, error) {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+
+	// Схема создается миграциями (bot migrate)
+	if err := requireTables(db, &models.TimeBankEntry{}, &models.TimeBankSettings{}); err != nil {
+		logger.WithError(err).Error("Time bank tables are missing")
+		return nil, err
+	}
+
+	logger.Info("Time bank repository initialized")
+
+	return &GormTimeBankRepository{
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+func (r *GormTimeBankRepository) Create(entry *models.TimeBankEntry) error {
+	r.logger.WithFields(logrus.Fields{
+		"user_id": entry.UserID,
+		"type":    entry.Type,
+		"minutes": entry.Minutes,
+	}).Info("Creating time bank entry")
+
+	if entry.UserID == 0 || entry.Type == "" || entry.Month < 1 || entry.Month > 12 {
+		return errors.New("некорректные данные записи банка времени")
+	}
+
+	result := r.db.Create(entry)
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to create time bank entry")
+		return result.Error
+	}
+
+	return nil
+}
+
+func (r *GormTimeBankRepository) Update(entry *models.TimeBankEntry) error {
+	r.logger.WithFields(logrus.Fields{
+		"id":      entry.ID,
+		"user_id": entry.UserID,
+		"minutes": entry.Minutes,
+	}).Info("Updating time bank entry")
+
+	result := r.db.Save(entry)
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to update time bank entry")
+		return result.Error
+	}
+
+	return nil
+}
+
+func (r *GormTimeBankRepository) GetByUserID(userID uint) ([]*models.TimeBankEntry, error) {
+	var entries []*models.TimeBankEntry
+	result := r.db.Where("user_id = ?", userID).
+		Order("year ASC, month ASC, id ASC").
+		Find(&entries)
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get time bank entries")
+		return nil, result.Error
+	}
+
+	return entries, nil
+}
+
+func (r *GormTimeBankRepository) GetMonthlyEntry(userID uint, year, month int) (*models.TimeBankEntry, error) {
+	var entry models.TimeBankEntry
+	result := r.db.Where("user_id = ? AND year = ? AND month = ? AND type = ?",
+		userID, year, month, models.TimeBankEntryMonthly).
+		First(&entry)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get monthly time bank entry")
+		return nil, result.Error
+	}
+
+	return &entry, nil
+}
+
+func (r *GormTimeBankRepository) GetBalance(userID uint) (int, error) {
+	var balance int64
+	result := r.db.Model(&models.TimeBankEntry{}).
+		Select("COALESCE(SUM(minutes), 0)").
+		Where("user_id = ?", userID).
+		Scan(&balance)
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get time bank balance")
+		return 0, result.Error
+	}
+
+	return int(balance), nil
+}
+
+func (r *GormTimeBankRepository) DeleteByUserID(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.TimeBankEntry{}).Error
+}
+
+// GetSettings возвращает настройки банка времени (значения по умолчанию, если не заданы)
+func (r *GormTimeBankRepository) GetSettings() (*models.TimeBankSettings, error) {
+	var settings models.TimeBankSettings
+	result := r.db.Order("id ASC").First(&settings)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return models.DefaultTimeBankSettings(), nil
+	}
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get time bank settings")
+		return nil, result.Error
+	}
+
+	return &settings, nil
+}
+
+func (r *GormTimeBankRepository) SaveSettings(settings *models.TimeBankSettings) error {
+	if !settings.IsValid() {
+		return errors.New("некорректные настройки банка времени")
+	}
+
+	if settings.ID == 0 {
+		existing, err := r.GetSettings()
+		if err != nil {
+			return err
+		}
+		settings.ID = existing.ID
+	}
+
+	result := r.db.Save(settings)
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to save time bank settings")
+		return result.Error
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"testing"
+	"work-schedule-bot/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func TestTimeBankBalance(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo, err := NewGormTimeBankRepository(db)
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+		user := createTestUser(t, db, 3001)
+
+		entries := []*models.TimeBankEntry{
+			{UserID: user.ID, Year: 2026, Month: 1, Type: models.TimeBankEntryMonthly, Minutes: 600},
+			{UserID: user.ID, Year: 2026, Month: 2, Type: models.TimeBankEntryMonthly, Minutes: -120},
+			{UserID: user.ID, Year: 2026, Month: 2, Type: models.TimeBankEntryAdjustment, Minutes: 40, CreatedBy: 1},
+		}
+		for _, entry := range entries {
+			if err := repo.Create(entry); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+		}
+
+		balance, err := repo.GetBalance(user.ID)
+		if err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+		if balance != 520 {
+			t.Errorf("GetBalance = %d, want 520", balance)
+		}
+
+		monthly, err := repo.GetMonthlyEntry(user.ID, 2026, 2)
+		if err != nil {
+			t.Fatalf("GetMonthlyEntry: %v", err)
+		}
+		if monthly == nil || monthly.Minutes != -120 {
+			t.Errorf("GetMonthlyEntry = %+v, want the -120 monthly entry", monthly)
+		}
+	})
+}
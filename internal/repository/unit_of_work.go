@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"work-schedule-bot/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Repositories - репозитории, работающие в одной транзакции
+type Repositories struct {
+	Users          UserRepository
+	WorkSessions   WorkSessionRepository
+	AbsencePeriods AbsencePeriodRepository
+	MonthlyStats   UserMonthlyStatRepository
+	WorkSchedules  WorkScheduleRepository
+	TimeBank       TimeBankRepository
+	NonWorkingDays NonWorkingDayRepository
+	Teams          TeamRepository
+	APITokens      APITokenRepository
+	Audit          AuditRepository
+}
+
+// UnitOfWork выполняет несколько операций над репозиториями атомарно
+type UnitOfWork interface {
+	// WithTx выполняет fn в транзакции: при ошибке или панике все изменения откатываются
+	WithTx(fn func(repos *Repositories) error) error
+}
+
+type GormUnitOfWork struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+	clock  clock.Clock
+}
+
+func NewGormUnitOfWork(db *gorm.DB, clk clock.Clock) *GormUnitOfWork {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+
+	return &GormUnitOfWork{
+		db:     withLocation(db, clk.Location()),
+		logger: logger,
+		clock:  clk,
+	}
+}
+
+func (u *GormUnitOfWork) WithTx(fn func(repos *Repositories) error) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		return fn(u.repositories(tx))
+	})
+}
+
+// repositories создает репозитории поверх транзакции.
+// Таблицы уже проверены основными конструкторами, поэтому структуры создаются напрямую.
+func (u *GormUnitOfWork) repositories(tx *gorm.DB) *Repositories {
+	return &Repositories{
+		Users:          UserRepository{db: tx},
+		WorkSessions:   &GormWorkSessionRepository{db: tx, logger: u.logger, clock: u.clock},
+		AbsencePeriods: &GormAbsencePeriodRepository{db: tx, clock: u.clock},
+		MonthlyStats:   &GormUserMonthlyStatRepository{db: tx, logger: u.logger, clock: u.clock},
+		WorkSchedules:  &GormWorkScheduleRepository{db: tx, logger: u.logger, clock: u.clock},
+		TimeBank:       &GormTimeBankRepository{db: tx, logger: u.logger},
+		NonWorkingDays: &GormNonWorkingDayRepository{db: tx},
+		Teams:          &GormTeamRepository{db: tx, logger: u.logger},
+		APITokens:      &GormAPITokenRepository{db: tx, logger: u.logger},
+		Audit:          &GormAuditRepository{db: tx, logger: u.logger, clock: u.clock},
+	}
+}
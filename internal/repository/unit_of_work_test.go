@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/clock"
+
+	"gorm.io/gorm"
+)
+
+func TestUnitOfWorkRollback(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		uow := NewGormUnitOfWork(db, clock.System(nil))
+		user := createTestUser(t, db, 4001)
+		failure := errors.New("failure after partial writes")
+
+		err := uow.WithTx(func(repos *Repositories) error {
+			period := &models.AbsencePeriod{
+				UserID:    user.ID,
+				StartDate: localDate(2026, time.July, 1),
+				EndDate:   localDate(2026, time.July, 1),
+				Type:      models.AbsenceTypeVacation,
+			}
+			if err := repos.AbsencePeriods.Create(period); err != nil {
+				return err
+			}
+
+			date := localDate(2026, time.July, 1)
+			session := &models.WorkSession{
+				UserID:          user.ID,
+				Date:            date,
+				ClockInTime:     date,
+				RequiredMinutes: 480,
+				Status:          models.StatusCompleted,
+				SessionType:     models.SessionTypeVacation,
+				AbsencePeriodID: &period.ID,
+			}
+			if err := repos.WorkSessions.CreateAbsenceSession(session); err != nil {
+				return err
+			}
+			return failure
+		})
+		if !errors.Is(err, failure) {
+			t.Fatalf("WithTx error = %v, want %v", err, failure)
+		}
+
+		var periods, sessions int64
+		db.Model(&models.AbsencePeriod{}).Count(&periods)
+		db.Model(&models.WorkSession{}).Count(&sessions)
+		if periods != 0 || sessions != 0 {
+			t.Errorf("after rollback %d periods and %d sessions remain, want none", periods, sessions)
+		}
+	})
+}
+
+func TestUnitOfWorkCommit(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		uow := NewGormUnitOfWork(db, clock.System(nil))
+		user := createTestUser(t, db, 4002)
+
+		err := uow.WithTx(func(repos *Repositories) error {
+			entry := &models.TimeBankEntry{UserID: user.ID, Year: 2026, Month: 7, Type: models.TimeBankEntryAdjustment, Minutes: 60}
+			if err := repos.TimeBank.Create(entry); err != nil {
+				return err
+			}
+			return repos.Users.UpdateRole(user.ChatID, models.Role(models.RoleHR))
+		})
+		if err != nil {
+			t.Fatalf("WithTx: %v", err)
+		}
+
+		var stored models.User
+		if err := db.First(&stored, user.ID).Error; err != nil {
+			t.Fatalf("failed to read user: %v", err)
+		}
+		if stored.Role != models.RoleHR {
+			t.Errorf("role after commit = %q, want %q", stored.Role, models.RoleHR)
+		}
+
+		var entries int64
+		db.Model(&models.TimeBankEntry{}).Where("user_id = ?", user.ID).Count(&entries)
+		if entries != 1 {
+			t.Errorf("time bank entries after commit = %d, want 1", entries)
+		}
+	})
+}
+
+func TestUnitOfWorkConcurrentWrites(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		uow := NewGormUnitOfWork(db, clock.System(nil))
+		user := createTestUser(t, db, 4003)
+
+		// Каждая транзакция сначала читает, потом пишет, как ClockOut или добавление отсутствия
+		const workers, txPerWorker = 16, 20
+		errs := make(chan error, workers*txPerWorker)
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < txPerWorker; j++ {
+					errs <- uow.WithTx(func(repos *Repositories) error {
+						if _, err := repos.TimeBank.GetBalance(user.ID); err != nil {
+							return err
+						}
+						entry := &models.TimeBankEntry{UserID: user.ID, Year: 2026, Month: 7, Type: models.TimeBankEntryAdjustment, Minutes: 1}
+						return repos.TimeBank.Create(entry)
+					})
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		failed := 0
+		for err := range errs {
+			if err != nil {
+				failed++
+				t.Log(err)
+			}
+		}
+		if failed > 0 {
+			t.Errorf("%d of %d concurrent transactions failed", failed, workers*txPerWorker)
+		}
+
+		var entries int64
+		db.Model(&models.TimeBankEntry{}).Where("user_id = ?", user.ID).Count(&entries)
+		if entries != workers*txPerWorker {
+			t.Errorf("time bank entries = %d, want %d", entries, workers*txPerWorker)
+		}
+	})
+}
@@ -2,8 +2,8 @@ package repository
 
 import (
 	"errors"
-	"time"
 	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/clock"
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -18,6 +18,7 @@ type UserMonthlyStatRepository interface {
 	GetByMonth(year, month int) ([]*models.UserMonthlyStat, error)
 	UpdatePlannedStats(userID uint, year, month, plannedDays, plannedMinutes int) error
 	UpdateWorkedStats(userID uint, year, month, workedDays, workedMinutes int) error
+	UpdateMonthTotals(userID uint, year, month int, totals models.MonthTotals) error
 	DeleteByUserID(userID uint) error
 	DeleteByID(id uint) error
 	Exists(userID uint, year, month int) (bool, error)
@@ -28,18 +29,19 @@ type UserMonthlyStatRepository interface {
 type GormUserMonthlyStatRepository struct {
 	db     *gorm.DB
 	logger *logrus.Logger
+	clock  clock.Clock
 }
 
-func NewGormUserMonthlyStatRepository(db *gorm.DB) (*GormUserMonthlyStatRepository, error) {
+func NewGormUserMonthlyStatRepository(db *gorm.DB, clk clock.Clock) (*GormUserMonthlyStatRepository, error) {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.TextFormatter{
 		FullTimestamp:   true,
 		TimestampFormat: "2006-01-02 15:04:05",
 	})
 
-	// Автомиграция
-	if err := db.AutoMigrate(&models.UserMonthlyStat{}); err != nil {
-		logger.WithError(err).Error("Failed to auto-migrate user_monthly_stats table")
+	// Схема создается миграциями (bot migrate)
+	if err := requireTables(db, &models.UserMonthlyStat{}); err != nil {
+		logger.WithError(err).Error("User monthly stats table is missing")
 		return nil, err
 	}
 
@@ -48,6 +50,7 @@ func NewGormUserMonthlyStatRepository(db *gorm.DB) (*GormUserMonthlyStatReposito
 	return &GormUserMonthlyStatRepository{
 		db:     db,
 		logger: logger,
+		clock:  clk,
 	}, nil
 }
 
@@ -130,7 +133,7 @@ func (r *GormUserMonthlyStatRepository) Update(stat *models.UserMonthlyStat) err
 
 	// Вычисляем статистику
 	stat.CalculateStats()
-	stat.UpdatedAt = time.Now()
+	stat.UpdatedAt = r.clock.Now()
 
 	result := r.db.Save(stat)
 	if result.Error != nil {
@@ -250,7 +253,7 @@ func (r *GormUserMonthlyStatRepository) UpdatePlannedStats(userID uint, year, mo
 	stat.PlannedDays = plannedDays
 	stat.PlannedMinutes = plannedMinutes
 	stat.CalculateStats()
-	stat.UpdatedAt = time.Now()
+	stat.UpdatedAt = r.clock.Now()
 
 	result := r.db.Save(stat)
 	if result.Error != nil {
@@ -298,7 +301,7 @@ func (r *GormUserMonthlyStatRepository) UpdateWorkedStats(userID uint, year, mon
 	stat.WorkedDays = workedDays
 	stat.WorkedMinutes = workedMinutes
 	stat.CalculateStats()
-	stat.UpdatedAt = time.Now()
+	stat.UpdatedAt = r.clock.Now()
 
 	result := r.db.Save(stat)
 	if result.Error != nil {
@@ -314,6 +317,53 @@ func (r *GormUserMonthlyStatRepository) UpdateWorkedStats(userID uint, year, mon
 	return nil
 }
 
+// UpdateMonthTotals обновляет фактические показатели с разбивкой по отсутствиям
+func (r *GormUserMonthlyStatRepository) UpdateMonthTotals(userID uint, year, month int, totals models.MonthTotals) error {
+	r.logger.WithFields(logrus.Fields{
+		"user_id":                    userID,
+		"year":                       year,
+		"month":                      month,
+		"worked_days":                totals.WorkedDays,
+		"worked_minutes":             totals.WorkedMinutes,
+		"credited_absence_minutes":   totals.CreditedAbsenceMinutes,
+		"uncredited_absence_minutes": totals.UncreditedAbsenceMinutes,
+	}).Debug("Updating month totals")
+
+	// Получаем существующую статистику
+	stat, err := r.GetByUserAndMonth(userID, year, month)
+	if err != nil {
+		return err
+	}
+
+	if stat == nil {
+		// Создаем новую запись если не существует
+		stat = &models.UserMonthlyStat{
+			UserID: userID,
+			Year:   year,
+			Month:  month,
+		}
+		stat.ApplyMonthTotals(totals)
+		return r.Create(stat)
+	}
+
+	// Обновляем существующую запись
+	stat.ApplyMonthTotals(totals)
+	stat.UpdatedAt = r.clock.Now()
+
+	result := r.db.Save(stat)
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to update month totals")
+		return result.Error
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"id":      stat.ID,
+		"user_id": userID,
+	}).Debug("Month totals updated successfully")
+
+	return nil
+}
+
 func (r *GormUserMonthlyStatRepository) DeleteByUserID(userID uint) error {
 	r.logger.WithField("user_id", userID).Info("Deleting all monthly stats for user")
 
@@ -387,8 +437,11 @@ func (r *GormUserMonthlyStatRepository) CreateForAllUsers(year, month, plannedDa
 		return result.Error
 	}
 
-	// Создаем статистику для каждого пользователя
+	// Создаем статистику для каждого пользователя, работавшего в этом месяце
 	for _, user := range users {
+		if !user.EmployedIn(year, month) {
+			continue
+		}
 		stat := &models.UserMonthlyStat{
 			UserID:         user.ID,
 			Year:           year,
@@ -435,7 +488,7 @@ func (r *GormUserMonthlyStatRepository) UpdateForAllUsers(year, month, plannedDa
 		Updates(map[string]interface{}{
 			"planned_days":    plannedDays,
 			"planned_minutes": plannedMinutes,
-			"updated_at":      time.Now(),
+			"updated_at":      r.clock.Now(),
 		})
 
 	if result.Error != nil {
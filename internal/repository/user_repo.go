@@ -12,9 +12,8 @@ type UserRepository struct {
 }
 
 func NewUserRepository(db *gorm.DB) (UserRepository, error) {
-	// Автомиграция - создает таблицы если их нет
-	err := db.AutoMigrate(&models.User{})
-	if err != nil {
+	// Схема создается миграциями (bot migrate)
+	if err := requireTables(db, &models.User{}); err != nil {
 		return UserRepository{}, err
 	}
 
@@ -39,7 +38,38 @@ func (r *UserRepository) Create(user *models.User) error {
 
 func (r *UserRepository) GetByChatID(chatID int64) (*models.User, error) {
 	var user models.User
-	result := r.db.Where("chat_id = ?", chatID).First(&user)
+	result := r.db.Preload("Team.Department").Where("chat_id = ?", chatID).First(&user)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepository) GetByID(id uint) (*models.User, error) {
+	var user models.User
+	result := r.db.Where("id = ?", id).First(&user)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+// GetByEmployeeNumber возвращает сотрудника по табельному номеру или nil
+func (r *UserRepository) GetByEmployeeNumber(number string) (*models.User, error) {
+	var user models.User
+	result := r.db.Where("employee_number = ?", number).First(&user)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -60,7 +90,7 @@ func (r *UserRepository) Update(user *models.User) error {
 		return errors.New("пользователь не найден")
 	}
 
-	result = r.db.Save(user)
+	result = r.db.Omit("Team").Save(user)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -104,6 +134,57 @@ func (r *UserRepository) GetAll() ([]*models.User, error) {
 	return users, nil
 }
 
+// GetActive возвращает одобренных сотрудников, которые не деактивированы
+func (r *UserRepository) GetActive() ([]*models.User, error) {
+	var users []*models.User
+	result := r.db.Where("termination_date IS NULL AND pending_approval = ?", false).Find(&users)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return users, nil
+}
+
+// GetPending возвращает профили, ожидающие одобрения, от старых к новым
+func (r *UserRepository) GetPending() ([]*models.User, error) {
+	var users []*models.User
+	result := r.db.Where("pending_approval = ?", true).Order("created_at, id").Find(&users)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) GetByTeamID(teamID uint) ([]*models.User, error) {
+	var users []*models.User
+	result := r.db.Where("team_id = ?", teamID).Find(&users)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) UpdateTeam(chatID int64, teamID *uint) error {
+	result := r.db.Model(&models.User{}).
+		Where("chat_id = ?", chatID).
+		Update("team_id", teamID)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("пользователь не найден")
+	}
+
+	return nil
+}
+
 func (r *UserRepository) UpdateRole(chatID int64, role models.Role) error {
 	result := r.db.Model(&models.User{}).
 		Where("chat_id = ?", chatID).
@@ -2,8 +2,8 @@ package repository
 
 import (
 	"errors"
-	"time"
 	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/clock"
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -24,18 +24,19 @@ type WorkScheduleRepository interface {
 type GormWorkScheduleRepository struct {
 	db     *gorm.DB
 	logger *logrus.Logger
+	clock  clock.Clock
 }
 
-func NewGormWorkScheduleRepository(db *gorm.DB) (*GormWorkScheduleRepository, error) {
+func NewGormWorkScheduleRepository(db *gorm.DB, clk clock.Clock) (*GormWorkScheduleRepository, error) {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.TextFormatter{
 		FullTimestamp:   true,
 		TimestampFormat: "2006-01-02 15:04:05",
 	})
 
-	// Автомиграция
-	if err := db.AutoMigrate(&models.WorkSchedule{}); err != nil {
-		logger.WithError(err).Error("Failed to auto-migrate work_schedules table")
+	// Схема создается миграциями (bot migrate)
+	if err := requireTables(db, &models.WorkSchedule{}); err != nil {
+		logger.WithError(err).Error("Work schedules table is missing")
 		return nil, err
 	}
 
@@ -44,6 +45,7 @@ func NewGormWorkScheduleRepository(db *gorm.DB) (*GormWorkScheduleRepository, er
 	return &GormWorkScheduleRepository{
 		db:     db,
 		logger: logger,
+		clock:  clk,
 	}, nil
 }
 
@@ -126,7 +128,7 @@ func (r *GormWorkScheduleRepository) Update(schedule *models.WorkSchedule) error
 
 	// Вычисляем total_minutes
 	schedule.TotalMinutes = schedule.CalculateTotalMinutes()
-	schedule.UpdatedAt = time.Now()
+	schedule.UpdatedAt = r.clock.Now()
 
 	result := r.db.Save(schedule)
 	if result.Error != nil {
@@ -250,7 +252,7 @@ func (r *GormWorkScheduleRepository) Exists(year, month int) (bool, error) {
 }
 
 func (r *GormWorkScheduleRepository) GetCurrentMonth() (*models.WorkSchedule, error) {
-	now := time.Now()
+	now := r.clock.Now()
 	year := now.Year()
 	month := int(now.Month())
 
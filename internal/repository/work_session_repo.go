@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/clock"
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -16,14 +17,16 @@ type WorkSessionRepository interface {
 	GetActiveByUserID(userID uint) (*models.WorkSession, error)
 	GetCompletedByUserID(userID uint) (*models.WorkSession, error)
 	GetByUserAndDate(userID uint, date time.Time) (*models.WorkSession, error)
-	GetAllTodayByUserID(userID uint) (*[]models.WorkSession, error)
+	GetAllByUserAndDate(userID uint, date time.Time) (*[]models.WorkSession, error)
 	GetTodayByUserID(userID uint) (*models.WorkSession, error)
 	GetByUserID(userID uint, limit int) ([]*models.WorkSession, error)
 	GetByUserIDAndMonth(userID uint, year, month int) ([]*models.WorkSession, error)
+	GetByUserIDAndPeriod(userID uint, startDate, endDate time.Time) ([]*models.WorkSession, error)
 	CompleteSession(userID uint, clockOutTime time.Time) (uint, error)
 	DeleteByID(id uint) error
 	DeleteByUserID(userID uint) error
 	GetStatsByUserAndMonth(userID uint, year, month int) (int, int, error) // дни, минуты
+	GetTotalsByUserAndMonth(userID uint, year, month int) ([]models.SessionTypeTotals, error)
 	UserHasActiveSession(userID uint) (bool, error)
 	UserHasSessionToday(userID uint) (bool, error)
 	GetAbsenceDays(userID uint, startDate, endDate time.Time) ([]models.WorkSession, error)
@@ -35,26 +38,28 @@ type WorkSessionRepository interface {
 type GormWorkSessionRepository struct {
 	db     *gorm.DB
 	logger *logrus.Logger
+	clock  clock.Clock
 }
 
-func NewGormWorkSessionRepository(db *gorm.DB) (*GormWorkSessionRepository, error) {
+func NewGormWorkSessionRepository(db *gorm.DB, clk clock.Clock) (*GormWorkSessionRepository, error) {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.TextFormatter{
 		FullTimestamp:   true,
 		TimestampFormat: "2006-01-02 15:04:05",
 	})
 
-	// Автомиграция
-	if err := db.AutoMigrate(&models.WorkSession{}); err != nil {
-		logger.WithError(err).Error("Failed to auto-migrate work_sessions table")
+	// Схема создается миграциями (bot migrate)
+	if err := requireTables(db, &models.WorkSession{}); err != nil {
+		logger.WithError(err).Error("Work sessions table is missing")
 		return nil, err
 	}
 
 	logger.Info("Work session repository initialized")
 
 	return &GormWorkSessionRepository{
-		db:     db,
+		db:     withLocation(db, clk.Location()),
 		logger: logger,
+		clock:  clk,
 	}, nil
 }
 
@@ -90,8 +95,8 @@ func (r *GormWorkSessionRepository) Create(session *models.WorkSession) error {
 	// Вычисляем поля
 	session.UpdateCalculatedFields()
 
-	tempDate := time.Date(session.Date.Year(), session.Date.Month(), session.Date.Day(), 0, 0, 0, 0, session.Date.Location())
-	session.Date = tempDate
+	session.Date = clock.DateOf(session.Date, r.clock.Location())
+	storeInUTC(session)
 
 	result := r.db.Create(session)
 	if result.Error != nil {
@@ -135,7 +140,8 @@ func (r *GormWorkSessionRepository) Update(session *models.WorkSession) error {
 
 	// Вычисляем поля
 	session.UpdateCalculatedFields()
-	session.UpdatedAt = time.Now()
+	session.UpdatedAt = r.clock.Now()
+	storeInUTC(session)
 
 	result := r.db.Save(session)
 	if result.Error != nil {
@@ -206,11 +212,9 @@ func (r *GormWorkSessionRepository) GetCompletedByUserID(userID uint) (*models.W
 
 func (r *GormWorkSessionRepository) GetByUserAndDate(userID uint, date time.Time) (*models.WorkSession, error) {
 	var session models.WorkSession
-	searchDate := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
-	result := r.db.Where("user_id = ? AND date = ?", userID, searchDate).First(&session)
+	dayStart, dayEnd := dayRange(date, r.clock.Location())
+	result := r.db.Where("user_id = ? AND date >= ? AND date < ?", userID, dayStart, dayEnd).First(&session)
 
-	logrus.Info("===\n=====\n=====\n\n\n", date.Format("2006-01-02"))
-	
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		r.logger.WithFields(logrus.Fields{
 			"user_id": userID,
@@ -227,21 +231,14 @@ func (r *GormWorkSessionRepository) GetByUserAndDate(userID uint, date time.Time
 	return &session, nil
 }
 
-func (r *GormWorkSessionRepository) GetAllTodayByUserID(userID uint) (*[]models.WorkSession, error) {
-	year, month, day := time.Now().Date()
-	startDate := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.Add(24 * time.Hour)
+// GetAllByUserAndDate возвращает все сессии пользователя за календарный день
+func (r *GormWorkSessionRepository) GetAllByUserAndDate(userID uint, date time.Time) (*[]models.WorkSession, error) {
+	startDate, endDate := dayRange(date, r.clock.Location())
 
 	var sessions []models.WorkSession
-	result := r.db.Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate).Find(&sessions)
-
-	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		r.logger.WithFields(logrus.Fields{
-			"user_id": userID,
-			"date":    time.Now().Format("2006-01-02"),
-		}).Debug("Work session not found for user/date")
-		return nil, nil
-	}
+	result := r.db.Where("user_id = ? AND date >= ? AND date < ?", userID, startDate, endDate).
+		Order("clock_in_time ASC").
+		Find(&sessions)
 
 	if result.Error != nil {
 		r.logger.WithError(result.Error).Error("Failed to get work session by user and date")
@@ -252,17 +249,15 @@ func (r *GormWorkSessionRepository) GetAllTodayByUserID(userID uint) (*[]models.
 }
 
 func (r *GormWorkSessionRepository) GetTodayByUserID(userID uint) (*models.WorkSession, error) {
-	year, month, day := time.Now().Date()
-	startDate := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.Add(24 * time.Hour)
+	startDate, endDate := dayRange(clock.Today(r.clock), r.clock.Location())
 
 	var session models.WorkSession
-	result := r.db.Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate).First(&session)
+	result := r.db.Where("user_id = ? AND date >= ? AND date < ?", userID, startDate, endDate).First(&session)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		r.logger.WithFields(logrus.Fields{
 			"user_id": userID,
-			"date":    time.Now().Format("2006-01-02"),
+			"date":    clock.Today(r.clock).Format("2006-01-02"),
 		}).Debug("Work session not found for user/date")
 		return nil, nil
 	}
@@ -298,16 +293,36 @@ func (r *GormWorkSessionRepository) GetByUserID(userID uint, limit int) ([]*mode
 	return sessions, nil
 }
 
+// GetByUserIDAndPeriod возвращает сессии пользователя с startDate по endDate включительно
+func (r *GormWorkSessionRepository) GetByUserIDAndPeriod(userID uint, startDate, endDate time.Time) ([]*models.WorkSession, error) {
+	var sessions []*models.WorkSession
+
+	periodStart, periodEnd := periodRange(startDate, endDate, r.clock.Location())
+
+	result := r.db.Where("user_id = ? AND date >= ? AND date < ?",
+		userID,
+		periodStart,
+		periodEnd).
+		Order("date ASC, clock_in_time ASC").
+		Find(&sessions)
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get work sessions by user and period")
+		return nil, result.Error
+	}
+
+	return sessions, nil
+}
+
 func (r *GormWorkSessionRepository) GetByUserIDAndMonth(userID uint, year, month int) ([]*models.WorkSession, error) {
 	var sessions []*models.WorkSession
 
-	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, -1)
+	startDate, endDate := monthRange(year, month, r.clock.Location())
 
-	result := r.db.Where("user_id = ? AND date BETWEEN ? AND ?",
+	result := r.db.Where("user_id = ? AND date >= ? AND date < ?",
 		userID,
-		startDate.Format("2006-01-02"),
-		endDate.Format("2006-01-02")).
+		startDate,
+		endDate).
 		Order("date DESC").
 		Find(&sessions)
 
@@ -346,7 +361,8 @@ func (r *GormWorkSessionRepository) CompleteSession(userID uint, clockOutTime ti
 	// Обновляем время выхода
 	session.ClockOutTime = &clockOutTime
 	session.UpdateCalculatedFields()
-	session.UpdatedAt = time.Now()
+	session.UpdatedAt = r.clock.Now()
+	storeInUTC(session)
 
 	result := r.db.Save(session)
 	if result.Error != nil {
@@ -405,16 +421,15 @@ func (r *GormWorkSessionRepository) GetStatsByUserAndMonth(userID uint, year, mo
 		Minutes int64
 	}
 
-	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, -1)
+	startDate, endDate := monthRange(year, month, r.clock.Location())
 
 	// Подсчитываем дни и минуты
 	result := r.db.Model(&models.WorkSession{}).
 		Select("COUNT(DISTINCT date) as days, COALESCE(SUM(worked_minutes), 0) as minutes").
-		Where("user_id = ? AND date BETWEEN ? AND ? AND status = ?",
+		Where("user_id = ? AND date >= ? AND date < ? AND status = ?",
 			userID,
-			startDate.Format("2006-01-02"),
-			endDate.Format("2006-01-02"),
+			startDate,
+			endDate,
 			models.StatusCompleted).
 		Scan(&data)
 
@@ -434,6 +449,54 @@ func (r *GormWorkSessionRepository) GetStatsByUserAndMonth(userID uint, year, mo
 	return int(data.Days), int(data.Minutes), nil
 }
 
+// GetTotalsByUserAndMonth возвращает показатели завершенных сессий за месяц с разбивкой по типу сессии
+func (r *GormWorkSessionRepository) GetTotalsByUserAndMonth(userID uint, year, month int) ([]models.SessionTypeTotals, error) {
+	var rows []struct {
+		SessionType     string
+		Days            int64
+		WorkedMinutes   int64
+		RequiredMinutes int64
+	}
+
+	startDate, endDate := monthRange(year, month, r.clock.Location())
+
+	result := r.db.Model(&models.WorkSession{}).
+		Select("session_type, COUNT(DISTINCT date) as days, " +
+			"COALESCE(SUM(worked_minutes), 0) as worked_minutes, " +
+			"COALESCE(SUM(required_minutes), 0) as required_minutes").
+		Where("user_id = ? AND date >= ? AND date < ? AND status = ?",
+			userID,
+			startDate,
+			endDate,
+			models.StatusCompleted).
+		Group("session_type").
+		Scan(&rows)
+
+	if result.Error != nil {
+		r.logger.WithError(result.Error).Error("Failed to get work session totals")
+		return nil, result.Error
+	}
+
+	totals := make([]models.SessionTypeTotals, 0, len(rows))
+	for _, row := range rows {
+		totals = append(totals, models.SessionTypeTotals{
+			SessionType:     row.SessionType,
+			Days:            int(row.Days),
+			WorkedMinutes:   int(row.WorkedMinutes),
+			RequiredMinutes: int(row.RequiredMinutes),
+		})
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"user_id": userID,
+		"year":    year,
+		"month":   month,
+		"types":   len(totals),
+	}).Debug("Retrieved work session totals")
+
+	return totals, nil
+}
+
 func (r *GormWorkSessionRepository) UserHasActiveSession(userID uint) (bool, error) {
 	session, err := r.GetActiveByUserID(userID)
 	if err != nil {
@@ -453,9 +516,9 @@ func (r *GormWorkSessionRepository) UserHasSessionToday(userID uint) (bool, erro
 // GetAbsenceDays возвращает дни отсутствия пользователя
 func (r *GormWorkSessionRepository) GetAbsenceDays(userID uint, startDate, endDate time.Time) ([]models.WorkSession, error) {
 	var sessions []models.WorkSession
-	err := r.db.Where("user_id = ? AND date BETWEEN ? AND ? AND session_type IN (?, ?, ?)", 
-		userID, startDate, endDate, 
-		models.SessionTypeVacation, models.SessionTypeSickLeave, models.SessionTypeDayOff).
+	periodStart, periodEnd := periodRange(startDate, endDate, r.clock.Location())
+	err := r.db.Where("user_id = ? AND date >= ? AND date < ? AND session_type <> ?",
+		userID, periodStart, periodEnd, models.SessionTypeWork).
 		Order("date DESC").
 		Find(&sessions).Error
 	return sessions, err
@@ -464,8 +527,9 @@ func (r *GormWorkSessionRepository) GetAbsenceDays(userID uint, startDate, endDa
 // GetAbsenceDaysByType возвращает дни отсутствия определенного типа
 func (r *GormWorkSessionRepository) GetAbsenceDaysByType(userID uint, sessionType string, startDate, endDate time.Time) ([]models.WorkSession, error) {
 	var sessions []models.WorkSession
-	err := r.db.Where("user_id = ? AND date BETWEEN ? AND ? AND session_type = ?", 
-		userID, startDate, endDate, sessionType).
+	periodStart, periodEnd := periodRange(startDate, endDate, r.clock.Location())
+	err := r.db.Where("user_id = ? AND date >= ? AND date < ? AND session_type = ?",
+		userID, periodStart, periodEnd, sessionType).
 		Order("date DESC").
 		Find(&sessions).Error
 	return sessions, err
@@ -474,13 +538,26 @@ func (r *GormWorkSessionRepository) GetAbsenceDaysByType(userID uint, sessionTyp
 // CheckDateAvailability проверяет, можно ли добавить сессию на эту дату
 func (r *GormWorkSessionRepository) CheckDateAvailability(userID uint, date time.Time) (bool, error) {
 	var count int64
+	dayStart, dayEnd := dayRange(date, r.clock.Location())
 	err := r.db.Model(&models.WorkSession{}).
-		Where("user_id = ? AND date = ?", userID, date).
+		Where("user_id = ? AND date >= ? AND date < ?", userID, dayStart, dayEnd).
 		Count(&count).Error
 	return count == 0, err
 }
 
 // CreateAbsenceSession создает сессию отсутствия
 func (r *GormWorkSessionRepository) CreateAbsenceSession(session *models.WorkSession) error {
+	session.Date = clock.DateOf(session.Date, r.clock.Location())
+	storeInUTC(session)
 	return r.db.Create(session).Error
+}
+
+// storeInUTC приводит моменты прихода и ухода к UTC перед записью.
+// Пояс сотрудника применяется только при отображении, дата сессии остается календарной.
+func storeInUTC(session *models.WorkSession) {
+	session.ClockInTime = session.ClockInTime.UTC()
+	if session.ClockOutTime != nil {
+		clockOut := session.ClockOutTime.UTC()
+		session.ClockOutTime = &clockOut
+	}
 }
\ No newline at end of file
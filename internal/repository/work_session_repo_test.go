@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"testing"
+	"time"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/clock"
+
+	"gorm.io/gorm"
+)
+
+func createCompletedSession(t *testing.T, repo WorkSessionRepository, userID uint, date time.Time, minutes int) {
+	t.Helper()
+
+	clockIn := date.Add(9 * time.Hour)
+	clockOut := clockIn.Add(time.Duration(minutes) * time.Minute)
+	session := &models.WorkSession{
+		UserID:          userID,
+		Date:            date,
+		ClockInTime:     clockIn,
+		ClockOutTime:    &clockOut,
+		RequiredMinutes: 480,
+		Status:          models.StatusCompleted,
+		SessionType:     models.SessionTypeWork,
+	}
+	if err := repo.Create(session); err != nil {
+		t.Fatalf("failed to create session for %s: %v", date.Format("2006-01-02"), err)
+	}
+}
+
+func TestWorkSessionMonthQueriesIncludeMonthBounds(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo, err := NewGormWorkSessionRepository(db, clock.System(nil))
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+		user := createTestUser(t, db, 1001)
+
+		createCompletedSession(t, repo, user.ID, localDate(2026, time.February, 28), 480)
+		createCompletedSession(t, repo, user.ID, localDate(2026, time.March, 1), 480)
+		createCompletedSession(t, repo, user.ID, localDate(2026, time.March, 31), 500)
+		createCompletedSession(t, repo, user.ID, localDate(2026, time.April, 1), 480)
+
+		sessions, err := repo.GetByUserIDAndMonth(user.ID, 2026, 3)
+		if err != nil {
+			t.Fatalf("GetByUserIDAndMonth: %v", err)
+		}
+		if len(sessions) != 2 {
+			t.Fatalf("GetByUserIDAndMonth returned %d sessions, want 2", len(sessions))
+		}
+		if !sessions[0].Date.Equal(localDate(2026, time.March, 31)) {
+			t.Errorf("first session date = %v, want 2026-03-31", sessions[0].Date)
+		}
+
+		days, minutes, err := repo.GetStatsByUserAndMonth(user.ID, 2026, 3)
+		if err != nil {
+			t.Fatalf("GetStatsByUserAndMonth: %v", err)
+		}
+		if days != 2 || minutes != 980 {
+			t.Errorf("GetStatsByUserAndMonth = %d days, %d minutes, want 2 days, 980 minutes", days, minutes)
+		}
+
+		totals, err := repo.GetTotalsByUserAndMonth(user.ID, 2026, 3)
+		if err != nil {
+			t.Fatalf("GetTotalsByUserAndMonth: %v", err)
+		}
+		if len(totals) != 1 || totals[0].Days != 2 || totals[0].WorkedMinutes != 980 {
+			t.Errorf("GetTotalsByUserAndMonth = %+v, want one work row with 2 days and 980 minutes", totals)
+		}
+	})
+}
+
+func TestWorkSessionDayQueriesIgnoreTimeOfDay(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo, err := NewGormWorkSessionRepository(db, clock.System(nil))
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+		user := createTestUser(t, db, 1002)
+
+		day := localDate(2026, time.May, 14)
+		createCompletedSession(t, repo, user.ID, day.Add(18*time.Hour), 480)
+
+		session, err := repo.GetByUserAndDate(user.ID, day.Add(23*time.Hour))
+		if err != nil {
+			t.Fatalf("GetByUserAndDate: %v", err)
+		}
+		if session == nil {
+			t.Fatal("GetByUserAndDate returned no session")
+		}
+		if !session.Date.Equal(day) || session.Date.Location() != time.Local {
+			t.Errorf("session date = %v, want local midnight %v", session.Date, day)
+		}
+
+		available, err := repo.CheckDateAvailability(user.ID, day.Add(12*time.Hour))
+		if err != nil {
+			t.Fatalf("CheckDateAvailability: %v", err)
+		}
+		if available {
+			t.Error("CheckDateAvailability reported a busy day as available")
+		}
+
+		available, err = repo.CheckDateAvailability(user.ID, day.AddDate(0, 0, 1))
+		if err != nil {
+			t.Fatalf("CheckDateAvailability: %v", err)
+		}
+		if !available {
+			t.Error("CheckDateAvailability reported a free day as busy")
+		}
+	})
+}
+
+func TestWorkSessionTodayUsesBusinessTimezone(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		// Пояс западнее UTC: PostgreSQL возвращает даты полночью UTC, т.е. предыдущим днем по Нью-Йорку
+		newYork := loadLocation(t, "America/New_York")
+		fake := clock.NewFake(time.Date(2026, time.March, 31, 20, 0, 0, 0, newYork))
+
+		repo, err := NewGormWorkSessionRepository(db, fake)
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+		user := createTestUser(t, db, 1004)
+
+		// 31 марта по Нью-Йорку, но уже 1 апреля по UTC
+		clockIn := time.Date(2026, time.March, 31, 21, 0, 0, 0, newYork)
+		session := &models.WorkSession{
+			UserID:          user.ID,
+			Date:            clock.DayOf(clockIn, newYork),
+			ClockInTime:     clockIn,
+			RequiredMinutes: 480,
+			Status:          models.StatusActive,
+			SessionType:     models.SessionTypeWork,
+		}
+		if err := repo.Create(session); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		fake.Set(clockIn.Add(2 * time.Hour))
+		today, err := repo.GetTodayByUserID(user.ID)
+		if err != nil {
+			t.Fatalf("GetTodayByUserID: %v", err)
+		}
+		if today == nil {
+			t.Fatal("GetTodayByUserID returned no session at 23:00 on the same business day")
+		}
+		if want := clock.Date(2026, time.March, 31, newYork); !today.Date.Equal(want) || today.Date.Location() != newYork {
+			t.Errorf("session date = %v, want %v", today.Date, want)
+		}
+
+		fake.Set(clockIn.Add(4 * time.Hour))
+		today, err = repo.GetTodayByUserID(user.ID)
+		if err != nil {
+			t.Fatalf("GetTodayByUserID: %v", err)
+		}
+		if today != nil {
+			t.Errorf("GetTodayByUserID returned %v after midnight, want no session", today.Date)
+		}
+
+		march, err := repo.GetByUserIDAndMonth(user.ID, 2026, 3)
+		if err != nil {
+			t.Fatalf("GetByUserIDAndMonth: %v", err)
+		}
+		april, err := repo.GetByUserIDAndMonth(user.ID, 2026, 4)
+		if err != nil {
+			t.Fatalf("GetByUserIDAndMonth: %v", err)
+		}
+		if len(march) != 1 || len(april) != 0 {
+			t.Errorf("sessions in March/April = %d/%d, want 1/0", len(march), len(april))
+		}
+	})
+}
+
+func TestWorkSessionAbsenceDaysByPeriod(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, db *gorm.DB) {
+		repo, err := NewGormWorkSessionRepository(db, clock.System(nil))
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+		user := createTestUser(t, db, 1003)
+
+		for day := 10; day <= 12; day++ {
+			date := localDate(2026, time.June, day)
+			session := &models.WorkSession{
+				UserID:          user.ID,
+				Date:            date,
+				ClockInTime:     date,
+				RequiredMinutes: 480,
+				Status:          models.StatusAbsent,
+				SessionType:     models.SessionTypeVacation,
+			}
+			if err := repo.CreateAbsenceSession(session); err != nil {
+				t.Fatalf("CreateAbsenceSession: %v", err)
+			}
+		}
+
+		sessions, err := repo.GetAbsenceDaysByType(user.ID, models.SessionTypeVacation,
+			localDate(2026, time.June, 11), localDate(2026, time.June, 12))
+		if err != nil {
+			t.Fatalf("GetAbsenceDaysByType: %v", err)
+		}
+		if len(sessions) != 2 {
+			t.Errorf("GetAbsenceDaysByType returned %d sessions, want 2", len(sessions))
+		}
+
+		sessions, err = repo.GetAbsenceDays(user.ID, localDate(2026, time.June, 1), localDate(2026, time.June, 30))
+		if err != nil {
+			t.Fatalf("GetAbsenceDays: %v", err)
+		}
+		if len(sessions) != 3 {
+			t.Errorf("GetAbsenceDays returned %d sessions, want 3", len(sessions))
+		}
+	})
+}
@@ -2,10 +2,11 @@
 package service
 
 import (
-	"fmt"
 	"time"
+	"work-schedule-bot/internal/i18n"
 	"work-schedule-bot/internal/models"
 	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
 
 	"github.com/sirupsen/logrus"
 )
@@ -16,7 +17,11 @@ type AbsenceService struct {
 	workSessionRepo      repository.WorkSessionRepository
 	userRepo             repository.UserRepository
 	workScheduleRepo     repository.WorkScheduleRepository
+	timeBankRepo         repository.TimeBankRepository
+	uow                  repository.UnitOfWork
 	nonWorkingDayService *NonWorkingDayService
+	policy               models.WorkPolicy
+	clock                clock.Clock
 	logger               *logrus.Logger
 }
 
@@ -26,193 +31,271 @@ func NewAbsenceService(
 	userMonthlyStatRepo repository.UserMonthlyStatRepository,
 	userRepo repository.UserRepository,
 	workScheduleRepo repository.WorkScheduleRepository,
+	timeBankRepo repository.TimeBankRepository,
+	uow repository.UnitOfWork,
 	nonWorkingDayService *NonWorkingDayService,
+	policy models.WorkPolicy,
+	clk clock.Clock,
 ) *AbsenceService {
 	return &AbsenceService{
 		absenceRepo:          absenceRepo,
 		workSessionRepo:      workSessionRepo,
 		userRepo:             userRepo,
 		workScheduleRepo:     workScheduleRepo,
+		timeBankRepo:         timeBankRepo,
+		uow:                  uow,
 		nonWorkingDayService: nonWorkingDayService,
 		userMonthlyStatRepo: userMonthlyStatRepo,
+		policy:               policy,
+		clock:                clk,
 		logger:               logrus.New(),
 	}
 }
 
 // AddVacation добавляет отпуск (только будущие даты)
-func (s *AbsenceService) AddVacation(userID uint, startDate, endDate time.Time) (*models.AbsencePeriod, error) {
+func (s *AbsenceService) AddVacation(userID uint, startDate, endDate time.Time, actor models.Actor) (*models.AbsencePeriod, error) {
 	// Нормализуем даты (оставляем только дату)
-	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.Local)
-	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, time.Local)
+	startDate = clock.DateOf(startDate, s.clock.Location())
+	endDate = clock.DateOf(endDate, s.clock.Location())
 
 	// Проверяем, что даты в будущем
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	today := clock.Today(s.clock)
 	if startDate.Before(today) {
-		return nil, fmt.Errorf("отпуск можно добавить только на будущие даты")
+		return nil, i18n.Errorf("absence.vacation_future_only")
 	}
 
-	return s.addAbsencePeriod(userID, startDate, endDate, models.AbsenceTypeVacation)
+	return s.addAbsencePeriod(userID, startDate, endDate, models.AbsenceTypeVacation, actor, nil)
 }
 
 // AddSickLeave добавляет больничный (можно на прошедшие дни)
-func (s *AbsenceService) AddSickLeave(userID uint, startDate, endDate time.Time) (*models.AbsencePeriod, error) {
+func (s *AbsenceService) AddSickLeave(userID uint, startDate, endDate time.Time, actor models.Actor) (*models.AbsencePeriod, error) {
 	// Нормализуем даты
-	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.Local)
-	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, time.Local)
+	startDate = clock.DateOf(startDate, s.clock.Location())
+	endDate = clock.DateOf(endDate, s.clock.Location())
 
-	return s.addAbsencePeriod(userID, startDate, endDate, models.AbsenceTypeSickLeave)
+	return s.addAbsencePeriod(userID, startDate, endDate, models.AbsenceTypeSickLeave, actor, nil)
 }
 
-// AddDayOff добавляет отгул (один день)
-func (s *AbsenceService) AddDayOff(userID uint, date time.Time) (*models.AbsencePeriod, error) {
+// AddDayOff добавляет отгул (один день) со списанием из банка времени
+func (s *AbsenceService) AddDayOff(userID uint, date time.Time, actor models.Actor) (*models.AbsencePeriod, error) {
 	// Нормализуем дату
-	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+	date = clock.DateOf(date, s.clock.Location())
 
-	return s.addAbsencePeriod(userID, date, date, models.AbsenceTypeDayOff)
+	// Списание из банка времени выполняется в той же транзакции, что и создание отгула
+	debit := func(repos *repository.Repositories) error {
+		balance, err := repos.TimeBank.GetBalance(userID)
+		if err != nil {
+			return i18n.Errorf("timebank.balance_get_failed", err)
+		}
+		dayMinutes := s.policy.WorkDayMinutes
+		if balance < dayMinutes {
+			return i18n.Errorf("timebank.insufficient", i18n.SignedMinutes(balance), i18n.Minutes(dayMinutes))
+		}
+
+		entry := &models.TimeBankEntry{
+			UserID:  userID,
+			Year:    date.Year(),
+			Month:   int(date.Month()),
+			Type:    models.TimeBankEntryDayOff,
+			Minutes: -dayMinutes,
+			Reason:  timeBankReason("timebank.reason_day_off", date),
+		}
+		if err := repos.TimeBank.Create(entry); err != nil {
+			return i18n.Errorf("timebank.debit_failed", err)
+		}
+		audit := models.NewAuditEntry(actor, models.AuditCreate, models.AuditEntityTimeBank, entry.ID).ForUser(userID)
+		return recordAudit(repos.Audit, audit, nil, entry)
+	}
+
+	return s.addAbsencePeriod(userID, date, date, models.AbsenceTypeDayOff, actor, debit)
 }
 
-// addAbsencePeriod общий метод добавления периода отсутствия
+// AddUnpaidLeave добавляет отпуск за свой счёт (только будущие даты)
+func (s *AbsenceService) AddUnpaidLeave(userID uint, startDate, endDate time.Time, actor models.Actor) (*models.AbsencePeriod, error) {
+	// Нормализуем даты
+	startDate = clock.DateOf(startDate, s.clock.Location())
+	endDate = clock.DateOf(endDate, s.clock.Location())
+
+	// Проверяем, что даты в будущем
+	today := clock.Today(s.clock)
+	if startDate.Before(today) {
+		return nil, i18n.Errorf("absence.unpaid_future_only")
+	}
+
+	return s.addAbsencePeriod(userID, startDate, endDate, models.AbsenceTypeUnpaidLeave, actor, nil)
+}
+
+// AddTruancy отмечает прогул (один рабочий день, отмечается администратором)
+func (s *AbsenceService) AddTruancy(userID uint, date time.Time, actor models.Actor) (*models.AbsencePeriod, error) {
+	// Нормализуем дату
+	date = clock.DateOf(date, s.clock.Location())
+
+	return s.addAbsencePeriod(userID, date, date, models.AbsenceTypeTruancy, actor, nil)
+}
+
+// addAbsencePeriod общий метод добавления периода отсутствия.
+// Период, сессии, статистика, запись журнала и дополнительные изменения (extra) сохраняются в одной транзакции.
 func (s *AbsenceService) addAbsencePeriod(
 	userID uint,
 	startDate, endDate time.Time,
 	absenceType string,
+	actor models.Actor,
+	extra func(repos *repository.Repositories) error,
 ) (*models.AbsencePeriod, error) {
 
 	// Проверяем, что даты корректны
 	if endDate.Before(startDate) {
-		return nil, fmt.Errorf("дата окончания не может быть раньше даты начала")
+		return nil, i18n.Errorf("period.end_before_start")
 	}
 
-	// Проверяем пересечения с существующими периодами
-	conflicts, err := s.absenceRepo.CheckPeriodConflict(userID, startDate, endDate)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка проверки конфликтов: %v", err)
-	}
-	if conflicts {
-		return nil, fmt.Errorf("период пересекается с существующим отпуском/больничным/отгулом")
-	}
-
-	// Проверяем, что все дни в периоде доступны (нет других сессий)
-	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
-		available, err := s.workSessionRepo.CheckDateAvailability(userID, date)
-		if err != nil {
-			return nil, fmt.Errorf("ошибка проверки доступности даты %s: %v", date.Format("02.01.2006"), err)
-		}
-		if !available {
-			return nil, fmt.Errorf("на дату %s уже есть запись", date.Format("02.01.2006"))
-		}
-	}
-
-	// Проверяем, что дни рабочие (для отпуска и отгула)
-	if absenceType == models.AbsenceTypeDayOff {
+	// Проверяем, что дни рабочие (для отгула и прогула)
+	if absenceType == models.AbsenceTypeDayOff || absenceType == models.AbsenceTypeTruancy {
 		for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
 			isNonWorking, err := s.nonWorkingDayService.IsNonWorkingDay(date)
 			if err != nil {
 				s.logger.Warnf("Failed to check if day %s is non-working: %v", date.Format("02.01.2006"), err)
 			} else if isNonWorking {
-				return nil, fmt.Errorf("дата %s является выходным днем", date.Format("02.01.2006"))
+				return nil, i18n.Errorf("absence.non_working_day", date)
 			}
 		}
 	}
-	
-	// Создаем период отсутствия
+
 	period := &models.AbsencePeriod{
 		UserID:    userID,
 		StartDate: startDate,
 		EndDate:   endDate,
 		Type:      absenceType,
 	}
+	createdCount := 0
 
-	err = s.absenceRepo.Create(period)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка создания периода: %v", err)
-	}
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		// Проверяем пересечения с существующими периодами
+		conflicts, err := repos.AbsencePeriods.CheckPeriodConflict(userID, startDate, endDate)
+		if err != nil {
+			return i18n.Errorf("absence.conflict_check_failed", err)
+		}
+		if conflicts {
+			return i18n.Errorf("absence.conflict")
+		}
+
+		// Проверяем, что все дни в периоде доступны (нет других сессий)
+		for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+			available, err := repos.WorkSessions.CheckDateAvailability(userID, date)
+			if err != nil {
+				return i18n.Errorf("absence.availability_check_failed", date, err)
+			}
+			if !available {
+				return i18n.Errorf("absence.date_taken", date)
+			}
+		}
+
+		// Создаем период отсутствия
+		if err := repos.AbsencePeriods.Create(period); err != nil {
+			return i18n.Errorf("absence.create_failed", err)
+		}
+
+		// Создаем work sessions для каждого дня периода
+		createdCount, err = s.createWorkSessionsForPeriod(repos, period)
+		if err != nil {
+			return i18n.Errorf("absence.sessions_create_failed", err)
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditCreate, models.AuditEntityAbsence, period.ID).ForUser(userID)
+		if err := recordAudit(repos.Audit, entry, nil, period); err != nil {
+			return err
+		}
 
-	// Создаем work sessions для каждого дня периода
-	createdCount, err := s.createWorkSessionsForPeriod(period)
+		if extra != nil {
+			return extra(repos)
+		}
+		return nil
+	})
 	if err != nil {
-		// Откатываем создание периода если не удалось создать сессии
-		s.absenceRepo.Delete(period.ID)
-		return nil, fmt.Errorf("ошибка создания рабочих сессий: %v", err)
+		return nil, err
 	}
 
 	s.logger.Infof("Created absence period ID %d with %d work sessions", period.ID, createdCount)
 	return period, nil
 }
 
-// createWorkSessionsForPeriod создает work sessions для каждого дня периода
-func (s *AbsenceService) createWorkSessionsForPeriod(period *models.AbsencePeriod) (int, error) {
-	var sessionType string
-	var requiredMinutes int = 520 // 8 часов 40 минут = 520 минут
-
-	// Определяем тип сессии и требуемое время
-	switch period.Type {
-	case models.AbsenceTypeVacation:
-		sessionType = models.SessionTypeVacation
-	case models.AbsenceTypeSickLeave:
-		sessionType = models.SessionTypeSickLeave
-	case models.AbsenceTypeDayOff:
-		sessionType = models.SessionTypeDayOff
-	default:
-		return 0, fmt.Errorf("неизвестный тип отсутствия: %s", period.Type)
-	}
-
+// createWorkSessionsForPeriod создает work sessions для каждого дня периода и пересчитывает затронутые месяцы
+func (s *AbsenceService) createWorkSessionsForPeriod(repos *repository.Repositories, period *models.AbsencePeriod) (int, error) {
 	createdCount := 0
+	var months []time.Time
 
 	// Создаем сессию для каждого дня периода
 	for date := period.StartDate; !date.After(period.EndDate); date = date.AddDate(0, 0, 1) {
 		nonWorkingDay, err := s.nonWorkingDayService.IsNonWorkingDay(date)
-		if err != nil{
-			return -1, fmt.Errorf("ошибка создания рабочих сессий: %v", err)
+		if err != nil {
+			return createdCount, i18n.Errorf("absence.non_working_check_failed", date, err)
 		}
-		if nonWorkingDay{
+		if nonWorkingDay {
 			continue
 		}
+
 		// Создаем сессию отсутствия
-		session := &models.WorkSession{
-			UserID:          period.UserID,
-			Date:            date,
-			SessionType:     sessionType,
-			ClockInTime:     date.Add(9 * time.Hour),                                  // Условное время начала 09:00
-			ClockOutTime:    &[]time.Time{date.Add(17*time.Hour + 40*time.Minute)}[0], // 17:40
-			RequiredMinutes: requiredMinutes,
-			WorkedMinutes:   requiredMinutes,
-			DiffMinutes:     0,
-			Status:          models.StatusCompleted,
-			AbsencePeriodID: &period.ID,
-		}
-
-		err = s.workSessionRepo.CreateAbsenceSession(session)
+		session, err := newAbsenceSession(period, date, s.policy.WorkDayMinutes)
 		if err != nil {
-			return createdCount, fmt.Errorf("ошибка создания сессии на %s: %v", date.Format("02.01.2006"), err)
+			return createdCount, err
 		}
-		if err := s.updateMonthlyStats(period.UserID, session); err != nil {
-			s.logger.WithError(err).Error("Failed to update monthly stats after clock out")
+
+		if err := repos.WorkSessions.CreateAbsenceSession(session); err != nil {
+			return createdCount, i18n.Errorf("absence.session_create_failed", date, err)
 		}
-		
-		
 		createdCount++
-		
-		
+
+		month := clock.MonthStart(date.Year(), date.Month(), date.Location())
+		if len(months) == 0 || !months[len(months)-1].Equal(month) {
+			months = append(months, month)
+		}
+	}
+
+	// Пересчитываем статистику один раз на каждый затронутый месяц
+	for _, month := range months {
+		if err := s.updateMonthlyStats(repos, period.UserID, month.Year(), int(month.Month())); err != nil {
+			return createdCount, i18n.Errorf("absence.stats_update_failed", int(month.Month()), month.Year(), err)
+		}
 	}
 
 	return createdCount, nil
 }
 
-func (s *AbsenceService) updateMonthlyStats(userID uint, session *models.WorkSession) error {
-	year := session.Date.Year()
-	month := int(session.Date.Month())
+// newAbsenceSession создает сессию дня отсутствия из периода с нормой дня dayMinutes. Отработанных
+// минут у сессии нет: засчитать ли день, решает режим учета при подсчете статистики.
+func newAbsenceSession(period *models.AbsencePeriod, date time.Time, dayMinutes int) (*models.WorkSession, error) {
+	var sessionType string
 
-	// Получаем статистику за месяц
-	days, minutes, err := s.workSessionRepo.GetStatsByUserAndMonth(userID, year, month)
-	if err != nil {
-		return err
+	// Определяем тип сессии и требуемое время
+	switch period.Type {
+	case models.AbsenceTypeVacation:
+		sessionType = models.SessionTypeVacation
+	case models.AbsenceTypeSickLeave:
+		sessionType = models.SessionTypeSickLeave
+	case models.AbsenceTypeDayOff:
+		sessionType = models.SessionTypeDayOff
+	case models.AbsenceTypeUnpaidLeave:
+		sessionType = models.SessionTypeUnpaidLeave
+	case models.AbsenceTypeTruancy:
+		sessionType = models.SessionTypeTruancy
+	default:
+		return nil, i18n.Errorf("absence.unknown_type", period.Type)
 	}
 
-	// Обновляем месячную статистику
-	err = s.userMonthlyStatRepo.UpdateWorkedStats(userID, year, month, days, minutes)
-	if err != nil {
+	return &models.WorkSession{
+		UserID:          period.UserID,
+		Date:            date,
+		SessionType:     sessionType,
+		ClockInTime:     clock.At(date, 9, 0),                    // Условное время начала 09:00
+		ClockOutTime:    &[]time.Time{clock.At(date, 17, 40)}[0], // 17:40
+		RequiredMinutes: dayMinutes,
+		Status:          models.StatusCompleted,
+		AbsencePeriodID: &period.ID,
+	}, nil
+}
+
+// updateMonthlyStats пересчитывает месячную статистику из сессий, отсутствий и графика
+func (s *AbsenceService) updateMonthlyStats(repos *repository.Repositories, userID uint, year, month int) error {
+	if _, err := newStatRules(s.policy, s.clock).rebuildMonthlyStat(repos, userID, year, month); err != nil {
 		return err
 	}
 
@@ -220,9 +303,7 @@ func (s *AbsenceService) updateMonthlyStats(userID uint, session *models.WorkSes
 		"user_id": userID,
 		"year":    year,
 		"month":   month,
-		"days":    days,
-		"minutes": minutes,
-	}).Info("Monthly stats updated after clock out")
+	}).Info("Monthly stats rebuilt after absence")
 
 	return nil
 }
@@ -232,6 +313,14 @@ func (s *AbsenceService) GetUserAbsences(userID uint) ([]models.AbsencePeriod, e
 	return s.absenceRepo.GetByUserID(userID)
 }
 
+// GetUserAbsencesForPeriod возвращает периоды отсутствия пользователя, пересекающиеся с указанным
+func (s *AbsenceService) GetUserAbsencesForPeriod(userID uint, startDate, endDate time.Time) ([]models.AbsencePeriod, error) {
+	if endDate.Before(startDate) {
+		return nil, i18n.Errorf("period.end_before_start")
+	}
+	return s.absenceRepo.GetByUserIDAndPeriod(userID, startDate, endDate)
+}
+
 // GetCurrentAbsence возвращает текущий период отсутствия пользователя
 func (s *AbsenceService) GetCurrentAbsence(userID uint, date time.Time) (*models.AbsencePeriod, error) {
 	return s.absenceRepo.GetCurrentAbsence(userID, date)
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/pkg/clock"
+
+	"gorm.io/gorm"
+)
+
+// countRows возвращает количество строк в таблицах отсутствий, сессий, банка времени и журнала
+func countRows(t *testing.T, db *gorm.DB) map[string]int64 {
+	t.Helper()
+
+	counts := make(map[string]int64)
+	for _, model := range []interface{}{&models.AbsencePeriod{}, &models.WorkSession{}, &models.TimeBankEntry{}, &models.AuditEntry{}} {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatalf("failed to parse model: %v", err)
+		}
+		var count int64
+		if err := db.Model(model).Count(&count).Error; err != nil {
+			t.Fatalf("failed to count %s: %v", stmt.Schema.Table, err)
+		}
+		counts[stmt.Schema.Table] = count
+	}
+	return counts
+}
+
+func TestAddDayOffDebitsTimeBank(t *testing.T) {
+	db := openTestDatabase(t)
+	clk := moscowClock(t, time.Date(2026, time.March, 10, 9, 0, 0, 0, time.UTC))
+	service := newTestAbsenceService(t, db, clk, models.DefaultWorkPolicy())
+	loc := clk.Location()
+	dayMinutes := models.DefaultWorkPolicy().WorkDayMinutes
+
+	user := models.User{ChatID: 1, FirstName: "Test", Role: models.RoleEmployee}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	deposit := func(minutes int) {
+		t.Helper()
+		entry := models.TimeBankEntry{UserID: user.ID, Year: 2026, Month: 2, Type: models.TimeBankEntryAdjustment, Minutes: minutes}
+		if err := db.Create(&entry).Error; err != nil {
+			t.Fatalf("failed to create time bank entry: %v", err)
+		}
+	}
+	balance := func() int {
+		t.Helper()
+		var total int
+		if err := db.Model(&models.TimeBankEntry{}).Where("user_id = ?", user.ID).Select("COALESCE(SUM(minutes), 0)").Scan(&total).Error; err != nil {
+			t.Fatalf("failed to read balance: %v", err)
+		}
+		return total
+	}
+
+	deposit(dayMinutes + 80)
+	period, err := service.AddDayOff(user.ID, clock.Date(2026, time.March, 11, loc), models.ChatActor(user.ChatID))
+	if err != nil {
+		t.Fatalf("AddDayOff: %v", err)
+	}
+	if got := balance(); got != 80 {
+		t.Errorf("balance after day off = %d, want 80", got)
+	}
+	var debit models.TimeBankEntry
+	if err := db.Where("user_id = ? AND type = ?", user.ID, models.TimeBankEntryDayOff).First(&debit).Error; err != nil {
+		t.Fatalf("day off debit was not written: %v", err)
+	}
+	if debit.Minutes != -dayMinutes || debit.Year != 2026 || debit.Month != 3 {
+		t.Errorf("debit = %+v, want -%d minutes in March 2026", debit, dayMinutes)
+	}
+	var sessions int64
+	db.Model(&models.WorkSession{}).Where("absence_period_id = ?", period.ID).Count(&sessions)
+	if sessions != 1 {
+		t.Errorf("day off created %d sessions, want 1", sessions)
+	}
+
+	// Остатка не хватает на день: отгул не создается, банк не меняется
+	before := countRows(t, db)
+	_, err = service.AddDayOff(user.ID, clock.Date(2026, time.March, 12, loc), models.ChatActor(user.ChatID))
+	if errorKey(err) != "timebank.insufficient" {
+		t.Fatalf("AddDayOff with balance 80 error = %v, want timebank.insufficient", err)
+	}
+	if after := countRows(t, db); !reflect.DeepEqual(after, before) {
+		t.Errorf("rows after refused day off = %v, want unchanged %v", after, before)
+	}
+	if got := balance(); got != 80 {
+		t.Errorf("balance after refused day off = %d, want 80", got)
+	}
+
+	// Остатка ровно на день хватает
+	deposit(dayMinutes - 80)
+	if _, err := service.AddDayOff(user.ID, clock.Date(2026, time.March, 13, loc), models.ChatActor(user.ChatID)); err != nil {
+		t.Fatalf("AddDayOff with balance of exactly one day: %v", err)
+	}
+	if got := balance(); got != 0 {
+		t.Errorf("balance after second day off = %d, want 0", got)
+	}
+}
+
+// sickLeavePolicy возвращает нормы по умолчанию с режимом учета mode для больничных
+func sickLeavePolicy(mode string) models.WorkPolicy {
+	policy := models.DefaultWorkPolicy()
+	policy.AbsenceCreditModes = map[string]string{models.AbsenceTypeSickLeave: mode}
+	return policy
+}
+
+func TestAbsenceCreditModes(t *testing.T) {
+	// Режим из политики заменяет режим по умолчанию только для своего типа
+	policy := sickLeavePolicy(models.AbsenceCreditNone)
+	if got := policy.AbsenceCreditMode(models.AbsenceTypeSickLeave); got != models.AbsenceCreditNone {
+		t.Errorf("sick leave credit mode = %q, want %q", got, models.AbsenceCreditNone)
+	}
+	if got := policy.AbsenceCreditMode(models.AbsenceTypeUnpaidLeave); got != models.AbsenceCreditReducePlan {
+		t.Errorf("unpaid leave credit mode = %q, want default %q", got, models.AbsenceCreditReducePlan)
+	}
+
+	// Дни отсутствия учитываются по норме дня из политики
+	dayMinutes := models.DefaultWorkPolicy().WorkDayMinutes
+	tests := []struct {
+		mode                 string
+		workedDays           int
+		creditedMinutes      int
+		uncreditedDays       int
+		uncreditedMinutes    int
+		planReductionMinutes int
+	}{
+		{mode: models.AbsenceCreditFull, workedDays: 2, creditedMinutes: 2 * dayMinutes},
+		{mode: models.AbsenceCreditReducePlan, uncreditedDays: 2, uncreditedMinutes: 2 * dayMinutes, planReductionMinutes: 2 * dayMinutes},
+		{mode: models.AbsenceCreditNone, uncreditedDays: 2, uncreditedMinutes: 2 * dayMinutes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			db := openTestDatabase(t)
+			clk := moscowClock(t, time.Date(2026, time.March, 10, 9, 0, 0, 0, time.UTC))
+			service := newTestAbsenceService(t, db, clk, sickLeavePolicy(tt.mode))
+			loc := clk.Location()
+
+			user := models.User{ChatID: 1, FirstName: "Test", Role: models.RoleEmployee}
+			if err := db.Create(&user).Error; err != nil {
+				t.Fatalf("failed to create user: %v", err)
+			}
+			schedule := models.WorkSchedule{Year: 2026, Month: 3, WorkDays: 22, WorkMinutesPerDay: dayMinutes, TotalMinutes: 22 * dayMinutes}
+			if err := db.Create(&schedule).Error; err != nil {
+				t.Fatalf("failed to create schedule: %v", err)
+			}
+
+			// Больничный на среду и четверг
+			if _, err := service.AddSickLeave(user.ID, clock.Date(2026, time.March, 11, loc), clock.Date(2026, time.March, 12, loc), models.SystemActor); err != nil {
+				t.Fatalf("AddSickLeave: %v", err)
+			}
+
+			var stat models.UserMonthlyStat
+			if err := db.Where("user_id = ? AND year = ? AND month = ?", user.ID, 2026, 3).First(&stat).Error; err != nil {
+				t.Fatalf("failed to read stat: %v", err)
+			}
+			if stat.WorkedDays != tt.workedDays || stat.WorkedMinutes != 0 || stat.CreditedAbsenceMinutes != tt.creditedMinutes ||
+				stat.UncreditedAbsenceDays != tt.uncreditedDays || stat.UncreditedAbsenceMinutes != tt.uncreditedMinutes ||
+				stat.PlanReductionMinutes != tt.planReductionMinutes {
+				t.Errorf("stat = %+v, want worked days %d, credited %d, uncredited %d days / %d min, plan reduction %d",
+					stat, tt.workedDays, tt.creditedMinutes, tt.uncreditedDays, tt.uncreditedMinutes, tt.planReductionMinutes)
+			}
+
+			// Недобор - план за вычетом уменьшения и засчитанного времени
+			wantDeficit := 22*dayMinutes - tt.planReductionMinutes - tt.creditedMinutes
+			if stat.DeficitMinutes != wantDeficit || stat.OvertimeMinutes != 0 {
+				t.Errorf("deficit = %d, overtime = %d; want deficit %d", stat.DeficitMinutes, stat.OvertimeMinutes, wantDeficit)
+			}
+		})
+	}
+}
+
+func TestAbsenceCreditModeChangeAppliesOnRecalculation(t *testing.T) {
+	db := openTestDatabase(t)
+	clk := moscowClock(t, time.Date(2026, time.March, 10, 9, 0, 0, 0, time.UTC))
+	loc := clk.Location()
+	dayMinutes := models.DefaultWorkPolicy().WorkDayMinutes
+
+	user := models.User{ChatID: 1, FirstName: "Test", Role: models.RoleEmployee}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	schedule := models.WorkSchedule{Year: 2026, Month: 3, WorkDays: 22, WorkMinutesPerDay: dayMinutes, TotalMinutes: 22 * dayMinutes}
+	if err := db.Create(&schedule).Error; err != nil {
+		t.Fatalf("failed to create schedule: %v", err)
+	}
+	readStat := func() models.UserMonthlyStat {
+		t.Helper()
+		var stat models.UserMonthlyStat
+		if err := db.Where("user_id = ? AND year = ? AND month = ?", user.ID, 2026, 3).First(&stat).Error; err != nil {
+			t.Fatalf("failed to read stat: %v", err)
+		}
+		return stat
+	}
+
+	// Больничный создается, пока он не засчитывается
+	absences := newTestAbsenceService(t, db, clk, sickLeavePolicy(models.AbsenceCreditNone))
+	if _, err := absences.AddSickLeave(user.ID, clock.Date(2026, time.March, 11, loc), clock.Date(2026, time.March, 12, loc), models.SystemActor); err != nil {
+		t.Fatalf("AddSickLeave: %v", err)
+	}
+	if stat := readStat(); stat.CreditedAbsenceMinutes != 0 || stat.UncreditedAbsenceDays != 2 {
+		t.Fatalf("stat before mode change = %+v, want 2 uncredited days", stat)
+	}
+
+	// После смены режима (перезапуска с новой конфигурацией) пересчет засчитывает уже созданные дни по норме
+	stats := newTestStatService(t, db, sickLeavePolicy(models.AbsenceCreditFull))
+	if _, err := stats.Recalculate([]*models.User{&user}, 2026, 3); err != nil {
+		t.Fatalf("Recalculate: %v", err)
+	}
+	if stat := readStat(); stat.WorkedDays != 2 || stat.CreditedAbsenceMinutes != 2*dayMinutes || stat.UncreditedAbsenceDays != 0 {
+		t.Errorf("stat after switching to credit = %+v, want 2 worked days and %d credited minutes", stat, 2*dayMinutes)
+	}
+
+	// И обратно: засчитанные дни перестают засчитываться
+	stats = newTestStatService(t, db, sickLeavePolicy(models.AbsenceCreditNone))
+	if _, err := stats.Recalculate([]*models.User{&user}, 2026, 3); err != nil {
+		t.Fatalf("Recalculate: %v", err)
+	}
+	if stat := readStat(); stat.WorkedDays != 0 || stat.CreditedAbsenceMinutes != 0 || stat.UncreditedAbsenceDays != 2 {
+		t.Errorf("stat after switching back to none = %+v, want 2 uncredited days", stat)
+	}
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// apiTokenPrefix отличает токены бота от других секретов в конфигурации клиентов
+const apiTokenPrefix = "wsb_"
+
+// lastUsedPrecision - как часто обновляется время последнего использования токена
+const lastUsedPrecision = time.Minute
+
+type APITokenService struct {
+	repo   repository.APITokenRepository
+	uow    repository.UnitOfWork
+	clock  clock.Clock
+	logger *logrus.Logger
+}
+
+func NewAPITokenService(repo repository.APITokenRepository, uow repository.UnitOfWork, clk clock.Clock) *APITokenService {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+
+	return &APITokenService{
+		repo:   repo,
+		uow:    uow,
+		clock:  clk,
+		logger: logger,
+	}
+}
+
+// hashAPIToken возвращает хэш токена, под которым он хранится в базе
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateToken выпускает новый токен. Значение токена возвращается только здесь.
+func (s *APITokenService) CreateToken(name string, createdBy int64) (string, *models.APIToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil, i18n.Errorf("apitoken.name_required")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, i18n.Errorf("apitoken.generate_failed", err)
+	}
+	value := apiTokenPrefix + hex.EncodeToString(secret)
+
+	token := &models.APIToken{
+		Name:      name,
+		TokenHash: hashAPIToken(value),
+		CreatedBy: createdBy,
+	}
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.APITokens.Create(token); err != nil {
+			return i18n.Errorf("apitoken.save_failed", err)
+		}
+
+		entry := models.NewAuditEntry(models.ChatActor(createdBy), models.AuditCreate, models.AuditEntityAPIToken, token.ID)
+		return recordAudit(repos.Audit, entry, nil, token)
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"token_id":   token.ID,
+		"name":       token.Name,
+		"created_by": createdBy,
+	}).Info("API token created")
+
+	return value, token, nil
+}
+
+// Authenticate возвращает активный токен по его значению или nil, если токен неизвестен или отозван
+func (s *APITokenService) Authenticate(value string) (*models.APIToken, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	token, err := s.repo.GetByHash(hashAPIToken(value))
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || !token.IsActive() {
+		return nil, nil
+	}
+
+	now := s.clock.Now()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedPrecision {
+		if err := s.repo.TouchLastUsed(token.ID, now); err != nil {
+			s.logger.WithError(err).Warn("Failed to update API token last use")
+		}
+	}
+
+	return token, nil
+}
+
+// GetTokens возвращает все токены, включая отозванные
+func (s *APITokenService) GetTokens() ([]*models.APIToken, error) {
+	return s.repo.GetAll()
+}
+
+// RevokeToken отзывает токен
+func (s *APITokenService) RevokeToken(id uint, actor models.Actor) (*models.APIToken, error) {
+	token, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, i18n.Errorf("apitoken.not_found", id)
+	}
+	if !token.IsActive() {
+		return nil, i18n.Errorf("apitoken.already_revoked", id)
+	}
+
+	before := *token
+	now := s.clock.Now()
+	token.RevokedAt = &now
+	err = s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.APITokens.Revoke(id, now); err != nil {
+			return err
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditUpdate, models.AuditEntityAPIToken, id)
+		return recordAudit(repos.Audit, entry, &before, token)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"token_id": id,
+		"name":     token.Name,
+	}).Info("API token revoked")
+
+	return token, nil
+}
+
+// FormatTokens форматирует список токенов
+func (s *APITokenService) FormatTokens(tr *i18n.Localizer, tokens []*models.APIToken) string {
+	if len(tokens) == 0 {
+		return tr.T("apitoken.list_empty")
+	}
+
+	var lines []string
+	lines = append(lines, tr.T("apitoken.list_title"))
+	lines = append(lines, "")
+
+	for _, token := range tokens {
+		status := "✅"
+		if !token.IsActive() {
+			status = tr.T("apitoken.revoked", tr.Date(*token.RevokedAt))
+		}
+
+		lastUsed := tr.T("apitoken.never_used")
+		if token.LastUsedAt != nil {
+			lastUsed = tr.T("apitoken.used_at", tr.DateTime(*token.LastUsedAt))
+		}
+
+		lines = append(lines, tr.T("apitoken.list_row",
+			token.ID, token.Name, status, tr.Date(token.CreatedAt), lastUsed))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxAuditChanges - сколько измененных полей записи показывается в чате
+const maxAuditChanges = 3
+
+// maxAuditValueLength - длина значения поля, после которой оно обрезается в чате
+const maxAuditValueLength = 40
+
+// AuditService показывает и выгружает журнал изменений.
+// Записи журнала создают сами сервисы в транзакциях изменений (см. recordAudit).
+type AuditService struct {
+	repo      repository.AuditRepository
+	userRepo  repository.UserRepository
+	tokenRepo repository.APITokenRepository
+	clock     clock.Clock
+	logger    *logrus.Logger
+}
+
+func NewAuditService(
+	repo repository.AuditRepository,
+	userRepo repository.UserRepository,
+	tokenRepo repository.APITokenRepository,
+	clk clock.Clock,
+) *AuditService {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+
+	return &AuditService{
+		repo:      repo,
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		clock:     clk,
+		logger:    logger,
+	}
+}
+
+// recordAudit записывает изменение сущности в журнал. before и after - состояние сущности
+// до и после изменения (nil - сущности нет). Обновление без изменений не записывается.
+func recordAudit(repo repository.AuditRepository, entry *models.AuditEntry, before, after interface{}) error {
+	var err error
+	if entry.Before, err = auditSnapshot(before); err != nil {
+		return i18n.Errorf("audit.record_failed", err)
+	}
+	if entry.After, err = auditSnapshot(after); err != nil {
+		return i18n.Errorf("audit.record_failed", err)
+	}
+
+	// Например, повторная генерация графиков при запуске бота
+	if entry.Action == models.AuditUpdate && entry.Before == entry.After {
+		return nil
+	}
+
+	if err := repo.Create(entry); err != nil {
+		return i18n.Errorf("audit.record_failed", err)
+	}
+	return nil
+}
+
+// auditSnapshot сохраняет поля сущности в JSON. Связанные сущности и время обновления
+// не сохраняются: они меняются независимо от самой записи.
+func auditSnapshot(value interface{}) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return "", nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return string(data), nil
+	}
+	for name, raw := range fields {
+		if name == "updated_at" || bytes.HasPrefix(raw, []byte("{")) || bytes.HasPrefix(raw, []byte("[")) {
+			delete(fields, name)
+		}
+	}
+
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Find возвращает записи журнала от новых к старым
+func (s *AuditService) Find(filter repository.AuditFilter) ([]*models.AuditEntry, error) {
+	entries, err := s.repo.Find(filter)
+	if err != nil {
+		return nil, i18n.Errorf("audit.get_failed", err)
+	}
+	return entries, nil
+}
+
+// auditNames - имена авторов и сотрудников для вывода журнала
+type auditNames struct {
+	usersByChatID map[int64]*models.User
+	usersByID     map[uint]*models.User
+	tokens        map[uint]string
+}
+
+func (s *AuditService) names() (*auditNames, error) {
+	users, err := s.userRepo.GetAll()
+	if err != nil {
+		return nil, i18n.Errorf("users.get_failed", err)
+	}
+	tokens, err := s.tokenRepo.GetAll()
+	if err != nil {
+		return nil, i18n.Errorf("audit.get_failed", err)
+	}
+
+	names := &auditNames{
+		usersByChatID: make(map[int64]*models.User, len(users)),
+		usersByID:     make(map[uint]*models.User, len(users)),
+		tokens:        make(map[uint]string, len(tokens)),
+	}
+	for _, user := range users {
+		names.usersByChatID[user.ChatID] = user
+		names.usersByID[user.ID] = user
+	}
+	for _, token := range tokens {
+		names.tokens[token.ID] = token.Name
+	}
+	return names, nil
+}
+
+func (n *auditNames) actor(tr *i18n.Localizer, actor models.Actor) string {
+	switch {
+	case actor.TokenID != 0:
+		return tr.T("audit.actor_token", n.tokens[actor.TokenID], actor.TokenID)
+	case actor.ChatID != 0:
+		if user := n.usersByChatID[actor.ChatID]; user != nil {
+			return tr.T("audit.actor_user", strings.TrimSpace(user.FirstName+" "+user.LastName), actor.ChatID)
+		}
+		return tr.T("audit.actor_chat", actor.ChatID)
+	default:
+		return tr.T("audit.actor_system")
+	}
+}
+
+func (n *auditNames) user(tr *i18n.Localizer, userID *uint) string {
+	if userID == nil {
+		return ""
+	}
+	if user := n.usersByID[*userID]; user != nil {
+		return tr.T("audit.actor_user", strings.TrimSpace(user.FirstName+" "+user.LastName), user.ChatID)
+	}
+	return tr.T("audit.deleted_user", *userID)
+}
+
+// FormatEntries форматирует записи журнала для чата: автор, действие и измененные поля
+func (s *AuditService) FormatEntries(tr *i18n.Localizer, entries []*models.AuditEntry) (string, error) {
+	if len(entries) == 0 {
+		return tr.T("audit.empty"), nil
+	}
+
+	names, err := s.names()
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tr.T("audit.title"))
+	for _, entry := range entries {
+		sb.WriteString("\n\n")
+		sb.WriteString(tr.T("audit.entry_header", tr.DateTime(entry.CreatedAt.In(s.clock.Location())), names.actor(tr, entry.Actor())))
+		sb.WriteString("\n")
+		sb.WriteString(tr.T("audit.entry_action", tr.T("audit.action."+entry.Action), tr.T("audit.entity."+entry.EntityType), entry.EntityID))
+		if target := names.user(tr, entry.UserID); target != "" {
+			sb.WriteString(" - " + target)
+		}
+
+		if entry.Action != models.AuditUpdate {
+			continue
+		}
+		changes := auditChanges(entry.Before, entry.After)
+		for i, change := range changes {
+			if i == maxAuditChanges {
+				sb.WriteString("\n" + tr.T("audit.more_changes", len(changes)-maxAuditChanges))
+				break
+			}
+			sb.WriteString("\n" + tr.T("audit.change", change.field, shortAuditValue(change.before), shortAuditValue(change.after)))
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// auditChange - измененное поле записи
+type auditChange struct {
+	field, before, after string
+}
+
+// auditChanges сравнивает состояния сущности до и после изменения
+func auditChanges(before, after string) []auditChange {
+	var beforeFields, afterFields map[string]json.RawMessage
+	json.Unmarshal([]byte(before), &beforeFields)
+	json.Unmarshal([]byte(after), &afterFields)
+
+	var changes []auditChange
+	for field, value := range afterFields {
+		if old, ok := beforeFields[field]; !ok || !bytes.Equal(old, value) {
+			changes = append(changes, auditChange{field: field, before: string(beforeFields[field]), after: string(value)})
+		}
+	}
+	for field, old := range beforeFields {
+		if _, ok := afterFields[field]; !ok {
+			changes = append(changes, auditChange{field: field, before: string(old), after: "null"})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].field < changes[j].field })
+	return changes
+}
+
+// shortAuditValue обрезает длинное значение поля, чтобы журнал поместился в сообщение
+func shortAuditValue(value string) string {
+	runes := []rune(value)
+	if len(runes) <= maxAuditValueLength {
+		return value
+	}
+	return string(runes[:maxAuditValueLength-1]) + "…"
+}
+
+// ExportCSV выгружает записи журнала в CSV с полными состояниями до и после изменения
+func (s *AuditService) ExportCSV(tr *i18n.Localizer, entries []*models.AuditEntry) ([]byte, error) {
+	names, err := s.names()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{
+		"id", "created_at", "actor_chat_id", "actor_token_id", "actor",
+		"action", "entity_type", "entity_id", "user_id", "user", "before", "after",
+	})
+	for _, entry := range entries {
+		userID := ""
+		if entry.UserID != nil {
+			userID = strconv.FormatUint(uint64(*entry.UserID), 10)
+		}
+		writer.Write([]string{
+			strconv.FormatUint(uint64(entry.ID), 10),
+			entry.CreatedAt.In(s.clock.Location()).Format("2006-01-02 15:04:05"),
+			strconv.FormatInt(entry.ActorChatID, 10),
+			strconv.FormatUint(uint64(entry.ActorTokenID), 10),
+			names.actor(tr, entry.Actor()),
+			entry.Action,
+			entry.EntityType,
+			strconv.FormatUint(uint64(entry.EntityID), 10),
+			userID,
+			names.user(tr, entry.UserID),
+			entry.Before,
+			entry.After,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("ошибка записи CSV: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"testing"
+	"time"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+)
+
+func TestAuditRecordsChangesOnly(t *testing.T) {
+	db := openTestDatabase(t)
+	clk := moscowClock(t, time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC))
+
+	timeBankRepo, err := repository.NewGormTimeBankRepository(db)
+	if err != nil {
+		t.Fatalf("failed to create time bank repository: %v", err)
+	}
+	statRepo, err := repository.NewGormUserMonthlyStatRepository(db, clk)
+	if err != nil {
+		t.Fatalf("failed to create stat repository: %v", err)
+	}
+	userRepo, err := repository.NewUserRepository(db)
+	if err != nil {
+		t.Fatalf("failed to create user repository: %v", err)
+	}
+	auditRepo, err := repository.NewGormAuditRepository(db, clk)
+	if err != nil {
+		t.Fatalf("failed to create audit repository: %v", err)
+	}
+	tokenRepo, err := repository.NewGormAPITokenRepository(db)
+	if err != nil {
+		t.Fatalf("failed to create token repository: %v", err)
+	}
+	service := NewTimeBankService(timeBankRepo, statRepo, userRepo, repository.NewGormUnitOfWork(db, clk), clk)
+	audit := NewAuditService(auditRepo, userRepo, tokenRepo, clk)
+
+	admin := models.User{ChatID: 300, FirstName: "Анна", Role: models.RoleAdmin}
+	if err := db.Create(&admin).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	// Второе обновление ничего не меняет и не попадает в журнал
+	for i := 0; i < 2; i++ {
+		if _, err := service.UpdateSettings(600, 300, admin.ChatID); err != nil {
+			t.Fatalf("UpdateSettings: %v", err)
+		}
+	}
+	if _, err := service.Adjust(admin.ID, 60, "переработка", 0); err != nil {
+		t.Fatalf("Adjust: %v", err)
+	}
+
+	entries, err := audit.Find(repository.AuditFilter{})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %d, want 2 (settings update and adjustment)", len(entries))
+	}
+
+	adjustment, settings := entries[0], entries[1]
+	if adjustment.Action != models.AuditCreate || adjustment.EntityType != models.AuditEntityTimeBank ||
+		adjustment.UserID == nil || *adjustment.UserID != admin.ID || !adjustment.Actor().IsSystem() {
+		t.Errorf("adjustment entry = %+v, want a system time bank create for user %d", adjustment, admin.ID)
+	}
+	if settings.Action != models.AuditUpdate || settings.ActorChatID != admin.ChatID || settings.Before == "" {
+		t.Errorf("settings entry = %+v, want an update by %d with the previous state", settings, admin.ChatID)
+	}
+
+	changes := auditChanges(settings.Before, settings.After)
+	fields := make(map[string]bool)
+	for _, change := range changes {
+		fields[change.field] = true
+	}
+	if !fields["max_monthly_surplus_minutes"] || !fields["max_monthly_deficit_minutes"] || fields["updated_at"] {
+		t.Errorf("changes = %+v, want both caps without updated_at", changes)
+	}
+
+	filtered, err := audit.Find(repository.AuditFilter{UserID: &admin.ID})
+	if err != nil {
+		t.Fatalf("Find by user: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].ID != adjustment.ID {
+		t.Errorf("entries for user = %+v, want only the adjustment", filtered)
+	}
+
+	formatted, err := audit.FormatEntries(i18n.For("ru"), entries)
+	if err != nil {
+		t.Fatalf("FormatEntries: %v", err)
+	}
+	if formatted == "" {
+		t.Error("FormatEntries returned an empty log")
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+	"work-schedule-bot/internal/backup"
+	"work-schedule-bot/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// BackupService создает резервные копии базы: по команде администратора и по расписанию
+type BackupService struct {
+	db     *gorm.DB
+	clock  clock.Clock
+	dir    string
+	keep   int
+	logger *logrus.Logger
+}
+
+// NewBackupService создает сервис. Пустой dir выключает копии по расписанию.
+func NewBackupService(db *gorm.DB, clk clock.Clock, dir string, keep int) *BackupService {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+
+	return &BackupService{
+		db:     db,
+		clock:  clk,
+		dir:    dir,
+		keep:   keep,
+		logger: logger,
+	}
+}
+
+// BackupSnapshot - снимок базы для отправки
+type BackupSnapshot struct {
+	Name      string
+	Data      []byte
+	CreatedAt time.Time
+}
+
+// Snapshot создает согласованный снимок базы во временном файле и возвращает его содержимое
+func (s *BackupService) Snapshot() (*BackupSnapshot, error) {
+	dir, err := os.MkdirTemp("", "work-schedule-backup-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	now := s.clock.Now()
+	name := backup.FileName(s.db, now)
+	path := filepath.Join(dir, name)
+	if err := backup.Snapshot(s.db, path, now); err != nil {
+		s.logger.WithError(err).Error("Failed to create backup snapshot")
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &BackupSnapshot{Name: name, Data: data, CreatedAt: now}, nil
+}
+
+// WriteScheduled сохраняет копию в каталог резервных копий, удаляя самые старые.
+// Без каталога ничего не делает и возвращает пустой путь.
+func (s *BackupService) WriteScheduled() (string, error) {
+	if s.dir == "" {
+		return "", nil
+	}
+
+	path, err := backup.WriteRotated(s.db, s.dir, s.keep, s.clock.Now())
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to write scheduled backup")
+		return "", err
+	}
+	s.logger.WithField("path", path).Info("Scheduled backup written")
+	return path, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
+
+	"gorm.io/gorm"
+)
+
+// moscowClock возвращает часы, стоящие на now, с Москвой в качестве пояса компании
+func moscowClock(t *testing.T, now time.Time) *clock.Fake {
+	t.Helper()
+
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("failed to load Europe/Moscow: %v", err)
+	}
+
+	fake := clock.NewFake(time.Date(2026, time.January, 1, 0, 0, 0, 0, moscow))
+	fake.Set(now)
+	return fake
+}
+
+func newTestAbsenceService(t *testing.T, db *gorm.DB, clk clock.Clock, policy models.WorkPolicy) *AbsenceService {
+	t.Helper()
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+	}
+	userRepo, err := repository.NewUserRepository(db)
+	must(err)
+	scheduleRepo, err := repository.NewGormWorkScheduleRepository(db, clk)
+	must(err)
+	statRepo, err := repository.NewGormUserMonthlyStatRepository(db, clk)
+	must(err)
+	sessionRepo, err := repository.NewGormWorkSessionRepository(db, clk)
+	must(err)
+	nonWorkingDayRepo, err := repository.NewGormNonWorkingDayRepository(db)
+	must(err)
+	absenceRepo, err := repository.NewGormAbsencePeriodRepository(db, clk)
+	must(err)
+	timeBankRepo, err := repository.NewGormTimeBankRepository(db)
+	must(err)
+
+	return NewAbsenceService(absenceRepo, sessionRepo, statRepo, userRepo, scheduleRepo, timeBankRepo,
+		repository.NewGormUnitOfWork(db, clk), NewNonWorkingDayService(nonWorkingDayRepo, clk), policy, clk)
+}
+
+func TestAddVacationUsesBusinessToday(t *testing.T) {
+	db := openTestDatabase(t)
+	// 22:30 по UTC 31 марта - уже 1 апреля в Москве
+	clk := moscowClock(t, time.Date(2026, time.March, 31, 22, 30, 0, 0, time.UTC))
+	service := newTestAbsenceService(t, db, clk, models.DefaultWorkPolicy())
+
+	user := models.User{ChatID: 1, FirstName: "Test", Role: models.RoleEmployee}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	loc := clk.Location()
+	if _, err := service.AddVacation(user.ID, clock.Date(2026, time.March, 31, loc), clock.Date(2026, time.March, 31, loc), models.SystemActor); err == nil {
+		t.Error("AddVacation accepted 31.03, which is yesterday in Moscow")
+	}
+
+	period, err := service.AddVacation(user.ID, clock.Date(2026, time.April, 1, loc), clock.Date(2026, time.April, 2, loc), models.SystemActor)
+	if err != nil {
+		t.Fatalf("AddVacation for today: %v", err)
+	}
+
+	var sessions []models.WorkSession
+	if err := db.Set(models.LocationSetting, loc).Where("absence_period_id = ?", period.ID).Order("date ASC").Find(&sessions).Error; err != nil {
+		t.Fatalf("failed to load sessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("vacation created %d sessions, want 2", len(sessions))
+	}
+	for _, session := range sessions {
+		if session.Date.Location() != loc || session.ClockInTime.In(loc).Hour() != 9 {
+			t.Errorf("session %v starts at %v, want 09:00 in Moscow", session.Date, session.ClockInTime.In(loc))
+		}
+	}
+
+	var stat models.UserMonthlyStat
+	if err := db.Where("user_id = ? AND year = ? AND month = ?", user.ID, 2026, 4).First(&stat).Error; err != nil {
+		t.Errorf("vacation was not counted in April stats: %v", err)
+	}
+}
+
+func TestClosePreviousMonthOnLastDayOfMonth(t *testing.T) {
+	db := openTestDatabase(t)
+	// 31 марта: прошлый месяц - февраль, даже если вычитание месяца из даты дает 3 марта
+	clk := moscowClock(t, time.Date(2026, time.March, 31, 12, 0, 0, 0, time.UTC))
+
+	timeBankRepo, err := repository.NewGormTimeBankRepository(db)
+	if err != nil {
+		t.Fatalf("failed to create time bank repository: %v", err)
+	}
+	statRepo, err := repository.NewGormUserMonthlyStatRepository(db, clk)
+	if err != nil {
+		t.Fatalf("failed to create stat repository: %v", err)
+	}
+	userRepo, err := repository.NewUserRepository(db)
+	if err != nil {
+		t.Fatalf("failed to create user repository: %v", err)
+	}
+	service := NewTimeBankService(timeBankRepo, statRepo, userRepo, repository.NewGormUnitOfWork(db, clk), clk)
+
+	user := models.User{ChatID: 1, FirstName: "Test", Role: models.RoleEmployee}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	stat := models.UserMonthlyStat{UserID: user.ID, Year: 2026, Month: 2, PlannedMinutes: 480, WorkedMinutes: 540}
+	if err := db.Create(&stat).Error; err != nil {
+		t.Fatalf("failed to create stat: %v", err)
+	}
+
+	closed, err := service.ClosePreviousMonth()
+	if err != nil {
+		t.Fatalf("ClosePreviousMonth: %v", err)
+	}
+	if closed != 1 {
+		t.Errorf("ClosePreviousMonth closed %d users, want 1", closed)
+	}
+
+	entry, err := timeBankRepo.GetMonthlyEntry(user.ID, 2026, 2)
+	if err != nil || entry == nil {
+		t.Fatalf("February entry = %v, %v, want a carry-over entry", entry, err)
+	}
+
+	if _, err := service.CloseMonth(2026, 3, 0); err == nil {
+		t.Error("CloseMonth accepted the current month")
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"time"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DialogService хранит состояния многошаговых диалогов
+type DialogService struct {
+	repo   repository.DialogStateRepository
+	clock  clock.Clock
+	logger *logrus.Logger
+}
+
+func NewDialogService(repo repository.DialogStateRepository, clk clock.Clock) *DialogService {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+
+	return &DialogService{
+		repo:   repo,
+		clock:  clk,
+		logger: logger,
+	}
+}
+
+// Start начинает диалог в чате, заменяя незавершенный
+func (s *DialogService) Start(chatID int64, flow, step string, timeout time.Duration, data interface{}) error {
+	state := &models.DialogState{
+		ChatID:         chatID,
+		Flow:           flow,
+		TimeoutSeconds: int(timeout / time.Second),
+	}
+
+	if err := s.save(state, step, data); err != nil {
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"chat_id": chatID,
+		"flow":    flow,
+		"step":    step,
+	}).Info("Dialog started")
+
+	return nil
+}
+
+// Advance переводит диалог на следующий шаг и продлевает время ожидания ответа
+func (s *DialogService) Advance(state *models.DialogState, step string, data interface{}) error {
+	return s.save(state, step, data)
+}
+
+func (s *DialogService) save(state *models.DialogState, step string, data interface{}) error {
+	state.Step = step
+	state.ExpiresAt = s.clock.Now().Add(state.Timeout())
+	if err := state.SetData(data); err != nil {
+		return i18n.Errorf("dialog.save_failed", err)
+	}
+
+	return s.repo.Save(state)
+}
+
+// Current возвращает активный диалог чата. Диалог с истекшим временем ожидания
+// тоже возвращается, чтобы обработчик мог сообщить пользователю о таймауте.
+func (s *DialogService) Current(chatID int64) (*models.DialogState, error) {
+	return s.repo.Get(chatID)
+}
+
+// Finish завершает диалог чата
+func (s *DialogService) Finish(chatID int64) error {
+	return s.repo.Delete(chatID)
+}
+
+// CleanupExpired удаляет диалоги, на которые так и не ответили
+func (s *DialogService) CleanupExpired() error {
+	deleted, err := s.repo.DeleteExpired(s.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		s.logger.WithField("count", deleted).Info("Expired dialogs removed")
+	}
+	return nil
+}
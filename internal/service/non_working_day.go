@@ -4,23 +4,25 @@ import (
 	"time"
 	"work-schedule-bot/internal/models"
 	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
 	"work-schedule-bot/pkg/weekends"
 
 	"github.com/sirupsen/logrus"
 )
 
 type NonWorkingDayService struct {
-	repo repository.NonWorkingDayRepository
+	repo  repository.NonWorkingDayRepository
+	clock clock.Clock
 }
 
-func NewNonWorkingDayService(repo repository.NonWorkingDayRepository) *NonWorkingDayService {
-	return &NonWorkingDayService{repo: repo}
+func NewNonWorkingDayService(repo repository.NonWorkingDayRepository, clk clock.Clock) *NonWorkingDayService {
+	return &NonWorkingDayService{repo: repo, clock: clk}
 }
 
 // LoadFromJSON загружает выходные дни из JSON файла в базу данных
 func (s *NonWorkingDayService) LoadFromJSON(filePath string) (int, error) {
 	// Парсим JSON
-	weekendDays, err := weekends.ParseWeekendsJSON(filePath)
+	weekendDays, err := weekends.ParseWeekendsJSON(filePath, s.clock.Location())
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,546 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxImportErrorsShown - сколько ошибок строк показывается в отчете об импорте
+const maxImportErrorsShown = 20
+
+// importHeaderNames - значения первой ячейки, по которым распознается строка заголовка
+var importHeaderNames = map[string]bool{
+	"user":         true,
+	"сотрудник":    true,
+	"пользователь": true,
+}
+
+// SessionImportError - ошибка в строке файла импорта
+type SessionImportError struct {
+	Line int
+	Err  error
+}
+
+// SessionImportReport - результат проверки или импорта файла с рабочими днями
+type SessionImportReport struct {
+	WorkDays    int
+	AbsenceDays int
+	Users       int
+	Months      int // пересчитанных месяцев статистики (после импорта)
+	Errors      []SessionImportError
+	Committed   bool // данные записаны в базу
+}
+
+// HasErrors сообщает, что в файле есть ошибки и импорт невозможен
+func (r *SessionImportReport) HasErrors() bool {
+	return len(r.Errors) > 0
+}
+
+// sessionImportRow - проверенная строка файла: рабочий день или день отсутствия
+type sessionImportRow struct {
+	Line        int
+	User        *models.User
+	Date        time.Time
+	ClockIn     time.Time
+	ClockOut    time.Time
+	AbsenceType string // пустой - рабочий день
+}
+
+// SessionImportService загружает историю рабочих дней из CSV.
+// Строка файла: сотрудник (ID чата или @username), дата, время прихода и ухода - или тип отсутствия.
+type SessionImportService struct {
+	uow    repository.UnitOfWork
+	policy models.WorkPolicy
+	clock  clock.Clock
+	logger *logrus.Logger
+}
+
+func NewSessionImportService(uow repository.UnitOfWork, policy models.WorkPolicy, clk clock.Clock) *SessionImportService {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+
+	return &SessionImportService{
+		uow:    uow,
+		policy: policy,
+		clock:  clk,
+		logger: logger,
+	}
+}
+
+// DryRun проверяет файл, ничего не записывая
+func (s *SessionImportService) DryRun(data []byte) (*SessionImportReport, error) {
+	records, err := readImportRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var report *SessionImportReport
+	err = s.uow.WithTx(func(repos *repository.Repositories) error {
+		var err error
+		report, _, err = s.validate(repos, records)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Import проверяет файл и, если ошибок нет, создает сессии и периоды отсутствия
+// и пересчитывает статистику затронутых месяцев. Все изменения - в одной транзакции.
+func (s *SessionImportService) Import(data []byte, actor models.Actor) (*SessionImportReport, error) {
+	records, err := readImportRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var report *SessionImportReport
+	err = s.uow.WithTx(func(repos *repository.Repositories) error {
+		var rows []*sessionImportRow
+		var err error
+		report, rows, err = s.validate(repos, records)
+		if err != nil || report.HasErrors() {
+			return err
+		}
+
+		months, err := s.createRows(repos, rows, actor)
+		if err != nil {
+			return err
+		}
+
+		for _, month := range months {
+			if _, err := newStatRules(s.policy, s.clock).rebuildMonthlyStat(repos, month.userID, month.year, month.month); err != nil {
+				return i18n.Errorf("absence.stats_update_failed", month.month, month.year, err)
+			}
+		}
+		report.Months = len(months)
+		report.Committed = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Committed {
+		s.logger.WithFields(logrus.Fields{
+			"work_days":    report.WorkDays,
+			"absence_days": report.AbsenceDays,
+			"users":        report.Users,
+			"months":       report.Months,
+		}).Info("Sessions imported")
+	}
+	return report, nil
+}
+
+// importRecord - строка CSV с номером строки файла
+type importRecord struct {
+	line   int
+	fields []string
+}
+
+// readImportRecords читает CSV. Разделитель - запятая или точка с запятой (выгрузка Excel),
+// строка заголовка и строки, начинающиеся с #, пропускаются.
+func readImportRecords(data []byte) ([]importRecord, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff")) // BOM из выгрузки Excel
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var records []importRecord
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, i18n.Errorf("import.invalid_csv", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		// Выгрузки таблиц дополняют строки пустыми столбцами до ширины заголовка
+		for len(fields) > 0 && fields[len(fields)-1] == "" {
+			fields = fields[:len(fields)-1]
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		if len(records) == 0 && importHeaderNames[strings.ToLower(fields[0])] {
+			continue
+		}
+
+		records = append(records, importRecord{line: line, fields: fields})
+	}
+
+	if len(records) == 0 {
+		return nil, i18n.Errorf("import.empty")
+	}
+	return records, nil
+}
+
+// validate разбирает и проверяет строки файла
+func (s *SessionImportService) validate(repos *repository.Repositories, records []importRecord) (*SessionImportReport, []*sessionImportRow, error) {
+	users, err := repos.Users.GetAll()
+	if err != nil {
+		return nil, nil, i18n.Errorf("users.get_failed", err)
+	}
+	byChatID := make(map[string]*models.User, len(users))
+	byUsername := make(map[string]*models.User, len(users))
+	for _, user := range users {
+		byChatID[strconv.FormatInt(user.ChatID, 10)] = user
+		if user.Username != "" {
+			byUsername[strings.ToLower(user.Username)] = user
+		}
+	}
+
+	report := &SessionImportReport{}
+	var rows []*sessionImportRow
+	accepted := make(map[string][]*sessionImportRow) // принятые строки по сотруднику и дате
+	importUsers := make(map[uint]bool)
+
+	for _, record := range records {
+		row, err := s.parseRow(repos, record, byChatID, byUsername)
+		if err == nil {
+			err = s.checkOverlaps(repos, row, accepted)
+		}
+		if err != nil {
+			report.Errors = append(report.Errors, SessionImportError{Line: record.line, Err: err})
+			continue
+		}
+
+		key := fmt.Sprintf("%d/%s", row.User.ID, row.Date.Format("2006-01-02"))
+		accepted[key] = append(accepted[key], row)
+		rows = append(rows, row)
+		importUsers[row.User.ID] = true
+		if row.AbsenceType == "" {
+			report.WorkDays++
+		} else {
+			report.AbsenceDays++
+		}
+	}
+
+	report.Users = len(importUsers)
+	return report, rows, nil
+}
+
+// parseRow разбирает строку: сотрудник, дата, приход, уход - или сотрудник, дата, тип отсутствия
+func (s *SessionImportService) parseRow(
+	repos *repository.Repositories,
+	record importRecord,
+	byChatID, byUsername map[string]*models.User,
+) (*sessionImportRow, error) {
+	fields := record.fields
+	if len(fields) != 3 && len(fields) != 4 {
+		return nil, i18n.Errorf("import.columns", len(fields))
+	}
+
+	user, ok := byChatID[fields[0]]
+	if !ok {
+		user, ok = byUsername[strings.ToLower(strings.TrimPrefix(fields[0], "@"))]
+	}
+	if !ok {
+		return nil, i18n.Errorf("import.unknown_user", fields[0])
+	}
+
+	date, err := parseImportDate(fields[1], s.clock.Location())
+	if err != nil {
+		return nil, err
+	}
+	if policy := s.policy; !policy.ValidYear(date.Year()) {
+		return nil, i18n.Errorf("date.invalid_year", policy.MinYear, policy.MaxYear)
+	}
+	if !date.Before(clock.Today(s.clock)) {
+		return nil, i18n.Errorf("import.not_past", date)
+	}
+
+	isNonWorking, err := repos.NonWorkingDays.IsNonWorkingDay(date)
+	if err != nil {
+		return nil, i18n.Errorf("absence.non_working_check_failed", date, err)
+	}
+	if isNonWorking {
+		return nil, i18n.Errorf("import.non_working_day", date)
+	}
+
+	row := &sessionImportRow{Line: record.line, User: user, Date: date}
+
+	if len(fields) == 3 {
+		if !models.IsValidAbsenceType(fields[2]) {
+			return nil, i18n.Errorf("import.unknown_absence_type", fields[2])
+		}
+		row.AbsenceType = fields[2]
+		return row, nil
+	}
+
+	// Время прихода и ухода - по часам сотрудника
+	loc := user.Location(s.clock.Location())
+	if row.ClockIn, err = parseImportTime(fields[2], date, loc); err != nil {
+		return nil, err
+	}
+	if row.ClockOut, err = parseImportTime(fields[3], date, loc); err != nil {
+		return nil, err
+	}
+	if !row.ClockOut.After(row.ClockIn) {
+		return nil, i18n.Errorf("import.clock_out_before_in")
+	}
+	return row, nil
+}
+
+// checkOverlaps проверяет, что строка не пересекается с сессиями и отсутствиями в базе
+// и с уже принятыми строками файла
+func (s *SessionImportService) checkOverlaps(repos *repository.Repositories, row *sessionImportRow, accepted map[string][]*sessionImportRow) error {
+	existing, err := repos.WorkSessions.GetAllByUserAndDate(row.User.ID, row.Date)
+	if err != nil {
+		return i18n.Errorf("absence.availability_check_failed", row.Date, err)
+	}
+	for _, session := range *existing {
+		if row.AbsenceType != "" || session.IsAbsence() || session.ClockOutTime == nil ||
+			(row.ClockIn.Before(*session.ClockOutTime) && session.ClockInTime.Before(row.ClockOut)) {
+			return i18n.Errorf("import.overlaps_session", row.Date)
+		}
+	}
+
+	conflict, err := repos.AbsencePeriods.CheckPeriodConflict(row.User.ID, row.Date, row.Date)
+	if err != nil {
+		return i18n.Errorf("absence.conflict_check_failed", err)
+	}
+	if conflict {
+		return i18n.Errorf("import.overlaps_absence", row.Date)
+	}
+
+	key := fmt.Sprintf("%d/%s", row.User.ID, row.Date.Format("2006-01-02"))
+	for _, other := range accepted[key] {
+		if row.AbsenceType != "" || other.AbsenceType != "" ||
+			(row.ClockIn.Before(other.ClockOut) && other.ClockIn.Before(row.ClockOut)) {
+			return i18n.Errorf("import.overlaps_row", other.Line)
+		}
+	}
+	return nil
+}
+
+// importMonth - месяц сотрудника, статистику которого нужно пересчитать
+type importMonth struct {
+	userID uint
+	year   int
+	month  int
+}
+
+// createRows создает рабочие сессии и периоды отсутствия. Дни отсутствия одного типа,
+// между которыми только нерабочие дни, объединяются в один период.
+func (s *SessionImportService) createRows(repos *repository.Repositories, rows []*sessionImportRow, actor models.Actor) ([]importMonth, error) {
+	seen := make(map[importMonth]bool)
+	var months []importMonth
+	touch := func(userID uint, date time.Time) {
+		month := importMonth{userID: userID, year: date.Year(), month: int(date.Month())}
+		if !seen[month] {
+			seen[month] = true
+			months = append(months, month)
+		}
+	}
+
+	var absences []*sessionImportRow
+	for _, row := range rows {
+		touch(row.User.ID, row.Date)
+		if row.AbsenceType != "" {
+			absences = append(absences, row)
+			continue
+		}
+
+		requiredMinutes, err := importRequiredMinutes(repos, row.Date, s.policy.DefaultDayMinutes)
+		if err != nil {
+			return nil, err
+		}
+		clockOut := row.ClockOut
+		session := &models.WorkSession{
+			UserID:          row.User.ID,
+			Date:            row.Date,
+			SessionType:     models.SessionTypeWork,
+			ClockInTime:     row.ClockIn,
+			ClockOutTime:    &clockOut,
+			RequiredMinutes: requiredMinutes,
+			Status:          models.StatusCompleted,
+		}
+		if err := repos.WorkSessions.Create(session); err != nil {
+			return nil, i18n.Errorf("import.create_failed", row.Line, err)
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditCreate, models.AuditEntitySession, session.ID).ForUser(session.UserID)
+		if err := recordAudit(repos.Audit, entry, nil, session); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(absences, func(i, j int) bool {
+		if absences[i].User.ID != absences[j].User.ID {
+			return absences[i].User.ID < absences[j].User.ID
+		}
+		return absences[i].Date.Before(absences[j].Date)
+	})
+
+	for start := 0; start < len(absences); {
+		end := start + 1
+		for end < len(absences) {
+			continuous, err := continuesAbsence(repos, absences[end-1], absences[end])
+			if err != nil {
+				return nil, err
+			}
+			if !continuous {
+				break
+			}
+			end++
+		}
+
+		if err := s.createImportedAbsence(repos, absences[start:end], actor); err != nil {
+			return nil, err
+		}
+		start = end
+	}
+
+	return months, nil
+}
+
+// continuesAbsence проверяет, продолжает ли next отсутствие prev: тот же сотрудник и тип,
+// а между днями только нерабочие
+func continuesAbsence(repos *repository.Repositories, prev, next *sessionImportRow) (bool, error) {
+	if prev.User.ID != next.User.ID || prev.AbsenceType != next.AbsenceType {
+		return false, nil
+	}
+	for date := prev.Date.AddDate(0, 0, 1); date.Before(next.Date); date = date.AddDate(0, 0, 1) {
+		isNonWorking, err := repos.NonWorkingDays.IsNonWorkingDay(date)
+		if err != nil {
+			return false, i18n.Errorf("absence.non_working_check_failed", date, err)
+		}
+		if !isNonWorking {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// createImportedAbsence создает период отсутствия и сессии его дней.
+// Отгул списывается из банка времени, как и при оформлении через бота.
+func (s *SessionImportService) createImportedAbsence(repos *repository.Repositories, days []*sessionImportRow, actor models.Actor) error {
+	first, last := days[0], days[len(days)-1]
+	period := &models.AbsencePeriod{
+		UserID:    first.User.ID,
+		StartDate: first.Date,
+		EndDate:   last.Date,
+		Type:      first.AbsenceType,
+	}
+	if err := repos.AbsencePeriods.Create(period); err != nil {
+		return i18n.Errorf("import.create_failed", first.Line, err)
+	}
+
+	audit := models.NewAuditEntry(actor, models.AuditCreate, models.AuditEntityAbsence, period.ID).ForUser(period.UserID)
+	if err := recordAudit(repos.Audit, audit, nil, period); err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		session, err := newAbsenceSession(period, day.Date, s.policy.WorkDayMinutes)
+		if err != nil {
+			return err
+		}
+		if err := repos.WorkSessions.CreateAbsenceSession(session); err != nil {
+			return i18n.Errorf("import.create_failed", day.Line, err)
+		}
+
+		if period.Type != models.AbsenceTypeDayOff {
+			continue
+		}
+		entry := &models.TimeBankEntry{
+			UserID:  period.UserID,
+			Year:    day.Date.Year(),
+			Month:   int(day.Date.Month()),
+			Type:    models.TimeBankEntryDayOff,
+			Minutes: -session.RequiredMinutes,
+			Reason:  timeBankReason("timebank.reason_day_off", day.Date),
+		}
+		if err := repos.TimeBank.Create(entry); err != nil {
+			return i18n.Errorf("timebank.debit_failed", err)
+		}
+
+		audit := models.NewAuditEntry(actor, models.AuditCreate, models.AuditEntityTimeBank, entry.ID).ForUser(period.UserID)
+		if err := recordAudit(repos.Audit, audit, nil, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importRequiredMinutes возвращает норму рабочего дня по графику месяца, без графика - defaultMinutes
+func importRequiredMinutes(repos *repository.Repositories, date time.Time, defaultMinutes int) (int, error) {
+	schedule, err := repos.WorkSchedules.GetByYearMonth(date.Year(), int(date.Month()))
+	if err != nil {
+		return 0, i18n.Errorf("schedule.get_failed", err)
+	}
+	if schedule == nil || schedule.WorkMinutesPerDay <= 0 {
+		return defaultMinutes, nil
+	}
+	return schedule.WorkMinutesPerDay, nil
+}
+
+// parseImportDate разбирает дату вида 02.01.2006 или 2006-01-02
+func parseImportDate(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range []string{"02.01.2006", "2006-01-02"} {
+		if date, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, i18n.Errorf("import.invalid_date", value)
+}
+
+// parseImportTime разбирает время ЧЧ:ММ дня date в часовом поясе loc
+func parseImportTime(value string, date time.Time, loc *time.Location) (time.Time, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return time.Time{}, i18n.Errorf("import.invalid_time", value)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), parsed.Hour(), parsed.Minute(), 0, 0, loc), nil
+}
+
+// FormatReport форматирует отчет о проверке или импорте файла
+func (s *SessionImportService) FormatReport(tr *i18n.Localizer, report *SessionImportReport) string {
+	var lines []string
+
+	switch {
+	case report.HasErrors():
+		lines = append(lines, tr.T("import.errors_title", len(report.Errors)))
+		for i, rowErr := range report.Errors {
+			if i == maxImportErrorsShown {
+				lines = append(lines, tr.T("import.more_errors", len(report.Errors)-maxImportErrorsShown))
+				break
+			}
+			lines = append(lines, tr.T("import.error_line", rowErr.Line, rowErr.Err))
+		}
+	case report.Committed:
+		lines = append(lines, tr.T("import.committed", report.WorkDays, report.AbsenceDays, report.Users, report.Months))
+	default:
+		lines = append(lines, tr.T("import.checked", report.WorkDays, report.AbsenceDays, report.Users))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// seedImportData создает двух сотрудников, график марта 2026 с выходными и рабочий день Петра 2 марта
+func seedImportData(t *testing.T, db *gorm.DB, loc *time.Location) (ivan, petr models.User) {
+	t.Helper()
+
+	ivan = models.User{ChatID: 101, Username: "Ivan", FirstName: "Иван", Role: models.RoleEmployee}
+	petr = models.User{ChatID: 102, Username: "petr", FirstName: "Петр", Role: models.RoleEmployee}
+	for _, user := range []*models.User{&ivan, &petr} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	schedule := models.WorkSchedule{Year: 2026, Month: 3, WorkDays: 22, WorkMinutesPerDay: 480, TotalMinutes: 22 * 480}
+	if err := db.Create(&schedule).Error; err != nil {
+		t.Fatalf("failed to create schedule: %v", err)
+	}
+	for _, day := range []int{7, 8} {
+		weekend := models.NonWorkingDay{Date: time.Date(2026, time.March, day, 0, 0, 0, 0, loc), Year: 2026, Month: 3, Day: day}
+		if err := db.Create(&weekend).Error; err != nil {
+			t.Fatalf("failed to create non-working day: %v", err)
+		}
+	}
+
+	date := time.Date(2026, time.March, 2, 0, 0, 0, 0, loc)
+	clockOut := date.Add(18 * time.Hour)
+	session := models.WorkSession{
+		UserID:          petr.ID,
+		Date:            date,
+		ClockInTime:     date.Add(9 * time.Hour),
+		ClockOutTime:    &clockOut,
+		RequiredMinutes: 480,
+		WorkedMinutes:   540,
+		Status:          models.StatusCompleted,
+		SessionType:     models.SessionTypeWork,
+	}
+	if err := db.Create(&session).Error; err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	return ivan, petr
+}
+
+func TestSessionImportReportsRowErrors(t *testing.T) {
+	db := openTestDatabase(t)
+	clk := moscowClock(t, time.Date(2026, time.March, 20, 12, 0, 0, 0, time.UTC))
+	seedImportData(t, db, clk.Location())
+	service := NewSessionImportService(repository.NewGormUnitOfWork(db, clk), models.DefaultWorkPolicy(), clk)
+
+	data := []byte(strings.Join([]string{
+		"user,date,clock_in,clock_out",
+		"@ivan,02.03.2026,09:00,18:00",
+		"@nobody,02.03.2026,09:00,18:00",
+		"102,02.03.2026,17:00,20:00",
+		"ivan,07.03.2026,09:00,18:00",
+		"ivan,02.03.2026,vacation",
+		"ivan,03.03.2026,18:00,09:00",
+		"ivan,25.03.2026,09:00,18:00",
+		"ivan,04.03.2026,holiday",
+		"ivan,2026/03/05,09:00,18:00",
+		"ivan,06.03.2026",
+		"petr,03.03.2026,09:00,13:00",
+		"petr,03.03.2026,14:00,18:00",
+	}, "\n"))
+
+	report, err := service.DryRun(data)
+	if err != nil {
+		t.Fatalf("DryRun: %v", err)
+	}
+
+	wantErrors := map[int]string{
+		3:  "import.unknown_user",
+		4:  "import.overlaps_session",
+		5:  "import.non_working_day",
+		6:  "import.overlaps_row",
+		7:  "import.clock_out_before_in",
+		8:  "import.not_past",
+		9:  "import.unknown_absence_type",
+		10: "import.invalid_date",
+		11: "import.columns",
+	}
+	if len(report.Errors) != len(wantErrors) {
+		t.Errorf("%d errors, want %d: %+v", len(report.Errors), len(wantErrors), report.Errors)
+	}
+	for _, rowErr := range report.Errors {
+		if key := errorKey(rowErr.Err); key != wantErrors[rowErr.Line] {
+			t.Errorf("line %d: error %s, want %s", rowErr.Line, key, wantErrors[rowErr.Line])
+		}
+	}
+	if report.WorkDays != 3 || report.AbsenceDays != 0 || report.Users != 2 {
+		t.Errorf("report = %+v, want 3 valid work days of 2 users", report)
+	}
+
+	// С ошибками импорт ничего не записывает
+	report, err = service.Import(data, models.ChatActor(1))
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if report.Committed {
+		t.Error("import with errors was committed")
+	}
+	var count int64
+	db.Model(&models.WorkSession{}).Count(&count)
+	if count != 1 {
+		t.Errorf("%d sessions after failed import, want only the existing one", count)
+	}
+}
+
+func TestSessionImportCreatesSessionsAndAbsences(t *testing.T) {
+	db := openTestDatabase(t)
+	clk := moscowClock(t, time.Date(2026, time.March, 20, 12, 0, 0, 0, time.UTC))
+	ivan, _ := seedImportData(t, db, clk.Location())
+	service := NewSessionImportService(repository.NewGormUnitOfWork(db, clk), models.DefaultWorkPolicy(), clk)
+
+	// Выгрузка Excel: BOM, точка с запятой, лишние пустые столбцы
+	data := []byte("\ufeffСотрудник;Дата;Приход;Уход\n" +
+		"101;02.03.2026;09:00;18:30\n" +
+		"@ivan;2026-03-03;10:00;17:00\n" +
+		"ivan;05.03.2026;vacation;\n" +
+		"ivan;06.03.2026;vacation;\n" +
+		"ivan;09.03.2026;vacation;\n" +
+		"ivan;10.03.2026;day_off;\n")
+
+	report, err := service.Import(data, models.ChatActor(1))
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if !report.Committed || report.HasErrors() {
+		t.Fatalf("report = %+v, want committed without errors", report)
+	}
+	if report.WorkDays != 2 || report.AbsenceDays != 4 || report.Users != 1 || report.Months != 1 {
+		t.Errorf("report = %+v, want 2 work days, 4 absence days, 1 user, 1 month", report)
+	}
+
+	var sessions []models.WorkSession
+	db.Where("user_id = ?", ivan.ID).Order("date").Find(&sessions)
+	if len(sessions) != 6 {
+		t.Fatalf("%d sessions, want 6", len(sessions))
+	}
+	if sessions[0].WorkedMinutes != 570 || sessions[0].RequiredMinutes != 480 || sessions[0].Status != models.StatusCompleted {
+		t.Errorf("first session = %+v, want 570 worked of 480 required", sessions[0])
+	}
+
+	// Отпуск через выходные - один период, отгул - отдельный
+	var periods []models.AbsencePeriod
+	db.Where("user_id = ?", ivan.ID).Order("start_date").Find(&periods)
+	if len(periods) != 2 || periods[0].Type != models.AbsenceTypeVacation || periods[0].EndDate.Day() != 9 {
+		t.Errorf("periods = %+v, want vacation 5-9 March and a day off", periods)
+	}
+
+	var entries []models.TimeBankEntry
+	db.Where("user_id = ?", ivan.ID).Find(&entries)
+	if len(entries) != 1 || entries[0].Type != models.TimeBankEntryDayOff || entries[0].Minutes != -models.DefaultWorkPolicy().WorkDayMinutes {
+		t.Errorf("time bank entries = %+v, want one day off debit", entries)
+	}
+
+	var stat models.UserMonthlyStat
+	if err := db.Where("user_id = ? AND year = 2026 AND month = 3", ivan.ID).First(&stat).Error; err != nil {
+		t.Fatalf("monthly stat was not recomputed: %v", err)
+	}
+	if stat.WorkedDays != 6 || stat.PlannedDays != 22 {
+		t.Errorf("stat = %d worked of %d planned days, want 6 of 22", stat.WorkedDays, stat.PlannedDays)
+	}
+
+	// Повторный импорт того же файла пересекается с созданными данными
+	report, err = service.DryRun(data)
+	if err != nil {
+		t.Fatalf("DryRun: %v", err)
+	}
+	if len(report.Errors) != 6 {
+		t.Errorf("%d errors on repeated import, want 6", len(report.Errors))
+	}
+}
+
+// errorKey возвращает ключ каталога ошибки
+func errorKey(err error) string {
+	var localized *i18n.Error
+	if errors.As(err, &localized) {
+		return localized.Key
+	}
+	return err.Error()
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+type TeamService struct {
+	teamRepo repository.TeamRepository
+	userRepo repository.UserRepository
+	uow      repository.UnitOfWork
+	logger   *logrus.Logger
+}
+
+func NewTeamService(teamRepo repository.TeamRepository, userRepo repository.UserRepository, uow repository.UnitOfWork) *TeamService {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+
+	return &TeamService{
+		teamRepo: teamRepo,
+		userRepo: userRepo,
+		uow:      uow,
+		logger:   logger,
+	}
+}
+
+// CreateDepartment создает отдел
+func (s *TeamService) CreateDepartment(name string, actor models.Actor) (*models.Department, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, i18n.Errorf("team.department_name_empty")
+	}
+
+	department := &models.Department{Name: name}
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.Teams.CreateDepartment(department); err != nil {
+			return i18n.Errorf("team.department_create_failed", err)
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditCreate, models.AuditEntityDepartment, department.ID)
+		return recordAudit(repos.Audit, entry, nil, department)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithField("department", name).Info("Department created")
+	return department, nil
+}
+
+// DeleteDepartment удаляет отдел (только если в нем нет команд)
+func (s *TeamService) DeleteDepartment(id uint, actor models.Actor) error {
+	teams, err := s.teamRepo.GetTeamsByDepartment(id)
+	if err != nil {
+		return i18n.Errorf("team.department_teams_get_failed", err)
+	}
+
+	if len(teams) > 0 {
+		return i18n.Errorf("team.department_has_teams", len(teams))
+	}
+
+	department, err := s.teamRepo.GetDepartmentByID(id)
+	if err != nil {
+		return i18n.Errorf("team.department_lookup_failed", err)
+	}
+
+	return s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.Teams.DeleteDepartment(id); err != nil {
+			return err
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditDelete, models.AuditEntityDepartment, id)
+		return recordAudit(repos.Audit, entry, department, nil)
+	})
+}
+
+// CreateTeam создает команду в отделе
+func (s *TeamService) CreateTeam(departmentID uint, name string, actor models.Actor) (*models.Team, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, i18n.Errorf("team.name_empty")
+	}
+
+	department, err := s.teamRepo.GetDepartmentByID(departmentID)
+	if err != nil {
+		return nil, i18n.Errorf("team.department_lookup_failed", err)
+	}
+
+	if department == nil {
+		return nil, i18n.Errorf("team.department_not_found", departmentID)
+	}
+
+	team := &models.Team{DepartmentID: departmentID, Name: name}
+	err = s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.Teams.CreateTeam(team); err != nil {
+			return i18n.Errorf("team.create_failed", err)
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditCreate, models.AuditEntityTeam, team.ID)
+		return recordAudit(repos.Audit, entry, nil, team)
+	})
+	if err != nil {
+		return nil, err
+	}
+	team.Department = *department
+
+	s.logger.WithFields(logrus.Fields{
+		"department": department.Name,
+		"team":       name,
+	}).Info("Team created")
+
+	return team, nil
+}
+
+// GetTeam возвращает команду по ID
+func (s *TeamService) GetTeam(id uint) (*models.Team, error) {
+	return s.teamRepo.GetTeamByID(id)
+}
+
+// DeleteTeam удаляет команду, участники остаются без команды
+func (s *TeamService) DeleteTeam(id uint, actor models.Actor) error {
+	team, err := s.teamRepo.GetTeamByID(id)
+	if err != nil {
+		return i18n.Errorf("team.lookup_failed", err)
+	}
+
+	return s.uow.WithTx(func(repos *repository.Repositories) error {
+		members, err := repos.Users.GetByTeamID(id)
+		if err != nil {
+			return i18n.Errorf("team.members_get_failed", err)
+		}
+
+		// Руководители команды теряют свою роль вместе с командой
+		for _, member := range members {
+			if !member.IsTeamLead() {
+				continue
+			}
+			if err := repos.Users.UpdateRole(member.ChatID, models.Role(models.RoleClient)); err != nil {
+				return i18n.Errorf("team.lead_reset_failed", err)
+			}
+
+			updated := *member
+			updated.Role = models.RoleClient
+			entry := models.NewAuditEntry(actor, models.AuditUpdate, models.AuditEntityUser, member.ID).ForUser(member.ID)
+			if err := recordAudit(repos.Audit, entry, member, &updated); err != nil {
+				return err
+			}
+		}
+
+		if err := repos.Teams.DeleteTeam(id); err != nil {
+			return err
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditDelete, models.AuditEntityTeam, id)
+		return recordAudit(repos.Audit, entry, team, nil)
+	})
+}
+
+// AssignUser добавляет пользователя в команду (teamID == 0 - исключить из команды)
+func (s *TeamService) AssignUser(targetChatID int64, teamID uint, actor models.Actor) (*models.User, error) {
+	user, err := s.userRepo.GetByChatID(targetChatID)
+	if err != nil {
+		return nil, i18n.Errorf("user.lookup_failed", err)
+	}
+
+	if user == nil {
+		return nil, i18n.Errorf("user.not_found")
+	}
+
+	var newTeamID *uint
+	if teamID != 0 {
+		team, err := s.teamRepo.GetTeamByID(teamID)
+		if err != nil {
+			return nil, i18n.Errorf("team.lookup_failed", err)
+		}
+		if team == nil {
+			return nil, i18n.Errorf("team.not_found", teamID)
+		}
+		newTeamID = &team.ID
+	} else if user.IsTeamLead() {
+		return nil, i18n.Errorf("team.lead_cannot_leave")
+	}
+
+	before := *user
+	user.TeamID = newTeamID
+	err = s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.Users.UpdateTeam(targetChatID, newTeamID); err != nil {
+			return i18n.Errorf("team.assign_failed", err)
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditUpdate, models.AuditEntityUser, user.ID).ForUser(user.ID)
+		return recordAudit(repos.Audit, entry, &before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"chat_id": targetChatID,
+		"team_id": teamID,
+	}).Info("User team updated")
+
+	return user, nil
+}
+
+// FormatTeams форматирует структуру отделов и команд
+func (s *TeamService) FormatTeams(tr *i18n.Localizer) (string, error) {
+	departments, err := s.teamRepo.GetDepartments()
+	if err != nil {
+		return "", i18n.Errorf("team.departments_get_failed", err)
+	}
+
+	if len(departments) == 0 {
+		return tr.T("team.departments_empty"), nil
+	}
+
+	var lines []string
+	lines = append(lines, tr.T("team.departments_title"))
+
+	for _, department := range departments {
+		lines = append(lines, "")
+		lines = append(lines, fmt.Sprintf("🏢 %s (ID: %d)", department.Name, department.ID))
+
+		if len(department.Teams) == 0 {
+			lines = append(lines, "   "+tr.T("team.no_teams"))
+			continue
+		}
+
+		for _, team := range department.Teams {
+			members, err := s.userRepo.GetByTeamID(team.ID)
+			if err != nil {
+				return "", i18n.Errorf("team.members_get_failed", err)
+			}
+			members = activeUsers(members)
+
+			var leads []string
+			for _, member := range members {
+				if member.IsTeamLead() {
+					leads = append(leads, strings.TrimSpace(member.FirstName+" "+member.LastName))
+				}
+			}
+
+			teamInfo := "   " + tr.T("team.info", team.Name, team.ID, len(members))
+			if len(leads) > 0 {
+				teamInfo += tr.T("team.info_leads", strings.Join(leads, ", "))
+			}
+			lines = append(lines, teamInfo)
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
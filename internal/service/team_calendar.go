@@ -0,0 +1,316 @@
+package service
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+	"time"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/calendar"
+	"work-schedule-bot/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TeamCalendar - отсутствия всех сотрудников за месяц
+type TeamCalendar struct {
+	Team             *models.Team // nil - вся компания
+	Year             int
+	Month            int
+	Days             int
+	TotalUsers       int
+	ThresholdPercent int
+
+	Users       []*models.User                  // сотрудники с отсутствиями в месяце
+	Periods     map[uint][]models.AbsencePeriod // периоды по ID пользователя
+	AbsentCount []int                           // количество отсутствующих по дням (индекс = день - 1)
+	NonWorking  []bool                          // выходные дни
+	Flagged     []bool                          // рабочие дни с превышением порога
+}
+
+type TeamCalendarService struct {
+	absenceRepo          repository.AbsencePeriodRepository
+	userRepo             repository.UserRepository
+	nonWorkingDayService *NonWorkingDayService
+	thresholdPercent     int
+	clock                clock.Clock
+	logger               *logrus.Logger
+}
+
+func NewTeamCalendarService(
+	absenceRepo repository.AbsencePeriodRepository,
+	userRepo repository.UserRepository,
+	nonWorkingDayService *NonWorkingDayService,
+	thresholdPercent int,
+	clk clock.Clock,
+) *TeamCalendarService {
+	return &TeamCalendarService{
+		absenceRepo:          absenceRepo,
+		userRepo:             userRepo,
+		nonWorkingDayService: nonWorkingDayService,
+		thresholdPercent:     thresholdPercent,
+		clock:                clk,
+		logger:               logrus.New(),
+	}
+}
+
+// GetThresholdPercent возвращает порог доли отсутствующих в процентах
+func (s *TeamCalendarService) GetThresholdPercent() int {
+	return s.thresholdPercent
+}
+
+// GetMonth собирает календарь отсутствий команды за месяц (team == nil - вся компания)
+func (s *TeamCalendarService) GetMonth(team *models.Team, year, month int) (*TeamCalendar, error) {
+	if month < 1 || month > 12 {
+		return nil, i18n.Errorf("date.invalid_month_number", month)
+	}
+
+	startDate := clock.MonthStart(year, time.Month(month), s.clock.Location())
+	endDate := startDate.AddDate(0, 1, -1)
+
+	users, periods, err := s.getScopedAbsences(team, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &TeamCalendar{
+		Team:             team,
+		Year:             year,
+		Month:            month,
+		Days:             endDate.Day(),
+		TotalUsers:       len(users),
+		ThresholdPercent: s.thresholdPercent,
+		Periods:          make(map[uint][]models.AbsencePeriod),
+		AbsentCount:      make([]int, endDate.Day()),
+		NonWorking:       make([]bool, endDate.Day()),
+		Flagged:          make([]bool, endDate.Day()),
+	}
+
+	for _, period := range periods {
+		if _, exists := cal.Periods[period.UserID]; !exists {
+			user := period.User
+			cal.Users = append(cal.Users, &user)
+		}
+		cal.Periods[period.UserID] = append(cal.Periods[period.UserID], period)
+
+		for date := maxDate(period.StartDate, startDate); !date.After(minDate(period.EndDate, endDate)); date = date.AddDate(0, 0, 1) {
+			cal.AbsentCount[date.Day()-1]++
+		}
+	}
+
+	sort.Slice(cal.Users, func(i, j int) bool {
+		return cal.Users[i].FirstName+cal.Users[i].LastName < cal.Users[j].FirstName+cal.Users[j].LastName
+	})
+
+	for day := 1; day <= cal.Days; day++ {
+		date := clock.Date(year, time.Month(month), day, s.clock.Location())
+		isNonWorking, err := s.nonWorkingDayService.IsNonWorkingDay(date)
+		if err != nil {
+			s.logger.Warnf("Failed to check if day %s is non-working: %v", date.Format("02.01.2006"), err)
+		}
+		cal.NonWorking[day-1] = isNonWorking
+		cal.Flagged[day-1] = !isNonWorking && s.exceedsThreshold(cal.AbsentCount[day-1], cal.TotalUsers)
+	}
+
+	return cal, nil
+}
+
+// GetOverloadedDays возвращает рабочие дни периода, в которые доля отсутствующих в команде превышает порог
+func (s *TeamCalendarService) GetOverloadedDays(team *models.Team, startDate, endDate time.Time) ([]time.Time, error) {
+	users, periods, err := s.getScopedAbsences(team, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var overloaded []time.Time
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		isNonWorking, err := s.nonWorkingDayService.IsNonWorkingDay(date)
+		if err != nil {
+			s.logger.Warnf("Failed to check if day %s is non-working: %v", date.Format("02.01.2006"), err)
+		}
+		if isNonWorking {
+			continue
+		}
+
+		absent := 0
+		for _, period := range periods {
+			if !date.Before(period.StartDate) && !date.After(period.EndDate) {
+				absent++
+			}
+		}
+
+		if s.exceedsThreshold(absent, len(users)) {
+			overloaded = append(overloaded, date)
+		}
+	}
+
+	return overloaded, nil
+}
+
+// getScopedAbsences возвращает сотрудников команды и их периоды отсутствия (team == nil - вся компания)
+func (s *TeamCalendarService) getScopedAbsences(team *models.Team, startDate, endDate time.Time) ([]*models.User, []models.AbsencePeriod, error) {
+	var users []*models.User
+	var err error
+	if team != nil {
+		users, err = s.userRepo.GetByTeamID(team.ID)
+	} else {
+		users, err = s.userRepo.GetAll()
+	}
+	if err != nil {
+		return nil, nil, i18n.Errorf("users.get_failed", err)
+	}
+	users = activeUsers(users)
+
+	periods, err := s.absenceRepo.GetOverlapping(startDate, endDate)
+	if err != nil {
+		return nil, nil, i18n.Errorf("absence.periods_get_failed", err)
+	}
+
+	if team == nil {
+		return users, periods, nil
+	}
+
+	members := make(map[uint]bool, len(users))
+	for _, user := range users {
+		members[user.ID] = true
+	}
+
+	var scoped []models.AbsencePeriod
+	for _, period := range periods {
+		if members[period.UserID] {
+			scoped = append(scoped, period)
+		}
+	}
+
+	return users, scoped, nil
+}
+
+// exceedsThreshold проверяет, превышает ли доля отсутствующих порог
+func (s *TeamCalendarService) exceedsThreshold(absent, total int) bool {
+	return total > 0 && absent*100 > total*s.thresholdPercent
+}
+
+// FormatCalendar форматирует календарь отсутствий в текст
+func (s *TeamCalendarService) FormatCalendar(tr *i18n.Localizer, cal *TeamCalendar) string {
+	var sb strings.Builder
+
+	sb.WriteString(tr.T("calendar.title", tr.MonthYear(cal.Year, time.Month(cal.Month))) + "\n")
+	if cal.Team != nil {
+		sb.WriteString(tr.T("calendar.team", cal.Team.Name) + "\n")
+	}
+	sb.WriteString(tr.T("calendar.users", cal.TotalUsers, cal.ThresholdPercent) + "\n\n")
+
+	if len(cal.Users) == 0 {
+		sb.WriteString(tr.T("calendar.nobody_absent"))
+		return sb.String()
+	}
+
+	for i, user := range cal.Users {
+		sb.WriteString(fmt.Sprintf("%d. %s %s\n", i+1, user.FirstName, user.LastName))
+		for _, period := range cal.Periods[user.ID] {
+			if period.StartDate.Equal(period.EndDate) {
+				sb.WriteString(fmt.Sprintf("   %s %s: %s\n",
+					getAbsenceTypeEmoji(period.Type), models.FormatAbsenceType(tr, period.Type),
+					tr.ShortDate(period.StartDate)))
+			} else {
+				sb.WriteString(fmt.Sprintf("   %s %s: %s - %s\n",
+					getAbsenceTypeEmoji(period.Type), models.FormatAbsenceType(tr, period.Type),
+					tr.ShortDate(period.StartDate), tr.ShortDate(period.EndDate)))
+			}
+		}
+	}
+
+	var flaggedDays []string
+	for day := 1; day <= cal.Days; day++ {
+		if cal.Flagged[day-1] {
+			flaggedDays = append(flaggedDays, tr.T("calendar.flagged_day", day, cal.AbsentCount[day-1], cal.TotalUsers))
+		}
+	}
+
+	if len(flaggedDays) > 0 {
+		sb.WriteString("\n" + tr.T("calendar.flagged_days", cal.ThresholdPercent, strings.Join(flaggedDays, ", ")))
+	} else {
+		sb.WriteString("\n" + tr.T("calendar.no_flagged_days", cal.ThresholdPercent))
+	}
+
+	return sb.String()
+}
+
+// RenderImage рисует календарь отсутствий в PNG
+func (s *TeamCalendarService) RenderImage(cal *TeamCalendar) ([]byte, error) {
+	grid := calendar.Grid{
+		Days:    cal.Days,
+		Weekend: cal.NonWorking,
+		Flagged: cal.Flagged,
+		Colors: map[string]color.RGBA{
+			models.AbsenceTypeVacation:    {66, 133, 244, 255},
+			models.AbsenceTypeSickLeave:   {251, 188, 5, 255},
+			models.AbsenceTypeDayOff:      {52, 168, 83, 255},
+			models.AbsenceTypeUnpaidLeave: {171, 71, 188, 255},
+			models.AbsenceTypeTruancy:     {120, 120, 120, 255},
+		},
+	}
+
+	for _, user := range cal.Users {
+		row := make([]string, cal.Days)
+		for _, period := range cal.Periods[user.ID] {
+			for day := 1; day <= cal.Days; day++ {
+				date := clock.Date(cal.Year, time.Month(cal.Month), day, s.clock.Location())
+				if !date.Before(period.StartDate) && !date.After(period.EndDate) {
+					row[day-1] = period.Type
+				}
+			}
+		}
+		grid.Rows = append(grid.Rows, row)
+	}
+
+	return calendar.RenderPNG(grid)
+}
+
+// FormatImageLegend возвращает подпись к изображению календаря
+func (s *TeamCalendarService) FormatImageLegend(tr *i18n.Localizer, cal *TeamCalendar) string {
+	var sb strings.Builder
+
+	sb.WriteString(tr.T("calendar.image_title", tr.MonthYear(cal.Year, time.Month(cal.Month))) + "\n")
+	for i, user := range cal.Users {
+		sb.WriteString(fmt.Sprintf("%d. %s %s\n", i+1, user.FirstName, user.LastName))
+	}
+	sb.WriteString("\n" + tr.T("calendar.legend", cal.ThresholdPercent))
+
+	return sb.String()
+}
+
+// getAbsenceTypeEmoji возвращает эмодзи для типа отсутствия
+func getAbsenceTypeEmoji(absenceType string) string {
+	switch absenceType {
+	case models.AbsenceTypeVacation:
+		return "🏖️"
+	case models.AbsenceTypeSickLeave:
+		return "🏥"
+	case models.AbsenceTypeDayOff:
+		return "🎯"
+	case models.AbsenceTypeUnpaidLeave:
+		return "💸"
+	case models.AbsenceTypeTruancy:
+		return "🚫"
+	default:
+		return "📋"
+	}
+}
+
+func minDate(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxDate(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
+)
+
+func TestTeamCalendarThreshold(t *testing.T) {
+	db := openTestDatabase(t)
+	clk := moscowClock(t, time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC))
+	loc := clk.Location()
+
+	userRepo, err := repository.NewUserRepository(db)
+	if err != nil {
+		t.Fatalf("failed to create user repository: %v", err)
+	}
+	absenceRepo, err := repository.NewGormAbsencePeriodRepository(db, clk)
+	if err != nil {
+		t.Fatalf("failed to create absence repository: %v", err)
+	}
+	nonWorkingDayRepo, err := repository.NewGormNonWorkingDayRepository(db)
+	if err != nil {
+		t.Fatalf("failed to create non-working day repository: %v", err)
+	}
+	service := NewTeamCalendarService(absenceRepo, userRepo, NewNonWorkingDayService(nonWorkingDayRepo, clk), 30, clk)
+
+	// 10 сотрудников, порог 30%: 2.03 отсутствуют двое (ниже порога), 3.03 - трое (ровно порог),
+	// 4.03 - четверо (выше порога)
+	var users []models.User
+	for i := 1; i <= 10; i++ {
+		user := models.User{ChatID: int64(i), FirstName: fmt.Sprintf("User%02d", i), Role: models.RoleEmployee}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		users = append(users, user)
+	}
+	absences := map[int]int{2: 2, 3: 3, 4: 4}
+	for day, absent := range absences {
+		for i := 0; i < absent; i++ {
+			date := clock.Date(2026, time.March, day, loc)
+			period := models.AbsencePeriod{UserID: users[i].ID, StartDate: date, EndDate: date, Type: models.AbsenceTypeVacation}
+			if err := db.Create(&period).Error; err != nil {
+				t.Fatalf("failed to create absence period: %v", err)
+			}
+		}
+	}
+
+	cal, err := service.GetMonth(nil, 2026, 3)
+	if err != nil {
+		t.Fatalf("GetMonth: %v", err)
+	}
+	if cal.TotalUsers != 10 {
+		t.Errorf("TotalUsers = %d, want 10", cal.TotalUsers)
+	}
+	for day := 2; day <= 4; day++ {
+		if cal.AbsentCount[day-1] != absences[day] {
+			t.Errorf("absent on %d.03 = %d, want %d", day, cal.AbsentCount[day-1], absences[day])
+		}
+		if want := day == 4; cal.Flagged[day-1] != want {
+			t.Errorf("%d.03 flagged = %v, want %v", day, cal.Flagged[day-1], want)
+		}
+	}
+
+	days, err := service.GetOverloadedDays(nil, clock.Date(2026, time.March, 1, loc), clock.Date(2026, time.March, 5, loc))
+	if err != nil {
+		t.Fatalf("GetOverloadedDays: %v", err)
+	}
+	if len(days) != 1 || !days[0].Equal(clock.Date(2026, time.March, 4, loc)) {
+		t.Errorf("overloaded days = %v, want only 04.03", days)
+	}
+}
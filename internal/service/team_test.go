@@ -0,0 +1,218 @@
+package service
+
+import (
+	"testing"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
+
+	"gorm.io/gorm"
+)
+
+// newTestTeamServices собирает сервисы пользователей и команд поверх базы db
+func newTestTeamServices(t *testing.T, db *gorm.DB) (*UserService, *TeamService) {
+	t.Helper()
+	clk := clock.System(nil)
+
+	userRepo, err := repository.NewUserRepository(db)
+	if err != nil {
+		t.Fatalf("failed to create user repository: %v", err)
+	}
+	teamRepo, err := repository.NewGormTeamRepository(db)
+	if err != nil {
+		t.Fatalf("failed to create team repository: %v", err)
+	}
+	scheduleRepo, err := repository.NewGormWorkScheduleRepository(db, clk)
+	if err != nil {
+		t.Fatalf("failed to create schedule repository: %v", err)
+	}
+	uow := repository.NewGormUnitOfWork(db, clk)
+	users := NewUserService(userRepo, scheduleRepo, newTestStatService(t, db, models.DefaultWorkPolicy()), uow, clk)
+	return users, NewTeamService(teamRepo, userRepo, uow)
+}
+
+func TestTeamLeadScopedAccess(t *testing.T) {
+	db := openTestDatabase(t)
+	users, teams := newTestTeamServices(t, db)
+
+	department, err := teams.CreateDepartment("Разработка", models.SystemActor)
+	if err != nil {
+		t.Fatalf("CreateDepartment: %v", err)
+	}
+	backend, err := teams.CreateTeam(department.ID, "Backend", models.SystemActor)
+	if err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+	frontend, err := teams.CreateTeam(department.ID, "Frontend", models.SystemActor)
+	if err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+
+	const (
+		lead       int64 = 10 // руководитель Backend
+		member     int64 = 20 // сотрудник Backend
+		outsider   int64 = 30 // сотрудник Frontend
+		loner      int64 = 40 // сотрудник без команды
+		admin      int64 = 50
+		hr         int64 = 60
+		leadNoTeam int64 = 70 // руководитель, исключенный из команды напрямую в базе
+	)
+	for _, user := range []*models.User{
+		{ChatID: lead, FirstName: "Лид", Role: models.RoleTeamLead, TeamID: &backend.ID},
+		{ChatID: member, FirstName: "Иван", Role: models.RoleEmployee, TeamID: &backend.ID},
+		{ChatID: outsider, FirstName: "Петр", Role: models.RoleEmployee, TeamID: &frontend.ID},
+		{ChatID: loner, FirstName: "Олег", Role: models.RoleEmployee},
+		{ChatID: admin, FirstName: "Анна", Role: models.RoleAdmin},
+		{ChatID: hr, FirstName: "Ольга", Role: models.RoleHR},
+		{ChatID: leadNoTeam, FirstName: "Глеб", Role: models.RoleTeamLead},
+	} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	check := func(actor, target int64, permission models.Permission, wantKey string) {
+		t.Helper()
+		user, err := users.GetManagedUser(actor, target, permission)
+		if wantKey == "" {
+			if err != nil || user == nil || user.ChatID != target {
+				t.Errorf("GetManagedUser(%d, %d, %s) = %v, %v; want the target", actor, target, permission, user, err)
+			}
+			return
+		}
+		if err == nil || errorKey(err) != wantKey {
+			t.Errorf("GetManagedUser(%d, %d, %s) error = %v, want %s", actor, target, permission, err, wantKey)
+		}
+	}
+
+	check(lead, member, models.PermStatsView, "")
+	check(lead, lead, models.PermStatsView, "")
+	check(lead, outsider, models.PermStatsView, "access.not_your_team")
+	check(lead, loner, models.PermStatsView, "access.not_your_team")
+	check(leadNoTeam, loner, models.PermStatsView, "access.not_your_team")
+	check(lead, member, models.PermTeamsManage, "access.denied")
+	check(member, member, models.PermStatsView, "access.denied")
+
+	// Роли уровня компании не ограничены командой
+	check(admin, outsider, models.PermStatsView, "")
+	check(admin, loner, models.PermTimeBankAdjust, "")
+	check(hr, loner, models.PermStatsView, "")
+
+	// /setteam меняет область руководителя
+	if _, err := teams.AssignUser(outsider, backend.ID, models.ChatActor(hr)); err != nil {
+		t.Fatalf("AssignUser: %v", err)
+	}
+	check(lead, outsider, models.PermStatsView, "")
+
+	if _, err := teams.AssignUser(outsider, 0, models.ChatActor(hr)); err != nil {
+		t.Fatalf("AssignUser without team: %v", err)
+	}
+	check(lead, outsider, models.PermStatsView, "access.not_your_team")
+
+	if _, err := teams.AssignUser(lead, 0, models.ChatActor(hr)); errorKey(err) != "team.lead_cannot_leave" {
+		t.Errorf("removing the lead from the team: error = %v, want team.lead_cannot_leave", err)
+	}
+	if _, err := teams.AssignUser(member, 999, models.ChatActor(hr)); errorKey(err) != "team.not_found" {
+		t.Errorf("assigning to an unknown team: error = %v, want team.not_found", err)
+	}
+	check(lead, member, models.PermStatsView, "")
+}
+
+func TestTeamLeadApprovesOwnTeam(t *testing.T) {
+	db := openTestDatabase(t)
+	users, teams := newTestTeamServices(t, db)
+
+	department, err := teams.CreateDepartment("Разработка", models.SystemActor)
+	if err != nil {
+		t.Fatalf("CreateDepartment: %v", err)
+	}
+	backend, err := teams.CreateTeam(department.ID, "Backend", models.SystemActor)
+	if err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+	frontend, err := teams.CreateTeam(department.ID, "Frontend", models.SystemActor)
+	if err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+
+	const (
+		lead      int64 = 10 // руководитель Backend
+		newcomer  int64 = 20 // новый профиль в Backend
+		outsider  int64 = 30 // новый профиль во Frontend
+		loner     int64 = 40 // новый профиль без команды
+		admin     int64 = 50
+		hr        int64 = 60
+		colleague int64 = 70 // сотрудник Backend
+	)
+	for _, user := range []*models.User{
+		{ChatID: lead, FirstName: "Лид", Role: models.RoleTeamLead, TeamID: &backend.ID},
+		{ChatID: newcomer, FirstName: "Иван", Role: models.RoleEmployee, TeamID: &backend.ID, PendingApproval: true},
+		{ChatID: outsider, FirstName: "Петр", Role: models.RoleEmployee, TeamID: &frontend.ID, PendingApproval: true},
+		{ChatID: loner, FirstName: "Олег", Role: models.RoleEmployee, PendingApproval: true},
+		{ChatID: admin, FirstName: "Анна", Role: models.RoleAdmin},
+		{ChatID: hr, FirstName: "Ольга", Role: models.RoleHR},
+		{ChatID: colleague, FirstName: "Глеб", Role: models.RoleEmployee, TeamID: &backend.ID},
+	} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	pending := func(actor int64) []int64 {
+		t.Helper()
+		list, err := users.GetPendingUsers(actor)
+		if err != nil {
+			t.Fatalf("GetPendingUsers(%d): %v", actor, err)
+		}
+		var chatIDs []int64
+		for _, user := range list {
+			chatIDs = append(chatIDs, user.ChatID)
+		}
+		return chatIDs
+	}
+
+	// Руководитель видит только профили своей команды, роли уровня компании - все
+	if got := pending(lead); len(got) != 1 || got[0] != newcomer {
+		t.Errorf("pending for the lead = %v, want [%d]", got, newcomer)
+	}
+	if got := pending(hr); len(got) != 3 {
+		t.Errorf("pending for HR = %v, want all 3", got)
+	}
+	if _, err := users.GetPendingUsers(colleague); errorKey(err) != "access.denied" {
+		t.Errorf("GetPendingUsers for an employee: error = %v, want access.denied", err)
+	}
+
+	if _, err := users.ApproveUser(outsider, models.ChatActor(lead)); errorKey(err) != "access.not_your_team" {
+		t.Errorf("lead approving another team: error = %v, want access.not_your_team", err)
+	}
+	if _, err := users.RejectUser(loner, models.ChatActor(lead)); errorKey(err) != "access.not_your_team" {
+		t.Errorf("lead rejecting a profile without team: error = %v, want access.not_your_team", err)
+	}
+	if _, err := users.ApproveUser(newcomer, models.ChatActor(colleague)); errorKey(err) != "access.denied" {
+		t.Errorf("employee approving: error = %v, want access.denied", err)
+	}
+
+	approved, err := users.ApproveUser(newcomer, models.ChatActor(lead))
+	if err != nil {
+		t.Fatalf("lead approving own team: %v", err)
+	}
+	if approved.PendingApproval {
+		t.Error("profile is still pending after approval")
+	}
+
+	// Добавление в команду передает профиль ее руководителю
+	if _, err := teams.AssignUser(loner, backend.ID, models.ChatActor(hr)); err != nil {
+		t.Fatalf("AssignUser: %v", err)
+	}
+	if got := pending(lead); len(got) != 1 || got[0] != loner {
+		t.Errorf("pending for the lead after /setteam = %v, want [%d]", got, loner)
+	}
+	if _, err := users.RejectUser(loner, models.ChatActor(lead)); err != nil {
+		t.Errorf("lead rejecting own team: %v", err)
+	}
+
+	// Роли уровня компании не ограничены командой
+	if _, err := users.ApproveUser(outsider, models.ChatActor(admin)); err != nil {
+		t.Errorf("admin approving: %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+type TimeBankService struct {
+	timeBankRepo        repository.TimeBankRepository
+	userMonthlyStatRepo repository.UserMonthlyStatRepository
+	userRepo            repository.UserRepository
+	uow                 repository.UnitOfWork
+	clock               clock.Clock
+	logger              *logrus.Logger
+}
+
+func NewTimeBankService(
+	timeBankRepo repository.TimeBankRepository,
+	userMonthlyStatRepo repository.UserMonthlyStatRepository,
+	userRepo repository.UserRepository,
+	uow repository.UnitOfWork,
+	clk clock.Clock,
+) *TimeBankService {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+
+	return &TimeBankService{
+		timeBankRepo:        timeBankRepo,
+		userMonthlyStatRepo: userMonthlyStatRepo,
+		userRepo:            userRepo,
+		uow:                 uow,
+		clock:               clk,
+		logger:              logger,
+	}
+}
+
+// GetBalance возвращает текущий баланс банка времени пользователя в минутах
+func (s *TimeBankService) GetBalance(userID uint) (int, error) {
+	return s.timeBankRepo.GetBalance(userID)
+}
+
+// GetLedger возвращает все записи банка времени пользователя
+func (s *TimeBankService) GetLedger(userID uint) ([]*models.TimeBankEntry, error) {
+	return s.timeBankRepo.GetByUserID(userID)
+}
+
+// GetSettings возвращает ограничения переноса итогов месяца
+func (s *TimeBankService) GetSettings() (*models.TimeBankSettings, error) {
+	return s.timeBankRepo.GetSettings()
+}
+
+// UpdateSettings обновляет ограничения переноса итогов месяца
+func (s *TimeBankService) UpdateSettings(maxSurplusMinutes, maxDeficitMinutes int, actorChatID int64) (*models.TimeBankSettings, error) {
+	settings, err := s.timeBankRepo.GetSettings()
+	if err != nil {
+		return nil, i18n.Errorf("timebank.settings_get_failed", err)
+	}
+
+	before := *settings
+	settings.MaxMonthlySurplusMinutes = maxSurplusMinutes
+	settings.MaxMonthlyDeficitMinutes = maxDeficitMinutes
+	settings.UpdatedBy = actorChatID
+
+	if !settings.IsValid() {
+		return nil, i18n.Errorf("timebank.negative_caps")
+	}
+
+	err = s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.TimeBank.SaveSettings(settings); err != nil {
+			return i18n.Errorf("timebank.settings_save_failed", err)
+		}
+
+		entry := models.NewAuditEntry(models.ChatActor(actorChatID), models.AuditUpdate, models.AuditEntityTimeBankSettings, settings.ID)
+		return recordAudit(repos.Audit, entry, &before, settings)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"max_surplus": maxSurplusMinutes,
+		"max_deficit": maxDeficitMinutes,
+		"updated_by":  actorChatID,
+	}).Info("Time bank settings updated")
+
+	return settings, nil
+}
+
+// CloseMonth переносит итоги месяца (переработку/недобор) в банк времени всех пользователей.
+// Повторный вызов пересчитывает уже перенесенные итоги. Итоги всех пользователей переносятся в одной транзакции.
+func (s *TimeBankService) CloseMonth(year, month int, actorChatID int64) (int, error) {
+	if month < 1 || month > 12 {
+		return 0, i18n.Errorf("date.invalid_month_number", month)
+	}
+
+	now := s.clock.Now()
+	currentMonthStart := clock.MonthStart(now.Year(), now.Month(), s.clock.Location())
+	monthStart := clock.MonthStart(year, time.Month(month), s.clock.Location())
+	if !monthStart.Before(currentMonthStart) {
+		return 0, i18n.Errorf("timebank.month_not_finished")
+	}
+
+	settings, err := s.timeBankRepo.GetSettings()
+	if err != nil {
+		return 0, i18n.Errorf("timebank.settings_get_failed", err)
+	}
+
+	closedCount := 0
+	err = s.uow.WithTx(func(repos *repository.Repositories) error {
+		closedCount = 0
+		stats, err := repos.MonthlyStats.GetByMonth(year, month)
+		if err != nil {
+			return i18n.Errorf("timebank.stats_get_failed", err)
+		}
+
+		for _, stat := range stats {
+			if err := s.closeUserMonth(repos, settings, stat, actorChatID); err != nil {
+				return err
+			}
+			closedCount++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"year":  year,
+		"month": month,
+		"users": closedCount,
+	}).Info("Month closed into time bank")
+
+	return closedCount, nil
+}
+
+// closeUserMonth переносит итог месяца одного пользователя, заменяя ранее перенесенный
+func (s *TimeBankService) closeUserMonth(
+	repos *repository.Repositories,
+	settings *models.TimeBankSettings,
+	stat *models.UserMonthlyStat,
+	actorChatID int64,
+) error {
+	year, month := stat.Year, stat.Month
+	minutes := settings.ClampMonthlyBalance(stat.MonthlyBalance())
+	reason := timeBankReason("timebank.reason_monthly", month, year)
+	if minutes != stat.MonthlyBalance() {
+		reason = timeBankReason("timebank.reason_monthly_capped", month, year, i18n.SignedMinutes(stat.MonthlyBalance()))
+	}
+
+	entry, err := repos.TimeBank.GetMonthlyEntry(stat.UserID, year, month)
+	if err != nil {
+		return i18n.Errorf("timebank.entry_get_failed", err)
+	}
+
+	audit := models.NewAuditEntry(models.ChatActor(actorChatID), models.AuditCreate, models.AuditEntityTimeBank, 0).ForUser(stat.UserID)
+	var before *models.TimeBankEntry
+	if entry == nil {
+		entry = &models.TimeBankEntry{
+			UserID:    stat.UserID,
+			Year:      year,
+			Month:     month,
+			Type:      models.TimeBankEntryMonthly,
+			Minutes:   minutes,
+			Reason:    reason,
+			CreatedBy: actorChatID,
+		}
+		err = repos.TimeBank.Create(entry)
+	} else {
+		previous := *entry
+		before = &previous
+		audit.Action = models.AuditUpdate
+		entry.Minutes = minutes
+		entry.Reason = reason
+		entry.CreatedBy = actorChatID
+		err = repos.TimeBank.Update(entry)
+	}
+	if err != nil {
+		return i18n.Errorf("timebank.transfer_failed", err)
+	}
+
+	audit.EntityID = entry.ID
+	return recordAudit(repos.Audit, audit, before, entry)
+}
+
+// ClosePreviousMonth переносит итоги прошлого месяца в банк времени
+func (s *TimeBankService) ClosePreviousMonth() (int, error) {
+	now := s.clock.Now()
+	prev := clock.MonthStart(now.Year(), now.Month(), s.clock.Location()).AddDate(0, -1, 0)
+	return s.CloseMonth(prev.Year(), int(prev.Month()), 0)
+}
+
+// Adjust создает ручную корректировку банка времени
+func (s *TimeBankService) Adjust(userID uint, minutes int, reason string, actorChatID int64) (*models.TimeBankEntry, error) {
+	if minutes == 0 {
+		return nil, i18n.Errorf("timebank.zero_adjustment")
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, i18n.Errorf("timebank.reason_required")
+	}
+
+	now := s.clock.Now()
+	entry := &models.TimeBankEntry{
+		UserID:    userID,
+		Year:      now.Year(),
+		Month:     int(now.Month()),
+		Type:      models.TimeBankEntryAdjustment,
+		Minutes:   minutes,
+		Reason:    reason,
+		CreatedBy: actorChatID,
+	}
+
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.TimeBank.Create(entry); err != nil {
+			return i18n.Errorf("timebank.adjust_failed", err)
+		}
+
+		audit := models.NewAuditEntry(models.ChatActor(actorChatID), models.AuditCreate, models.AuditEntityTimeBank, entry.ID).ForUser(userID)
+		return recordAudit(repos.Audit, audit, nil, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":    userID,
+		"minutes":    minutes,
+		"created_by": actorChatID,
+	}).Info("Time bank adjusted")
+
+	return entry, nil
+}
+
+// FormatLedger форматирует историю банка времени с нарастающим итогом
+func (s *TimeBankService) FormatLedger(tr *i18n.Localizer, user *models.User, entries []*models.TimeBankEntry) string {
+	var sb strings.Builder
+
+	sb.WriteString(tr.T("timebank.ledger_title", user.FirstName, user.LastName) + "\n\n")
+
+	balance := 0
+	if len(entries) == 0 {
+		sb.WriteString(tr.T("timebank.ledger_empty") + "\n")
+	}
+
+	for _, entry := range entries {
+		balance += entry.Minutes
+		sb.WriteString(fmt.Sprintf("%s %s  %s → %s\n",
+			getTimeBankEntryEmoji(entry.Type),
+			tr.Date(entry.CreatedAt),
+			tr.SignedDuration(entry.Minutes),
+			tr.SignedDuration(balance)))
+		if entry.Reason != "" {
+			sb.WriteString(fmt.Sprintf("    %s\n", entry.Reason))
+		}
+	}
+
+	sb.WriteString("\n" + tr.T("timebank.balance", tr.SignedDuration(balance)))
+
+	// Текущий месяц еще не перенесен в банк — показываем его отдельно
+	now := s.clock.Now()
+	stat, err := s.userMonthlyStatRepo.GetByUserAndMonth(user.ID, now.Year(), int(now.Month()))
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to get current month stat for ledger")
+	} else if stat != nil {
+		sb.WriteString("\n" + tr.T("timebank.current_month", tr.SignedDuration(stat.MonthlyBalance())))
+	}
+
+	return sb.String()
+}
+
+// timeBankReason формирует причину записи банка времени. Причина хранится в записи,
+// поэтому пишется на языке по умолчанию, а не на языке пользователя.
+func timeBankReason(key string, args ...interface{}) string {
+	return i18n.For("").Message(i18n.Msg(key, args...))
+}
+
+// getTimeBankEntryEmoji возвращает эмодзи для типа записи банка времени
+func getTimeBankEntryEmoji(entryType string) string {
+	switch entryType {
+	case models.TimeBankEntryMonthly:
+		return "📅"
+	case models.TimeBankEntryDayOff:
+		return "🎯"
+	case models.TimeBankEntryAdjustment:
+		return "✏️"
+	default:
+		return "•"
+	}
+}
@@ -3,8 +3,11 @@ package service
 import (
 	"fmt"
 	"strings"
+	"time"
+	"work-schedule-bot/internal/i18n"
 	"work-schedule-bot/internal/models"
 	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
 
 	"github.com/sirupsen/logrus"
 )
@@ -13,6 +16,8 @@ type UserService struct {
 	repo                   repository.UserRepository
 	workScheduleRepo       repository.WorkScheduleRepository // НОВОЕ
 	userMonthlyStatService *UserMonthlyStatService           // НОВОЕ
+	uow                    repository.UnitOfWork
+	clock                  clock.Clock
 	logger                 *logrus.Logger
 }
 
@@ -20,6 +25,8 @@ func NewUserService(
 	repo repository.UserRepository,
 	workScheduleRepo repository.WorkScheduleRepository, // НОВОЕ
 	userMonthlyStatService *UserMonthlyStatService, // НОВОЕ
+	uow repository.UnitOfWork,
+	clk clock.Clock,
 ) *UserService {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.TextFormatter{
@@ -31,12 +38,16 @@ func NewUserService(
 		repo:                   repo,
 		workScheduleRepo:       workScheduleRepo,       // НОВОЕ
 		userMonthlyStatService: userMonthlyStatService, // НОВОЕ
+		uow:                    uow,
+		clock:                  clk,
 		logger:                 logger,
 	}
 }
 
-// CreateUser создает нового пользователя с ролью client по умолчанию
-func (s *UserService) CreateUser(chatID int64, username, firstName, lastName string) (*models.User, error) {
+// CreateUser создает нового пользователя с ролью client по умолчанию.
+// Пустой timezone - пользователь работает в поясе компании, language - код языка интерфейса.
+// Профиль с pending ждет одобрения (см. ApproveUser), статистика для него пока не создается.
+func (s *UserService) CreateUser(chatID int64, username, firstName, lastName, patronymic, timezone, language string, pending bool) (*models.User, error) {
 	s.logger.WithFields(logrus.Fields{
 		"chat_id":    chatID,
 		"username":   username,
@@ -47,7 +58,7 @@ func (s *UserService) CreateUser(chatID int64, username, firstName, lastName str
 	// Проверяем валидность данных
 	if firstName == "" {
 		s.logger.WithField("chat_id", chatID).Error("First name is empty")
-		return nil, fmt.Errorf("имя не может быть пустым")
+		return nil, i18n.Errorf("user.first_name_empty")
 	}
 
 	// Создаем пользователя с ролью client
@@ -56,23 +67,39 @@ func (s *UserService) CreateUser(chatID int64, username, firstName, lastName str
 		Username:  username,
 		FirstName: firstName,
 		LastName:  lastName,
-		Role:      "client",
+		Role:      models.RoleEmployee,
+		Timezone:  timezone,
+		Language:  string(i18n.Match(language)),
+
+		Patronymic:      patronymic,
+		PendingApproval: pending,
 	}
 
-	err := s.repo.Create(user)
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.Users.Create(user); err != nil {
+			s.logger.WithError(err).Error("Failed to create user in repository")
+			return i18n.Errorf("user.create_failed", err)
+		}
+		entry := models.NewAuditEntry(models.ChatActor(chatID), models.AuditCreate, models.AuditEntityUser, user.ID).ForUser(user.ID)
+		return recordAudit(repos.Audit, entry, nil, user)
+	})
 	if err != nil {
-		s.logger.WithError(err).Error("Failed to create user in repository")
-		return nil, fmt.Errorf("ошибка создания пользователя: %v", err)
+		return nil, err
 	}
 
-	s.logger.WithField("chat_id", chatID).Info("User created successfully")
+	s.logger.WithFields(logrus.Fields{
+		"chat_id": chatID,
+		"pending": pending,
+	}).Info("User created successfully")
+
+	if pending {
+		return user, nil
+	}
 
 	// Создаем статистику для нового пользователя для всех существующих графиков
-	go func() {
-		if err := s.createMonthlyStatsForNewUser(user.ID); err != nil {
-			s.logger.WithError(err).Error("Failed to create monthly stats for new user")
-		}
-	}()
+	if err := s.createMonthlyStatsForNewUser(user.ID); err != nil {
+		s.logger.WithError(err).Error("Failed to create monthly stats for new user")
+	}
 
 	return user, nil
 }
@@ -105,43 +132,188 @@ func (s *UserService) createMonthlyStatsForNewUser(userID uint) error {
 func (s *UserService) GetUser(chatID int64) (*models.User, error) {
 	user, err := s.repo.GetByChatID(chatID)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка получения пользователя: %v", err)
+		return nil, i18n.Errorf("user.get_failed", err)
 	}
 
 	if user == nil {
-		return nil, fmt.Errorf("пользователь не найден")
+		return nil, i18n.Errorf("user.not_found")
 	}
 
 	return user, nil
 }
 
+// FindUser возвращает пользователя или nil, если он не зарегистрирован
+func (s *UserService) FindUser(chatID int64) (*models.User, error) {
+	return s.repo.GetByChatID(chatID)
+}
+
 // UpdateUser обновляет данные пользователя
-func (s *UserService) UpdateUser(chatID int64, username, firstName, lastName string) (*models.User, error) {
-	user, err := s.repo.GetByChatID(chatID)
+func (s *UserService) UpdateUser(chatID int64, username, firstName, lastName, patronymic string) (*models.User, error) {
+	return s.updateUser(chatID, models.ChatActor(chatID), func(user *models.User) error {
+		// Обновляем поля (кроме роли)
+		if username != "" {
+			user.Username = username
+		}
+		if firstName != "" {
+			user.FirstName = firstName
+		}
+		if lastName != "" {
+			user.LastName = lastName
+		}
+		if patronymic != "" {
+			user.Patronymic = patronymic
+		}
+		return nil
+	})
+}
+
+// updateUser изменяет профиль пользователя функцией change и записывает изменение в журнал
+// от имени actor
+func (s *UserService) updateUser(chatID int64, actor models.Actor, change func(user *models.User) error) (*models.User, error) {
+	var user *models.User
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		var err error
+		user, err = repos.Users.GetByChatID(chatID)
+		if err != nil {
+			return i18n.Errorf("user.get_failed", err)
+		}
+
+		if user == nil {
+			return i18n.Errorf("user.not_found")
+		}
+
+		before := *user
+		if err := change(user); err != nil {
+			return err
+		}
+
+		if err := repos.Users.Update(user); err != nil {
+			return i18n.Errorf("user.update_failed", err)
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditUpdate, models.AuditEntityUser, user.ID).ForUser(user.ID)
+		return recordAudit(repos.Audit, entry, before, user)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("ошибка получения пользователя: %v", err)
+		return nil, err
 	}
 
-	if user == nil {
-		return nil, fmt.Errorf("пользователь не найден")
+	return user, nil
+}
+
+// SetTimezone задает часовой пояс пользователя по IANA-имени или названию города.
+// Пустая строка возвращает пользователя к поясу компании.
+func (s *UserService) SetTimezone(chatID int64, timezone string) (*models.User, error) {
+	zone := ""
+	if timezone != "" {
+		var err error
+		zone, _, err = clock.ParseZone(timezone)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// Обновляем поля (кроме роли)
-	if username != "" {
-		user.Username = username
+	user, err := s.updateUser(chatID, models.ChatActor(chatID), func(user *models.User) error {
+		user.Timezone = zone
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if firstName != "" {
-		user.FirstName = firstName
+
+	s.logger.WithFields(logrus.Fields{
+		"chat_id":  chatID,
+		"timezone": zone,
+	}).Info("User timezone updated")
+
+	return user, nil
+}
+
+// SetLanguage задает язык интерфейса пользователя
+func (s *UserService) SetLanguage(chatID int64, language string) (*models.User, error) {
+	lang, ok := i18n.Parse(language)
+	if !ok {
+		return nil, i18n.Errorf("language.unknown", language)
 	}
-	if lastName != "" {
-		user.LastName = lastName
+
+	user, err := s.updateUser(chatID, models.ChatActor(chatID), func(user *models.User) error {
+		user.Language = string(lang)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	err = s.repo.Update(user)
+	s.logger.WithFields(logrus.Fields{
+		"chat_id":  chatID,
+		"language": lang,
+	}).Info("User language updated")
+
+	return user, nil
+}
+
+// SetPosition задает должность сотрудника. Пустая строка очищает должность.
+func (s *UserService) SetPosition(chatID int64, position string, actor models.Actor) (*models.User, error) {
+	return s.updateUser(chatID, actor, func(user *models.User) error {
+		user.Position = position
+		return nil
+	})
+}
+
+// SetEmployeeNumber задает табельный номер сотрудника. Номер не может повторяться,
+// пустая строка очищает номер.
+func (s *UserService) SetEmployeeNumber(chatID int64, number string, actor models.Actor) (*models.User, error) {
+	if number != "" {
+		other, err := s.repo.GetByEmployeeNumber(number)
+		if err != nil {
+			return nil, i18n.Errorf("user.get_failed", err)
+		}
+		if other != nil && other.ChatID != chatID {
+			return nil, i18n.Errorf("user.employee_number_taken", number, strings.TrimSpace(other.FirstName+" "+other.LastName))
+		}
+	}
+
+	return s.updateUser(chatID, actor, func(user *models.User) error {
+		user.EmployeeNumber = number
+		return nil
+	})
+}
+
+// SetHireDate задает дату приема сотрудника (nil - очищает) и пересчитывает его статистику:
+// в месяце приема план уменьшается на рабочие дни до приема
+func (s *UserService) SetHireDate(chatID int64, hireDate *time.Time, actor models.Actor) (*models.User, error) {
+	var user *models.User
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		var err error
+		user, err = repos.Users.GetByChatID(chatID)
+		if err != nil {
+			return i18n.Errorf("user.get_failed", err)
+		}
+		if user == nil {
+			return i18n.Errorf("user.not_found_id", chatID)
+		}
+		if hireDate != nil && user.TerminationDate != nil && hireDate.After(*user.TerminationDate) {
+			return i18n.Errorf("user.hire_after_termination", *user.TerminationDate)
+		}
+
+		before := *user
+		user.HireDate = hireDate
+		if err := repos.Users.Update(user); err != nil {
+			return i18n.Errorf("user.update_failed", err)
+		}
+
+		if err := s.userMonthlyStatService.statRules().rebuildUserStats(repos, user); err != nil {
+			return err
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditUpdate, models.AuditEntityUser, user.ID).ForUser(user.ID)
+		return recordAudit(repos.Audit, entry, &before, user)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("ошибка обновления пользователя: %v", err)
+		return nil, err
 	}
 
+	s.logger.WithField("chat_id", chatID).Info("User hire date updated")
 	return user, nil
 }
 
@@ -150,74 +322,383 @@ func (s *UserService) UpdateRole(adminChatID, targetChatID int64, role models.Ro
 	// Проверяем, что админ существует и является админом
 	admin, err := s.repo.GetByChatID(adminChatID)
 	if err != nil {
-		return fmt.Errorf("ошибка проверки админа: %v", err)
+		return i18n.Errorf("access.check_failed", err)
 	}
 
-	if admin == nil || !admin.IsAdmin() {
-		return fmt.Errorf("доступ запрещен: только администраторы могут менять роли")
+	if admin == nil || !admin.HasPermission(models.PermRolesManage) {
+		return i18n.Errorf("role.change_denied")
+	}
+
+	if !models.IsValidRole(string(role)) {
+		return i18n.Errorf("role.unknown", role)
 	}
 
 	// Проверяем, что целевой пользователь существует
 	targetUser, err := s.repo.GetByChatID(targetChatID)
 	if err != nil {
-		return fmt.Errorf("ошибка поиска пользователя: %v", err)
+		return i18n.Errorf("user.lookup_failed", err)
 	}
 
 	if targetUser == nil {
-		return fmt.Errorf("пользователь не найден")
+		return i18n.Errorf("user.not_found")
+	}
+
+	// Руководитель должен состоять в команде, которой руководит
+	if string(role) == models.RoleTeamLead && targetUser.TeamID == nil {
+		return i18n.Errorf("role.team_lead_needs_team")
 	}
 
 	// Обновляем роль
-	return s.repo.UpdateRole(targetChatID, role)
+	return s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.Users.UpdateRole(targetChatID, role); err != nil {
+			return err
+		}
+		after := *targetUser
+		after.Role = string(role)
+		entry := models.NewAuditEntry(models.ChatActor(adminChatID), models.AuditUpdate, models.AuditEntityUser, targetUser.ID).ForUser(targetUser.ID)
+		return recordAudit(repos.Audit, entry, targetUser, after)
+	})
 }
 
 // FormatUserInfo форматирует информацию о пользователе для вывода
-func (s *UserService) FormatUserInfo(user *models.User) string {
+func (s *UserService) FormatUserInfo(tr *i18n.Localizer, user *models.User) string {
 	var lines []string
 
-	lines = append(lines, "👤 Профиль пользователя:")
+	lines = append(lines, tr.T("profile.info.title"))
 	lines = append(lines, "")
-	lines = append(lines, fmt.Sprintf("🆔 ID чата: %d", user.ChatID))
+	lines = append(lines, tr.T("profile.info.chat_id", user.ChatID))
 
 	if user.Username != "" {
-		lines = append(lines, fmt.Sprintf("📛 Никнейм: @%s", user.Username))
+		lines = append(lines, tr.T("profile.info.username", user.Username))
 	}
 
-	lines = append(lines, fmt.Sprintf("👨‍💼 Имя: %s", user.FirstName))
+	lines = append(lines, tr.T("profile.info.first_name", user.FirstName))
 
 	if user.LastName != "" {
-		lines = append(lines, fmt.Sprintf("👨‍💼 Фамилия: %s", user.LastName))
+		lines = append(lines, tr.T("profile.info.last_name", user.LastName))
+	}
+
+	if user.Patronymic != "" {
+		lines = append(lines, tr.T("profile.info.patronymic", user.Patronymic))
 	}
 
 	// Добавляем информацию о роли
-	roleEmoji := "👤"
-	if user.IsAdmin() {
-		roleEmoji = "👑"
+	lines = append(lines, tr.T("profile.info.role", getRoleEmoji(user), models.NormalizeRole(user.Role)))
+
+	if user.Team != nil {
+		if user.Team.Department.Name != "" {
+			lines = append(lines, tr.T("profile.info.department", user.Team.Department.Name))
+		}
+		lines = append(lines, tr.T("profile.info.team", user.Team.Name))
+	}
+
+	if user.Position != "" {
+		lines = append(lines, tr.T("profile.info.position", user.Position))
+	}
+	if user.EmployeeNumber != "" {
+		lines = append(lines, tr.T("profile.info.employee_number", user.EmployeeNumber))
+	}
+	if user.HireDate != nil {
+		lines = append(lines, tr.T("profile.info.hire_date", *user.HireDate))
+	}
+
+	if user.Timezone != "" {
+		lines = append(lines, tr.T("profile.info.timezone", user.Timezone))
+	} else {
+		lines = append(lines, tr.T("profile.info.timezone_default", s.clock.Location()))
+	}
+
+	lines = append(lines, tr.T("profile.info.language", tr.T("language.name")))
+
+	if user.PendingApproval {
+		lines = append(lines, tr.T("profile.info.pending"))
+	}
+	if !user.IsActive() {
+		lines = append(lines, tr.T("profile.info.terminated", *user.TerminationDate))
 	}
-	lines = append(lines, fmt.Sprintf("%s Роль: %s", roleEmoji, string(user.Role)))
 
 	return strings.Join(lines, "\n")
 }
 
-// DeleteUser удаляет пользователя
-func (s *UserService) DeleteUser(chatID int64) error {
-	exists, err := s.repo.Exists(chatID)
+// DeactivateUser деактивирует сотрудника с даты увольнения. История сотрудника сохраняется
+// и остается в отчетах, но в списки работающих сотрудников и новую статистику он не попадает,
+// а план месяца увольнения уменьшается.
+func (s *UserService) DeactivateUser(chatID int64, terminationDate time.Time, actor models.Actor) (*models.User, error) {
+	var user *models.User
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		var err error
+		user, err = repos.Users.GetByChatID(chatID)
+		if err != nil {
+			return i18n.Errorf("user.get_failed", err)
+		}
+		if user == nil {
+			return i18n.Errorf("user.not_found_id", chatID)
+		}
+		if !user.IsActive() {
+			return i18n.Errorf("user.already_deactivated", *user.TerminationDate)
+		}
+		if user.PendingApproval {
+			return i18n.Errorf("user.deactivate_pending")
+		}
+
+		active, err := repos.WorkSessions.GetActiveByUserID(user.ID)
+		if err != nil {
+			return i18n.Errorf("session.active_get_failed", err)
+		}
+		if active != nil {
+			return i18n.Errorf("user.deactivate_active_session")
+		}
+		if user.HireDate != nil && terminationDate.Before(*user.HireDate) {
+			return i18n.Errorf("user.termination_before_hire", *user.HireDate)
+		}
+
+		before := *user
+		user.TerminationDate = &terminationDate
+		if err := repos.Users.Update(user); err != nil {
+			return i18n.Errorf("user.update_failed", err)
+		}
+
+		// План месяца увольнения уменьшается на рабочие дни после увольнения
+		if err := s.userMonthlyStatService.statRules().rebuildUserStats(repos, user); err != nil {
+			return err
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditUpdate, models.AuditEntityUser, user.ID).ForUser(user.ID)
+		return recordAudit(repos.Audit, entry, &before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"chat_id":          chatID,
+		"termination_date": terminationDate.Format("2006-01-02"),
+	}).Info("User deactivated")
+
+	return user, nil
+}
+
+// ReactivateUser возвращает деактивированного сотрудника в работающие
+func (s *UserService) ReactivateUser(chatID int64, actor models.Actor) (*models.User, error) {
+	var user *models.User
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		var err error
+		user, err = repos.Users.GetByChatID(chatID)
+		if err != nil {
+			return i18n.Errorf("user.get_failed", err)
+		}
+		if user == nil {
+			return i18n.Errorf("user.not_found_id", chatID)
+		}
+		if user.IsActive() {
+			return i18n.Errorf("user.not_deactivated")
+		}
+
+		before := *user
+		user.TerminationDate = nil
+		if err := repos.Users.Update(user); err != nil {
+			return i18n.Errorf("user.update_failed", err)
+		}
+
+		if err := s.userMonthlyStatService.statRules().rebuildUserStats(repos, user); err != nil {
+			return err
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditUpdate, models.AuditEntityUser, user.ID).ForUser(user.ID)
+		return recordAudit(repos.Audit, entry, &before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithField("chat_id", chatID).Info("User reactivated")
+	return user, nil
+}
+
+// ApproveUser одобряет новый профиль: сотрудник получает доступ к учету времени,
+// для него создается статистика по всем графикам. Руководитель команды одобряет только профили своей команды.
+func (s *UserService) ApproveUser(chatID int64, actor models.Actor) (*models.User, error) {
+	var user *models.User
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		var err error
+		user, err = repos.Users.GetByChatID(chatID)
+		if err != nil {
+			return i18n.Errorf("user.get_failed", err)
+		}
+		if user == nil {
+			return i18n.Errorf("user.not_found_id", chatID)
+		}
+		if !user.PendingApproval {
+			return i18n.Errorf("user.not_pending")
+		}
+		if err := checkApprover(repos, actor, user); err != nil {
+			return err
+		}
+
+		before := *user
+		user.PendingApproval = false
+		if err := repos.Users.Update(user); err != nil {
+			return i18n.Errorf("user.update_failed", err)
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditUpdate, models.AuditEntityUser, user.ID).ForUser(user.ID)
+		return recordAudit(repos.Audit, entry, &before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithField("chat_id", chatID).Info("User approved")
+
+	if err := s.createMonthlyStatsForNewUser(user.ID); err != nil {
+		s.logger.WithError(err).Error("Failed to create monthly stats for approved user")
+	}
+
+	return user, nil
+}
+
+// RejectUser отклоняет новый профиль и удаляет его. Данных учета у неодобренного профиля нет,
+// из журнала изменений стираются сохраненные состояния профиля. Руководитель команды отклоняет
+// только профили своей команды.
+func (s *UserService) RejectUser(chatID int64, actor models.Actor) (*models.User, error) {
+	var user *models.User
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		var err error
+		user, err = repos.Users.GetByChatID(chatID)
+		if err != nil {
+			return i18n.Errorf("user.get_failed", err)
+		}
+		if user == nil {
+			return i18n.Errorf("user.not_found_id", chatID)
+		}
+		if !user.PendingApproval {
+			return i18n.Errorf("user.not_pending")
+		}
+		if err := checkApprover(repos, actor, user); err != nil {
+			return err
+		}
+
+		if err := repos.Users.Delete(chatID); err != nil {
+			return err
+		}
+
+		if err := repos.Audit.EraseUserData(user.ID); err != nil {
+			return i18n.Errorf("audit.record_failed", err)
+		}
+		entry := models.NewAuditEntry(actor, models.AuditDelete, models.AuditEntityUser, user.ID).ForUser(user.ID)
+		return recordAudit(repos.Audit, entry, nil, nil)
+	})
 	if err != nil {
-		return fmt.Errorf("ошибка проверки пользователя: %v", err)
+		return nil, err
 	}
 
-	if !exists {
-		return fmt.Errorf("пользователь не найден")
+	s.logger.WithField("chat_id", chatID).Info("User rejected")
+	return user, nil
+}
+
+// checkApprover проверяет, что actor может решить судьбу нового профиля target:
+// роли уровня компании решают по всем профилям, руководитель - только по профилям своей команды
+func checkApprover(repos *repository.Repositories, actor models.Actor, target *models.User) error {
+	if actor.IsSystem() {
+		return nil
 	}
 
-	return s.repo.Delete(chatID)
+	approver, err := repos.Users.GetByChatID(actor.ChatID)
+	if err != nil {
+		return i18n.Errorf("access.check_failed", err)
+	}
+	if approver == nil || !approver.HasPermission(models.PermUsersApprove) {
+		return i18n.Errorf("access.denied")
+	}
+	if !approver.CanManage(target, models.PermUsersApprove) {
+		return i18n.Errorf("access.not_your_team")
+	}
+	return nil
 }
 
-// GetAllUsers возвращает всех пользователей
+// PurgeUser безвозвратно удаляет деактивированного сотрудника вместе с его сессиями, отсутствиями,
+// статистикой и банком времени, а из журнала изменений - сохраненные состояния с его данными
+func (s *UserService) PurgeUser(chatID int64, actor models.Actor) error {
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		user, err := repos.Users.GetByChatID(chatID)
+		if err != nil {
+			return i18n.Errorf("user.get_failed", err)
+		}
+
+		if user == nil {
+			return i18n.Errorf("user.not_found_id", chatID)
+		}
+		if user.IsActive() {
+			return i18n.Errorf("user.purge_active")
+		}
+
+		// Сессии удаляются раньше периодов отсутствия, на которые они ссылаются
+		if err := repos.WorkSessions.DeleteByUserID(user.ID); err != nil {
+			return i18n.Errorf("user.delete_sessions_failed", err)
+		}
+		if err := repos.AbsencePeriods.DeleteByUserID(user.ID); err != nil {
+			return i18n.Errorf("user.delete_absences_failed", err)
+		}
+		if err := repos.MonthlyStats.DeleteByUserID(user.ID); err != nil {
+			return i18n.Errorf("user.delete_stats_failed", err)
+		}
+		if err := repos.TimeBank.DeleteByUserID(user.ID); err != nil {
+			return i18n.Errorf("user.delete_time_bank_failed", err)
+		}
+
+		if err := repos.Users.Delete(chatID); err != nil {
+			return err
+		}
+
+		if err := repos.Audit.EraseUserData(user.ID); err != nil {
+			return i18n.Errorf("audit.record_failed", err)
+		}
+		entry := models.NewAuditEntry(actor, models.AuditDelete, models.AuditEntityUser, user.ID).ForUser(user.ID)
+		return recordAudit(repos.Audit, entry, nil, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.WithField("chat_id", chatID).Warn("User purged")
+	return nil
+}
+
+// GetAllUsers возвращает всех пользователей, включая деактивированных
 func (s *UserService) GetAllUsers() ([]*models.User, error) {
 	return s.repo.GetAll()
 }
 
+// GetPendingUsers возвращает профили, ожидающие одобрения, по которым может решать actorChatID:
+// для ролей уровня компании - все, для руководителя команды - профили его команды
+func (s *UserService) GetPendingUsers(actorChatID int64) ([]*models.User, error) {
+	actor, err := s.repo.GetByChatID(actorChatID)
+	if err != nil {
+		return nil, i18n.Errorf("access.check_failed", err)
+	}
+	if actor == nil || !actor.HasPermission(models.PermUsersApprove) {
+		return nil, i18n.Errorf("access.denied")
+	}
+
+	users, err := s.repo.GetPending()
+	if err != nil {
+		return nil, i18n.Errorf("users.get_failed", err)
+	}
+
+	scoped := make([]*models.User, 0, len(users))
+	for _, user := range users {
+		if actor.CanManage(user, models.PermUsersApprove) {
+			scoped = append(scoped, user)
+		}
+	}
+	return scoped, nil
+}
+
+// GetActiveUsers возвращает работающих сотрудников
+func (s *UserService) GetActiveUsers() ([]*models.User, error) {
+	return s.repo.GetActive()
+}
+
 // GetAdmins возвращает всех администраторов
 func (s *UserService) GetAdmins() ([]*models.User, error) {
 	return s.repo.GetAdmins()
@@ -229,27 +710,55 @@ func (s *UserService) GetStats() (int, int, error) {
 }
 
 // FormatAllUsers форматирует список всех пользователей
-func (s *UserService) FormatAllUsers() (string, error) {
+func (s *UserService) FormatAllUsers(tr *i18n.Localizer) (string, error) {
 	users, err := s.GetAllUsers()
 	if err != nil {
 		return "", err
 	}
 
+	return s.FormatUsers(tr, tr.T("users.all_title"), users), nil
+}
+
+// activeUsers оставляет в списке только работающих сотрудников: одобренных и не деактивированных
+func activeUsers(users []*models.User) []*models.User {
+	active := make([]*models.User, 0, len(users))
+	for _, user := range users {
+		if user.CanWork() {
+			active = append(active, user)
+		}
+	}
+	return active
+}
+
+// FormatUsers форматирует список пользователей с заголовком.
+// Ожидающие одобрения и деактивированные сотрудники перечисляются отдельно и не входят в итоги.
+func (s *UserService) FormatUsers(tr *i18n.Localizer, title string, users []*models.User) string {
 	if len(users) == 0 {
-		return "📭 Список пользователей пуст.", nil
+		return tr.T("users.empty")
 	}
 
 	var lines []string
-	lines = append(lines, "📋 Все пользователи:")
+	lines = append(lines, title)
 	lines = append(lines, "")
 
-	for i, user := range users {
-		roleEmoji := "👤"
+	var pending, archived []*models.User
+	active := activeUsers(users)
+	admins := 0
+	for _, user := range users {
+		switch {
+		case !user.IsActive():
+			archived = append(archived, user)
+		case user.PendingApproval:
+			pending = append(pending, user)
+		}
+	}
+
+	for i, user := range active {
 		if user.IsAdmin() {
-			roleEmoji = "👑"
+			admins++
 		}
 
-		userInfo := fmt.Sprintf("%d. %s ", i+1, roleEmoji)
+		userInfo := fmt.Sprintf("%d. %s ", i+1, getRoleEmoji(user))
 		if user.FirstName != "" {
 			userInfo += user.FirstName + " "
 		}
@@ -263,12 +772,105 @@ func (s *UserService) FormatAllUsers() (string, error) {
 		lines = append(lines, userInfo)
 	}
 
-	total, admins, _ := s.GetStats()
+	if len(pending) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, tr.T("users.pending_title"))
+		for _, user := range pending {
+			name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+			lines = append(lines, tr.T("users.pending_line", name, user.ChatID))
+		}
+	}
+
+	if len(archived) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, tr.T("users.archived_title"))
+		for _, user := range archived {
+			name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+			lines = append(lines, tr.T("users.archived_line", name, user.ChatID, *user.TerminationDate))
+		}
+	}
+
 	lines = append(lines, "")
-	lines = append(lines, fmt.Sprintf("📊 Всего пользователей: %d", total))
-	lines = append(lines, fmt.Sprintf("👑 Администраторов: %d", admins))
+	lines = append(lines, tr.T("users.total", len(active)))
+	lines = append(lines, tr.T("users.admins", admins))
+
+	return strings.Join(lines, "\n")
+}
+
+// getRoleEmoji возвращает эмодзи для роли пользователя
+func getRoleEmoji(user *models.User) string {
+	switch models.NormalizeRole(user.Role) {
+	case models.RoleAdmin:
+		return "👑"
+	case models.RoleTeamLead:
+		return "🧑‍💼"
+	case models.RoleHR:
+		return "🗂"
+	case models.RoleAccountant:
+		return "🧮"
+	default:
+		return "👤"
+	}
+}
 
-	return strings.Join(lines, "\n"), nil
+// HasPermission проверяет, есть ли у пользователя право
+func (s *UserService) HasPermission(chatID int64, permission models.Permission) (bool, error) {
+	user, err := s.repo.GetByChatID(chatID)
+	if err != nil {
+		return false, err
+	}
+
+	return user != nil && user.CanWork() && user.HasPermission(permission), nil
+}
+
+// GetScopedUsers возвращает пользователей, к которым применимо право:
+// для ролей уровня компании - всех, для руководителя команды - участников его команды
+func (s *UserService) GetScopedUsers(actorChatID int64, permission models.Permission) ([]*models.User, error) {
+	actor, err := s.repo.GetByChatID(actorChatID)
+	if err != nil {
+		return nil, i18n.Errorf("access.check_failed", err)
+	}
+
+	if actor == nil || !actor.HasPermission(permission) {
+		return nil, i18n.Errorf("access.denied")
+	}
+
+	if actor.HasCompanyScope() {
+		return s.repo.GetAll()
+	}
+
+	if actor.TeamID == nil {
+		return []*models.User{}, nil
+	}
+
+	return s.repo.GetByTeamID(*actor.TeamID)
+}
+
+// GetManagedUser возвращает пользователя, если у actor есть право работать с его данными
+func (s *UserService) GetManagedUser(actorChatID, targetChatID int64, permission models.Permission) (*models.User, error) {
+	actor, err := s.repo.GetByChatID(actorChatID)
+	if err != nil {
+		return nil, i18n.Errorf("access.check_failed", err)
+	}
+
+	if actor == nil || !actor.HasPermission(permission) {
+		return nil, i18n.Errorf("access.denied")
+	}
+
+	target, err := s.repo.GetByChatID(targetChatID)
+	if err != nil {
+		return nil, i18n.Errorf("user.lookup_failed", err)
+	}
+
+	if target == nil {
+		return nil, i18n.Errorf("user.not_found_id", targetChatID)
+	}
+
+	if !actor.CanManage(target, permission) {
+		return nil, i18n.Errorf("access.not_your_team")
+	}
+
+	return target, nil
 }
 
 // IsAdmin проверяет, является ли пользователь администратором
@@ -294,31 +896,47 @@ func (s *UserService) InitializeAdmin(adminChatID int64) error {
 	}
 
 	if existingUser != nil {
-		// Если пользователь существует, обновляем его роль на админа
-		return s.repo.UpdateRole(adminChatID, "admin")
-	}
+		if existingUser.Role == models.RoleAdmin {
+			return nil
+		}
 
-	// Создаем нового администратора
+		// Если пользователь существует, обновляем его роль на админа
+		return s.uow.WithTx(func(repos *repository.Repositories) error {
+			if err := repos.Users.UpdateRole(adminChatID, models.Role(models.RoleAdmin)); err != nil {
+				return err
+			}
+			after := *existingUser
+			after.Role = models.RoleAdmin
+			entry := models.NewAuditEntry(models.SystemActor, models.AuditUpdate, models.AuditEntityUser, existingUser.ID).ForUser(existingUser.ID)
+			return recordAudit(repos.Audit, entry, existingUser, after)
+		})
+	}
+
+	// Создаем нового администратора. Язык ему не известен, поэтому имя берется из каталога по умолчанию
 	adminUser := &models.User{
 		ChatID:    adminChatID,
 		Username:  "admin",
-		FirstName: "Администратор",
+		FirstName: i18n.For("").T("user.admin_default_name"),
 		LastName:  "",
 		Role:      models.RoleAdmin,
 	}
 
-	err = s.repo.Create(adminUser)
+	err = s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.Users.Create(adminUser); err != nil {
+			s.logger.WithError(err).Error("Failed to create admin in repository")
+			return i18n.Errorf("user.create_failed", err)
+		}
+		entry := models.NewAuditEntry(models.SystemActor, models.AuditCreate, models.AuditEntityUser, adminUser.ID).ForUser(adminUser.ID)
+		return recordAudit(repos.Audit, entry, nil, adminUser)
+	})
 	if err != nil {
-		s.logger.WithError(err).Error("Failed to create admin in repository")
-		return fmt.Errorf("ошибка создания админа: %v", err)
+		return err
 	}
 
 	// Создаем статистику для нового пользователя для всех существующих графиков
-	go func() {
-		if err := s.createMonthlyStatsForNewUser(adminUser.ID); err != nil {
-			s.logger.WithError(err).Error("Failed to create monthly stats for admin")
-		}
-	}()
+	if err := s.createMonthlyStatsForNewUser(adminUser.ID); err != nil {
+		s.logger.WithError(err).Error("Failed to create monthly stats for admin")
+	}
 
 	return nil
 }
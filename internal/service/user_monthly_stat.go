@@ -1,11 +1,16 @@
 package service
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
+	"work-schedule-bot/internal/i18n"
 	"work-schedule-bot/internal/models"
 	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
 
 	"github.com/sirupsen/logrus"
 )
@@ -13,12 +18,18 @@ import (
 type UserMonthlyStatService struct {
 	statRepo repository.UserMonthlyStatRepository
 	userRepo repository.UserRepository
+	uow      repository.UnitOfWork
+	policy   models.WorkPolicy
+	clock    clock.Clock
 	logger   *logrus.Logger
 }
 
 func NewUserMonthlyStatService(
 	statRepo repository.UserMonthlyStatRepository,
 	userRepo repository.UserRepository,
+	uow repository.UnitOfWork,
+	policy models.WorkPolicy,
+	clk clock.Clock,
 ) *UserMonthlyStatService {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.TextFormatter{
@@ -29,6 +40,9 @@ func NewUserMonthlyStatService(
 	return &UserMonthlyStatService{
 		statRepo: statRepo,
 		userRepo: userRepo,
+		uow:      uow,
+		policy:   policy,
+		clock:    clk,
 		logger:   logger,
 	}
 }
@@ -37,20 +51,17 @@ func NewUserMonthlyStatService(
 func (s *UserMonthlyStatService) CreateStatsForNewUser(userID uint, schedules []*models.WorkSchedule) error {
 	s.logger.WithField("user_id", userID).Info("Creating monthly stats for new user")
 
-	for _, schedule := range schedules {
-		stat := &models.UserMonthlyStat{
-			UserID:         userID,
-			Year:           schedule.Year,
-			Month:          schedule.Month,
-			PlannedDays:    schedule.WorkDays,
-			PlannedMinutes: schedule.TotalMinutes,
-		}
-		stat.CalculateStats()
-
-		if err := s.statRepo.Create(stat); err != nil {
-			s.logger.WithError(err).Error("Failed to create monthly stat for new user")
-			return err
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		for _, schedule := range schedules {
+			if _, err := s.statRules().rebuildMonthlyStat(repos, userID, schedule.Year, schedule.Month); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to create monthly stat for new user")
+		return err
 	}
 
 	s.logger.WithFields(logrus.Fields{
@@ -61,34 +72,6 @@ func (s *UserMonthlyStatService) CreateStatsForNewUser(userID uint, schedules []
 	return nil
 }
 
-// UpdateStatsForWorkSchedule обновляет статистику всех пользователей при изменении графика
-func (s *UserMonthlyStatService) UpdateStatsForWorkSchedule(schedule *models.WorkSchedule) error {
-	s.logger.WithFields(logrus.Fields{
-		"year":  schedule.Year,
-		"month": schedule.Month,
-	}).Info("Updating monthly stats for work schedule update")
-
-	// Обновляем статистику для всех пользователей за этот месяц
-	err := s.statRepo.UpdateForAllUsers(
-		schedule.Year,
-		schedule.Month,
-		schedule.WorkDays,
-		schedule.TotalMinutes,
-	)
-
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to update monthly stats for work schedule")
-		return err
-	}
-
-	s.logger.WithFields(logrus.Fields{
-		"year":  schedule.Year,
-		"month": schedule.Month,
-	}).Info("Monthly stats updated for work schedule")
-
-	return nil
-}
-
 // GetUserStats возвращает статистику пользователя
 func (s *UserMonthlyStatService) GetUserStats(userID uint) ([]*models.UserMonthlyStat, error) {
 	s.logger.WithField("user_id", userID).Debug("Getting user monthly stats")
@@ -106,208 +89,190 @@ func (s *UserMonthlyStatService) GetUserStatByMonth(userID uint, year, month int
 	return s.statRepo.GetByUserAndMonth(userID, year, month)
 }
 
-// UpdateWorkedTime обновляет отработанное время пользователя
-func (s *UserMonthlyStatService) UpdateWorkedTime(userID uint, year, month, workedDays, workedMinutes int) error {
-	s.logger.WithFields(logrus.Fields{
-		"user_id":        userID,
-		"year":           year,
-		"month":          month,
-		"worked_days":    workedDays,
-		"worked_minutes": workedMinutes,
-	}).Info("Updating worked time in monthly stats")
-
-	return s.statRepo.UpdateWorkedStats(userID, year, month, workedDays, workedMinutes)
-}
-
 // FormatStat форматирует статистику для отображения
-func (s *UserMonthlyStatService) FormatStat(stat *models.UserMonthlyStat) string {
+func (s *UserMonthlyStatService) FormatStat(tr *i18n.Localizer, stat *models.UserMonthlyStat) string {
 	if stat == nil {
-		return "❌ Статистика не найдена"
+		return tr.T("stats.not_found")
 	}
 
-	// Конвертируем минуты в часы:минуты
-	plannedHours := stat.PlannedMinutes / 60
-	plannedMinutes := stat.PlannedMinutes % 60
-	workedHours := stat.WorkedMinutes / 60
-	workedMinutes := stat.WorkedMinutes % 60
-	overtimeHours := stat.OvertimeMinutes / 60
-	overtimeMinutes := stat.OvertimeMinutes % 60
-	deficitHours := stat.DeficitMinutes / 60
-	deficitMinutes := stat.DeficitMinutes % 60
-
-	monthName := time.Month(stat.Month).String()
-
-	var plannedTime, workedTime, overtimeTime, deficitTime string
+	result := tr.T("stats.planned",
+		tr.MonthYear(stat.Year, time.Month(stat.Month)),
+		tr.N("count.days", stat.PlannedDays), tr.Duration(stat.PlannedMinutes),
+	)
 
-	if plannedMinutes == 0 {
-		plannedTime = fmt.Sprintf("%dч", plannedHours)
-	} else {
-		plannedTime = fmt.Sprintf("%dч %dм", plannedHours, plannedMinutes)
+	if stat.PlanReductionMinutes > 0 {
+		result += "\n   " + tr.T("stats.plan_reduction", tr.Duration(stat.PlanReductionMinutes))
+		result += "\n   " + tr.T("stats.effective_plan", tr.Duration(stat.EffectivePlannedMinutes()))
 	}
 
-	if workedMinutes == 0 {
-		workedTime = fmt.Sprintf("%dч", workedHours)
-	} else {
-		workedTime = fmt.Sprintf("%dч %dм", workedHours, workedMinutes)
-	}
+	result += "\n\n" + tr.T("stats.actual", tr.N("count.days", stat.WorkedDays), tr.Duration(stat.WorkedMinutes))
 
-	if stat.OvertimeMinutes > 0 {
-		if overtimeMinutes == 0 {
-			overtimeTime = fmt.Sprintf("%dч", overtimeHours)
-		} else {
-			overtimeTime = fmt.Sprintf("%dч %dм", overtimeHours, overtimeMinutes)
-		}
+	if stat.CreditedAbsenceMinutes > 0 {
+		result += "\n   " + tr.T("stats.credited_absences", tr.Duration(stat.CreditedAbsenceMinutes))
 	}
 
-	if stat.DeficitMinutes > 0 {
-		if deficitMinutes == 0 {
-			deficitTime = fmt.Sprintf("%dч", deficitHours)
-		} else {
-			deficitTime = fmt.Sprintf("%dч %dм", deficitHours, deficitMinutes)
-		}
+	if stat.UncreditedAbsenceMinutes > 0 {
+		result += "\n   " + tr.T("stats.uncredited_absences",
+			tr.Duration(stat.UncreditedAbsenceMinutes), tr.N("count.days", stat.UncreditedAbsenceDays))
 	}
 
-	result := fmt.Sprintf(
-		`📊 Статистика за %s %d
-
-📅 Плановые показатели:
-   📋 Рабочих дней: %d
-   ⏰ Плановое время: %s
-
-✅ Фактические показатели:
-   📋 Отработано дней: %d
-   ⏰ Отработано времени: %s`,
-		monthName, stat.Year,
-		stat.PlannedDays, plannedTime,
-		stat.WorkedDays, workedTime,
-	)
+	if stat.CreditedAbsenceMinutes > 0 || stat.UncreditedAbsenceMinutes > 0 {
+		result += "\n   " + tr.T("stats.counted_total", tr.Duration(stat.CountedMinutes()))
+	}
 
 	if stat.OvertimeMinutes > 0 {
-		result += fmt.Sprintf("\n\n➕ Переработка за месяц: %s", overtimeTime)
+		result += "\n\n" + tr.T("stats.month_overtime", tr.Duration(stat.OvertimeMinutes))
 	}
 
 	if stat.DeficitMinutes > 0 {
-		result += fmt.Sprintf("\n\n➖ Недобор за месяц: %s", deficitTime)
+		result += "\n\n" + tr.T("stats.month_deficit", tr.Duration(stat.DeficitMinutes))
 	}
 
 	// Расчет оставшегося времени
-	remainingMinutes := stat.PlannedMinutes - stat.WorkedMinutes
+	remainingMinutes := stat.EffectivePlannedMinutes() - stat.CountedMinutes()
 	if remainingMinutes > 0 {
-		remainingHours := remainingMinutes / 60
-		remainingMins := remainingMinutes % 60
-		var remainingTime string
-		if remainingMins == 0 {
-			remainingTime = fmt.Sprintf("%dч", remainingHours)
-		} else {
-			remainingTime = fmt.Sprintf("%dч %dм", remainingHours, remainingMins)
-		}
-
-		remainingDays := stat.PlannedDays - stat.WorkedDays
-		result += fmt.Sprintf("\n\n⏳ Осталось отработать: %d дней, %s", remainingDays, remainingTime)
+		remainingDays := stat.RemainingDays()
+		result += "\n\n" + tr.T("stats.remaining", tr.N("count.days", remainingDays), tr.Duration(remainingMinutes))
 
 		// Расчет необходимого времени работы в день (ДОБАВЛЕНО)
 		if remainingDays > 0 {
 			minutesPerDay := remainingMinutes / remainingDays
-			
-			hoursPerDay := minutesPerDay / 60
 			minsPerDay := minutesPerDay % 60
-			
-			var dailyTime string
-			if minsPerDay == 0 {
-				dailyTime = fmt.Sprintf("%dч", hoursPerDay)
-			} else {
-				dailyTime = fmt.Sprintf("%dч %dм", hoursPerDay, minsPerDay)
-			}
 
-			result += fmt.Sprintf("\n📈 Необходимо работать в день: %s", dailyTime)
-			
-			extraStr := "\n\n"
-			extraMin := 0
-			
-			if minutesPerDay < 520 {
-				extraStr += "➕ Переработка: "
-				extraMin = remainingDays * 520 - remainingMinutes
-			} else if minsPerDay > 520 {
-				extraStr += "➖ Недобор: "
-				extraMin = remainingMinutes - remainingDays * 520 
-			} else {
-				extraStr += "✅ План выполняется идеально"
-				extraMin = 0
-			}
-			
-			extraHours := extraMin / 60
-			extraMinutes := extraMin % 60
-			
-			var extraTime string
-			if extraMinutes == 0 {
-				extraTime = fmt.Sprintf("%dч", extraHours)
+			result += "\n" + tr.T("stats.required_per_day", tr.Duration(minutesPerDay))
+
+			dayMinutes := s.policy.WorkDayMinutes
+			if minutesPerDay < dayMinutes {
+				result += "\n\n" + tr.T("stats.expected_overtime", tr.Duration(remainingDays*dayMinutes-remainingMinutes))
+			} else if minsPerDay > dayMinutes {
+				result += "\n\n" + tr.T("stats.expected_deficit", tr.Duration(remainingMinutes-remainingDays*dayMinutes))
 			} else {
-				extraTime = fmt.Sprintf("%dч %dм", extraHours, extraMinutes)
+				result += "\n\n" + tr.T("stats.on_plan")
 			}
-			
-			result += fmt.Sprintf("%s%s", extraStr, extraTime)
 		}
 	}
 
 	// Если уже есть переработка
 	if stat.OvertimeMinutes > 0 && remainingMinutes <= 0 {
-		overHours := stat.OvertimeMinutes / 60
-		overMins := stat.OvertimeMinutes % 60
-		var overTime string
-		if overMins == 0 {
-			overTime = fmt.Sprintf("%dч", overHours)
-		} else {
-			overTime = fmt.Sprintf("%dч %dм", overHours, overMins)
-		}
-		result += fmt.Sprintf("\n\n✅ Вы уже выполнили норму месяца! Переработка: %s", overTime)
+		result += "\n\n" + tr.T("stats.plan_done", tr.Duration(stat.OvertimeMinutes))
 	}
 
-	result += fmt.Sprintf("\n\n📅 Последнее обновление: %s",
-		stat.UpdatedAt.Format("02.01.2006 15:04"))
+	result += "\n\n" + tr.T("stats.updated_at", tr.DateTime(stat.UpdatedAt))
 
 	return result
 }
 
+// FormatUsersSummary форматирует сводку по пользователям за месяц
+func (s *UserMonthlyStatService) FormatUsersSummary(tr *i18n.Localizer, users []*models.User, year, month int) (string, error) {
+	var lines []string
+	lines = append(lines, tr.T("stats.summary_title", tr.MonthYear(year, time.Month(month))))
+	lines = append(lines, "")
+
+	if len(users) == 0 {
+		lines = append(lines, tr.T("stats.summary_no_users"))
+		return strings.Join(lines, "\n"), nil
+	}
+
+	for i, user := range users {
+		stat, err := s.statRepo.GetByUserAndMonth(user.ID, year, month)
+		if err != nil {
+			return "", err
+		}
+
+		name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+		if user.EmployeeNumber != "" {
+			name = tr.T("stats.summary_name_number", name, user.EmployeeNumber)
+		}
+		if stat == nil {
+			lines = append(lines, tr.T("stats.summary_no_data", i+1, name))
+			continue
+		}
+
+		balance := stat.CountedMinutes() - stat.EffectivePlannedMinutes()
+		sign := "+"
+		if balance < 0 {
+			sign = "-"
+			balance = -balance
+		}
+
+		lines = append(lines, tr.T("stats.summary_row",
+			i+1, name,
+			tr.Duration(stat.CountedMinutes()),
+			tr.Duration(stat.EffectivePlannedMinutes()),
+			s.CalculateCompletionPercentage(stat),
+			sign, tr.Duration(balance)))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// ExportTimesheetCSV выгружает табель за месяц в CSV: строка на сотрудника с плановым,
+// засчитанным и отработанным временем в минутах. У сотрудников без статистики показатели пустые.
+func (s *UserMonthlyStatService) ExportTimesheetCSV(users []*models.User, year, month int) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{
+		"chat_id", "employee_number", "last_name", "first_name", "year", "month",
+		"planned_days", "planned_minutes", "worked_days", "worked_minutes",
+		"credited_absence_minutes", "uncredited_absence_days", "counted_minutes", "balance_minutes",
+	})
+	for _, user := range users {
+		row := []string{
+			strconv.FormatInt(user.ChatID, 10),
+			user.EmployeeNumber,
+			user.LastName,
+			user.FirstName,
+			strconv.Itoa(year),
+			strconv.Itoa(month),
+		}
+
+		stat, err := s.statRepo.GetByUserAndMonth(user.ID, year, month)
+		if err != nil {
+			return nil, err
+		}
+		if stat == nil {
+			row = append(row, make([]string, 8)...)
+		} else {
+			row = append(row,
+				strconv.Itoa(stat.PlannedDays),
+				strconv.Itoa(stat.EffectivePlannedMinutes()),
+				strconv.Itoa(stat.WorkedDays),
+				strconv.Itoa(stat.WorkedMinutes),
+				strconv.Itoa(stat.CreditedAbsenceMinutes),
+				strconv.Itoa(stat.UncreditedAbsenceDays),
+				strconv.Itoa(stat.CountedMinutes()),
+				strconv.Itoa(stat.CountedMinutes()-stat.EffectivePlannedMinutes()),
+			)
+		}
+		writer.Write(row)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("ошибка записи CSV: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
 // FormatStatsList форматирует список статистики
-func (s *UserMonthlyStatService) FormatStatsList(stats []*models.UserMonthlyStat) string {
+func (s *UserMonthlyStatService) FormatStatsList(tr *i18n.Localizer, stats []*models.UserMonthlyStat) string {
 	if len(stats) == 0 {
-		return "📭 Статистика пока отсутствует"
+		return tr.T("stats.list_empty")
 	}
 
 	var result strings.Builder
-	result.WriteString("📋 Ваша статистика по месяцам:\n\n")
+	result.WriteString(tr.T("stats.list_title") + "\n\n")
 
 	for i, stat := range stats {
 
-		nowYear := time.Now().Year()
-		nowMonth := int(time.Now().Month())
+		nowYear := s.clock.Now().Year()
+		nowMonth := int(s.clock.Now().Month())
 		if nowYear < stat.Year {
 			continue
 		} else if nowMonth < stat.Month && nowYear == stat.Year {
 			continue
 		}
 
-		monthName := time.Month(stat.Month).String()
-
-		// Краткий формат
-		workedHours := stat.WorkedMinutes / 60
-		workedMinutes := stat.WorkedMinutes % 60
-		plannedHours := stat.PlannedMinutes / 60
-		plannedMinutes := stat.PlannedMinutes % 60
-
-		var workedTime, plannedTime string
-		if workedMinutes == 0 {
-			workedTime = fmt.Sprintf("%dч", workedHours)
-		} else {
-			workedTime = fmt.Sprintf("%dч %dм", workedHours, workedMinutes)
-		}
-
-		if plannedMinutes == 0 {
-			plannedTime = fmt.Sprintf("%dч", plannedHours)
-		} else {
-			plannedTime = fmt.Sprintf("%dч %dм", plannedHours, plannedMinutes)
-		}
-
 		status := "✅"
 		if stat.DeficitMinutes > 0 {
 			status = "⚠️"
@@ -315,33 +280,22 @@ func (s *UserMonthlyStatService) FormatStatsList(stats []*models.UserMonthlyStat
 			status = "➕"
 		}
 
-		fmt.Fprintf(&result, "%d. %s %s %d - %d/%d дней, %s/%s %s\n",
+		balance := ""
+		if stat.OvertimeMinutes > 0 {
+			balance = " (+" + tr.Duration(stat.OvertimeMinutes) + ")"
+		} else if stat.DeficitMinutes > 0 {
+			balance = " (-" + tr.Duration(stat.DeficitMinutes) + ")"
+		}
+
+		result.WriteString(tr.T("stats.list_row",
 			i+1,
 			status,
-			monthName,
-			stat.Year,
+			tr.MonthYear(stat.Year, time.Month(stat.Month)),
 			stat.WorkedDays,
-			stat.PlannedDays,
-			workedTime,
-			plannedTime,
-			func() string {
-				if stat.OvertimeMinutes > 0 {
-					overtimeHours := stat.OvertimeMinutes / 60
-					overtimeMinutes := stat.OvertimeMinutes % 60
-					if overtimeMinutes == 0 {
-						return fmt.Sprintf("(+%dч)", overtimeHours)
-					}
-					return fmt.Sprintf("(+%dч %dм)", overtimeHours, overtimeMinutes)
-				} else if stat.DeficitMinutes > 0 {
-					deficitHours := stat.DeficitMinutes / 60
-					deficitMinutes := stat.DeficitMinutes % 60
-					if deficitMinutes == 0 {
-						return fmt.Sprintf("(-%dч)", deficitHours)
-					}
-					return fmt.Sprintf("(-%dч %dм)", deficitHours, deficitMinutes)
-				}
-				return ""
-			}())
+			tr.N("count.days", stat.PlannedDays, stat.PlannedDays),
+			tr.Duration(stat.WorkedMinutes),
+			tr.Duration(stat.PlannedMinutes),
+			balance) + "\n")
 	}
 
 	return result.String()
@@ -353,7 +307,11 @@ func (s *UserMonthlyStatService) CalculateCompletionPercentage(stat *models.User
 		return 0
 	}
 
-	percentage := (float64(stat.WorkedMinutes) / float64(stat.PlannedMinutes)) * 100
+	if stat.EffectivePlannedMinutes() == 0 {
+		return 100
+	}
+
+	percentage := (float64(stat.CountedMinutes()) / float64(stat.EffectivePlannedMinutes())) * 100
 	if percentage > 100 {
 		return 100
 	}
@@ -362,50 +320,6 @@ func (s *UserMonthlyStatService) CalculateCompletionPercentage(stat *models.User
 
 // GetCurrentMonthStat возвращает статистику за текущий месяц
 func (s *UserMonthlyStatService) GetCurrentMonthStat(userID uint) (*models.UserMonthlyStat, error) {
-	now := time.Now()
+	now := s.clock.Now()
 	return s.GetUserStatByMonth(userID, now.Year(), int(now.Month()))
 }
-
-// CreateStatsForNewSchedule создает статистику для всех пользователей при создании нового графика
-func (s *UserMonthlyStatService) CreateStatsForNewSchedule(schedule *models.WorkSchedule) error {
-	s.logger.WithFields(logrus.Fields{
-		"year":  schedule.Year,
-		"month": schedule.Month,
-	}).Info("Creating monthly stats for all users for new schedule")
-
-	return s.statRepo.CreateForAllUsers(
-		schedule.Year,
-		schedule.Month,
-		schedule.WorkDays,
-		schedule.TotalMinutes,
-	)
-}
-
-// UpdateStatsForSchedule обновляет статистику всех пользователей при изменении графика
-func (s *UserMonthlyStatService) UpdateStatsForSchedule(schedule *models.WorkSchedule) error {
-	s.logger.WithFields(logrus.Fields{
-		"year":  schedule.Year,
-		"month": schedule.Month,
-	}).Info("Updating monthly stats for all users for schedule update")
-
-	return s.statRepo.UpdateForAllUsers(
-		schedule.Year,
-		schedule.Month,
-		schedule.WorkDays,
-		schedule.TotalMinutes,
-	)
-}
-
-func (s *UserMonthlyStatService) GetRequiredMinutesByUserID(userID uint, year int, month int) (int, error) {
-	stats, err := s.statRepo.GetByUserAndMonth(userID, year, month)
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to get monthly stats")
-		return 0, err
-	}
-	// if stats == nil{
-	// 	s.logger.Info("No monthly stat")
-	// 	return 0, nil
-	// }
-
-	return stats.DeficitMinutes / (stats.PlannedDays - stats.WorkedDays), nil
-}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"work-schedule-bot/internal/i18n"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StatDrift - расхождение сохраненной статистики с пересчитанной из исходных данных
+type StatDrift struct {
+	UserID  uint
+	Year    int
+	Month   int
+	Fields  []string // показатели, которые отличались
+	Created bool     // записи статистики не было
+}
+
+// statRules - нормы учета и часовой пояс компании, по которым статистика считается из исходных данных
+type statRules struct {
+	policy   models.WorkPolicy
+	location *time.Location
+}
+
+func newStatRules(policy models.WorkPolicy, clk clock.Clock) statRules {
+	return statRules{policy: policy, location: clk.Location()}
+}
+
+// computeMonthlyStat строит статистику месяца только из графика, дат приема и увольнения,
+// рабочих сессий и периодов отсутствия
+func (r statRules) computeMonthlyStat(repos *repository.Repositories, userID uint, year, month int) (*models.UserMonthlyStat, error) {
+	stat := &models.UserMonthlyStat{
+		UserID: userID,
+		Year:   year,
+		Month:  month,
+	}
+
+	monthStart := clock.MonthStart(year, time.Month(month), r.location)
+	monthEnd := monthStart.AddDate(0, 1, -1)
+
+	// Плановые показатели - из графика месяца
+	schedule, err := repos.WorkSchedules.GetByYearMonth(year, month)
+	if err != nil {
+		return nil, i18n.Errorf("schedule.get_failed", err)
+	}
+	if schedule != nil {
+		stat.PlannedDays = schedule.WorkDays
+		stat.PlannedMinutes = schedule.TotalMinutes
+
+		user, err := repos.Users.GetByID(userID)
+		if err != nil {
+			return nil, i18n.Errorf("user.get_failed", err)
+		}
+		if err := clipPlanToEmployment(repos, user, stat, schedule, monthStart, monthEnd); err != nil {
+			return nil, err
+		}
+	}
+
+	sessions, err := repos.WorkSessions.GetByUserIDAndMonth(userID, year, month)
+	if err != nil {
+		return nil, i18n.Errorf("session.list_get_failed", err)
+	}
+
+	// Фактические показатели - из завершенных сессий с разбивкой по типам
+	type typeTotals struct {
+		days            map[string]bool
+		workedMinutes   int
+		requiredMinutes int
+	}
+	byType := make(map[string]*typeTotals)
+	add := func(sessionType, day string, worked, required int) {
+		totals, ok := byType[sessionType]
+		if !ok {
+			totals = &typeTotals{days: make(map[string]bool)}
+			byType[sessionType] = totals
+		}
+		totals.days[day] = true
+		totals.workedMinutes += worked
+		totals.requiredMinutes += required
+	}
+
+	busyDays := make(map[string]bool)
+	for _, session := range sessions {
+		day := session.Date.Format("2006-01-02")
+		busyDays[day] = true
+		if session.Status != models.StatusCompleted {
+			continue
+		}
+		add(session.SessionType, day, session.WorkedMinutes, session.RequiredMinutes)
+	}
+
+	// Рабочие дни периодов отсутствия, для которых сессия так и не была создана
+	periods, err := repos.AbsencePeriods.GetByUserID(userID)
+	if err != nil {
+		return nil, i18n.Errorf("absence.periods_get_failed", err)
+	}
+
+	dayMinutes := r.policy.WorkDayMinutes
+	for _, period := range periods {
+		for date := maxDate(period.StartDate, monthStart); !date.After(minDate(period.EndDate, monthEnd)); date = date.AddDate(0, 0, 1) {
+			day := date.Format("2006-01-02")
+			if busyDays[day] {
+				continue
+			}
+
+			isNonWorking, err := repos.NonWorkingDays.IsNonWorkingDay(date)
+			if err != nil {
+				return nil, i18n.Errorf("absence.non_working_check_failed", date, err)
+			}
+			if isNonWorking {
+				continue
+			}
+
+			add(period.Type, day, 0, dayMinutes)
+		}
+	}
+
+	rows := make([]models.SessionTypeTotals, 0, len(byType))
+	for sessionType, totals := range byType {
+		rows = append(rows, models.SessionTypeTotals{
+			SessionType:     sessionType,
+			Days:            len(totals.days),
+			WorkedMinutes:   totals.workedMinutes,
+			RequiredMinutes: totals.requiredMinutes,
+		})
+	}
+
+	stat.ApplyMonthTotals(models.AggregateMonthTotals(rows, r.policy))
+	return stat, nil
+}
+
+// clipPlanToEmployment исключает из плана месяца рабочие дни до приема и после увольнения сотрудника
+func clipPlanToEmployment(
+	repos *repository.Repositories,
+	user *models.User,
+	stat *models.UserMonthlyStat,
+	schedule *models.WorkSchedule,
+	monthStart, monthEnd time.Time,
+) error {
+	if user == nil || (user.HireDate == nil && user.TerminationDate == nil) {
+		return nil
+	}
+
+	excluded := 0
+	for date := monthStart; !date.After(monthEnd); date = date.AddDate(0, 0, 1) {
+		if user.EmployedOn(date) {
+			continue
+		}
+
+		isNonWorking, err := repos.NonWorkingDays.IsNonWorkingDay(date)
+		if err != nil {
+			return i18n.Errorf("absence.non_working_check_failed", date, err)
+		}
+		if !isNonWorking {
+			excluded++
+		}
+	}
+
+	stat.PlannedDays = max(stat.PlannedDays-excluded, 0)
+	stat.PlannedMinutes = max(stat.PlannedMinutes-excluded*schedule.WorkMinutesPerDay, 0)
+	return nil
+}
+
+// rebuildMonthlyStat пересчитывает статистику месяца и сохраняет ее, если сохраненная отличается.
+// Возвращает nil, если статистика уже была верной.
+func (r statRules) rebuildMonthlyStat(repos *repository.Repositories, userID uint, year, month int) (*StatDrift, error) {
+	computed, err := r.computeMonthlyStat(repos, userID, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := repos.MonthlyStats.GetByUserAndMonth(userID, year, month)
+	if err != nil {
+		return nil, i18n.Errorf("stats.get_failed", err)
+	}
+
+	if stored == nil {
+		// Месяц без графика и без событий не требует записи
+		if len(computed.DiffTotals(&models.UserMonthlyStat{})) == 0 {
+			return nil, nil
+		}
+		if err := repos.MonthlyStats.Create(computed); err != nil {
+			return nil, i18n.Errorf("stats.create_failed", err)
+		}
+		return &StatDrift{UserID: userID, Year: year, Month: month, Created: true}, nil
+	}
+
+	diff := stored.DiffTotals(computed)
+	if len(diff) == 0 {
+		return nil, nil
+	}
+
+	stored.CopyTotals(computed)
+	if err := repos.MonthlyStats.Update(stored); err != nil {
+		return nil, i18n.Errorf("stats.save_failed", err)
+	}
+
+	return &StatDrift{UserID: userID, Year: year, Month: month, Fields: diff}, nil
+}
+
+// rebuildMonthForAllUsers пересчитывает статистику месяца всех пользователей,
+// работавших в этом месяце
+func (r statRules) rebuildMonthForAllUsers(repos *repository.Repositories, year, month int) error {
+	users, err := repos.Users.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if !user.EmployedIn(year, month) {
+			continue
+		}
+		if _, err := r.rebuildMonthlyStat(repos, user.ID, year, month); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebuildUserStats пересчитывает статистику сотрудника за все его месяцы,
+// например после изменения дат приема или увольнения
+func (r statRules) rebuildUserStats(repos *repository.Repositories, user *models.User) error {
+	months, err := statMonths(repos, user)
+	if err != nil {
+		return i18n.Errorf("stats.get_failed", err)
+	}
+
+	for _, m := range months {
+		if _, err := r.rebuildMonthlyStat(repos, user.ID, m[0], m[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statMonths возвращает месяцы, по которым должна быть статистика пользователя:
+// месяцы с графиком, пока сотрудник работал, и месяцы, по которым статистика уже сохранена
+func statMonths(repos *repository.Repositories, user *models.User) ([][2]int, error) {
+	seen := make(map[[2]int]bool)
+
+	schedules, err := repos.WorkSchedules.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, schedule := range schedules {
+		if user.EmployedIn(schedule.Year, schedule.Month) {
+			seen[[2]int{schedule.Year, schedule.Month}] = true
+		}
+	}
+
+	stats, err := repos.MonthlyStats.GetByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, stat := range stats {
+		seen[[2]int{stat.Year, stat.Month}] = true
+	}
+
+	months := make([][2]int, 0, len(seen))
+	for month := range seen {
+		months = append(months, month)
+	}
+	sort.Slice(months, func(i, j int) bool {
+		if months[i][0] != months[j][0] {
+			return months[i][0] < months[j][0]
+		}
+		return months[i][1] < months[j][1]
+	})
+
+	return months, nil
+}
+
+// statRules возвращает нормы и пояс, по которым сервис пересчитывает статистику
+func (s *UserMonthlyStatService) statRules() statRules {
+	return newStatRules(s.policy, s.clock)
+}
+
+// Recalculate пересчитывает статистику указанных пользователей.
+// Если year равен 0, пересчитываются все месяцы с графиком или сохраненной статистикой.
+func (s *UserMonthlyStatService) Recalculate(users []*models.User, year, month int) ([]StatDrift, error) {
+	var drifts []StatDrift
+	rules := s.statRules()
+
+	for _, user := range users {
+		// Расхождения пользователя учитываются, только если его транзакция зафиксирована
+		var userDrifts []StatDrift
+		err := s.uow.WithTx(func(repos *repository.Repositories) error {
+			months := [][2]int{{year, month}}
+			if year == 0 {
+				var err error
+				months, err = statMonths(repos, user)
+				if err != nil {
+					return err
+				}
+			}
+
+			for _, m := range months {
+				drift, err := rules.rebuildMonthlyStat(repos, user.ID, m[0], m[1])
+				if err != nil {
+					return fmt.Errorf("%02d.%d: %v", m[1], m[0], err)
+				}
+				if drift != nil {
+					userDrifts = append(userDrifts, *drift)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return drifts, i18n.Errorf("stats.recalc_failed", user.ChatID, err)
+		}
+		drifts = append(drifts, userDrifts...)
+	}
+
+	for i := range drifts {
+		s.logDrift(&drifts[i])
+	}
+
+	return drifts, nil
+}
+
+// CheckConsistency сверяет всю сохраненную статистику с пересчитанной и исправляет расхождения
+func (s *UserMonthlyStatService) CheckConsistency() ([]StatDrift, error) {
+	users, err := s.userRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	drifts, err := s.Recalculate(users, 0, 0)
+	if err != nil {
+		return drifts, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"users": len(users),
+		"fixed": len(drifts),
+	}).Info("Monthly stats consistency check finished")
+
+	return drifts, nil
+}
+
+func (s *UserMonthlyStatService) logDrift(drift *StatDrift) {
+	if drift == nil {
+		return
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id": drift.UserID,
+		"year":    drift.Year,
+		"month":   drift.Month,
+		"fields":  strings.Join(drift.Fields, ","),
+		"created": drift.Created,
+	}).Warn("Monthly stat differed from recomputed value and was fixed")
+}
+
+// FormatDrifts форматирует отчет о пересчете статистики
+func (s *UserMonthlyStatService) FormatDrifts(tr *i18n.Localizer, drifts []StatDrift, users []*models.User) string {
+	if len(drifts) == 0 {
+		return tr.T("stats.recalc_no_drifts")
+	}
+
+	names := make(map[uint]string, len(users))
+	for _, user := range users {
+		names[user.ID] = strings.TrimSpace(user.FirstName + " " + user.LastName)
+	}
+
+	var lines []string
+	lines = append(lines, tr.T("stats.recalc_fixed", len(drifts)))
+	lines = append(lines, "")
+
+	for _, drift := range drifts {
+		name := names[drift.UserID]
+		if name == "" {
+			name = fmt.Sprintf("ID %d", drift.UserID)
+		}
+
+		if drift.Created {
+			lines = append(lines, tr.T("stats.recalc_created", name, drift.Month, drift.Year))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("• %s, %02d.%d - %s", name, drift.Month, drift.Year, strings.Join(drift.Fields, ", ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
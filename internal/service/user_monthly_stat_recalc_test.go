@@ -0,0 +1,202 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+	"work-schedule-bot/internal/migrations"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := repository.OpenDatabase(repository.DriverSQLite, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database instance: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if _, err := migrations.NewMigrator(db).Up(); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func newTestStatService(t *testing.T, db *gorm.DB, policy models.WorkPolicy) *UserMonthlyStatService {
+	t.Helper()
+
+	statRepo, err := repository.NewGormUserMonthlyStatRepository(db, clock.System(nil))
+	if err != nil {
+		t.Fatalf("failed to create stat repository: %v", err)
+	}
+	userRepo, err := repository.NewUserRepository(db)
+	if err != nil {
+		t.Fatalf("failed to create user repository: %v", err)
+	}
+
+	return NewUserMonthlyStatService(statRepo, userRepo, repository.NewGormUnitOfWork(db, clock.System(nil)), policy, clock.System(nil))
+}
+
+func TestCheckConsistencyFixesDrift(t *testing.T) {
+	db := openTestDatabase(t)
+	service := newTestStatService(t, db, models.DefaultWorkPolicy())
+
+	user := models.User{ChatID: 1, FirstName: "Test", Role: models.RoleEmployee}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	schedule := models.WorkSchedule{Year: 2026, Month: 3, WorkDays: 22, WorkMinutesPerDay: 480, TotalMinutes: 22 * 480}
+	if err := db.Create(&schedule).Error; err != nil {
+		t.Fatalf("failed to create schedule: %v", err)
+	}
+
+	// Два завершенных рабочих дня (понедельник и вторник)
+	for day := 2; day <= 3; day++ {
+		date := time.Date(2026, time.March, day, 0, 0, 0, 0, time.Local)
+		clockOut := date.Add(17 * time.Hour)
+		session := models.WorkSession{
+			UserID:          user.ID,
+			Date:            date,
+			ClockInTime:     date.Add(9 * time.Hour),
+			ClockOutTime:    &clockOut,
+			RequiredMinutes: 480,
+			WorkedMinutes:   500,
+			Status:          models.StatusCompleted,
+			SessionType:     models.SessionTypeWork,
+		}
+		if err := db.Create(&session).Error; err != nil {
+			t.Fatalf("failed to create session: %v", err)
+		}
+	}
+
+	// Прогул в среду, для которого сессия отсутствия потерялась
+	truancy := models.AbsencePeriod{
+		UserID:    user.ID,
+		Type:      models.AbsenceTypeTruancy,
+		StartDate: time.Date(2026, time.March, 4, 0, 0, 0, 0, time.Local),
+		EndDate:   time.Date(2026, time.March, 4, 0, 0, 0, 0, time.Local),
+	}
+	if err := db.Create(&truancy).Error; err != nil {
+		t.Fatalf("failed to create absence period: %v", err)
+	}
+
+	// Сохраненная статистика разошлась с данными
+	stale := models.UserMonthlyStat{UserID: user.ID, Year: 2026, Month: 3, PlannedDays: 20, WorkedDays: 7, WorkedMinutes: 10}
+	if err := db.Create(&stale).Error; err != nil {
+		t.Fatalf("failed to create stat: %v", err)
+	}
+
+	drifts, err := service.CheckConsistency()
+	if err != nil {
+		t.Fatalf("CheckConsistency: %v", err)
+	}
+	if len(drifts) != 1 || drifts[0].Created {
+		t.Fatalf("drifts = %+v, want one fixed row", drifts)
+	}
+
+	stat, err := service.GetUserStatByMonth(user.ID, 2026, 3)
+	if err != nil || stat == nil {
+		t.Fatalf("GetUserStatByMonth = %v, %v", stat, err)
+	}
+	if stat.PlannedDays != 22 || stat.PlannedMinutes != 22*480 {
+		t.Errorf("planned = %d days / %d min, want 22 / %d", stat.PlannedDays, stat.PlannedMinutes, 22*480)
+	}
+	if stat.WorkedDays != 2 || stat.WorkedMinutes != 1000 {
+		t.Errorf("worked = %d days / %d min, want 2 / 1000", stat.WorkedDays, stat.WorkedMinutes)
+	}
+	if stat.UncreditedAbsenceDays != 1 || stat.UncreditedAbsenceMinutes != models.DefaultWorkPolicy().WorkDayMinutes {
+		t.Errorf("uncredited absence = %d days / %d min, want 1 / %d",
+			stat.UncreditedAbsenceDays, stat.UncreditedAbsenceMinutes, models.DefaultWorkPolicy().WorkDayMinutes)
+	}
+
+	// Повторная проверка ничего не меняет
+	drifts, err = service.CheckConsistency()
+	if err != nil {
+		t.Fatalf("second CheckConsistency: %v", err)
+	}
+	if len(drifts) != 0 {
+		t.Errorf("second check found drifts %+v, want none", drifts)
+	}
+}
+
+func TestPlanClippedToEmploymentDates(t *testing.T) {
+	db := openTestDatabase(t)
+	statService := newTestStatService(t, db, models.DefaultWorkPolicy())
+
+	userRepo, err := repository.NewUserRepository(db)
+	if err != nil {
+		t.Fatalf("failed to create user repository: %v", err)
+	}
+	scheduleRepo, err := repository.NewGormWorkScheduleRepository(db, clock.System(nil))
+	if err != nil {
+		t.Fatalf("failed to create schedule repository: %v", err)
+	}
+	users := NewUserService(userRepo, scheduleRepo, statService, repository.NewGormUnitOfWork(db, clock.System(nil)), clock.System(nil))
+
+	user := models.User{ChatID: 1, FirstName: "Test", Role: models.RoleEmployee}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	schedule := models.WorkSchedule{Year: 2026, Month: 3, WorkDays: 22, WorkMinutesPerDay: 480, TotalMinutes: 22 * 480}
+	if err := db.Create(&schedule).Error; err != nil {
+		t.Fatalf("failed to create schedule: %v", err)
+	}
+	// Выходные марта 2026
+	for _, day := range []int{1, 7, 8, 14, 15, 21, 22, 28, 29} {
+		date := time.Date(2026, time.March, day, 0, 0, 0, 0, time.Local)
+		if err := db.Create(&models.NonWorkingDay{Date: date, Year: 2026, Month: 3, Day: day}).Error; err != nil {
+			t.Fatalf("failed to create non-working day: %v", err)
+		}
+	}
+	if err := statService.CreateStatsForNewUser(user.ID, []*models.WorkSchedule{&schedule}); err != nil {
+		t.Fatalf("CreateStatsForNewUser: %v", err)
+	}
+
+	planned := func(wantDays int) {
+		t.Helper()
+		stat, err := statService.GetUserStatByMonth(user.ID, 2026, 3)
+		if err != nil || stat == nil {
+			t.Fatalf("GetUserStatByMonth = %v, %v", stat, err)
+		}
+		if stat.PlannedDays != wantDays || stat.PlannedMinutes != wantDays*480 {
+			t.Errorf("planned = %d days / %d min, want %d / %d", stat.PlannedDays, stat.PlannedMinutes, wantDays, wantDays*480)
+		}
+	}
+	planned(22)
+
+	// Прием в понедельник 16 марта: 10 рабочих дней до приема не входят в план
+	hired := time.Date(2026, time.March, 16, 0, 0, 0, 0, time.Local)
+	if _, err := users.SetHireDate(user.ChatID, &hired, models.SystemActor); err != nil {
+		t.Fatalf("SetHireDate: %v", err)
+	}
+	planned(12)
+
+	// Увольнение в пятницу 20 марта: еще 7 рабочих дней после увольнения
+	if _, err := users.DeactivateUser(user.ChatID, time.Date(2026, time.March, 20, 0, 0, 0, 0, time.Local), models.SystemActor); err != nil {
+		t.Fatalf("DeactivateUser: %v", err)
+	}
+	planned(5)
+
+	if _, err := users.SetHireDate(user.ChatID, nil, models.SystemActor); err != nil {
+		t.Fatalf("clear hire date: %v", err)
+	}
+	if _, err := users.ReactivateUser(user.ChatID, models.SystemActor); err != nil {
+		t.Fatalf("ReactivateUser: %v", err)
+	}
+	planned(22)
+}
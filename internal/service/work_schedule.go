@@ -5,8 +5,10 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"work-schedule-bot/internal/i18n"
 	"work-schedule-bot/internal/models"
 	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
 
 	"github.com/sirupsen/logrus"
 )
@@ -15,6 +17,9 @@ type WorkScheduleService struct {
 	repo                   repository.WorkScheduleRepository
 	userMonthlyStatService *UserMonthlyStatService
 	nonWorkingDayService   *NonWorkingDayService // ДОБАВЛЕНО
+	uow                    repository.UnitOfWork
+	policy                 models.WorkPolicy
+	clock                  clock.Clock
 	logger                 *logrus.Logger
 }
 
@@ -22,24 +27,30 @@ func NewWorkScheduleService(
 	repo repository.WorkScheduleRepository,
 	userMonthlyStatService *UserMonthlyStatService,
 	nonWorkingDayService *NonWorkingDayService, // ДОБАВЛЕНО
+	uow repository.UnitOfWork,
+	policy models.WorkPolicy,
+	clk clock.Clock,
 ) *WorkScheduleService {
 	return &WorkScheduleService{
 		repo:                   repo,
 		userMonthlyStatService: userMonthlyStatService,
 		nonWorkingDayService:   nonWorkingDayService, // ДОБАВЛЕНО
+		uow:                    uow,
+		policy:                 policy,
+		clock:                  clk,
 		logger:                 logrus.New(),
 	}
 }
 
 // GenerateSchedulesFromNonWorkingDays автоматически создает/обновляет графики на основе выходных дней
-func (s *WorkScheduleService) GenerateSchedulesFromNonWorkingDays(year int, workMinutesPerDay int) ([]*models.WorkSchedule, error) {
+func (s *WorkScheduleService) GenerateSchedulesFromNonWorkingDays(year int, workMinutesPerDay int, actor models.Actor) ([]*models.WorkSchedule, error) {
 	s.logger.Infof("Generating schedules for year %d with %d minutes per day", year, workMinutesPerDay)
 	
 	var generatedSchedules []*models.WorkSchedule
 	
 	// Для каждого месяца года
 	for month := 1; month <= 12; month++ {
-		schedule, err := s.GenerateScheduleForMonth(year, month, workMinutesPerDay)
+		schedule, err := s.GenerateScheduleForMonth(year, month, workMinutesPerDay, actor)
 		if err != nil {
 			s.logger.Errorf("Failed to generate schedule for %d-%02d: %v", year, month, err)
 			continue
@@ -52,7 +63,7 @@ func (s *WorkScheduleService) GenerateSchedulesFromNonWorkingDays(year int, work
 }
 
 // GenerateScheduleForMonth создает или обновляет график для конкретного месяца
-func (s *WorkScheduleService) GenerateScheduleForMonth(year, month, workMinutesPerDay int) (*models.WorkSchedule, error) {
+func (s *WorkScheduleService) GenerateScheduleForMonth(year, month, workMinutesPerDay int, actor models.Actor) (*models.WorkSchedule, error) {
 	// Получаем выходные дни для этого месяца
 	nonWorkingDays, err := s.nonWorkingDayService.GetNonWorkingDaysForMonth(year, month)
 	if err != nil {
@@ -60,7 +71,7 @@ func (s *WorkScheduleService) GenerateScheduleForMonth(year, month, workMinutesP
 	}
 	
 	// Получаем общее количество дней в месяце
-	daysInMonth := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	daysInMonth := clock.DaysInMonth(year, time.Month(month))
 	
 	// Рассчитываем рабочие дни: всего дней в месяце минус выходные
 	workDays := daysInMonth - len(nonWorkingDays)
@@ -81,7 +92,7 @@ func (s *WorkScheduleService) GenerateScheduleForMonth(year, month, workMinutesP
 			workMinutesPerDay = existingSchedule.WorkMinutesPerDay
 		}
 		
-		updatedSchedule, err := s.UpdateSchedule(existingSchedule.ID, workDays, workMinutesPerDay)
+		updatedSchedule, err := s.UpdateSchedule(existingSchedule.ID, workDays, workMinutesPerDay, actor)
 		if err != nil {
 			return nil, fmt.Errorf("failed to update schedule: %v", err)
 		}
@@ -91,7 +102,7 @@ func (s *WorkScheduleService) GenerateScheduleForMonth(year, month, workMinutesP
 		s.logger.Infof("Creating new schedule for %d-%02d: %d working days, %d minutes per day", 
 			year, month, workDays, workMinutesPerDay)
 		
-		newSchedule, err := s.CreateSchedule(year, month, workDays, workMinutesPerDay)
+		newSchedule, err := s.CreateSchedule(year, month, workDays, workMinutesPerDay, actor)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create schedule: %v", err)
 		}
@@ -102,7 +113,7 @@ func (s *WorkScheduleService) GenerateScheduleForMonth(year, month, workMinutesP
 // CalculateWorkingDaysForMonth рассчитывает количество рабочих дней в месяце
 func (s *WorkScheduleService) CalculateWorkingDaysForMonth(year, month int) (int, error) {
 	// Получаем общее количество дней в месяце
-	daysInMonth := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	daysInMonth := clock.DaysInMonth(year, time.Month(month))
 	
 	// Получаем выходные дни для этого месяца
 	nonWorkingDays, err := s.nonWorkingDayService.GetNonWorkingDaysForMonth(year, month)
@@ -115,7 +126,7 @@ func (s *WorkScheduleService) CalculateWorkingDaysForMonth(year, month int) (int
 }
 
 // UpdateAllSchedulesFromNonWorkingDays обновляет все графики на основе текущих выходных дней
-func (s *WorkScheduleService) UpdateAllSchedulesFromNonWorkingDays() (int, error) {
+func (s *WorkScheduleService) UpdateAllSchedulesFromNonWorkingDays(actor models.Actor) (int, error) {
 	s.logger.Info("Updating all schedules from non-working days")
 	
 	// Получаем все графики
@@ -139,7 +150,7 @@ func (s *WorkScheduleService) UpdateAllSchedulesFromNonWorkingDays() (int, error
 			s.logger.Infof("Updating schedule for %d-%02d: %d → %d working days", 
 				schedule.Year, schedule.Month, schedule.WorkDays, calculatedWorkDays)
 			
-			_, err = s.UpdateSchedule(schedule.ID, calculatedWorkDays, schedule.WorkMinutesPerDay)
+			_, err = s.UpdateSchedule(schedule.ID, calculatedWorkDays, schedule.WorkMinutesPerDay, actor)
 			if err != nil {
 				s.logger.Errorf("Failed to update schedule %d: %v", schedule.ID, err)
 				continue
@@ -192,14 +203,14 @@ func (s *WorkScheduleService) GetWorkMinutesForDay(date time.Time) (int, error)
 	
 	if schedule == nil {
 		// Если график не установлен, используем значение по умолчанию
-		return 480, nil // 8 часов по умолчанию
+		return s.policy.DefaultDayMinutes, nil
 	}
 	
 	return schedule.WorkMinutesPerDay, nil
 }
 
 // CreateSchedule создает новый рабочий график
-func (s *WorkScheduleService) CreateSchedule(year, month, workDays, workMinutesPerDay int) (*models.WorkSchedule, error) {
+func (s *WorkScheduleService) CreateSchedule(year, month, workDays, workMinutesPerDay int, actor models.Actor) (*models.WorkSchedule, error) {
 	s.logger.WithFields(logrus.Fields{
 		"year":                 year,
 		"month":                month,
@@ -214,15 +225,30 @@ func (s *WorkScheduleService) CreateSchedule(year, month, workDays, workMinutesP
 		WorkMinutesPerDay: workMinutesPerDay,
 	}
 
-	if !schedule.IsValid() {
+	if !s.policy.ValidYear(schedule.Year) || !schedule.IsValid() {
 		s.logger.Warn("Invalid schedule data provided")
-		return nil, fmt.Errorf("некорректные данные: год 2000-2100, месяц 1-12, дни 0-31, минуты в день 1-1440")
+		return nil, i18n.Errorf("schedule.invalid", s.policy.MinYear, s.policy.MaxYear)
 	}
 
-	// Создаем график
-	err := s.repo.Create(schedule)
+	// Создаем график и статистику всех пользователей для него в одной транзакции
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.WorkSchedules.Create(schedule); err != nil {
+			s.logger.WithError(err).Error("Failed to create schedule")
+			return err
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditCreate, models.AuditEntitySchedule, schedule.ID)
+		if err := recordAudit(repos.Audit, entry, nil, schedule); err != nil {
+			return err
+		}
+
+		if err := newStatRules(s.policy, s.clock).rebuildMonthForAllUsers(repos, schedule.Year, schedule.Month); err != nil {
+			s.logger.WithError(err).Error("Failed to create monthly stats for new schedule")
+			return i18n.Errorf("schedule.stats_create_failed", err)
+		}
+		return nil
+	})
 	if err != nil {
-		s.logger.WithError(err).Error("Failed to create schedule")
 		return nil, err
 	}
 
@@ -231,17 +257,11 @@ func (s *WorkScheduleService) CreateSchedule(year, month, workDays, workMinutesP
 		"total_minutes": schedule.TotalMinutes,
 	}).Info("Schedule created successfully")
 
-	// Создаем статистику для всех пользователей для нового графика
-	go func() {
-		if err := s.userMonthlyStatService.CreateStatsForNewSchedule(schedule); err != nil {
-			s.logger.WithError(err).Error("Failed to create monthly stats for new schedule")
-		}
-	}()
-
 	return schedule, nil
 }
 
-func (s *WorkScheduleService) UpdateSchedule(id uint, workDays, workMinutesPerDay int) (*models.WorkSchedule, error) {
+// UpdateSchedule обновляет рабочие дни и норму графика
+func (s *WorkScheduleService) UpdateSchedule(id uint, workDays, workMinutesPerDay int, actor models.Actor) (*models.WorkSchedule, error) {
 	s.logger.WithFields(logrus.Fields{
 		"id":                   id,
 		"work_days":            workDays,
@@ -257,23 +277,40 @@ func (s *WorkScheduleService) UpdateSchedule(id uint, workDays, workMinutesPerDa
 
 	if schedule == nil {
 		s.logger.WithField("id", id).Warn("Schedule not found")
-		return nil, fmt.Errorf("график с ID %d не найден", id)
+		return nil, i18n.Errorf("schedule.not_found_id", id)
 	}
 
+	before := *schedule
+
 	// Обновляем поля
 	schedule.WorkDays = workDays
 	schedule.WorkMinutesPerDay = workMinutesPerDay
 	schedule.TotalMinutes = schedule.CalculateTotalMinutes()
 
-	if !schedule.IsValid() {
+	if !s.policy.ValidYear(schedule.Year) || !schedule.IsValid() {
 		s.logger.Warn("Invalid schedule data after update")
-		return nil, fmt.Errorf("некорректные данные после обновления")
+		return nil, i18n.Errorf("schedule.invalid_after_update")
 	}
 
-	// Обновляем график
-	err = s.repo.Update(schedule) // Только ошибка
+	// Обновляем график и статистику всех пользователей в одной транзакции
+	err = s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.WorkSchedules.Update(schedule); err != nil {
+			s.logger.WithError(err).Error("Failed to update schedule")
+			return err
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditUpdate, models.AuditEntitySchedule, schedule.ID)
+		if err := recordAudit(repos.Audit, entry, &before, schedule); err != nil {
+			return err
+		}
+
+		if err := newStatRules(s.policy, s.clock).rebuildMonthForAllUsers(repos, schedule.Year, schedule.Month); err != nil {
+			s.logger.WithError(err).Error("Failed to update monthly stats after schedule update")
+			return i18n.Errorf("schedule.stats_update_failed", err)
+		}
+		return nil
+	})
 	if err != nil {
-		s.logger.WithError(err).Error("Failed to update schedule")
 		return nil, err
 	}
 
@@ -284,18 +321,11 @@ func (s *WorkScheduleService) UpdateSchedule(id uint, workDays, workMinutesPerDa
 		"total_minutes": schedule.TotalMinutes,
 	}).Info("Schedule updated successfully")
 
-	// Обновляем статистику для всех пользователей при изменении графика
-	go func() {
-		if err := s.userMonthlyStatService.UpdateStatsForSchedule(schedule); err != nil {
-			s.logger.WithError(err).Error("Failed to update monthly stats after schedule update")
-		}
-	}()
-
 	return schedule, nil
 }
 
 // DeleteSchedule удаляет график
-func (s *WorkScheduleService) DeleteSchedule(id uint) error {
+func (s *WorkScheduleService) DeleteSchedule(id uint, actor models.Actor) error {
 	s.logger.WithField("id", id).Info("Deleting work schedule")
 
 	// Проверяем существование
@@ -307,12 +337,19 @@ func (s *WorkScheduleService) DeleteSchedule(id uint) error {
 
 	if schedule == nil {
 		s.logger.WithField("id", id).Warn("Schedule not found for deletion")
-		return fmt.Errorf("график с ID %d не найден", id)
+		return i18n.Errorf("schedule.not_found_id", id)
 	}
 
-	err = s.repo.Delete(id)
+	err = s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.WorkSchedules.Delete(id); err != nil {
+			s.logger.WithError(err).Error("Failed to delete schedule")
+			return err
+		}
+
+		entry := models.NewAuditEntry(actor, models.AuditDelete, models.AuditEntitySchedule, id)
+		return recordAudit(repos.Audit, entry, schedule, nil)
+	})
 	if err != nil {
-		s.logger.WithError(err).Error("Failed to delete schedule")
 		return err
 	}
 
@@ -355,82 +392,38 @@ func (s *WorkScheduleService) GetSchedulesByYear(year int) ([]*models.WorkSchedu
 }
 
 // FormatSchedule форматирует график для отображения
-func (s *WorkScheduleService) FormatSchedule(schedule *models.WorkSchedule) string {
+func (s *WorkScheduleService) FormatSchedule(tr *i18n.Localizer, schedule *models.WorkSchedule) string {
 	if schedule == nil {
-		return "❌ График не найден"
+		return tr.T("schedule.not_found")
 	}
 
-	// Конвертируем минуты в часы:минуты
-	hoursPerDay := schedule.WorkMinutesPerDay / 60
-	minutesPerDay := schedule.WorkMinutesPerDay % 60
-
-	totalHours := schedule.TotalMinutes / 60
-	totalMinutes := schedule.TotalMinutes % 60
-
-	var timePerDay string
-	if minutesPerDay == 0 {
-		timePerDay = fmt.Sprintf("%dч", hoursPerDay)
-	} else {
-		timePerDay = fmt.Sprintf("%dч %dм", hoursPerDay, minutesPerDay)
-	}
-
-	var totalTime string
-	if totalMinutes == 0 {
-		totalTime = fmt.Sprintf("%dч", totalHours)
-	} else {
-		totalTime = fmt.Sprintf("%dч %dм", totalHours, totalMinutes)
-	}
-
-	monthName := time.Month(schedule.Month).String()
-
-	return fmt.Sprintf(
-		`📅 График работы: %s %d
-
-🆔 ID: %d
-📊 Рабочих дней: %d
-⏰ Время в день: %s
-📈 Всего времени: %s
-📅 Создан: %s
-🔄 Обновлен: %s`,
-		monthName, schedule.Year,
+	return tr.T("schedule.details",
+		tr.MonthYear(schedule.Year, time.Month(schedule.Month)),
 		schedule.ID,
 		schedule.WorkDays,
-		timePerDay,
-		totalTime,
-		schedule.CreatedAt.Format("02.01.2006 15:04"),
-		schedule.UpdatedAt.Format("02.01.2006 15:04"),
+		tr.Duration(schedule.WorkMinutesPerDay),
+		tr.Duration(schedule.TotalMinutes),
+		tr.DateTime(schedule.CreatedAt),
+		tr.DateTime(schedule.UpdatedAt),
 	)
 }
 
 // FormatScheduleList форматирует список графиков
-func (s *WorkScheduleService) FormatScheduleList(schedules []*models.WorkSchedule) string {
+func (s *WorkScheduleService) FormatScheduleList(tr *i18n.Localizer, schedules []*models.WorkSchedule) string {
 	if len(schedules) == 0 {
-		return "📭 Графиков работы пока нет"
+		return tr.T("schedule.list_empty")
 	}
 
 	var result strings.Builder
-	result.WriteString("📋 Все графики работы:\n\n")
+	result.WriteString(tr.T("schedule.list_title") + "\n\n")
 
 	for i, schedule := range schedules {
-		monthName := time.Month(schedule.Month).String()
-
-		hoursPerDay := schedule.WorkMinutesPerDay / 60
-		minutesPerDay := schedule.WorkMinutesPerDay % 60
-
-		var timePerDay string
-		if minutesPerDay == 0 {
-			timePerDay = fmt.Sprintf("%dч", hoursPerDay)
-		} else {
-			timePerDay = fmt.Sprintf("%dч %dм", hoursPerDay, minutesPerDay)
-		}
-
 		result.WriteString(fmt.Sprintf(
-			"%d. %s %d - %d дней × %s (ID: %d)\n",
+			"%d. %s - %s × %s (ID: %d)\n",
 			i+1,
-			monthName,
-			schedule.Year,
-			schedule.WorkDays,
-			timePerDay,
+			tr.MonthYear(schedule.Year, time.Month(schedule.Month)),
+			tr.N("count.days", schedule.WorkDays),
+			tr.Duration(schedule.WorkMinutesPerDay),
 			schedule.ID,
 		))
 	}
@@ -443,31 +436,31 @@ func (s *WorkScheduleService) ParseScheduleData(input string) (year, month, work
 	// Формат: "2024 12 22 480" (год месяц дни минуты_в_день)
 	parts := strings.Fields(input)
 	if len(parts) != 4 {
-		return 0, 0, 0, 0, fmt.Errorf("неверный формат. Используйте: год месяц дни минуты_в_день")
+		return 0, 0, 0, 0, i18n.Errorf("schedule.parse_format")
 	}
 
 	// Парсим год
 	year, err = strconv.Atoi(parts[0])
-	if err != nil || year < 2000 || year > 2100 {
-		return 0, 0, 0, 0, fmt.Errorf("неверный год. Должен быть между 2000 и 2100")
+	if policy := s.policy; err != nil || !policy.ValidYear(year) {
+		return 0, 0, 0, 0, i18n.Errorf("schedule.parse_year", policy.MinYear, policy.MaxYear)
 	}
 
 	// Парсим месяц
 	month, err = strconv.Atoi(parts[1])
 	if err != nil || month < 1 || month > 12 {
-		return 0, 0, 0, 0, fmt.Errorf("неверный месяц. Должен быть между 1 и 12")
+		return 0, 0, 0, 0, i18n.Errorf("schedule.parse_month")
 	}
 
 	// Парсим рабочие дни
 	workDays, err = strconv.Atoi(parts[2])
 	if err != nil || workDays < 0 || workDays > 31 {
-		return 0, 0, 0, 0, fmt.Errorf("неверное количество дней. Должно быть между 0 и 31")
+		return 0, 0, 0, 0, i18n.Errorf("schedule.parse_days")
 	}
 
 	// Парсим минуты в день
 	workMinutesPerDay, err = strconv.Atoi(parts[3])
 	if err != nil || workMinutesPerDay <= 0 || workMinutesPerDay > 1440 {
-		return 0, 0, 0, 0, fmt.Errorf("неверное количество минут в день. Должно быть между 1 и 1440")
+		return 0, 0, 0, 0, i18n.Errorf("schedule.parse_minutes")
 	}
 
 	return year, month, workDays, workMinutesPerDay, nil
@@ -477,17 +470,17 @@ func (s *WorkScheduleService) ParseScheduleData(input string) (year, month, work
 func (s *WorkScheduleService) ParseTime(timeStr string) (int, error) {
 	parts := strings.Split(timeStr, ":")
 	if len(parts) != 2 {
-		return 0, fmt.Errorf("неверный формат времени. Используйте ЧЧ:ММ")
+		return 0, i18n.Errorf("time.parse_format")
 	}
 
 	hours, err := strconv.Atoi(parts[0])
 	if err != nil || hours < 0 || hours > 23 {
-		return 0, fmt.Errorf("неверное количество часов. Должно быть между 0 и 23")
+		return 0, i18n.Errorf("time.parse_hours")
 	}
 
 	minutes, err := strconv.Atoi(parts[1])
 	if err != nil || minutes < 0 || minutes > 59 {
-		return 0, fmt.Errorf("неверное количество минут. Должно быть между 0 и 59")
+		return 0, i18n.Errorf("time.parse_minutes")
 	}
 
 	return hours*60 + minutes, nil
@@ -4,18 +4,54 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"work-schedule-bot/internal/i18n"
 	"work-schedule-bot/internal/models"
 	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
 
 	"github.com/sirupsen/logrus"
 )
 
+// ClockReason - правило, по которому отклонена отметка прихода или ухода
+type ClockReason int
+
+const (
+	ClockFuture        ClockReason = iota + 1 // время отметки еще не наступило
+	ClockTooEarly                             // год раньше WorkPolicy.MinClockInYear
+	ClockNonWorkingDay                        // выходной по производственному календарю
+	ClockNotAllowed                           // уже на работе, в отсутствии или нет активной сессии
+	ClockBeforeStart                          // уход раньше прихода
+)
+
+// ClockError - отметка отклонена правилами учета. Текст для пользователя - ошибка из каталога
+// в Unwrap, Reason позволяет боту и API выбрать реакцию.
+type ClockError struct {
+	Reason ClockReason
+	err    error
+}
+
+func newClockError(reason ClockReason, key string, args ...interface{}) *ClockError {
+	return &ClockError{Reason: reason, err: i18n.Errorf(key, args...)}
+}
+
+func (e *ClockError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ClockError) Unwrap() error {
+	return e.err
+}
+
 type WorkSessionService struct {
-	sessionRepo         repository.WorkSessionRepository
-	userMonthlyStatRepo repository.UserMonthlyStatRepository
-	workScheduleRepo    repository.WorkScheduleRepository
-	absenceRepo         repository.AbsencePeriodRepository
-	logger              *logrus.Logger
+	sessionRepo          repository.WorkSessionRepository
+	userMonthlyStatRepo  repository.UserMonthlyStatRepository
+	workScheduleRepo     repository.WorkScheduleRepository
+	absenceRepo          repository.AbsencePeriodRepository
+	nonWorkingDayService *NonWorkingDayService
+	uow                  repository.UnitOfWork
+	policy               models.WorkPolicy
+	clock                clock.Clock
+	logger               *logrus.Logger
 }
 
 func NewWorkSessionService(
@@ -23,6 +59,10 @@ func NewWorkSessionService(
 	userMonthlyStatRepo repository.UserMonthlyStatRepository,
 	workScheduleRepo 	repository.WorkScheduleRepository,
 	absenceRepo         repository.AbsencePeriodRepository,
+	nonWorkingDayService *NonWorkingDayService,
+	uow                 repository.UnitOfWork,
+	policy              models.WorkPolicy,
+	clk                 clock.Clock,
 ) *WorkSessionService {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.TextFormatter{
@@ -31,15 +71,48 @@ func NewWorkSessionService(
 	})
 
 	return &WorkSessionService{
-		sessionRepo:         sessionRepo,
-		userMonthlyStatRepo: userMonthlyStatRepo,
-		workScheduleRepo:    workScheduleRepo,
-		logger:              logger,
+		sessionRepo:          sessionRepo,
+		userMonthlyStatRepo:  userMonthlyStatRepo,
+		workScheduleRepo:     workScheduleRepo,
+		absenceRepo:          absenceRepo,
+		nonWorkingDayService: nonWorkingDayService,
+		uow:                  uow,
+		policy:               policy,
+		clock:                clk,
+		logger:               logger,
 	}
 }
 
-// ClockIn отмечает начало рабочего дня
-func (s *WorkSessionService) ClockIn(userID uint, clockInTime time.Time, requiredMinutes int) (*models.WorkSession, error) {
+// ClockIn отмечает начало рабочего дня по правилам /in: время не в будущем и не раньше
+// MinClockInYear, день рабочий, сотрудник не на работе и не в отсутствии.
+// Норма на день - остаток плана месяца на оставшиеся дни, без статистики - FallbackRequiredMinutes.
+// clockInTime передается в часовом поясе сотрудника: по его календарю определяется рабочий день.
+// actor - кто отмечает: сам сотрудник или клиент API. Нарушение правил возвращается как *ClockError.
+func (s *WorkSessionService) ClockIn(userID uint, clockInTime time.Time, actor models.Actor) (*models.WorkSession, error) {
+	if clockInTime.After(s.clock.Now()) {
+		return nil, newClockError(ClockFuture, "session.error.clock_in_future")
+	}
+	if minYear := s.policy.MinClockInYear; clockInTime.Year() < minYear {
+		return nil, newClockError(ClockTooEarly, "session.error.clock_in_too_early", minYear)
+	}
+	if s.isNonWorkingDay(clockInTime) {
+		return nil, newClockError(ClockNonWorkingDay, "session.error.clock_in_non_working", clockInTime)
+	}
+
+	canClockIn, reason, err := s.CanClockIn(userID, clockInTime)
+	if err != nil {
+		return nil, err
+	}
+	if !canClockIn {
+		return nil, newClockError(ClockNotAllowed, reason.Key, reason.Args...)
+	}
+
+	requiredMinutes, err := s.requiredMinutes(userID, clockInTime.Year(), int(clockInTime.Month()))
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to get required minutes, using default")
+		requiredMinutes = s.policy.FallbackRequiredMinutes
+	}
+
 	s.logger.WithFields(logrus.Fields{
 		"user_id":          userID,
 		"clock_in_time":    clockInTime.Format("15:04"),
@@ -55,7 +128,7 @@ func (s *WorkSessionService) ClockIn(userID uint, clockInTime time.Time, require
 
 	if hasActive {
 		s.logger.WithField("user_id", userID).Warn("User already has active session")
-		return nil, fmt.Errorf("у вас уже есть активная рабочая сессия")
+		return nil, i18n.Errorf("session.already_active")
 	}
 
 	// Проверяем, есть ли сессия на сегодня
@@ -73,7 +146,7 @@ func (s *WorkSessionService) ClockIn(userID uint, clockInTime time.Time, require
 	// Создаем новую сессию
 	session := &models.WorkSession{
 		UserID:          userID,
-		Date:            clockInTime,
+		Date:            clock.DateOf(clockInTime, s.clock.Location()),
 		ClockInTime:     clockInTime,
 		ClockOutTime:    nil,
 		RequiredMinutes: requiredMinutes,
@@ -85,12 +158,18 @@ func (s *WorkSessionService) ClockIn(userID uint, clockInTime time.Time, require
 
 	if !session.IsValid() {
 		s.logger.Warn("Invalid work session data")
-		return nil, fmt.Errorf("некорректные данные сессии")
+		return nil, i18n.Errorf("session.invalid")
 	}
 
-	err = s.sessionRepo.Create(session)
+	err = s.uow.WithTx(func(repos *repository.Repositories) error {
+		if err := repos.WorkSessions.Create(session); err != nil {
+			s.logger.WithError(err).Error("Failed to create work session")
+			return err
+		}
+		entry := models.NewAuditEntry(actor, models.AuditCreate, models.AuditEntitySession, session.ID).ForUser(userID)
+		return recordAudit(repos.Audit, entry, nil, session)
+	})
 	if err != nil {
-		s.logger.WithError(err).Error("Failed to create work session")
 		return nil, err
 	}
 
@@ -103,33 +182,65 @@ func (s *WorkSessionService) ClockIn(userID uint, clockInTime time.Time, require
 	return session, nil
 }
 
-// ClockOut отмечает конец рабочего дня
-func (s *WorkSessionService) ClockOut(userID uint, clockOutTime time.Time) (*models.WorkSession, error) {
+// ClockOut отмечает конец рабочего дня по правилам /out: время не в будущем и не раньше прихода,
+// в выходной - только с allowNonWorkingDay (сотрудник подтвердил). Нарушение правил возвращается как *ClockError.
+func (s *WorkSessionService) ClockOut(userID uint, clockOutTime time.Time, allowNonWorkingDay bool, actor models.Actor) (*models.WorkSession, error) {
+	if clockOutTime.After(s.clock.Now()) {
+		return nil, newClockError(ClockFuture, "session.error.clock_out_future")
+	}
+	if !allowNonWorkingDay && s.isNonWorkingDay(clockOutTime) {
+		return nil, newClockError(ClockNonWorkingDay, "session.error.clock_out_non_working", clockOutTime)
+	}
+
+	active, err := s.sessionRepo.GetActiveByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if active == nil {
+		return nil, newClockError(ClockNotAllowed, "session.no_active")
+	}
+	if clockOutTime.Before(active.ClockInTime) {
+		return nil, newClockError(ClockBeforeStart, "session.error.clock_out_before_in")
+	}
+
 	s.logger.WithFields(logrus.Fields{
 		"user_id":        userID,
 		"clock_out_time": clockOutTime.Format("15:04"),
 	}).Info("User clocking out")
 
-	// Завершаем сессию
-	sessionID, err := s.sessionRepo.CompleteSession(userID, clockOutTime)
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to complete work session")
-		return nil, err
-	}
+	// Завершаем сессию и обновляем статистику за месяц в одной транзакции
+	var session *models.WorkSession
+	err = s.uow.WithTx(func(repos *repository.Repositories) error {
+		before, err := repos.WorkSessions.GetActiveByUserID(userID)
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to get active session")
+			return err
+		}
 
-	// Получаем обновленную сессию
-	session, err := s.sessionRepo.GetByID(sessionID)
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to get completed session")
-		return nil, err
-	}
+		sessionID, err := repos.WorkSessions.CompleteSession(userID, clockOutTime)
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to complete work session")
+			return err
+		}
 
-	// Обновляем статистику за месяц
-	go func() {
-		if err := s.updateMonthlyStats(userID, session); err != nil {
+		// Получаем обновленную сессию
+		session, err = repos.WorkSessions.GetByID(sessionID)
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to get completed session")
+			return err
+		}
+
+		if err := s.updateMonthlyStats(repos, userID, session); err != nil {
 			s.logger.WithError(err).Error("Failed to update monthly stats after clock out")
+			return i18n.Errorf("stats.update_failed", err)
 		}
-	}()
+
+		entry := models.NewAuditEntry(actor, models.AuditUpdate, models.AuditEntitySession, session.ID).ForUser(userID)
+		return recordAudit(repos.Audit, entry, before, session)
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	s.logger.WithFields(logrus.Fields{
 		"id":             session.ID,
@@ -141,20 +252,12 @@ func (s *WorkSessionService) ClockOut(userID uint, clockOutTime time.Time) (*mod
 	return session, nil
 }
 
-// updateMonthlyStats обновляет месячную статистику после завершения рабочего дня
-func (s *WorkSessionService) updateMonthlyStats(userID uint, session *models.WorkSession) error {
+// updateMonthlyStats пересчитывает месячную статистику после завершения рабочего дня
+func (s *WorkSessionService) updateMonthlyStats(repos *repository.Repositories, userID uint, session *models.WorkSession) error {
 	year := session.Date.Year()
 	month := int(session.Date.Month())
 
-	// Получаем статистику за месяц
-	days, minutes, err := s.sessionRepo.GetStatsByUserAndMonth(userID, year, month)
-	if err != nil {
-		return err
-	}
-
-	// Обновляем месячную статистику
-	err = s.userMonthlyStatRepo.UpdateWorkedStats(userID, year, month, days, minutes)
-	if err != nil {
+	if _, err := newStatRules(s.policy, s.clock).rebuildMonthlyStat(repos, userID, year, month); err != nil {
 		return err
 	}
 
@@ -162,23 +265,127 @@ func (s *WorkSessionService) updateMonthlyStats(userID uint, session *models.Wor
 		"user_id": userID,
 		"year":    year,
 		"month":   month,
-		"days":    days,
-		"minutes": minutes,
-	}).Info("Monthly stats updated after clock out")
+	}).Info("Monthly stats rebuilt after clock out")
 
 	return nil
 }
 
-// GetAllTodaySession возвращает все сессии на сегодня
-func (s *WorkSessionService) GetAllTodaySessions(userID uint) (*[]models.WorkSession, error) {
+// CorrectSession исправляет время прихода и ухода сотрудника за день date: обновляет единственную
+// рабочую сессию дня или создает завершенную, если сотрудник не отмечался. Дни отсутствия не исправляются.
+// clockIn и clockOut передаются в часовом поясе сотрудника. Статистика месяца пересчитывается в той же транзакции.
+func (s *WorkSessionService) CorrectSession(userID uint, date, clockIn, clockOut time.Time, actor models.Actor) (*models.WorkSession, error) {
+	date = clock.DateOf(date, s.clock.Location())
+	if !s.policy.ValidYear(date.Year()) {
+		return nil, i18n.Errorf("date.invalid_year", s.policy.MinYear, s.policy.MaxYear)
+	}
+	if !clockOut.After(clockIn) {
+		return nil, i18n.Errorf("session.error.clock_out_before_in")
+	}
+	if clockOut.After(s.clock.Now()) {
+		return nil, i18n.Errorf("session.error.clock_out_future")
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":   userID,
+		"date":      date.Format("2006-01-02"),
+		"clock_in":  clockIn.Format("15:04"),
+		"clock_out": clockOut.Format("15:04"),
+	}).Info("Correcting work session")
+
+	var session *models.WorkSession
+	err := s.uow.WithTx(func(repos *repository.Repositories) error {
+		existing, err := repos.WorkSessions.GetAllByUserAndDate(userID, date)
+		if err != nil {
+			return err
+		}
+
+		var before *models.WorkSession
+		for i := range *existing {
+			found := &(*existing)[i]
+			if found.IsAbsence() {
+				return i18n.Errorf("session.correct.absence_day", date)
+			}
+			if before != nil {
+				return i18n.Errorf("session.correct.several", date, len(*existing))
+			}
+			before = found
+		}
+
+		action := models.AuditUpdate
+		if before == nil {
+			requiredMinutes, err := importRequiredMinutes(repos, date, s.policy.DefaultDayMinutes)
+			if err != nil {
+				return err
+			}
+			action = models.AuditCreate
+			session = &models.WorkSession{
+				UserID:          userID,
+				Date:            date,
+				SessionType:     models.SessionTypeWork,
+				RequiredMinutes: requiredMinutes,
+			}
+		} else {
+			copied := *before
+			session = &copied
+		}
+
+		session.ClockInTime = clockIn
+		session.ClockOutTime = &clockOut
+		session.UpdateCalculatedFields()
+
+		if before == nil {
+			err = repos.WorkSessions.Create(session)
+		} else {
+			err = repos.WorkSessions.Update(session)
+		}
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to save corrected work session")
+			return err
+		}
+
+		if err := s.updateMonthlyStats(repos, userID, session); err != nil {
+			return i18n.Errorf("stats.update_failed", err)
+		}
+
+		entry := models.NewAuditEntry(actor, action, models.AuditEntitySession, session.ID).ForUser(userID)
+		return recordAudit(repos.Audit, entry, before, session)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"id":             session.ID,
+		"user_id":        userID,
+		"worked_minutes": session.WorkedMinutes,
+	}).Info("Work session corrected")
+
+	return session, nil
+}
+
+// GetSessionsForPeriod возвращает сессии пользователя за период (даты включительно)
+func (s *WorkSessionService) GetSessionsForPeriod(userID uint, startDate, endDate time.Time) ([]*models.WorkSession, error) {
+	if endDate.Before(startDate) {
+		return nil, i18n.Errorf("period.end_before_start")
+	}
+	return s.sessionRepo.GetByUserIDAndPeriod(userID, startDate, endDate)
+}
+
+// GetAllTodaySession возвращает все сессии на сегодня по календарю часового пояса сотрудника
+func (s *WorkSessionService) GetAllTodaySessions(userID uint, loc *time.Location) (*[]models.WorkSession, error) {
 	s.logger.WithField("user_id", userID).Debug("Getting today's work session")
-	return s.sessionRepo.GetAllTodayByUserID(userID)
+	return s.sessionRepo.GetAllByUserAndDate(userID, s.userToday(loc))
 }
 
-// GetTodaySession возвращает сессию на сегодня
-func (s *WorkSessionService) GetTodaySession(userID uint) (*models.WorkSession, error) {
+// GetTodaySession возвращает сессию на сегодня по календарю часового пояса сотрудника
+func (s *WorkSessionService) GetTodaySession(userID uint, loc *time.Location) (*models.WorkSession, error) {
 	s.logger.WithField("user_id", userID).Debug("Getting today's work session")
-	return s.sessionRepo.GetTodayByUserID(userID)
+	return s.sessionRepo.GetByUserAndDate(userID, s.userToday(loc))
+}
+
+// userToday возвращает сегодняшний день в поясе сотрудника как дату в поясе компании
+func (s *WorkSessionService) userToday(loc *time.Location) time.Time {
+	return clock.DateOf(s.clock.Now().In(loc), s.clock.Location())
 }
 
 // GetActiveSession возвращает активную сессию
@@ -208,244 +415,174 @@ func (s *WorkSessionService) GetMonthSessions(userID uint, year, month int) ([]*
 	return s.sessionRepo.GetByUserIDAndMonth(userID, year, month)
 }
 
-// FormatSession форматирует сессию для отображения
-func (s *WorkSessionService) FormatSession(session *models.WorkSession) string {
+// FormatSession форматирует сессию для отображения. Время показывается в поясе loc.
+func (s *WorkSessionService) FormatSession(tr *i18n.Localizer, session *models.WorkSession, loc *time.Location) string {
 	if session == nil {
-		return "❌ Сессия не найдена"
+		return tr.T("session.not_found")
 	}
 
 	// Если это отсутствие
 	if session.IsAbsence() {
-		emoji := session.GetAbsenceEmoji()
-		sessionType := session.FormatSessionType()
-
-		return fmt.Sprintf(
-			`%s %s: %s
-
-📅 Дата: %s
-⏰ Время: 09:00 - 17:40
-⏳ Отметка: %d:%02d часов
-📊 Статус: %s
+		creditStatus := tr.T("session.absence.credited")
+		creditHint := tr.T("session.absence.credited_hint")
+		switch s.policy.AbsenceCreditMode(session.SessionType) {
+		case models.AbsenceCreditReducePlan:
+			creditStatus = tr.T("session.absence.reduce_plan")
+			creditHint = tr.T("session.absence.reduce_plan_hint")
+		case models.AbsenceCreditNone:
+			creditStatus = tr.T("session.absence.not_credited")
+			creditHint = tr.T("session.absence.not_credited_hint")
+		}
 
-%s`,
-			emoji, sessionType,
-			session.Date.Format("02.01.2006"),
-			session.Date.Format("02.01.2006"),
+		return tr.T("session.absence.details",
+			session.GetAbsenceEmoji(), session.FormatSessionType(tr),
+			tr.Date(session.Date),
+			tr.Date(session.Date),
 			session.RequiredMinutes/60, session.RequiredMinutes%60,
-			"✅ Засчитано",
-			"💡 Этот день засчитан как рабочий согласно графику",
+			creditStatus,
+			creditHint,
 		)
 	}
 
-	dateStr := session.Date.Format("02.01.2006")
-	timeStr := session.FormatTime()
-	durationStr := session.Duration()
-
-	// Конвертируем минуты в часы:минуты
-	requiredHours := session.RequiredMinutes / 60
-	requiredMinutes := session.RequiredMinutes % 60
-	workedHours := session.WorkedMinutes / 60
-	workedMinutes := session.WorkedMinutes % 60
-	diffHours := session.DiffMinutes / 60
-	diffMinutes := session.DiffMinutes % 60
-
-	var requiredTime, workedTime, diffTime string
-
-	if requiredMinutes == 0 {
-		requiredTime = fmt.Sprintf("%dч", requiredHours)
-	} else {
-		requiredTime = fmt.Sprintf("%dч %dм", requiredHours, requiredMinutes)
-	}
-
-	if workedMinutes == 0 {
-		workedTime = fmt.Sprintf("%dч", workedHours)
-	} else {
-		workedTime = fmt.Sprintf("%dч %dм", workedHours, workedMinutes)
-	}
-
-	if session.DiffMinutes != 0 {
-		absDiffHours := diffHours
-		if diffHours < 0 {
-			absDiffHours = -diffHours
-		}
-		absDiffMinutes := diffMinutes
-		if diffMinutes < 0 {
-			absDiffMinutes = -diffMinutes
-		}
-
-		if absDiffMinutes == 0 {
-			diffTime = fmt.Sprintf("%dч", absDiffHours)
-		} else {
-			diffTime = fmt.Sprintf("%dч %dм", absDiffHours, absDiffMinutes)
-		}
-	}
-
 	statusEmoji := "🟢"
 	if session.Status == models.StatusCompleted {
 		statusEmoji = "✅"
 	}
 
-	diffStatus := ""
-	if session.DiffMinutes > 0 {
-		diffStatus = fmt.Sprintf("➕ Переработка: %s", diffTime)
-	} else if session.DiffMinutes < 0 {
-		diffStatus = fmt.Sprintf("➖ Недобор: %s", diffTime)
-	}
-
-	result := fmt.Sprintf(
-		`📅 Рабочий день: %s
-%s %s
-
-%s
-⏳ Продолжительность: %s
-
-📊 Нормы:
-   📋 Плановое время: %s
-   ⏰ Отработано: %s`,
-		dateStr,
-		statusEmoji, session.Status,
-		timeStr,
-		durationStr,
-		requiredTime,
-		workedTime,
+	result := tr.T("session.details",
+		tr.Date(session.Date),
+		statusEmoji, tr.T("session.status."+session.Status),
+		session.FormatTime(tr, loc),
+		session.Duration(tr),
+		tr.Duration(session.RequiredMinutes),
+		tr.Duration(session.WorkedMinutes),
 	)
 
-	if diffStatus != "" {
-		result += fmt.Sprintf("\n\n%s", diffStatus)
+	if session.DiffMinutes > 0 {
+		result += "\n\n" + tr.T("session.overtime", tr.Duration(session.DiffMinutes))
+	} else if session.DiffMinutes < 0 {
+		result += "\n\n" + tr.T("session.deficit", tr.Duration(-session.DiffMinutes))
 	}
 
 	if session.Notes != "" {
-		result += fmt.Sprintf("\n\n📝 Примечание: %s", session.Notes)
+		result += "\n\n" + tr.T("session.notes", session.Notes)
 	}
 
-	result += fmt.Sprintf("\n\n🕒 Обновлено: %s",
-		session.UpdatedAt.Format("02.01.2006 15:04"))
+	result += "\n\n" + tr.T("session.updated_at", tr.DateTime(session.UpdatedAt.In(loc)))
 
 	return result
 }
 
-// FormatSessionList форматирует список сессий
-func (s *WorkSessionService) FormatSessionList(sessions []*models.WorkSession) string {
+// FormatSessionList форматирует список сессий. Время показывается в поясе loc.
+func (s *WorkSessionService) FormatSessionList(tr *i18n.Localizer, sessions []*models.WorkSession, loc *time.Location) string {
 	if len(sessions) == 0 {
-		return "📭 Рабочих сессий пока нет"
+		return tr.T("session.history_empty")
 	}
 
 	var result strings.Builder
-	result.WriteString("📋 История рабочих дней:\n\n")
+	result.WriteString(tr.T("session.history_title") + "\n\n")
 
 	for i, session := range sessions {
-		dateStr := session.Date.Format("02.01")
-		timeStr := session.FormatTime()
-
 		statusEmoji := "🟢"
 		if session.Status == models.StatusCompleted {
 			statusEmoji = "✅"
 		}
 
-		workedHours := session.WorkedMinutes / 60
-		workedMinutes := session.WorkedMinutes % 60
-
-		var workedTime string
-		if workedMinutes == 0 {
-			workedTime = fmt.Sprintf("%dч", workedHours)
-		} else {
-			workedTime = fmt.Sprintf("%dч %dм", workedHours, workedMinutes)
-		}
-
 		fmt.Fprintf(&result, "%d. %s %s - %s (%s)\n",
 			i+1,
 			statusEmoji,
-			dateStr,
-			workedTime,
-			timeStr)
+			tr.ShortDate(session.Date),
+			tr.Duration(session.CountedMinutes(s.policy)),
+			session.FormatTime(tr, loc))
 	}
 
 	return result.String()
 }
 
-// GetRequiredMinutesForToday возвращает необходимое время работы на сегодня
-func (s *WorkSessionService) GetRequiredMinutesForToday(userID uint) (int, error) {
-	now := time.Now()
-
-	// Получаем график на текущий месяц
-	schedule, err := s.workScheduleRepo.GetByYearMonth(now.Year(), int(now.Month()))
+// requiredMinutes возвращает норму на день: недобор месяца, поделенный на оставшиеся рабочие дни
+func (s *WorkSessionService) requiredMinutes(userID uint, year int, month int) (int, error) {
+	stats, err := s.userMonthlyStatRepo.GetByUserAndMonth(userID, year, month)
 	if err != nil {
-		return 480, err // По умолчанию 8 часов
+		s.logger.WithError(err).Error("Failed to get monthly stats")
+		return 0, err
+	}
+	if stats == nil {
+		return 0, i18n.Errorf("stats.not_found_month", month, year)
 	}
 
-	if schedule == nil {
-		return 480, nil // По умолчанию 8 часов
+	remainingDays := stats.RemainingDays()
+	if remainingDays == 0 {
+		return 0, i18n.Errorf("stats.no_days_left")
 	}
 
-	return schedule.WorkMinutesPerDay, nil
+	return stats.DeficitMinutes / remainingDays, nil
 }
 
-// CanClockIn проверяет, может ли пользователь начать работу
-func (s *WorkSessionService) CanClockIn(userID uint, targetTime time.Time) (bool, string, error) {
+// isNonWorkingDay проверяет день по производственному календарю.
+// Если проверка не удалась, отметка не блокируется.
+func (s *WorkSessionService) isNonWorkingDay(t time.Time) bool {
+	if s.nonWorkingDayService == nil {
+		return false
+	}
+	isNonWorking, err := s.nonWorkingDayService.IsNonWorkingDay(t)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to check if day is non-working")
+		return false
+	}
+	return isNonWorking
+}
+
+// CanClockIn проверяет, может ли пользователь начать работу. Если нет - возвращает причину.
+func (s *WorkSessionService) CanClockIn(userID uint, targetTime time.Time) (bool, i18n.Message, error) {
 	// Проверяем активную сессию
 	activeSession, err := s.sessionRepo.GetActiveByUserID(userID)
 	if err != nil {
-		return false, "", err
+		return false, i18n.Message{}, err
 	}
 
 	if activeSession != nil {
-		return false, "у вас уже есть активная рабочая сессия", nil
+		return false, i18n.Msg("session.already_active"), nil
 	}
 
-	todaySession, err := s.sessionRepo.GetByUserAndDate(userID, targetTime)
+	targetDate := clock.DateOf(targetTime, s.clock.Location())
+
+	todaySession, err := s.sessionRepo.GetByUserAndDate(userID, targetDate)
 	if err != nil {
-		return false, "", err
+		return false, i18n.Message{}, err
 	}
 
 	if todaySession != nil {
 		// Проверяем, не является ли это отсутствием
 		if todaySession.IsAbsence() {
-			return false, "сегодня у вас " + strings.ToLower(todaySession.FormatSessionType()), nil
+			return false, i18n.Msg("session.today_absent", i18n.Msg("absence.type_lower."+todaySession.SessionType)), nil
 		}
 
-		// if todaySession.IsCompleted() {
-		// 	return false, "вы уже завершили работу сегодня", nil
-		// }
 	}
 
 	// Проверяем, не находится ли пользователь в отпуске/больничном
 	if s.absenceRepo != nil {
-		currentAbsence, err := s.absenceRepo.GetCurrentAbsence(userID, targetTime)
+		currentAbsence, err := s.absenceRepo.GetCurrentAbsence(userID, targetDate)
 		if err != nil {
 			s.logger.Warnf("Failed to check current absence: %v", err)
 		} else if currentAbsence != nil {
-			return false, "сегодня у вас " + getAbsenceTypeText(currentAbsence.Type), nil
+			return false, i18n.Msg("session.today_absent", i18n.Msg("absence.type_lower."+currentAbsence.Type)), nil
 		}
 	}
 
-	return true, "", nil
-}
-
-// Вспомогательная функция
-func getAbsenceTypeText(absenceType string) string {
-	switch absenceType {
-	case models.AbsenceTypeVacation:
-		return "отпуск"
-	case models.AbsenceTypeSickLeave:
-		return "больничный"
-	case models.AbsenceTypeDayOff:
-		return "отгул"
-	default:
-		return "отсутствие"
-	}
+	return true, i18n.Message{}, nil
 }
 
-// CanClockOut проверяет, может ли пользователь закончить работу
-func (s *WorkSessionService) CanClockOut(userID uint) (bool, string, error) {
+// CanClockOut проверяет, может ли пользователь закончить работу. Если нет - возвращает причину.
+func (s *WorkSessionService) CanClockOut(userID uint) (bool, i18n.Message, error) {
 	// Проверяем активную сессию
 	hasActive, err := s.sessionRepo.UserHasActiveSession(userID)
 	if err != nil {
-		return false, "", err
+		return false, i18n.Message{}, err
 	}
 
 	if !hasActive {
-		return false, "у вас нет активной рабочей сессии", nil
+		return false, i18n.Msg("session.no_active"), nil
 	}
 
-	return true, "", nil
+	return true, i18n.Message{}, nil
 }
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"work-schedule-bot/internal/models"
+	"work-schedule-bot/internal/repository"
+	"work-schedule-bot/pkg/clock"
+
+	"gorm.io/gorm"
+)
+
+func newTestWorkSessionService(t *testing.T, db *gorm.DB, clk clock.Clock) *WorkSessionService {
+	t.Helper()
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("failed to create repository: %v", err)
+		}
+	}
+	sessionRepo, err := repository.NewGormWorkSessionRepository(db, clk)
+	must(err)
+	statRepo, err := repository.NewGormUserMonthlyStatRepository(db, clk)
+	must(err)
+	scheduleRepo, err := repository.NewGormWorkScheduleRepository(db, clk)
+	must(err)
+	absenceRepo, err := repository.NewGormAbsencePeriodRepository(db, clk)
+	must(err)
+	nonWorkingDayRepo, err := repository.NewGormNonWorkingDayRepository(db)
+	must(err)
+
+	return NewWorkSessionService(sessionRepo, statRepo, scheduleRepo, absenceRepo,
+		NewNonWorkingDayService(nonWorkingDayRepo, clk), repository.NewGormUnitOfWork(db, clk), models.DefaultWorkPolicy(), clk)
+}
+
+// clockReason возвращает правило, по которому отклонена отметка, или 0
+func clockReason(err error) ClockReason {
+	var clockErr *ClockError
+	if errors.As(err, &clockErr) {
+		return clockErr.Reason
+	}
+	return 0
+}
+
+func TestClockInOutRules(t *testing.T) {
+	db := openTestDatabase(t)
+	// Вторник, 10 марта 2026, 12:00 по Москве; 8 марта - воскресенье
+	clk := moscowClock(t, time.Date(2026, time.March, 10, 9, 0, 0, 0, time.UTC))
+	service := newTestWorkSessionService(t, db, clk)
+	loc := clk.Location()
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, time.March, day, hour, 0, 0, 0, loc)
+	}
+
+	user := models.User{ChatID: 1, FirstName: "Test", Role: models.RoleEmployee}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	sunday := models.NonWorkingDay{Date: clock.Date(2026, time.March, 8, loc), Year: 2026, Month: 3, Day: 8}
+	if err := db.Create(&sunday).Error; err != nil {
+		t.Fatalf("failed to create non-working day: %v", err)
+	}
+	actor := models.ChatActor(user.ChatID)
+
+	if _, err := service.ClockIn(user.ID, at(10, 13), actor); clockReason(err) != ClockFuture {
+		t.Errorf("ClockIn in the future error = %v, want ClockFuture", err)
+	}
+	if _, err := service.ClockIn(user.ID, time.Date(2000, time.March, 10, 9, 0, 0, 0, loc), actor); clockReason(err) != ClockTooEarly {
+		t.Errorf("ClockIn in 2000 error = %v, want ClockTooEarly", err)
+	}
+	if _, err := service.ClockIn(user.ID, at(8, 9), actor); clockReason(err) != ClockNonWorkingDay {
+		t.Errorf("ClockIn on Sunday error = %v, want ClockNonWorkingDay", err)
+	}
+	if _, err := service.ClockOut(user.ID, at(10, 11), false, actor); clockReason(err) != ClockNotAllowed {
+		t.Errorf("ClockOut without session error = %v, want ClockNotAllowed", err)
+	}
+
+	// Статистики за месяц нет - норма на день берется из FallbackRequiredMinutes
+	session, err := service.ClockIn(user.ID, at(10, 9), actor)
+	if err != nil {
+		t.Fatalf("ClockIn: %v", err)
+	}
+	if want := models.DefaultWorkPolicy().FallbackRequiredMinutes; session.RequiredMinutes != want {
+		t.Errorf("required minutes = %d, want fallback %d", session.RequiredMinutes, want)
+	}
+	if _, err := service.ClockIn(user.ID, at(10, 10), actor); clockReason(err) != ClockNotAllowed {
+		t.Errorf("second ClockIn error = %v, want ClockNotAllowed", err)
+	}
+
+	if _, err := service.ClockOut(user.ID, at(10, 8), false, actor); clockReason(err) != ClockBeforeStart {
+		t.Errorf("ClockOut before clock in error = %v, want ClockBeforeStart", err)
+	}
+	if _, err := service.ClockOut(user.ID, at(10, 13), false, actor); clockReason(err) != ClockFuture {
+		t.Errorf("ClockOut in the future error = %v, want ClockFuture", err)
+	}
+	session, err = service.ClockOut(user.ID, at(10, 11), false, actor)
+	if err != nil {
+		t.Fatalf("ClockOut: %v", err)
+	}
+	if session.Status != models.StatusCompleted || session.WorkedMinutes != 120 {
+		t.Errorf("session = %+v, want completed with 120 worked minutes", session)
+	}
+
+	// Смена с субботы на воскресенье: завершить в выходной можно только с подтверждением
+	if _, err := service.ClockIn(user.ID, at(7, 20), actor); err != nil {
+		t.Fatalf("ClockIn on Saturday: %v", err)
+	}
+	if _, err := service.ClockOut(user.ID, at(8, 1), false, actor); clockReason(err) != ClockNonWorkingDay {
+		t.Errorf("ClockOut on Sunday error = %v, want ClockNonWorkingDay", err)
+	}
+	if _, err := service.ClockOut(user.ID, at(8, 1), true, actor); err != nil {
+		t.Errorf("confirmed ClockOut on Sunday: %v", err)
+	}
+}
+
+func TestCorrectSession(t *testing.T) {
+	db := openTestDatabase(t)
+	// Вторник, 10 марта 2026, 12:00 по Москве
+	clk := moscowClock(t, time.Date(2026, time.March, 10, 9, 0, 0, 0, time.UTC))
+	service := newTestWorkSessionService(t, db, clk)
+	loc := clk.Location()
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.March, day, hour, minute, 0, 0, loc)
+	}
+	day := func(day int) time.Time {
+		return clock.Date(2026, time.March, day, loc)
+	}
+
+	user := models.User{ChatID: 1, FirstName: "Test", Role: models.RoleEmployee}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	actor := models.ChatActor(99)
+
+	if _, err := service.CorrectSession(user.ID, day(9), at(9, 18, 0), at(9, 9, 0), actor); errorKey(err) != "session.error.clock_out_before_in" {
+		t.Errorf("clock out before clock in error = %v", err)
+	}
+	if _, err := service.CorrectSession(user.ID, day(10), at(10, 9, 0), at(10, 18, 0), actor); errorKey(err) != "session.error.clock_out_future" {
+		t.Errorf("clock out in the future error = %v", err)
+	}
+
+	// Сотрудник на работе с 9:00 - исправление закрывает его сессию
+	active, err := service.ClockIn(user.ID, at(10, 9, 0), models.ChatActor(user.ChatID))
+	if err != nil {
+		t.Fatalf("ClockIn: %v", err)
+	}
+	corrected, err := service.CorrectSession(user.ID, day(10), at(10, 9, 30), at(10, 11, 0), actor)
+	if err != nil {
+		t.Fatalf("CorrectSession of the active day: %v", err)
+	}
+	if corrected.ID != active.ID || corrected.Status != models.StatusCompleted || corrected.WorkedMinutes != 90 {
+		t.Errorf("corrected session = %+v, want session %d completed with 90 worked minutes", corrected, active.ID)
+	}
+
+	// Сотрудник не отмечался - день создается с нормой по умолчанию
+	created, err := service.CorrectSession(user.ID, day(9), at(9, 9, 0), at(9, 17, 0), actor)
+	if err != nil {
+		t.Fatalf("CorrectSession of a missed day: %v", err)
+	}
+	if created.ID == active.ID || created.WorkedMinutes != 480 || created.RequiredMinutes != models.DefaultWorkPolicy().DefaultDayMinutes {
+		t.Errorf("created session = %+v, want a new day with 480 worked minutes", created)
+	}
+
+	var stat models.UserMonthlyStat
+	if err := db.Where("user_id = ? AND year = ? AND month = ?", user.ID, 2026, 3).First(&stat).Error; err != nil {
+		t.Fatalf("failed to load stats: %v", err)
+	}
+	if stat.WorkedMinutes != 90+480 || stat.WorkedDays != 2 {
+		t.Errorf("stats = %d minutes in %d days, want 570 in 2", stat.WorkedMinutes, stat.WorkedDays)
+	}
+
+	for action, want := range map[string]int64{models.AuditCreate: 1, models.AuditUpdate: 1} {
+		var count int64
+		if err := db.Model(&models.AuditEntry{}).
+			Where("actor_chat_id = ? AND entity_type = ? AND action = ?", actor.ChatID, models.AuditEntitySession, action).
+			Count(&count).Error; err != nil {
+			t.Fatalf("failed to count audit entries: %v", err)
+		}
+		if count != want {
+			t.Errorf("%d %s audit entries for corrections, want %d", count, action, want)
+		}
+	}
+
+	// Дни отсутствия и дни с несколькими сессиями не исправляются
+	truancy := models.WorkSession{UserID: user.ID, Date: day(6), ClockInTime: at(6, 9, 0), RequiredMinutes: 480,
+		Status: models.StatusAbsent, SessionType: models.SessionTypeTruancy}
+	if err := db.Create(&truancy).Error; err != nil {
+		t.Fatalf("failed to create absence session: %v", err)
+	}
+	if _, err := service.CorrectSession(user.ID, day(6), at(6, 9, 0), at(6, 17, 0), actor); errorKey(err) != "session.correct.absence_day" {
+		t.Errorf("absence day error = %v", err)
+	}
+
+	for _, hours := range [][2]int{{9, 12}, {13, 17}} {
+		clockOut := at(5, hours[1], 0)
+		session := models.WorkSession{UserID: user.ID, Date: day(5), ClockInTime: at(5, hours[0], 0), ClockOutTime: &clockOut,
+			RequiredMinutes: 480, Status: models.StatusCompleted, SessionType: models.SessionTypeWork}
+		if err := db.Create(&session).Error; err != nil {
+			t.Fatalf("failed to create work session: %v", err)
+		}
+	}
+	if _, err := service.CorrectSession(user.ID, day(5), at(5, 9, 0), at(5, 17, 0), actor); errorKey(err) != "session.correct.several" {
+		t.Errorf("several sessions error = %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package calendar
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+)
+
+// Grid - сетка календаря: строки - сотрудники, столбцы - дни месяца
+type Grid struct {
+	Days    int        // количество дней в месяце
+	Rows    [][]string // для каждой строки и дня - ключ цвета ("" - нет отсутствия)
+	Weekend []bool     // выходные дни (индекс = день - 1)
+	Flagged []bool     // дни с превышением порога (индекс = день - 1)
+	Colors  map[string]color.RGBA
+}
+
+const (
+	cellSize    = 20
+	labelWidth  = 40
+	headerSize  = 22
+	digitScale  = 2
+	digitWidth  = 3
+	digitHeight = 5
+)
+
+var (
+	colorBackground = color.RGBA{255, 255, 255, 255}
+	colorWeekend    = color.RGBA{230, 230, 230, 255}
+	colorGrid       = color.RGBA{200, 200, 200, 255}
+	colorText       = color.RGBA{40, 40, 40, 255}
+	colorFlagged    = color.RGBA{220, 50, 50, 255}
+	colorDefault    = color.RGBA{120, 120, 120, 255}
+)
+
+// digitFont - шрифт 3x5 для цифр 0-9
+var digitFont = [10][digitHeight]string{
+	{"###", "#.#", "#.#", "#.#", "###"},
+	{".#.", "##.", ".#.", ".#.", "###"},
+	{"###", "..#", "###", "#..", "###"},
+	{"###", "..#", "###", "..#", "###"},
+	{"#.#", "#.#", "###", "..#", "..#"},
+	{"###", "#..", "###", "..#", "###"},
+	{"###", "#..", "###", "#.#", "###"},
+	{"###", "..#", ".#.", ".#.", ".#."},
+	{"###", "#.#", "###", "#.#", "###"},
+	{"###", "#.#", "###", "..#", "###"},
+}
+
+// RenderPNG рисует сетку календаря в PNG.
+// Строки подписаны порядковыми номерами (с 1), столбцы - числами месяца.
+func RenderPNG(grid Grid) ([]byte, error) {
+	width := labelWidth + grid.Days*cellSize + 1
+	height := headerSize + len(grid.Rows)*cellSize + 1
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBackground}, image.Point{}, draw.Src)
+
+	// Заголовок с числами месяца
+	for day := 1; day <= grid.Days; day++ {
+		x := labelWidth + (day-1)*cellSize
+		textColor := colorText
+		if isSet(grid.Flagged, day-1) {
+			fillRect(img, x, 0, cellSize, headerSize, colorFlagged)
+			textColor = colorBackground
+		}
+		drawNumber(img, day, x+2, (headerSize-digitHeight*digitScale)/2, textColor)
+	}
+
+	// Строки сотрудников
+	for rowIndex, row := range grid.Rows {
+		y := headerSize + rowIndex*cellSize
+		drawNumber(img, rowIndex+1, 4, y+(cellSize-digitHeight*digitScale)/2, colorText)
+
+		for day := 1; day <= grid.Days; day++ {
+			x := labelWidth + (day-1)*cellSize
+			if isSet(grid.Weekend, day-1) {
+				fillRect(img, x, y, cellSize, cellSize, colorWeekend)
+			}
+			if day-1 < len(row) && row[day-1] != "" {
+				cellColor, ok := grid.Colors[row[day-1]]
+				if !ok {
+					cellColor = colorDefault
+				}
+				fillRect(img, x+2, y+2, cellSize-3, cellSize-3, cellColor)
+			}
+		}
+	}
+
+	// Линии сетки
+	for day := 0; day <= grid.Days; day++ {
+		fillRect(img, labelWidth+day*cellSize, headerSize, 1, len(grid.Rows)*cellSize+1, colorGrid)
+	}
+	for rowIndex := 0; rowIndex <= len(grid.Rows); rowIndex++ {
+		fillRect(img, labelWidth, headerSize+rowIndex*cellSize, grid.Days*cellSize+1, 1, colorGrid)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func isSet(flags []bool, index int) bool {
+	return index >= 0 && index < len(flags) && flags[index]
+}
+
+func fillRect(img *image.RGBA, x, y, w, h int, c color.RGBA) {
+	draw.Draw(img, image.Rect(x, y, x+w, y+h), &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+// drawNumber рисует число шрифтом digitFont
+func drawNumber(img *image.RGBA, number, x, y int, c color.RGBA) {
+	for i, ch := range strconv.Itoa(number) {
+		digit := digitFont[ch-'0']
+		offsetX := x + i*(digitWidth+1)*digitScale
+		for row := 0; row < digitHeight; row++ {
+			for col := 0; col < digitWidth; col++ {
+				if digit[row][col] == '#' {
+					fillRect(img, offsetX+col*digitScale, y+row*digitScale, digitScale, digitScale, c)
+				}
+			}
+		}
+	}
+}
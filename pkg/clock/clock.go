@@ -0,0 +1,77 @@
+// Package clock - источник текущего времени и часового пояса, который можно подменить в тестах
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock возвращает текущее время в часовом поясе компании
+type Clock interface {
+	Now() time.Time
+	// Location - часовой пояс, в котором считаются календарные дни и месяцы
+	Location() *time.Location
+}
+
+type systemClock struct {
+	location *time.Location
+}
+
+func (c systemClock) Now() time.Time {
+	return time.Now().In(c.location)
+}
+
+func (c systemClock) Location() *time.Location {
+	return c.location
+}
+
+// System возвращает часы, показывающие системное время в поясе loc.
+// Если loc не задан, используется часовой пояс сервера.
+func System(loc *time.Location) Clock {
+	if loc == nil {
+		loc = time.Local
+	}
+	return systemClock{location: loc}
+}
+
+// Fake - часы, которые стоят на месте, пока их не переведут
+type Fake struct {
+	mu       sync.Mutex
+	now      time.Time
+	location *time.Location
+}
+
+// NewFake создает часы, показывающие now в часовом поясе now
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now, location: now.Location()}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now.In(f.location)
+}
+
+func (f *Fake) Location() *time.Location {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.location
+}
+
+// Set переводит часы на указанное время
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = now
+}
+
+// Advance переводит часы вперед на d
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+}
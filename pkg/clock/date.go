@@ -0,0 +1,51 @@
+package clock
+
+import "time"
+
+// Календарные даты хранятся как полночь дня в часовом поясе компании.
+// Моменты времени (отметки прихода и ухода) перед выделением даты переводятся в этот пояс.
+
+// Date возвращает полночь указанного дня в поясе loc
+func Date(year int, month time.Month, day int, loc *time.Location) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// DateOf возвращает полночь календарного дня t в поясе loc.
+// Год, месяц и день берутся в зоне самого t: так дата, прочитанная из БД в UTC
+// или введенная пользователем, не сдвигается на соседний день.
+func DateOf(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.Date()
+	return Date(year, month, day, loc)
+}
+
+// DayOf возвращает полночь дня, на который приходится момент t в поясе loc
+func DayOf(t time.Time, loc *time.Location) time.Time {
+	return DateOf(t.In(loc), loc)
+}
+
+// MonthStart возвращает полночь первого дня месяца в поясе loc
+func MonthStart(year int, month time.Month, loc *time.Location) time.Time {
+	return Date(year, month, 1, loc)
+}
+
+// DaysInMonth возвращает количество дней в месяце
+func DaysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// Today возвращает полночь текущего дня по часам c
+func Today(c Clock) time.Time {
+	return DayOf(c.Now(), c.Location())
+}
+
+// SameDay проверяет, что моменты a и b приходятся на один календарный день в поясе loc
+func SameDay(a, b time.Time, loc *time.Location) bool {
+	return DayOf(a, loc).Equal(DayOf(b, loc))
+}
+
+// At возвращает время hour:min календарного дня date в его часовом поясе.
+// В отличие от date.Add, не сдвигается в дни перехода на летнее время.
+func At(date time.Time, hour, min int) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, hour, min, 0, 0, date.Location())
+}
@@ -0,0 +1,155 @@
+package clock
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestDayOfUsesBusinessTimezone(t *testing.T) {
+	moscow := mustLoad(t, "Europe/Moscow")
+	newYork := mustLoad(t, "America/New_York")
+
+	tests := []struct {
+		name    string
+		instant time.Time
+		loc     *time.Location
+		want    time.Time
+	}{
+		{
+			name:    "UTC evening is next month in Moscow",
+			instant: time.Date(2026, time.March, 31, 22, 30, 0, 0, time.UTC),
+			loc:     moscow,
+			want:    time.Date(2026, time.April, 1, 0, 0, 0, 0, moscow),
+		},
+		{
+			name:    "UTC night is previous year in New York",
+			instant: time.Date(2027, time.January, 1, 3, 0, 0, 0, time.UTC),
+			loc:     newYork,
+			want:    time.Date(2026, time.December, 31, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:    "last minute of the day stays on that day",
+			instant: time.Date(2026, time.February, 28, 23, 59, 0, 0, moscow),
+			loc:     moscow,
+			want:    time.Date(2026, time.February, 28, 0, 0, 0, 0, moscow),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DayOf(tt.instant, tt.loc); !got.Equal(tt.want) || got.Location() != tt.loc {
+				t.Errorf("DayOf(%v) = %v, want %v", tt.instant, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateOfKeepsCalendarDate(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+
+	// PostgreSQL возвращает колонку date как полночь UTC: переводом в пояс западнее UTC
+	// дата съехала бы на предыдущий день
+	stored := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	want := time.Date(2026, time.March, 1, 0, 0, 0, 0, newYork)
+	if got := DateOf(stored, newYork); !got.Equal(want) {
+		t.Errorf("DateOf = %v, want %v", got, want)
+	}
+	if got := DayOf(stored, newYork); got.Equal(want) {
+		t.Errorf("DayOf = %v, expected the instant to fall on the previous day", got)
+	}
+}
+
+func TestDaysAcrossDST(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	// 29 марта 2026 в Берлине переводят часы на летнее время: в сутках 23 часа
+	springForward := Date(2026, time.March, 29, berlin)
+	next := springForward.AddDate(0, 0, 1)
+	if next.Sub(springForward) != 23*time.Hour {
+		t.Fatalf("day length = %v, want 23h", next.Sub(springForward))
+	}
+	if !next.Equal(Date(2026, time.March, 30, berlin)) || next.Hour() != 0 {
+		t.Errorf("next day = %v, want midnight of 30.03", next)
+	}
+
+	start := At(springForward, 9, 0)
+	if start.Hour() != 9 || start.Minute() != 0 {
+		t.Errorf("At(9:00) = %v, want 09:00 local time", start)
+	}
+	if shifted := springForward.Add(9 * time.Hour); shifted.Hour() == 9 {
+		t.Errorf("Add(9h) = %v, expected the DST shift to move it", shifted)
+	}
+
+	// 25 октября 2026 - обратный перевод: в сутках 25 часов
+	fallBack := Date(2026, time.October, 25, berlin)
+	if day := fallBack.AddDate(0, 0, 1).Sub(fallBack); day != 25*time.Hour {
+		t.Errorf("day length = %v, want 25h", day)
+	}
+	end := At(fallBack, 17, 40)
+	if end.Hour() != 17 || end.Minute() != 40 {
+		t.Errorf("At(17:40) = %v, want 17:40 local time", end)
+	}
+	if !SameDay(At(fallBack, 0, 30), At(fallBack, 23, 30), berlin) {
+		t.Error("00:30 and 23:30 of the same day are reported as different days")
+	}
+}
+
+func TestMonthBoundaries(t *testing.T) {
+	moscow := mustLoad(t, "Europe/Moscow")
+
+	tests := []struct {
+		year  int
+		month time.Month
+		days  int
+	}{
+		{2026, time.January, 31},
+		{2026, time.February, 28},
+		{2028, time.February, 29},
+		{2026, time.April, 30},
+		{2026, time.December, 31},
+	}
+	for _, tt := range tests {
+		if got := DaysInMonth(tt.year, tt.month); got != tt.days {
+			t.Errorf("DaysInMonth(%d, %v) = %d, want %d", tt.year, tt.month, got, tt.days)
+		}
+
+		start := MonthStart(tt.year, tt.month, moscow)
+		end := start.AddDate(0, 1, 0)
+		if last := end.AddDate(0, 0, -1); last.Day() != tt.days || last.Month() != tt.month {
+			t.Errorf("last day of %d-%02d = %v, want day %d", tt.year, tt.month, last, tt.days)
+		}
+	}
+
+	// Прошлый месяц считается от начала текущего: 31 марта минус месяц дает 3 марта, а не февраль
+	now := time.Date(2026, time.March, 31, 12, 0, 0, 0, moscow)
+	prev := MonthStart(now.Year(), now.Month(), moscow).AddDate(0, -1, 0)
+	if prev.Month() != time.February {
+		t.Errorf("previous month of %v = %v, want February", now, prev.Month())
+	}
+}
+
+func TestFakeClockReportsTimeInItsLocation(t *testing.T) {
+	moscow := mustLoad(t, "Europe/Moscow")
+
+	fake := NewFake(time.Date(2026, time.March, 31, 12, 0, 0, 0, moscow))
+	fake.Set(time.Date(2026, time.March, 31, 21, 30, 0, 0, time.UTC))
+
+	if got := fake.Now(); got.Location() != moscow || got.Day() != 1 || got.Month() != time.April {
+		t.Errorf("Now() = %v, want 1 April in Moscow", got)
+	}
+	if got, want := Today(fake), Date(2026, time.April, 1, moscow); !got.Equal(want) {
+		t.Errorf("Today() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package clock
+
+import (
+	"strings"
+	"time"
+	"work-schedule-bot/internal/i18n"
+
+	// База часовых поясов встраивается в бинарник: в контейнере ее может не быть
+	_ "time/tzdata"
+)
+
+// zoneShortcuts - короткие названия городов для часовых поясов России
+var zoneShortcuts = map[string]string{
+	"москва":        "Europe/Moscow",
+	"мск":           "Europe/Moscow",
+	"moscow":        "Europe/Moscow",
+	"msk":           "Europe/Moscow",
+	"калининград":   "Europe/Kaliningrad",
+	"kaliningrad":   "Europe/Kaliningrad",
+	"самара":        "Europe/Samara",
+	"samara":        "Europe/Samara",
+	"екатеринбург":  "Asia/Yekaterinburg",
+	"екб":           "Asia/Yekaterinburg",
+	"yekaterinburg": "Asia/Yekaterinburg",
+	"омск":          "Asia/Omsk",
+	"omsk":          "Asia/Omsk",
+	"новосибирск":   "Asia/Novosibirsk",
+	"нск":           "Asia/Novosibirsk",
+	"novosibirsk":   "Asia/Novosibirsk",
+	"красноярск":    "Asia/Krasnoyarsk",
+	"krasnoyarsk":   "Asia/Krasnoyarsk",
+	"иркутск":       "Asia/Irkutsk",
+	"irkutsk":       "Asia/Irkutsk",
+	"владивосток":   "Asia/Vladivostok",
+	"vladivostok":   "Asia/Vladivostok",
+}
+
+// ParseZone распознает часовой пояс по IANA-имени (Europe/Moscow) или названию города (Москва, екб).
+// Возвращает каноническое IANA-имя и пояс.
+func ParseZone(input string) (string, *time.Location, error) {
+	name := strings.TrimSpace(input)
+	if name == "" {
+		return "", nil, i18n.Errorf("timezone.empty")
+	}
+
+	if zone, ok := zoneShortcuts[strings.ToLower(name)]; ok {
+		name = zone
+	}
+
+	// Смещения и локальный пояс сервера не принимаются: они не описывают переходы на летнее время
+	if !strings.Contains(name, "/") && name != "UTC" {
+		return "", nil, i18n.Errorf("timezone.unknown", input)
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return "", nil, i18n.Errorf("timezone.unknown", input)
+	}
+
+	return loc.String(), loc, nil
+}
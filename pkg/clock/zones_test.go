@@ -0,0 +1,34 @@
+package clock
+
+import "testing"
+
+func TestParseZone(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Europe/Moscow", "Europe/Moscow"},
+		{" Asia/Novosibirsk ", "Asia/Novosibirsk"},
+		{"Москва", "Europe/Moscow"},
+		{"екб", "Asia/Yekaterinburg"},
+		{"НСК", "Asia/Novosibirsk"},
+		{"UTC", "UTC"},
+	}
+
+	for _, tt := range tests {
+		name, loc, err := ParseZone(tt.input)
+		if err != nil {
+			t.Errorf("ParseZone(%q): %v", tt.input, err)
+			continue
+		}
+		if name != tt.want || loc.String() != tt.want {
+			t.Errorf("ParseZone(%q) = %s, want %s", tt.input, name, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "Local", "+03:00", "Марс", "Europe/Atlantis"} {
+		if _, _, err := ParseZone(input); err == nil {
+			t.Errorf("ParseZone(%q) succeeded, want error", input)
+		}
+	}
+}
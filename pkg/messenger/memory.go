@@ -0,0 +1,114 @@
+package messenger
+
+import (
+	"fmt"
+	"sync"
+)
+
+// MemoryClient - Client, который запоминает отправленное вместо отправки. Используется в тестах.
+type MemoryClient struct {
+	mu                sync.Mutex
+	sent              []OutgoingMessage
+	answeredCallbacks []string
+	removedButtons    []int
+	files             map[string][]byte
+}
+
+func NewMemoryClient() *MemoryClient {
+	return &MemoryClient{}
+}
+
+func (c *MemoryClient) Send(msg OutgoingMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sent = append(c.sent, msg)
+	return nil
+}
+
+func (c *MemoryClient) AnswerCallback(callbackID, text string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.answeredCallbacks = append(c.answeredCallbacks, callbackID)
+	return nil
+}
+
+func (c *MemoryClient) RemoveButtons(chatID int64, messageID int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removedButtons = append(c.removedButtons, messageID)
+	return nil
+}
+
+func (c *MemoryClient) Download(fileID string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, ok := c.files[fileID]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found", fileID)
+	}
+	return data, nil
+}
+
+// AddFile делает файл доступным для Download
+func (c *MemoryClient) AddFile(fileID string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.files == nil {
+		c.files = make(map[string][]byte)
+	}
+	c.files[fileID] = data
+}
+
+// Sent возвращает сообщения, отправленные в чат
+func (c *MemoryClient) Sent(chatID int64) []OutgoingMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var result []OutgoingMessage
+	for _, msg := range c.sent {
+		if msg.ChatID == chatID {
+			result = append(result, msg)
+		}
+	}
+	return result
+}
+
+// Last возвращает последнее сообщение, отправленное в чат
+func (c *MemoryClient) Last(chatID int64) (OutgoingMessage, bool) {
+	sent := c.Sent(chatID)
+	if len(sent) == 0 {
+		return OutgoingMessage{}, false
+	}
+	return sent[len(sent)-1], true
+}
+
+// AnsweredCallbacks возвращает ID подтвержденных нажатий кнопок
+func (c *MemoryClient) AnsweredCallbacks() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string(nil), c.answeredCallbacks...)
+}
+
+// RemovedButtons возвращает ID сообщений, у которых убраны кнопки
+func (c *MemoryClient) RemovedButtons() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]int(nil), c.removedButtons...)
+}
+
+// Reset забывает все отправленное
+func (c *MemoryClient) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sent = nil
+	c.answeredCallbacks = nil
+	c.removedButtons = nil
+}
@@ -0,0 +1,153 @@
+// Package messenger описывает общение бота с пользователями без привязки к конкретному мессенджеру.
+// Обработчики получают Update и отвечают через Client; реализация для Telegram - в pkg/telegram.
+package messenger
+
+import "strings"
+
+// User - автор сообщения или нажатия кнопки
+type User struct {
+	ID           int64
+	Username     string
+	FirstName    string
+	LastName     string
+	LanguageCode string // язык интерфейса клиента пользователя (IETF), может быть пустым
+}
+
+// Message - входящее сообщение
+type Message struct {
+	ID       int
+	ChatID   int64
+	From     User
+	Text     string    // полный текст, включая команду; для сообщения с файлом - подпись
+	Command  string    // команда без "/" и имени бота; пустая, если сообщение - не команда
+	Args     string    // текст после команды
+	Document *Document // приложенный файл или nil
+}
+
+// Document - файл, приложенный к входящему сообщению
+type Document struct {
+	ID   string // идентификатор для Client.Download
+	Name string
+	Size int64
+}
+
+// IsCommand сообщает, что сообщение - команда
+func (m *Message) IsCommand() bool {
+	return m.Command != ""
+}
+
+// Callback - нажатие кнопки под сообщением
+type Callback struct {
+	ID        string
+	ChatID    int64
+	MessageID int // сообщение, к которому прикреплена кнопка
+	From      User
+	Data      string
+}
+
+// Update - входящее событие: сообщение или нажатие кнопки
+type Update struct {
+	ID       int
+	Message  *Message
+	Callback *Callback
+}
+
+// ChatID возвращает чат, к которому относится событие, или 0
+func (u Update) ChatID() int64 {
+	switch {
+	case u.Message != nil:
+		return u.Message.ChatID
+	case u.Callback != nil:
+		return u.Callback.ChatID
+	}
+	return 0
+}
+
+// ParseCommand разбирает текст вида "/команда@бот аргументы"
+func ParseCommand(text string) (command, args string) {
+	if !strings.HasPrefix(text, "/") {
+		return "", ""
+	}
+
+	command = text[1:]
+	if i := strings.IndexAny(command, " \t\n"); i >= 0 {
+		command, args = command[:i], command[i+1:]
+	}
+	command, _, _ = strings.Cut(command, "@")
+	return command, args
+}
+
+// Format - разметка текста исходящего сообщения
+type Format string
+
+const (
+	FormatPlain    Format = ""
+	FormatMarkdown Format = "markdown"
+)
+
+// Button - кнопка под сообщением. Data возвращается в Callback при нажатии.
+type Button struct {
+	Text string
+	Data string
+}
+
+// NewButton создает кнопку
+func NewButton(text, data string) Button {
+	return Button{Text: text, Data: data}
+}
+
+// NewRow объединяет кнопки в один ряд
+func NewRow(buttons ...Button) []Button {
+	return buttons
+}
+
+// File - вложение исходящего сообщения
+type File struct {
+	Name  string
+	Data  []byte
+	Image bool // показать как изображение, а не как документ
+}
+
+// OutgoingMessage - исходящее сообщение. Если есть File, Text отправляется подписью к нему.
+type OutgoingMessage struct {
+	ChatID  int64
+	Text    string
+	Format  Format
+	Buttons [][]Button
+	File    *File
+}
+
+// NewMessage создает текстовое сообщение
+func NewMessage(chatID int64, text string) OutgoingMessage {
+	return OutgoingMessage{ChatID: chatID, Text: text}
+}
+
+// NewImage создает сообщение с изображением и подписью
+func NewImage(chatID int64, name string, data []byte, caption string) OutgoingMessage {
+	return OutgoingMessage{
+		ChatID: chatID,
+		Text:   caption,
+		File:   &File{Name: name, Data: data, Image: true},
+	}
+}
+
+// NewDocument создает сообщение с файлом и подписью
+func NewDocument(chatID int64, name string, data []byte, caption string) OutgoingMessage {
+	return OutgoingMessage{
+		ChatID: chatID,
+		Text:   caption,
+		File:   &File{Name: name, Data: data},
+	}
+}
+
+// Client отправляет сообщения пользователям
+type Client interface {
+	// Send отправляет сообщение
+	Send(msg OutgoingMessage) error
+	// AnswerCallback подтверждает нажатие кнопки; text показывается пользователю, если не пустой
+	AnswerCallback(callbackID, text string) error
+	// RemoveButtons убирает кнопки из отправленного ранее сообщения
+	RemoveButtons(chatID int64, messageID int) error
+	// Download скачивает файл, приложенный к входящему сообщению
+	Download(fileID string) ([]byte, error)
+}
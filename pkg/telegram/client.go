@@ -1,12 +1,17 @@
 package telegram
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 type Client struct {
 	Bot          *tgbotapi.BotAPI
 	UpdateConfig tgbotapi.UpdateConfig
+	FileEndpoint string // шаблон адреса скачивания файлов; пустой - tgbotapi.FileEndpoint
 }
 
 func NewClient(token string) (*Client, error) {
@@ -25,3 +30,39 @@ func NewClient(token string) (*Client, error) {
 		UpdateConfig: updateConfig,
 	}, nil
 }
+
+// SetWebhook регистрирует вебхук в Telegram. Сертификат передается, если он самоподписанный
+// и сервер бота слушает TLS сам.
+func (c *Client) SetWebhook(config WebhookConfig) error {
+	params := tgbotapi.Params{"url": config.URL}
+	params.AddNonEmpty("secret_token", config.SecretToken)
+
+	var resp *tgbotapi.APIResponse
+	var err error
+	if config.CertFile != "" {
+		cert, readErr := os.ReadFile(config.CertFile)
+		if readErr != nil {
+			return fmt.Errorf("ошибка чтения сертификата: %v", readErr)
+		}
+		resp, err = c.Bot.UploadFiles("setWebhook", params, []tgbotapi.RequestFile{{
+			Name: "certificate",
+			Data: tgbotapi.FileBytes{Name: filepath.Base(config.CertFile), Bytes: cert},
+		}})
+	} else {
+		resp, err = c.Bot.MakeRequest("setWebhook", params)
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка регистрации вебхука: %v", err)
+	}
+	if !resp.Ok {
+		return fmt.Errorf("ошибка регистрации вебхука: %s", resp.Description)
+	}
+
+	return nil
+}
+
+// DeleteWebhook отключает вебхук, иначе Telegram не отдает обновления через long polling
+func (c *Client) DeleteWebhook() error {
+	_, err := c.Bot.Request(tgbotapi.DeleteWebhookConfig{})
+	return err
+}
@@ -0,0 +1,179 @@
+package telegram
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"work-schedule-bot/pkg/messenger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Client реализует messenger.Client
+var _ messenger.Client = (*Client)(nil)
+
+// Send отправляет сообщение в Telegram
+func (c *Client) Send(msg messenger.OutgoingMessage) error {
+	var parseMode string
+	if msg.Format == messenger.FormatMarkdown {
+		parseMode = tgbotapi.ModeMarkdown
+	}
+
+	var markup interface{}
+	if len(msg.Buttons) > 0 {
+		markup = inlineKeyboard(msg.Buttons)
+	}
+
+	if msg.File != nil {
+		file := tgbotapi.FileBytes{Name: msg.File.Name, Bytes: msg.File.Data}
+		if msg.File.Image {
+			photo := tgbotapi.NewPhoto(msg.ChatID, file)
+			photo.Caption = msg.Text
+			photo.ParseMode = parseMode
+			photo.ReplyMarkup = markup
+			_, err := c.Bot.Send(photo)
+			return err
+		}
+
+		document := tgbotapi.NewDocument(msg.ChatID, file)
+		document.Caption = msg.Text
+		document.ParseMode = parseMode
+		document.ReplyMarkup = markup
+		_, err := c.Bot.Send(document)
+		return err
+	}
+
+	text := tgbotapi.NewMessage(msg.ChatID, msg.Text)
+	text.ParseMode = parseMode
+	text.ReplyMarkup = markup
+	_, err := c.Bot.Send(text)
+	return err
+}
+
+// AnswerCallback убирает "часики" у нажатой кнопки
+func (c *Client) AnswerCallback(callbackID, text string) error {
+	_, err := c.Bot.Request(tgbotapi.NewCallback(callbackID, text))
+	return err
+}
+
+// RemoveButtons убирает inline-клавиатуру у сообщения
+func (c *Client) RemoveButtons(chatID int64, messageID int) error {
+	_, err := c.Bot.Request(tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, tgbotapi.InlineKeyboardMarkup{
+		InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{},
+	}))
+	return err
+}
+
+// maxDownloadSize - наибольший размер скачиваемого файла (Bot API отдает файлы до 20 МБ)
+const maxDownloadSize = 20 << 20
+
+// Download скачивает файл по его идентификатору
+func (c *Client) Download(fileID string) ([]byte, error) {
+	file, err := c.Bot.GetFile(tgbotapi.FileConfig{FileID: fileID})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := c.FileEndpoint
+	if endpoint == "" {
+		endpoint = tgbotapi.FileEndpoint
+	}
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf(endpoint, c.Bot.Token, file.FilePath), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.Bot.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ошибка скачивания файла: %s", response.Status)
+	}
+	return io.ReadAll(io.LimitReader(response.Body, maxDownloadSize))
+}
+
+func inlineKeyboard(buttons [][]messenger.Button) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(buttons))
+	for _, row := range buttons {
+		keyboardRow := make([]tgbotapi.InlineKeyboardButton, 0, len(row))
+		for _, button := range row {
+			keyboardRow = append(keyboardRow, tgbotapi.NewInlineKeyboardButtonData(button.Text, button.Data))
+		}
+		rows = append(rows, keyboardRow)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// ConvertUpdate переводит обновление Telegram в messenger.Update.
+// Возвращает false для обновлений, которые бот не обрабатывает.
+func ConvertUpdate(update tgbotapi.Update) (messenger.Update, bool) {
+	switch {
+	case update.Message != nil && update.Message.Chat != nil:
+		message := update.Message
+		converted := &messenger.Message{
+			ID:      message.MessageID,
+			ChatID:  message.Chat.ID,
+			From:    convertUser(message.From),
+			Text:    message.Text,
+			Command: message.Command(),
+			Args:    message.CommandArguments(),
+		}
+		if document := message.Document; document != nil {
+			// Команда к файлу передается в подписи
+			converted.Text = message.Caption
+			converted.Command, converted.Args = messenger.ParseCommand(message.Caption)
+			converted.Document = &messenger.Document{
+				ID:   document.FileID,
+				Name: document.FileName,
+				Size: int64(document.FileSize),
+			}
+		}
+		return messenger.Update{ID: update.UpdateID, Message: converted}, true
+
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil && update.CallbackQuery.Message.Chat != nil:
+		callback := update.CallbackQuery
+		return messenger.Update{
+			ID: update.UpdateID,
+			Callback: &messenger.Callback{
+				ID:        callback.ID,
+				ChatID:    callback.Message.Chat.ID,
+				MessageID: callback.Message.MessageID,
+				From:      convertUser(callback.From),
+				Data:      callback.Data,
+			},
+		}, true
+	}
+
+	return messenger.Update{}, false
+}
+
+func convertUser(user *tgbotapi.User) messenger.User {
+	if user == nil {
+		return messenger.User{}
+	}
+	return messenger.User{
+		ID:           user.ID,
+		Username:     user.UserName,
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+		LanguageCode: user.LanguageCode,
+	}
+}
+
+// ConvertUpdates переводит канал обновлений Telegram в канал messenger.Update.
+// Выходной канал закрывается вслед за входным.
+func ConvertUpdates(updates tgbotapi.UpdatesChannel) <-chan messenger.Update {
+	result := make(chan messenger.Update, cap(updates))
+	go func() {
+		defer close(result)
+		for update := range updates {
+			if converted, ok := ConvertUpdate(update); ok {
+				result <- converted
+			}
+		}
+	}()
+	return result
+}
@@ -0,0 +1,79 @@
+package telegram
+
+import (
+	"testing"
+	"work-schedule-bot/pkg/messenger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestConvertUpdate(t *testing.T) {
+	command, ok := ConvertUpdate(tgbotapi.Update{
+		UpdateID: 1,
+		Message: &tgbotapi.Message{
+			MessageID: 10,
+			Chat:      &tgbotapi.Chat{ID: 100},
+			From:      &tgbotapi.User{ID: 100, UserName: "ivan", FirstName: "Иван", LanguageCode: "en-US"},
+			Text:      "/out@test_bot 18:30",
+			Entities:  []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 13}},
+		},
+	})
+	if !ok || command.Message == nil {
+		t.Fatal("command message was not converted")
+	}
+	got := command.Message
+	if got.ChatID != 100 || got.From.Username != "ivan" || got.Command != "out" || got.Args != "18:30" {
+		t.Errorf("message = %+v, want /out 18:30 from ivan in chat 100", got)
+	}
+	if got.From.LanguageCode != "en-US" {
+		t.Errorf("language code = %q, want en-US", got.From.LanguageCode)
+	}
+	if command.ChatID() != 100 {
+		t.Errorf("ChatID() = %d, want 100", command.ChatID())
+	}
+
+	callback, ok := ConvertUpdate(tgbotapi.Update{
+		UpdateID: 2,
+		CallbackQuery: &tgbotapi.CallbackQuery{
+			ID:      "callback-1",
+			From:    &tgbotapi.User{ID: 100},
+			Message: &tgbotapi.Message{MessageID: 11, Chat: &tgbotapi.Chat{ID: 100}},
+			Data:    "command_clock_out",
+		},
+	})
+	if !ok || callback.Callback == nil {
+		t.Fatal("callback was not converted")
+	}
+	if callback.Callback.MessageID != 11 || callback.Callback.Data != "command_clock_out" || callback.ChatID() != 100 {
+		t.Errorf("callback = %+v, want command_clock_out on message 11 in chat 100", callback.Callback)
+	}
+
+	// Обновления без чата (например, inline-запросы) бот не обрабатывает
+	if _, ok := ConvertUpdate(tgbotapi.Update{UpdateID: 3, InlineQuery: &tgbotapi.InlineQuery{ID: "q"}}); ok {
+		t.Error("inline query was converted")
+	}
+}
+
+func TestConvertDocument(t *testing.T) {
+	update, ok := ConvertUpdate(tgbotapi.Update{
+		UpdateID: 1,
+		Message: &tgbotapi.Message{
+			MessageID: 12,
+			Chat:      &tgbotapi.Chat{ID: 100},
+			From:      &tgbotapi.User{ID: 100},
+			Caption:   "/importsessions@test_bot",
+			Document:  &tgbotapi.Document{FileID: "file-1", FileName: "sessions.csv", FileSize: 42},
+		},
+	})
+	if !ok || update.Message == nil {
+		t.Fatal("document message was not converted")
+	}
+
+	got := update.Message
+	if got.Command != "importsessions" || got.Text != "/importsessions@test_bot" {
+		t.Errorf("message = %+v, want /importsessions from the caption", got)
+	}
+	if got.Document == nil || *got.Document != (messenger.Document{ID: "file-1", Name: "sessions.csv", Size: 42}) {
+		t.Errorf("document = %+v, want sessions.csv with ID file-1", got.Document)
+	}
+}
@@ -0,0 +1,152 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// SecretTokenHeader - заголовок, в котором Telegram передает секрет вебхука
+const SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// webhookBufferSize - размер буфера канала обновлений, как у long polling в tgbotapi
+const webhookBufferSize = 100
+
+// WebhookConfig - настройки приема обновлений через вебхук
+type WebhookConfig struct {
+	URL         string // публичный адрес вебхука, который регистрируется в Telegram
+	ListenAddr  string // адрес встроенного HTTP-сервера, например :8443
+	Path        string // путь, на который Telegram присылает обновления
+	SecretToken string // секрет для заголовка X-Telegram-Bot-Api-Secret-Token
+	CertFile    string // сертификат TLS; без него сервер слушает HTTP (TLS на обратном прокси)
+	KeyFile     string // ключ сертификата TLS
+}
+
+// Validate проверяет обязательные настройки вебхука
+func (c WebhookConfig) Validate() error {
+	if c.URL == "" {
+		return errors.New("не задан публичный адрес вебхука")
+	}
+	if c.ListenAddr == "" {
+		return errors.New("не задан адрес HTTP-сервера вебхука")
+	}
+	if c.SecretToken == "" {
+		return errors.New("не задан секрет вебхука")
+	}
+	if !strings.HasPrefix(c.Path, "/") {
+		return fmt.Errorf("путь вебхука должен начинаться с /: %q", c.Path)
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("для TLS нужны и сертификат, и ключ")
+	}
+	return nil
+}
+
+// WebhookServer принимает обновления от Telegram и отдает их в канал,
+// который обрабатывается так же, как канал long polling
+type WebhookServer struct {
+	config  WebhookConfig
+	server  *http.Server
+	updates chan tgbotapi.Update
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewWebhookServer(config WebhookConfig) *WebhookServer {
+	s := &WebhookServer{
+		config:  config,
+		updates: make(chan tgbotapi.Update, webhookBufferSize),
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(config.Path, s)
+	s.server = &http.Server{
+		Addr:    config.ListenAddr,
+		Handler: mux,
+	}
+
+	return s
+}
+
+// Updates возвращает канал обновлений. Канал закрывается после Shutdown.
+func (s *WebhookServer) Updates() tgbotapi.UpdatesChannel {
+	return s.updates
+}
+
+// ListenAndServe запускает HTTP-сервер и блокируется до его остановки
+func (s *WebhookServer) ListenAndServe() error {
+	var err error
+	if s.config.CertFile != "" {
+		err = s.server.ListenAndServeTLS(s.config.CertFile, s.config.KeyFile)
+	} else {
+		err = s.server.ListenAndServe()
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown останавливает сервер, дожидается текущих запросов и закрывает канал обновлений
+func (s *WebhookServer) Shutdown(ctx context.Context) error {
+	err := s.server.Shutdown(ctx)
+	if err != nil {
+		// Не дождались запросов - закрываем соединения, чтобы освободить их обработчики
+		s.server.Close()
+	}
+
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.updates)
+	}
+	s.mu.Unlock()
+
+	return err
+}
+
+// ServeHTTP принимает одно обновление от Telegram
+func (s *WebhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Пустой секрет не совпадает ни с чем: сервер без секрета не принимает обновления
+	token := r.Header.Get(SecretTokenHeader)
+	if s.config.SecretToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.config.SecretToken)) != 1 {
+		http.Error(w, "invalid secret token", http.StatusUnauthorized)
+		return
+	}
+
+	var update tgbotapi.Update
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "invalid update", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Пока обновление не принято в очередь, Telegram не получает ответ и повторит доставку при ошибке
+	select {
+	case s.updates <- update:
+		w.WriteHeader(http.StatusOK)
+	case <-r.Context().Done():
+	}
+}
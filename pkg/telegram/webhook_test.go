@@ -0,0 +1,177 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const testSecret = "test-secret"
+
+// postUpdate отправляет обновление так же, как это делает Telegram
+func postUpdate(t *testing.T, url, secret string, body []byte) int {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(SecretTokenHeader, secret)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func TestWebhookServerDeliversUpdates(t *testing.T) {
+	server := NewWebhookServer(WebhookConfig{
+		URL:         "https://bot.example.com/telegram",
+		ListenAddr:  "127.0.0.1:0",
+		Path:        "/telegram",
+		SecretToken: testSecret,
+	})
+	fakeTelegram := httptest.NewServer(server.server.Handler)
+	defer fakeTelegram.Close()
+
+	update, err := json.Marshal(tgbotapi.Update{
+		UpdateID: 7,
+		Message: &tgbotapi.Message{
+			MessageID: 1,
+			Chat:      &tgbotapi.Chat{ID: 100},
+			Text:      "/start",
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode update: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		secret string
+		body   []byte
+		want   int
+	}{
+		{name: "wrong secret", path: "/telegram", secret: "other", body: update, want: http.StatusUnauthorized},
+		{name: "missing secret", path: "/telegram", body: update, want: http.StatusUnauthorized},
+		{name: "broken body", path: "/telegram", secret: testSecret, body: []byte("{"), want: http.StatusBadRequest},
+		{name: "unknown path", path: "/other", secret: testSecret, body: update, want: http.StatusNotFound},
+		{name: "valid update", path: "/telegram", secret: testSecret, body: update, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		if got := postUpdate(t, fakeTelegram.URL+tt.path, tt.secret, tt.body); got != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	resp, err := http.Get(fakeTelegram.URL + "/telegram")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+
+	// В канал попадает только принятое обновление
+	select {
+	case got := <-server.Updates():
+		if got.UpdateID != 7 || got.Message == nil || got.Message.Chat.ID != 100 {
+			t.Errorf("update = %+v, want update 7 from chat 100", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("update was not delivered to the channel")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if _, ok := <-server.Updates(); ok {
+		t.Error("updates channel is open after Shutdown")
+	}
+	if got := postUpdate(t, fakeTelegram.URL+"/telegram", testSecret, update); got != http.StatusServiceUnavailable {
+		t.Errorf("status after Shutdown = %d, want %d", got, http.StatusServiceUnavailable)
+	}
+}
+
+func TestSetWebhookSendsSecretToken(t *testing.T) {
+	var got map[string]string
+	fakeAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/bottoken/getMe":
+			w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"Test","username":"test_bot"}}`))
+		case "/bottoken/setWebhook":
+			r.ParseForm()
+			got = map[string]string{"url": r.PostForm.Get("url"), "secret_token": r.PostForm.Get("secret_token")}
+			w.Write([]byte(`{"ok":true,"result":true}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer fakeAPI.Close()
+
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint("token", fakeAPI.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatalf("failed to create bot: %v", err)
+	}
+	client := &Client{Bot: bot}
+
+	config := WebhookConfig{URL: "https://bot.example.com/telegram", SecretToken: testSecret}
+	if err := client.SetWebhook(config); err != nil {
+		t.Fatalf("SetWebhook: %v", err)
+	}
+	if got["url"] != config.URL || got["secret_token"] != testSecret {
+		t.Errorf("setWebhook params = %v, want url %q and the secret token", got, config.URL)
+	}
+}
+
+func TestWebhookConfigValidate(t *testing.T) {
+	valid := WebhookConfig{URL: "https://bot.example.com/telegram", ListenAddr: ":8443", Path: "/telegram", SecretToken: testSecret}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate(valid) = %v", err)
+	}
+
+	noKey := valid
+	noKey.CertFile = "cert.pem"
+	if err := noKey.Validate(); err == nil {
+		t.Error("Validate accepted a certificate without a key")
+	}
+
+	badPath := valid
+	badPath.Path = "telegram"
+	if err := badPath.Validate(); err == nil {
+		t.Error("Validate accepted a path without leading slash")
+	}
+
+	noSecret := valid
+	noSecret.SecretToken = ""
+	if err := noSecret.Validate(); err == nil {
+		t.Error("Validate accepted a webhook without a secret token")
+	}
+}
+
+func TestWebhookServerWithoutSecretRejectsUpdates(t *testing.T) {
+	server := NewWebhookServer(WebhookConfig{ListenAddr: "127.0.0.1:0", Path: "/telegram"})
+	fakeTelegram := httptest.NewServer(server.server.Handler)
+	defer fakeTelegram.Close()
+
+	if got := postUpdate(t, fakeTelegram.URL+"/telegram", "", []byte("{}")); got != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d for a server without a secret", got, http.StatusUnauthorized)
+	}
+}
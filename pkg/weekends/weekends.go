@@ -43,8 +43,8 @@ type NonWorkingDay struct {
 	Day   int       `json:"day"`
 }
 
-// ParseWeekendsJSON - парсит JSON и возвращает массив выходных дней
-func ParseWeekendsJSON(filePath string) ([]NonWorkingDay, error) {
+// ParseWeekendsJSON - парсит JSON и возвращает массив выходных дней (полночь каждого дня в поясе loc)
+func ParseWeekendsJSON(filePath string, loc *time.Location) ([]NonWorkingDay, error) {
 	// Читаем файл
 	data, err := os.ReadFile(filePath)
 	if err != nil {
@@ -82,7 +82,7 @@ func ParseWeekendsJSON(filePath string) ([]NonWorkingDay, error) {
 			}
 			
 			// Создаем дату
-			date := time.Date(weekendJSON.Year, time.Month(monthData.Month), day, 0, 0, 0, 0, time.Local)
+			date := time.Date(weekendJSON.Year, time.Month(monthData.Month), day, 0, 0, 0, 0, loc)
 			
 			// Добавляем в результат
 			nonWorkingDays = append(nonWorkingDays, NonWorkingDay{